@@ -1,25 +1,34 @@
 package dto
 
+import "time"
+
 // StudentCreationPayload
 type StudentCreationPayload struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
+	FirstName string `json:"first_name" validate:"required,max=255"`
+	LastName  string `json:"last_name" validate:"required,max=255"`
+	Email     string `json:"email" validate:"required,email,max=255"`
 }
 
-// CourseCreationPayload
+// CourseCreationPayload. The category and instructor are given either by
+// UUID or by name
 type CourseCreationPayload struct {
-	Title       string `json:"title" `
-	Price       uint   `json:"price"`
-	Description string `json:"description"`
-	Instructor  string `json:"instructor"`
-	Category    string `json:"category"`
+	Title          string `json:"title" validate:"required,min=3,max=120"`
+	Price          uint   `json:"price" validate:"required,min=1,max=1000000"`
+	Description    string `json:"description" validate:"required,max=5000"`
+	Instructor     string `json:"instructor" validate:"required_without=InstructorUUID,max=255"`
+	InstructorUUID string `json:"instructor_uuid" validate:"omitempty,uuid"`
+	Category       string `json:"category" validate:"required_without=CategoryUUID,max=100"`
+	CategoryUUID   string `json:"category_uuid" validate:"omitempty,uuid"`
+	Capacity       uint   `json:"capacity" validate:"max=100000"`
 }
 
-// StudentCourseAssigningPayload
+// StudentCourseAssigningPayload. Override skips the prerequisites check and
+// is reserved for admins
 type StudentCourseAssigningPayload struct {
-	Email       string `json:"email" `
-	CourseTitle string `json:"course_title"`
+	Email       string `json:"email" validate:"required,email"`
+	CourseTitle string `json:"course_title" validate:"required,max=120"`
+	Override    bool   `json:"override"`
+	CouponCode  string `json:"coupon_code" validate:"max=64"`
 }
 
 // GetStudentPayload
@@ -31,3 +40,211 @@ type GetStudentPayload struct {
 type GetCoursePayload struct {
 	CourseTitle string `json:"email"`
 }
+
+// ModulePayload
+type ModulePayload struct {
+	Title    string `json:"title" validate:"required,max=255"`
+	Position int    `json:"position" validate:"min=0"`
+}
+
+// LessonPayload
+type LessonPayload struct {
+	Title           string `json:"title" validate:"required,max=255"`
+	Type            string `json:"type" validate:"required,oneof=video text quiz"`
+	Position        int    `json:"position" validate:"min=0"`
+	DurationMinutes uint   `json:"duration_minutes" validate:"max=1440"`
+	ContentURL      string `json:"content_url" validate:"omitempty,url"`
+	Body            string `json:"body"`
+}
+
+// ReorderPayload lists the UUIDs of all the items in their new order
+type ReorderPayload struct {
+	UUIDs []string `json:"uuids" validate:"required,min=1,dive,uuid"`
+}
+
+// LessonProgressPayload
+type LessonProgressPayload struct {
+	Status              string `json:"status" validate:"required,oneof=not_started in_progress completed"`
+	LastPositionSeconds uint   `json:"last_position_seconds"`
+}
+
+// QuestionPayload
+type QuestionPayload struct {
+	Type           string   `json:"type" validate:"required,oneof=single_choice multiple_choice short_text code_output"`
+	Prompt         string   `json:"prompt" validate:"required"`
+	Options        []string `json:"options"`
+	CorrectAnswers []string `json:"correct_answers" validate:"required,min=1"`
+	Points         uint     `json:"points" validate:"max=100"`
+}
+
+// QuizCreationPayload
+type QuizCreationPayload struct {
+	Title         string            `json:"title" validate:"required,max=255"`
+	PassThreshold float64           `json:"pass_threshold" validate:"required,gt=0,lte=100"`
+	MaxAttempts   uint              `json:"max_attempts"`
+	Questions     []QuestionPayload `json:"questions" validate:"required,min=1,dive"`
+}
+
+// AttemptPayload maps each question's UUID to the answers given for it
+type AttemptPayload struct {
+	StudentUUID string              `json:"student_uuid" validate:"required,uuid"`
+	Answers     map[string][]string `json:"answers" validate:"required"`
+}
+
+// CertificateRevocationPayload
+type CertificateRevocationPayload struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// PrerequisitePayload
+type PrerequisitePayload struct {
+	PrerequisiteUUID string `json:"prerequisite_uuid" validate:"required,uuid"`
+}
+
+// CourseRunPayload. The instructor overrides the course's instructor for
+// this run
+type CourseRunPayload struct {
+	Name       string    `json:"name" validate:"required,max=255"`
+	StartDate  time.Time `json:"start_date" validate:"required"`
+	EndDate    time.Time `json:"end_date" validate:"required"`
+	Timezone   string    `json:"timezone" validate:"max=64"`
+	Instructor string    `json:"instructor" validate:"max=255"`
+}
+
+// LiveSessionPayload
+type LiveSessionPayload struct {
+	Title           string    `json:"title" validate:"required,max=255"`
+	StartsAt        time.Time `json:"starts_at" validate:"required"`
+	DurationMinutes uint      `json:"duration_minutes" validate:"required,max=1440"`
+}
+
+// RunEnrollmentPayload. Override skips the prerequisites check and is
+// reserved for admins
+type RunEnrollmentPayload struct {
+	Email      string `json:"email" validate:"required,email"`
+	Override   bool   `json:"override"`
+	CouponCode string `json:"coupon_code" validate:"max=64"`
+}
+
+// ReviewPayload
+type ReviewPayload struct {
+	Rating int    `json:"rating" validate:"required,min=1,max=5"`
+	Text   string `json:"text" validate:"max=5000"`
+}
+
+// ReviewModerationPayload
+type ReviewModerationPayload struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+}
+
+// CategoryPayload. The slug is derived from the name when it is empty
+type CategoryPayload struct {
+	Name       string `json:"name" validate:"required,max=100"`
+	Slug       string `json:"slug" validate:"max=100"`
+	ParentUUID string `json:"parent_uuid" validate:"omitempty,uuid"`
+}
+
+// InstructorPayload
+type InstructorPayload struct {
+	Name      string `json:"name" validate:"required,max=255"`
+	Bio       string `json:"bio" validate:"max=5000"`
+	AvatarURL string `json:"avatar_url" validate:"omitempty,url,max=2048"`
+	UserUUID  string `json:"user_uuid" validate:"omitempty,uuid"`
+}
+
+// CoursePricePayload. The amount is in the currency's minor units, e.g.
+// cents, and the price takes effect immediately when no date is given
+type CoursePricePayload struct {
+	Amount        int64      `json:"amount" validate:"required,gt=0"`
+	Currency      string     `json:"currency" validate:"required,len=3"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+// CouponPayload. Percentage coupons set percent_off, fixed coupons set
+// amount_off in the minor units of its currency. A zero limit means no limit
+type CouponPayload struct {
+	Code            string     `json:"code" validate:"required,max=64"`
+	DiscountType    string     `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	PercentOff      uint       `json:"percent_off" validate:"required_if=DiscountType percentage,max=100"`
+	AmountOff       int64      `json:"amount_off" validate:"required_if=DiscountType fixed,min=0"`
+	Currency        string     `json:"currency" validate:"required_if=DiscountType fixed,omitempty,len=3"`
+	ValidFrom       *time.Time `json:"valid_from"`
+	ValidUntil      *time.Time `json:"valid_until"`
+	MaxRedemptions  uint       `json:"max_redemptions"`
+	PerStudentLimit uint       `json:"per_student_limit"`
+	CourseUUIDs     []string   `json:"course_uuids" validate:"dive,uuid"`
+	CategoryUUIDs   []string   `json:"category_uuids" validate:"dive,uuid"`
+}
+
+// CouponValidationPayload. The price is quoted in the base currency unless a
+// currency is given, and the student's own use of the coupon is checked when
+// they are given
+type CouponValidationPayload struct {
+	Code        string `json:"code" validate:"required,max=64"`
+	CourseUUID  string `json:"course_uuid" validate:"required,uuid"`
+	StudentUUID string `json:"student_uuid" validate:"omitempty,uuid"`
+	Currency    string `json:"currency" validate:"omitempty,len=3"`
+}
+
+// PlanPayload. The price is in the minor units of its currency, and plans
+// renew yearly with a week of grace unless they say otherwise
+type PlanPayload struct {
+	Name           string `json:"name" validate:"required,max=100"`
+	Price          int64  `json:"price" validate:"required,gt=0"`
+	Currency       string `json:"currency" validate:"required,len=3"`
+	IntervalMonths uint   `json:"interval_months" validate:"max=36"`
+	TrialDays      uint   `json:"trial_days" validate:"max=365"`
+	GraceDays      uint   `json:"grace_days" validate:"max=90"`
+}
+
+// SubscriptionPayload
+type SubscriptionPayload struct {
+	PlanUUID string `json:"plan_uuid" validate:"required,uuid"`
+}
+
+// TaxRatePayload. The rate is in basis points, e.g. 1600 for 16%, and is
+// included in prices charged in the currency
+type TaxRatePayload struct {
+	Name        string `json:"name" validate:"required,max=50"`
+	BasisPoints uint   `json:"basis_points" validate:"max=10000"`
+}
+
+// RefundPayload. The payment refunded is a subscription charge, as students
+// don't pay per course. The amount is in the minor units of the charge's
+// currency, and the whole of what is left of it is refunded without one.
+// CourseUUID is the course the student is dropping, if any, which only gives
+// back part of their subscription charge
+type RefundPayload struct {
+	Amount     int64  `json:"amount" validate:"min=0"`
+	Currency   string `json:"currency" validate:"omitempty,len=3"`
+	Reason     string `json:"reason" validate:"required,max=500"`
+	CourseUUID string `json:"course_uuid" validate:"omitempty,uuid"`
+}
+
+// AccessTokenPayload. Subject is the instructor's UUID for instructor tokens
+// and the student's for student tokens, and tokens last 30 days unless
+// TTLHours says otherwise
+type AccessTokenPayload struct {
+	Role     string `json:"role" validate:"required,oneof=admin instructor student"`
+	Subject  string `json:"subject" validate:"omitempty,uuid"`
+	TTLHours uint   `json:"ttl_hours" validate:"max=8760"`
+}
+
+// RevenueSharePayload. The share is in basis points, e.g. 6000 for 60%, and
+// covers all the instructor's courses unless CourseUUID is given
+type RevenueSharePayload struct {
+	CourseUUID    string     `json:"course_uuid" validate:"omitempty,uuid"`
+	BasisPoints   uint       `json:"basis_points" validate:"max=10000"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+// PayoutStatementsPayload. Month is the month to pay out, e.g. "2023-05"
+type PayoutStatementsPayload struct {
+	Month string `json:"month" validate:"required,len=7"`
+}
+
+// PayoutPaidPayload. Reference is the bank or payment provider's reference of
+// the transfer
+type PayoutPaidPayload struct {
+	Reference string `json:"reference" validate:"required,max=100"`
+}
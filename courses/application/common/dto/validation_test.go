@@ -0,0 +1,111 @@
+package dto_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/platform/validation"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestValidate(t *testing.T) {
+	validCourse := &dto.CourseCreationPayload{
+		Title:       gofakeit.LastName(),
+		Price:       gofakeit.UintRange(12, 34),
+		Description: gofakeit.Address().City,
+		Instructor:  gofakeit.Name(),
+		Category:    gofakeit.Car().Brand,
+	}
+	invalidCourse := &dto.CourseCreationPayload{
+		Title:       "Go",
+		Price:       2000000,
+		Description: "",
+		Instructor:  gofakeit.Name(),
+		Category:    strings.Repeat("a", 101),
+	}
+	validStudent := &dto.StudentCreationPayload{
+		FirstName: gofakeit.FirstName(),
+		LastName:  gofakeit.LastName(),
+		Email:     gofakeit.Email(),
+	}
+	invalidStudent := &dto.StudentCreationPayload{
+		FirstName: "",
+		LastName:  "",
+		Email:     "not-an-email",
+	}
+	invalidAssignment := &dto.StudentCourseAssigningPayload{
+		Email:       "",
+		CourseTitle: "",
+	}
+
+	type args struct {
+		payload interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantFields []string
+	}{
+		{
+			name: "Happy case - valid course",
+			args: args{
+				payload: validCourse,
+			},
+		},
+		{
+			name: "Happy case - valid student",
+			args: args{
+				payload: validStudent,
+			},
+		},
+		{
+			name: "Sad case - invalid course",
+			args: args{
+				payload: invalidCourse,
+			},
+			wantFields: []string{"title", "price", "description", "category"},
+		},
+		{
+			name: "Sad case - invalid student",
+			args: args{
+				payload: invalidStudent,
+			},
+			wantFields: []string{"first_name", "last_name", "email"},
+		},
+		{
+			name: "Sad case - empty assignment",
+			args: args{
+				payload: invalidAssignment,
+			},
+			wantFields: []string{"email", "course_title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validation.Validate(tt.args.payload)
+			if (err != nil) != (len(tt.wantFields) > 0) {
+				t.Fatalf("Validate() error = %v, want violations on %v", err, tt.wantFields)
+			}
+			if err == nil {
+				return
+			}
+			var violations validation.ValidationErrors
+			if !errors.As(err, &violations) {
+				t.Fatalf("expected validation errors, got %T", err)
+			}
+			if len(violations) != len(tt.wantFields) {
+				t.Fatalf("expected %d violations, got %d: %v", len(tt.wantFields), len(violations), violations)
+			}
+			for i, field := range tt.wantFields {
+				if violations[i].Field != field {
+					t.Errorf("expected violation on %s, got %s", field, violations[i].Field)
+				}
+				if violations[i].Code == "" || violations[i].Message == "" {
+					t.Errorf("expected violation on %s to have a code and a message", field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Command paymentsim posts signed payment provider webhooks to the courses
+// service, so payment flows can be tried out without a real provider:
+//
+//	paymentsim -type refund.succeeded -reference log_re_123
+//
+// Events are signed with PAYMENTS_WEBHOOK_SECRET unless -secret is given.
+// -repeat sends the same event more than once and -skew signs it in the
+// past, to see deliveries being de-duplicated and replays refused
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:9000/api/v1/payments/webhooks", "webhook endpoint")
+	secret := flag.String("secret", os.Getenv("PAYMENTS_WEBHOOK_SECRET"), "shared webhook secret")
+	eventType := flag.String("type", domain.PaymentEventRefundSucceeded, "event type, e.g. refund.failed")
+	reference := flag.String("reference", "", "provider reference of the charge or refund")
+	key := flag.String("key", "", "idempotency key the charge was made with, e.g. charge:<uuid>")
+	reason := flag.String("reason", "", "failure reason for failed events")
+	id := flag.String("id", "", "provider event ID, random when not set")
+	repeat := flag.Int("repeat", 1, "times to send the event")
+	skew := flag.Duration("skew", 0, "how far in the past to sign the event")
+	flag.Parse()
+
+	if *secret == "" || (*reference == "" && *key == "") {
+		fmt.Fprintln(os.Stderr, "paymentsim: -secret (or PAYMENTS_WEBHOOK_SECRET) and -reference or -key are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *id == "" {
+		*id = "evt_" + uuid.NewString()
+	}
+	body, err := json.Marshal(domain.PaymentEvent{
+		ID:             *id,
+		Type:           *eventType,
+		Reference:      *reference,
+		FailureReason:  *reason,
+		IdempotencyKey: *key,
+		CreatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "paymentsim: can't encode event: %v\n", err)
+		os.Exit(1)
+	}
+
+	verifier := payments.NewWebhookVerifier(*secret, payments.DefaultWebhookTolerance)
+	client := &http.Client{Timeout: 10 * time.Second}
+	for i := 0; i < *repeat; i++ {
+		if err := send(client, *url, verifier.Sign(body, time.Now().Add(-*skew)), body); err != nil {
+			fmt.Fprintf(os.Stderr, "paymentsim: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// send posts a signed event and prints the service's answer
+func send(client *http.Client, url string, signature string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(payments.SignatureHeader, signature)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("can't post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	answer, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("can't read response: %w", err)
+	}
+	fmt.Printf("%s %s\n", resp.Status, bytes.TrimSpace(answer))
+	return nil
+}
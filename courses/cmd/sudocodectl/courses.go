@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+var courseSorts = []string{
+	string(domain.CourseSortTitle),
+	string(domain.CourseSortNewest),
+	string(domain.CourseSortPrice),
+	string(domain.CourseSortRating),
+}
+
+func (a *app) coursesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "courses",
+		Aliases: []string{"course"},
+		Short:   "Create, list and delete courses",
+	}
+
+	course := domain.Course{}
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a course, linking it to its category and instructor",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			created, err := u.CreateCourse(cmd.Context(), &course)
+			if err != nil {
+				return err
+			}
+			return a.print(cmd.OutOrStdout(), created, courseTable([]*domain.Course{created}))
+		},
+	}
+	create.Flags().StringVar(&course.Title, "title", "", "course title")
+	create.Flags().UintVar(&course.Price, "price", 0, "launch price in whole "+domain.BaseCurrency)
+	create.Flags().StringVar(&course.Description, "description", "", "course description")
+	create.Flags().StringVar(&course.Instructor, "instructor", "", "instructor's name")
+	create.Flags().StringVar(&course.Category, "category", "", "category name")
+	create.Flags().UintVar(&course.Capacity, "capacity", 0, "seats per run, unlimited when 0")
+	for _, flag := range []string{"title", "price", "description"} {
+		_ = create.MarkFlagRequired(flag)
+	}
+
+	var sort string
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the catalog",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			courses, err := u.ListCourses(cmd.Context(), domain.CourseSort(sort))
+			if err != nil {
+				return err
+			}
+			return a.print(cmd.OutOrStdout(), courses, courseTable(courses))
+		},
+	}
+	list.Flags().StringVar(&sort, "sort", string(domain.CourseSortTitle), fmt.Sprintf("order of the courses: one of %v", courseSorts))
+	_ = list.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(courseSorts, cobra.ShellCompDirectiveNoFileComp))
+
+	remove := &cobra.Command{
+		Use:   "delete TITLE",
+		Short: "Delete a course no student is enrolled in",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			c, err := findCourse(cmd, u, args[0])
+			if err != nil {
+				return err
+			}
+			if err := u.DeleteCourse(cmd.Context(), &c.UUID); err != nil {
+				return err
+			}
+			return a.report(cmd.OutOrStdout(), "course %s deleted", c.Title)
+		},
+	}
+
+	cmd.AddCommand(create, list, remove)
+	return cmd
+}
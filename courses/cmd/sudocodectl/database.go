@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/database"
+	"github.com/MelvinKim/courses/usecase"
+)
+
+func (a *app) migrateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the database to the current schema and backfill data",
+		Long: "Run the migrations the courses service runs when it starts: create and alter tables, " +
+			"then backfill what older data is missing. Migrations are safe to run more than once.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.database()
+			if err != nil {
+				return err
+			}
+			database.Migrate(db.DB)
+			return a.report(cmd.OutOrStdout(), "database migrated")
+		},
+	}
+}
+
+// demoPlan is the plan seeded students subscribe to. Its trial covers the
+// demo, so nothing is charged
+var demoPlan = domain.Plan{
+	Name:           "Demo",
+	Price:          domain.Money{Amount: 9900, Currency: domain.BaseCurrency},
+	IntervalMonths: 12,
+	TrialDays:      30,
+}
+
+var demoCourses = []domain.Course{
+	{
+		Title:       "Go Fundamentals",
+		Price:       49,
+		Description: "Types, functions, packages and the standard library",
+		Instructor:  "Ada Mwangi",
+		Category:    "Programming",
+		Capacity:    30,
+	},
+	{
+		Title:       "Postgres for Developers",
+		Price:       79,
+		Description: "Schemas, indexes, transactions and query plans",
+		Instructor:  "Brian Otieno",
+		Category:    "Databases",
+	},
+	{
+		Title:       "Kubernetes in Practice",
+		Price:       99,
+		Description: "Deploying and operating services on Kubernetes",
+		Instructor:  "Ada Mwangi",
+		Category:    "DevOps",
+		Capacity:    20,
+	},
+}
+
+var demoStudents = []struct {
+	student domain.Student
+	courses []string
+}{
+	{domain.Student{FirstName: "Jane", LastName: "Wanjiku", Email: "jane.wanjiku@example.com"}, []string{"Go Fundamentals", "Postgres for Developers"}},
+	{domain.Student{FirstName: "Tom", LastName: "Kariuki", Email: "tom.kariuki@example.com"}, []string{"Go Fundamentals"}},
+	{domain.Student{FirstName: "Amina", LastName: "Hassan", Email: "amina.hassan@example.com"}, []string{"Kubernetes in Practice"}},
+}
+
+func (a *app) seedCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "seed",
+		Short: "Add demo courses, students and enrollments",
+		Long: "Add a demo plan, courses and students subscribed to the plan and enrolled in some of the courses. " +
+			"What is already there is left alone, so seeding again only adds what is missing.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			added, err := seed(cmd.Context(), u)
+			if err != nil {
+				return err
+			}
+			return a.report(cmd.OutOrStdout(), "seeded %d courses, %d students and %d enrollments",
+				added.courses, added.students, added.enrollments)
+		},
+	}
+}
+
+type seeded struct {
+	courses, students, enrollments int
+}
+
+// seed adds the demo data that is missing
+func seed(ctx context.Context, u *usecase.Usecase) (seeded, error) {
+	added := seeded{}
+	plan, err := seedPlan(ctx, u)
+	if err != nil {
+		return added, err
+	}
+
+	courses := map[string]*domain.Course{}
+	for _, c := range demoCourses {
+		c := c
+		course, err := u.GetCourse(ctx, &c.Title)
+		if err != nil {
+			return added, err
+		}
+		if course == nil {
+			if course, err = u.CreateCourse(ctx, &c); err != nil {
+				return added, err
+			}
+			added.courses++
+		}
+		courses[course.Title] = course
+	}
+
+	for _, demo := range demoStudents {
+		s := demo.student
+		student, err := u.GetStudent(ctx, &s.Email)
+		if err != nil {
+			return added, err
+		}
+		if student == nil {
+			if student, err = u.CreateStudent(ctx, &s); err != nil {
+				return added, err
+			}
+			added.students++
+		}
+		subscription, err := u.GetStudentSubscription(ctx, &student.UUID)
+		if err != nil {
+			return added, err
+		}
+		if subscription == nil {
+			if _, err := u.Subscribe(ctx, operator, &student.UUID, &plan.UUID, "demo"); err != nil {
+				return added, err
+			}
+		}
+		for _, title := range demo.courses {
+			enrollment, err := u.Get.GetEnrollment(ctx, &student.UUID, &courses[title].UUID)
+			if err != nil {
+				return added, err
+			}
+			if enrollment != nil {
+				continue
+			}
+			title := title
+			if _, err := u.AssignCourseToStudent(ctx, &student.Email, &title, true, ""); err != nil {
+				return added, err
+			}
+			added.enrollments++
+		}
+	}
+	return added, nil
+}
+
+// seedPlan returns the demo plan, creating it when missing
+func seedPlan(ctx context.Context, u *usecase.Usecase) (*domain.Plan, error) {
+	plans, err := u.ListPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		if plan.Name == demoPlan.Name {
+			return plan, nil
+		}
+	}
+	plan := demoPlan
+	return u.CreatePlan(ctx, &plan)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+func (a *app) assignCommand() *cobra.Command {
+	var override bool
+	var coupon string
+	cmd := &cobra.Command{
+		Use:   "assign EMAIL COURSE_TITLE",
+		Short: "Enroll a student in a course, or put them on its waitlist when it is full",
+		Long: "Enroll a student in a course. The student needs a subscription that grants access, " +
+			"and must have completed the course's prerequisites unless --override is given.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			email, title := args[0], args[1]
+			student, err := u.AssignCourseToStudent(cmd.Context(), &email, &title, override, coupon)
+			var waitlisted *domain.WaitlistedError
+			if errors.As(err, &waitlisted) {
+				return a.report(cmd.OutOrStdout(), "%v", waitlisted)
+			}
+			if err != nil {
+				return err
+			}
+			return a.report(cmd.OutOrStdout(), "%s enrolled in %s", student.Email, title)
+		},
+	}
+	cmd.Flags().BoolVar(&override, "override", false, "enroll without checking prerequisites")
+	cmd.Flags().StringVar(&coupon, "coupon", "", "coupon code to redeem")
+	return cmd
+}
+
+func (a *app) unassignCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unassign EMAIL COURSE_TITLE",
+		Short: "Unenroll a student from a course, promoting the first student on its waitlist",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			s, err := findStudent(cmd, u, args[0])
+			if err != nil {
+				return err
+			}
+			c, err := findCourse(cmd, u, args[1])
+			if err != nil {
+				return err
+			}
+			if err := u.UnenrollStudent(cmd.Context(), operator, &s.UUID, &c.UUID); err != nil {
+				return err
+			}
+			return a.report(cmd.OutOrStdout(), "%s unenrolled from %s", s.Email, c.Title)
+		},
+	}
+}
+
+func (a *app) enrollmentsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "enrollments",
+		Aliases: []string{"enrollment"},
+		Short:   "List enrollments",
+	}
+	query := domain.EnrollmentQuery{}
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List enrollments in the order students enrolled",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			enrollments, err := u.ListEnrollments(cmd.Context(), &query)
+			if err != nil {
+				return err
+			}
+			return a.print(cmd.OutOrStdout(), enrollments, enrollmentTable(enrollments))
+		},
+	}
+	list.Flags().IntVar(&query.Limit, "limit", 0, "enrollments to list, at most 1000 (default 100)")
+	list.Flags().IntVar(&query.Offset, "offset", 0, "enrollments to skip")
+	cmd.AddCommand(list)
+	return cmd
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/exports"
+)
+
+var exportTables = []string{
+	string(domain.ExportTableStudents),
+	string(domain.ExportTableCourses),
+	string(domain.ExportTableEnrollments),
+}
+
+var exportFormats = []string{
+	string(domain.ExportFormatCSV),
+	string(domain.ExportFormatNDJSON),
+	string(domain.ExportFormatParquet),
+}
+
+func (a *app) exportCommand() *cobra.Command {
+	var (
+		file, format, from, to string
+		columns                []string
+		compressed             bool
+	)
+	cmd := &cobra.Command{
+		Use:   "export TABLE",
+		Short: "Export students, courses or enrollments as CSV, NDJSON or Parquet",
+		Long: fmt.Sprintf(`Export a table, one of %v, to stdout or a file. Rows are read a page at a
+time and written as they are read, so tables of any size can be exported.
+
+The format and gzip compression are taken from the file's name, e.g.
+students.parquet or enrollments.ndjson.gz, unless --format or --gzip say
+otherwise, and are CSV and uncompressed by default.`, exportTables),
+		Example: `  sudocodectl export students --columns uuid,email,created_at --from 2023-01-01 -f students.csv.gz
+  sudocodectl export enrollments --format ndjson | jq .course_uuid`,
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: exportTables,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := &domain.ExportQuery{
+				Table:   domain.ExportTable(args[0]),
+				Format:  domain.ExportFormat(format),
+				Columns: columns,
+			}
+			name := file
+			if strings.HasSuffix(name, ".gz") {
+				name = strings.TrimSuffix(name, ".gz")
+				if !cmd.Flags().Changed("gzip") {
+					compressed = true
+				}
+			}
+			if query.Format == "" {
+				query.Format = domain.ExportFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+				if _, ok := exports.ContentTypes[query.Format]; !ok {
+					query.Format = domain.ExportFormatCSV
+				}
+			}
+			var err error
+			if query.From, err = parseTime("from", from); err != nil {
+				return err
+			}
+			if query.To, err = parseTime("to", to); err != nil {
+				return err
+			}
+
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			var w io.Writer = cmd.OutOrStdout()
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("can't create export file: %w", err)
+				}
+				defer f.Close()
+				w = f
+			}
+			var zw *gzip.Writer
+			if compressed {
+				zw = gzip.NewWriter(w)
+				w = zw
+			}
+			written, err := u.Export(cmd.Context(), query, w)
+			if err == nil && zw != nil {
+				if err = zw.Close(); err != nil {
+					err = fmt.Errorf("can't finish export: %w", err)
+				}
+			}
+			if err != nil {
+				if file != "" {
+					// don't leave a partial export behind to be mistaken for a whole one
+					_ = os.Remove(file)
+				}
+				return err
+			}
+			if file != "" {
+				return a.report(cmd.ErrOrStderr(), "%d %s exported to %s", written, args[0], file)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&file, "file", "f", "", "file to write to instead of stdout")
+	flags.StringVar(&format, "format", "", fmt.Sprintf("file format, one of %v", exportFormats))
+	flags.StringSliceVar(&columns, "columns", nil, "columns to export, in order (default all)")
+	flags.StringVar(&from, "from", "", "only rows created from this date or RFC 3339 time, or enrollments made from it")
+	flags.StringVar(&to, "to", "", "only rows created before this date or RFC 3339 time, or enrollments made before it")
+	flags.BoolVar(&compressed, "gzip", false, "compress the export with gzip")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(exportFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("columns", completeColumns)
+	return cmd
+}
+
+// completeColumns completes the columns of the table being exported
+func completeColumns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	table, ok := exports.Tables[domain.ExportTable(args[0])]
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return table.ColumnNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// parseTime reads a date like 2006-01-02 or an RFC 3339 time, or nothing
+func parseTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		if t, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("--%s must be a date like 2006-01-02 or an RFC 3339 time", name)
+		}
+	}
+	return &t, nil
+}
@@ -0,0 +1,160 @@
+// Command sudocodectl operates the academy from a terminal. It manages
+// students, courses and enrollments, migrates and seeds the database and
+// exports its tables, going through the same usecases as the courses service:
+//
+//	sudocodectl --profile staging students list -o yaml
+//	sudocodectl assign jane@example.com "Go Fundamentals"
+//
+// Profiles in $HOME/.config/sudocodectl/config.yaml name the database of each
+// environment, see profiles.go. Without a profile the DB_* variables the
+// service reads are used. `sudocodectl completion bash` prints a bash
+// completion script, and likewise for zsh, fish and powershell
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
+	"github.com/MelvinKim/courses/infrastructure/database"
+	"github.com/MelvinKim/courses/infrastructure/events"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+	"github.com/MelvinKim/courses/usecase"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// app holds the global flags and the database connection, opened by the
+// first command that needs it
+type app struct {
+	configPath string
+	profile    string
+	output     string
+
+	db *database.PostgresDB
+}
+
+func newRootCommand() *cobra.Command {
+	a := &app{}
+	root := &cobra.Command{
+		Use:          "sudocodectl",
+		Short:        "Operate sudoCODE academy: students, courses, enrollments and the database",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return a.loadDefaults()
+		},
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", "", "config file (default $SUDOCODECTL_CONFIG or $HOME/.config/sudocodectl/config.yaml)")
+	flags.StringVarP(&a.profile, "profile", "p", "", "profile to use (default $SUDOCODECTL_PROFILE or the config's current_profile)")
+	flags.StringVarP(&a.output, "output", "o", "", "output format: table, json or yaml (default table)")
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = root.RegisterFlagCompletionFunc("profile", a.completeProfiles)
+
+	root.AddCommand(
+		a.studentsCommand(),
+		a.coursesCommand(),
+		a.assignCommand(),
+		a.unassignCommand(),
+		a.enrollmentsCommand(),
+		a.migrateCommand(),
+		a.seedCommand(),
+		a.exportCommand(),
+		a.profilesCommand(),
+	)
+	return root
+}
+
+// loadDefaults fills in the output format from the profile when it isn't
+// given on the command line
+func (a *app) loadDefaults() error {
+	profile, _, err := a.currentProfile()
+	if err != nil {
+		return err
+	}
+	if a.output == "" && profile != nil {
+		a.output = profile.Output
+	}
+	if a.output == "" {
+		a.output = outputTable
+	}
+	if !validOutput(a.output) {
+		return fmt.Errorf("unknown output format %q, use one of %v", a.output, outputFormats)
+	}
+	return nil
+}
+
+// database connects to the profile's database without migrating it
+func (a *app) database() (*database.PostgresDB, error) {
+	if a.db != nil {
+		return a.db, nil
+	}
+	config, err := a.databaseConfig()
+	if err != nil {
+		return nil, err
+	}
+	a.db = database.Open(config)
+	return a.db, nil
+}
+
+// usecase wires the courses usecase to the profile's database. Events are
+// logged rather than delivered, so no emails go out from the command line
+func (a *app) usecase() (*usecase.Usecase, error) {
+	db, err := a.database()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := certificates.NewEd25519SignerFromEnv("CERTIFICATE_SIGNING_KEY")
+	if err != nil {
+		return nil, err
+	}
+	return usecase.NewUsecase(
+		db, db, db, db,
+		events.NewLogPublisher(),
+		signer,
+		db,
+		fx.NewStaticRatesFromEnv(),
+		payments.NewLogProvider(),
+	), nil
+}
+
+// operator is who the command line acts as. Whoever can reach the database
+// can already do anything, so it acts as an admin
+var operator = &domain.Principal{Role: domain.RoleAdmin}
+
+// notFound is the error for a student or course that doesn't exist
+func notFound(kind, key string) error {
+	return fmt.Errorf("%s %q not found", kind, key)
+}
+
+// findStudent looks a student up by email
+func findStudent(cmd *cobra.Command, u *usecase.Usecase, email string) (*domain.Student, error) {
+	student, err := u.GetStudent(cmd.Context(), &email)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, notFound("student", email)
+	}
+	return student, nil
+}
+
+// findCourse looks a course up by title
+func findCourse(cmd *cobra.Command, u *usecase.Usecase, title string) (*domain.Course, error) {
+	course, err := u.GetCourse(cmd.Context(), &title)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, notFound("course", title)
+	}
+	return course, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+func validOutput(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// table is how a command's result is shown as a table
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// print writes v in the chosen output format. JSON and YAML show v with the
+// same field names as the REST API, tables show t
+func (a *app) print(w io.Writer, v interface{}, t table) error {
+	switch a.output {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case outputYAML:
+		// go through JSON so YAML keys match the JSON ones
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(generic); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+func studentTable(students []*domain.Student) table {
+	t := table{header: []string{"UUID", "FIRST NAME", "LAST NAME", "EMAIL", "CREATED"}}
+	for _, s := range students {
+		t.rows = append(t.rows, []string{s.UUID, s.FirstName, s.LastName, s.Email, timestamp(s.CreatedAt)})
+	}
+	return t
+}
+
+func courseTable(courses []*domain.Course) table {
+	t := table{header: []string{"UUID", "TITLE", "PRICE", "INSTRUCTOR", "CATEGORY", "CAPACITY", "RATING"}}
+	for _, c := range courses {
+		capacity := "unlimited"
+		if c.Capacity > 0 {
+			capacity = strconv.FormatUint(uint64(c.Capacity), 10)
+		}
+		t.rows = append(t.rows, []string{
+			c.UUID,
+			c.Title,
+			strconv.FormatUint(uint64(c.Price), 10),
+			c.Instructor,
+			c.Category,
+			capacity,
+			strconv.FormatFloat(c.AverageRating, 'f', 1, 64),
+		})
+	}
+	return t
+}
+
+func enrollmentTable(enrollments []*domain.StudentCourse) table {
+	t := table{header: []string{"STUDENT", "COURSE", "RUN", "STATUS", "ENROLLED", "COMPLETED"}}
+	for _, e := range enrollments {
+		t.rows = append(t.rows, []string{
+			e.StudentUUID,
+			e.CourseUUID,
+			e.RunUUID,
+			string(e.Status),
+			timestamp(e.EnrolledAt),
+			timestamp(e.CompletedAt),
+		})
+	}
+	return t
+}
+
+func profileTable(profiles []profileRow) table {
+	t := table{header: []string{"CURRENT", "NAME", "HOST", "DATABASE", "USER"}}
+	for _, p := range profiles {
+		current := ""
+		if p.Current {
+			current = "*"
+		}
+		t.rows = append(t.rows, []string{current, p.Name, p.Host, p.Database, p.User})
+	}
+	return t
+}
+
+// report tells what a command did: as a line of text under the table format
+// and as {"message": ...} otherwise
+func (a *app) report(w io.Writer, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if a.output == outputTable {
+		_, err := fmt.Fprintln(w, msg)
+		return err
+	}
+	return a.print(w, map[string]string{"message": msg}, table{})
+}
+
+func timestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
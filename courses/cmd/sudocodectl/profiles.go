@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/MelvinKim/courses/infrastructure/database"
+	"github.com/MelvinKim/platform/postgres"
+)
+
+// Config is sudocodectl's config file. It names a profile per environment,
+// e.g.
+//
+//	current_profile: local
+//	profiles:
+//	  local:
+//	    database: {host: localhost, port: "5432", user: postgres, name: sudocode}
+//	  prod:
+//	    database: {host: db.internal, user: ops, name: sudocode, sslmode: require}
+//	    password_env: SUDOCODE_PROD_DB_PASSWORD
+//	    output: json
+type Config struct {
+	CurrentProfile string              `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles"`
+}
+
+// Profile is the database of an environment and how to show its data. The
+// password is best kept out of the file by naming the variable to read it
+// from in PasswordEnv. Settings left out are those of the in-cluster database
+type Profile struct {
+	Database    ProfileDatabase `yaml:"database"`
+	PasswordEnv string          `yaml:"password_env,omitempty"`
+	Output      string          `yaml:"output,omitempty"`
+}
+
+// ProfileDatabase is the postgres connection settings of a profile
+type ProfileDatabase struct {
+	Host     string `yaml:"host,omitempty"`
+	Port     string `yaml:"port,omitempty"`
+	User     string `yaml:"user,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Name     string `yaml:"name,omitempty"`
+	SSLMode  string `yaml:"sslmode,omitempty"`
+	TimeZone string `yaml:"timezone,omitempty"`
+}
+
+// path is the config file to use
+func (a *app) path() (string, error) {
+	if a.configPath != "" {
+		return a.configPath, nil
+	}
+	if path := os.Getenv("SUDOCODECTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("can't find the config directory: %w", err)
+	}
+	return filepath.Join(dir, "sudocodectl", "config.yaml"), nil
+}
+
+// config reads the config file. A missing file is an empty config
+func (a *app) config() (*Config, error) {
+	path, err := a.path()
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("can't parse config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// saveConfig writes the config file, creating its directory when missing
+func (a *app) saveConfig(config *Config) error {
+	path, err := a.path()
+	if err != nil {
+		return err
+	}
+	var data bytes.Buffer
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return fmt.Errorf("can't encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("can't create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("can't write config: %w", err)
+	}
+	return nil
+}
+
+// currentProfile returns the profile picked by --profile, SUDOCODECTL_PROFILE
+// or the config's current profile, in that order, and its name. No profile is
+// picked when none of them is set
+func (a *app) currentProfile() (*Profile, string, error) {
+	config, err := a.config()
+	if err != nil {
+		return nil, "", err
+	}
+	name := a.profile
+	if name == "" {
+		name = os.Getenv("SUDOCODECTL_PROFILE")
+	}
+	if name == "" {
+		name = config.CurrentProfile
+	}
+	if name == "" {
+		return nil, "", nil
+	}
+	profile, ok := config.Profiles[name]
+	if !ok || profile == nil {
+		return nil, "", fmt.Errorf("profile %q is not in the config", name)
+	}
+	return profile, name, nil
+}
+
+// databaseConfig is the current profile's database on top of the in-cluster
+// one, or the database in DB_* when no profile is picked
+func (a *app) databaseConfig() (postgres.Config, error) {
+	profile, _, err := a.currentProfile()
+	if err != nil {
+		return postgres.Config{}, err
+	}
+	if profile == nil {
+		return postgres.ConfigFromEnv(database.DefaultConfig), nil
+	}
+	return profile.database(), nil
+}
+
+// database fills in the settings the profile leaves out
+func (p *Profile) database() postgres.Config {
+	config := database.DefaultConfig
+	for _, setting := range []struct {
+		from string
+		to   *string
+	}{
+		{p.Database.Host, &config.Host},
+		{p.Database.Port, &config.Port},
+		{p.Database.User, &config.User},
+		{p.Database.Password, &config.Password},
+		{p.Database.Name, &config.Name},
+		{p.Database.SSLMode, &config.SSLMode},
+		{p.Database.TimeZone, &config.TimeZone},
+	} {
+		if setting.from != "" {
+			*setting.to = setting.from
+		}
+	}
+	if p.PasswordEnv != "" {
+		config.Password = os.Getenv(p.PasswordEnv)
+	}
+	if config.SSLMode == "" {
+		config.SSLMode = "disable"
+	}
+	return config
+}
+
+// completeProfiles completes the names of the config's profiles
+func (a *app) completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config, err := a.config()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return profileNames(config), cobra.ShellCompDirectiveNoFileComp
+}
+
+// profileNames returns the config's profile names in order
+func profileNames(config *Config) []string {
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (a *app) profilesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "List and switch between environment profiles",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the profiles in the config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := a.config()
+			if err != nil {
+				return err
+			}
+			_, current, err := a.currentProfile()
+			if err != nil {
+				return err
+			}
+			rows := []profileRow{}
+			for _, name := range profileNames(config) {
+				database := config.Profiles[name].database()
+				rows = append(rows, profileRow{
+					Name:     name,
+					Current:  name == current,
+					Host:     database.Host,
+					Database: database.Name,
+					User:     database.User,
+				})
+			}
+			return a.print(cmd.OutOrStdout(), rows, profileTable(rows))
+		},
+	}, &cobra.Command{
+		Use:               "use NAME",
+		Short:             "Make a profile the current one",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := a.config()
+			if err != nil {
+				return err
+			}
+			if _, ok := config.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q is not in the config", args[0])
+			}
+			config.CurrentProfile = args[0]
+			if err := a.saveConfig(config); err != nil {
+				return err
+			}
+			return a.report(cmd.OutOrStdout(), "using profile %s", args[0])
+		},
+	})
+	return cmd
+}
+
+// profileRow is how a profile is listed. Passwords are never shown
+type profileRow struct {
+	Name     string `json:"name"`
+	Current  bool   `json:"current"`
+	Host     string `json:"host"`
+	Database string `json:"database"`
+	User     string `json:"user"`
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfile_database(t *testing.T) {
+	t.Setenv("SUDOCODE_TEST_DB_PASSWORD", "s3cret")
+	profile := &Profile{
+		Database:    ProfileDatabase{Host: "db.internal", SSLMode: "require"},
+		PasswordEnv: "SUDOCODE_TEST_DB_PASSWORD",
+	}
+
+	config := profile.database()
+	if config.Host != "db.internal" || config.SSLMode != "require" {
+		t.Errorf("expected the profile's settings to be used, got %+v", config)
+	}
+	if config.Port != "5432" || config.Name != "sudocode" {
+		t.Errorf("expected missing settings to be the in-cluster database's, got %+v", config)
+	}
+	if config.Password != "s3cret" {
+		t.Errorf("expected the password to be read from the environment, got %q", config.Password)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+current_profile: local
+profiles:
+  local:
+    database: {host: localhost}
+  prod:
+    database: {host: db.internal, password: hunter2}
+    output: json
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		var out bytes.Buffer
+		root := newRootCommand()
+		root.SetOut(&out)
+		root.SetArgs(append([]string{"--config", path}, args...))
+		if err := root.Execute(); err != nil {
+			t.Fatalf("sudocodectl %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	if out := run("profiles", "list"); currentRow(out) != "local" || strings.Contains(out, "hunter2") {
+		t.Errorf("expected local to be the current profile and no passwords shown, got\n%s", out)
+	}
+	run("profiles", "use", "prod")
+	if out := run("profiles", "list"); !strings.Contains(out, `"current": true`) || !strings.Contains(out, `"name": "prod"`) {
+		t.Errorf("expected prod to be current and to list as JSON, got\n%s", out)
+	}
+	if out := run("profiles", "list", "-o", "yaml"); !strings.Contains(out, "host: db.internal") {
+		t.Errorf("expected YAML output, got\n%s", out)
+	}
+}
+
+// currentRow returns the name of the profile marked current in a table
+func currentRow(table string) string {
+	for _, line := range strings.Split(table, "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && fields[0] == "*" {
+			return fields[1]
+		}
+	}
+	return ""
+}
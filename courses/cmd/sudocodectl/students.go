@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+func (a *app) studentsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "students",
+		Aliases: []string{"student"},
+		Short:   "Create, list and delete students",
+	}
+
+	student := domain.Student{}
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a student",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			created, err := u.CreateStudent(cmd.Context(), &student)
+			if err != nil {
+				return err
+			}
+			return a.print(cmd.OutOrStdout(), created, studentTable([]*domain.Student{created}))
+		},
+	}
+	create.Flags().StringVar(&student.FirstName, "first-name", "", "student's first name")
+	create.Flags().StringVar(&student.LastName, "last-name", "", "student's last name")
+	create.Flags().StringVar(&student.Email, "email", "", "student's email address")
+	for _, flag := range []string{"first-name", "last-name", "email"} {
+		_ = create.MarkFlagRequired(flag)
+	}
+
+	query := domain.StudentQuery{}
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List students in the order they signed up",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			students, err := u.ListStudents(cmd.Context(), &query)
+			if err != nil {
+				return err
+			}
+			return a.print(cmd.OutOrStdout(), students, studentTable(students))
+		},
+	}
+	list.Flags().IntVar(&query.Limit, "limit", 0, "students to list, at most 1000 (default 100)")
+	list.Flags().IntVar(&query.Offset, "offset", 0, "students to skip")
+
+	remove := &cobra.Command{
+		Use:   "delete EMAIL",
+		Short: "Delete a student who isn't enrolled in any course and has no running subscription",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := a.usecase()
+			if err != nil {
+				return err
+			}
+			s, err := findStudent(cmd, u, args[0])
+			if err != nil {
+				return err
+			}
+			if err := u.DeleteStudent(cmd.Context(), &s.UUID); err != nil {
+				return err
+			}
+			return a.report(cmd.OutOrStdout(), "student %s deleted", s.Email)
+		},
+	}
+
+	cmd.AddCommand(create, list, remove)
+	return cmd
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrUnauthenticated is returned when a request has no valid access token
+	ErrUnauthenticated = errors.New("missing or invalid access token")
+	// ErrForbidden is returned when the caller's role doesn't allow the action
+	ErrForbidden = errors.New("not allowed")
+)
+
+// Role is what a caller of the API is allowed to do
+type Role string
+
+const (
+	// RoleAdmin runs sudoCODE Academy and can do everything
+	RoleAdmin Role = "admin"
+	// RoleInstructor can see what they earn from their courses
+	RoleInstructor Role = "instructor"
+	// RoleStudent can manage their own calendar feed
+	RoleStudent Role = "student"
+)
+
+// Valid reports whether the role exists
+func (r Role) Valid() bool {
+	return r == RoleAdmin || r == RoleInstructor || r == RoleStudent
+}
+
+// Principal is who is calling the API, as vouched for by their access token.
+// Instructors' Subject is their instructor profile's UUID and students' their
+// student UUID
+type Principal struct {
+	Role      Role      `json:"role"`
+	Subject   string    `json:"sub,omitempty"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// HasRole reports whether the principal has one of the roles
+func (p *Principal) HasRole(roles ...Role) bool {
+	if p == nil {
+		return false
+	}
+	for _, role := range roles {
+		if p.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanActAsInstructor reports whether the principal may see an instructor's
+// earnings: admins see everyone's, instructors only their own
+func (p *Principal) CanActAsInstructor(instructorUUID string) bool {
+	if p == nil {
+		return false
+	}
+	return p.Role == RoleAdmin || (p.Role == RoleInstructor && p.Subject != "" && p.Subject == instructorUUID)
+}
+
+// CanActAsStudent reports whether the principal may manage a student's
+// account: admins manage everyone's, students only their own
+func (p *Principal) CanActAsStudent(studentUUID string) bool {
+	if p == nil {
+		return false
+	}
+	return p.Role == RoleAdmin || (p.Role == RoleStudent && p.Subject != "" && p.Subject == studentUUID)
+}
+
+// AccessToken is a signed access token handed out to a caller
+type AccessToken struct {
+	Token     string    `json:"token"`
+	Role      Role      `json:"role"`
+	Subject   string    `json:"sub,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
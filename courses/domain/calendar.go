@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidCalendarToken is returned when a calendar feed is requested with a
+// token that doesn't belong to the student
+var ErrInvalidCalendarToken = errors.New("invalid calendar token")
+
+// CalendarSubscription holds the secret that lets calendar clients fetch a
+// student's feed without auth headers. Rotating the token revokes every
+// existing subscription
+type CalendarSubscription struct {
+	StudentUUID string     `json:"student_uuid" gorm:"primaryKey"`
+	Token       string     `json:"token" gorm:"uniqueIndex;not null"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
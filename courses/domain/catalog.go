@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Category groups courses in the catalog. Categories nest under a parent and
+// are addressed by a unique slug
+type Category struct {
+	AbstractBase `gorm:"embedded"`
+	Name         string      `json:"name" gorm:"type:varchar(100);not null"`
+	Slug         string      `json:"slug" gorm:"type:varchar(100);uniqueIndex;not null"`
+	ParentUUID   *string     `json:"parent_uuid,omitempty" gorm:"index"`
+	Children     []*Category `json:"children,omitempty" gorm:"foreignKey:ParentUUID"`
+}
+
+// Instructor is the profile of someone teaching courses. Instructors who
+// have a sudoCODE account are linked to their user
+type Instructor struct {
+	AbstractBase `gorm:"embedded"`
+	Name         string  `json:"name" gorm:"type:varchar(255);not null"`
+	Bio          string  `json:"bio" gorm:"type:text"`
+	AvatarURL    string  `json:"avatar_url"`
+	UserUUID     *string `json:"user_uuid,omitempty" gorm:"uniqueIndex"`
+}
+
+// Slugify turns a name into a lower case, dash separated slug, so that
+// "GoLang" and " golang " share the slug "golang"
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Certificate proves that a student completed a course. The signature
+// covers the serial, student, course and issue date
+type Certificate struct {
+	AbstractBase     `gorm:"embedded"`
+	Serial           string     `json:"serial" gorm:"uniqueIndex;not null"`
+	StudentUUID      string     `json:"student_uuid" gorm:"uniqueIndex:idx_certificate_student_course;not null"`
+	CourseUUID       string     `json:"course_uuid" gorm:"uniqueIndex:idx_certificate_student_course;not null"`
+	StudentName      string     `json:"student_name" gorm:"type:varchar(255);not null"`
+	CourseTitle      string     `json:"course_title" gorm:"type:varchar(255);not null"`
+	IssuedAt         time.Time  `json:"issued_at" gorm:"not null"`
+	Signature        string     `json:"signature" gorm:"not null"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason string     `json:"revocation_reason,omitempty"`
+}
+
+// SignedContent is the canonical message that the certificate's signature covers
+func (c *Certificate) SignedContent() []byte {
+	return []byte(fmt.Sprintf(
+		"%s|%s|%s|%s",
+		c.Serial,
+		c.StudentUUID,
+		c.CourseUUID,
+		c.IssuedAt.UTC().Format(time.RFC3339),
+	))
+}
+
+// CertificateVerification is the public answer to whether a certificate is genuine
+type CertificateVerification struct {
+	Valid       bool         `json:"valid"`
+	Revoked     bool         `json:"revoked"`
+	Certificate *Certificate `json:"certificate,omitempty"`
+}
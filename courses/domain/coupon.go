@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrCouponRejected is returned when a coupon can't be used. The errors below
+// wrap it with the reason
+var ErrCouponRejected = errors.New("coupon can't be used")
+
+var (
+	ErrCouponNotFound     = fmt.Errorf("%w: it does not exist", ErrCouponRejected)
+	ErrCouponInactive     = fmt.Errorf("%w: it is not valid at this time", ErrCouponRejected)
+	ErrCouponNotApplied   = fmt.Errorf("%w: it does not apply to this course", ErrCouponRejected)
+	ErrCouponExhausted    = fmt.Errorf("%w: it has been fully redeemed", ErrCouponRejected)
+	ErrCouponLimitReached = fmt.Errorf("%w: the student has already used it", ErrCouponRejected)
+)
+
+// DiscountType is how a coupon takes money off a price
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+)
+
+// IsValid checks that the discount type is a known one
+func (t DiscountType) IsValid() bool {
+	switch t {
+	case DiscountTypePercentage, DiscountTypeFixed:
+		return true
+	}
+	return false
+}
+
+// NormalizeCouponCode upper cases a coupon code so codes aren't case
+// sensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Coupon is a discount code. It takes either a percentage or a fixed amount
+// off a course's price, and only applies to the listed courses and the
+// courses of the listed categories when there are any. Redemptions counts
+// how many times it has been used; a zero MaxRedemptions or PerStudentLimit
+// means there is no limit
+type Coupon struct {
+	AbstractBase    `gorm:"embedded"`
+	Code            string            `json:"code" gorm:"type:varchar(64);uniqueIndex;not null"`
+	DiscountType    DiscountType      `json:"discount_type" gorm:"type:varchar(20);not null"`
+	PercentOff      uint              `json:"percent_off,omitempty"`
+	AmountOff       Money             `json:"amount_off" gorm:"embedded;embeddedPrefix:amount_off_"`
+	ValidFrom       *time.Time        `json:"valid_from"`
+	ValidUntil      *time.Time        `json:"valid_until"`
+	MaxRedemptions  uint              `json:"max_redemptions"`
+	PerStudentLimit uint              `json:"per_student_limit"`
+	Redemptions     uint              `json:"redemptions" gorm:"not null;default:0"`
+	Courses         []*CouponCourse   `json:"courses,omitempty" gorm:"foreignKey:CouponUUID"`
+	Categories      []*CouponCategory `json:"categories,omitempty" gorm:"foreignKey:CouponUUID"`
+}
+
+// CouponCourse limits a coupon to a course
+type CouponCourse struct {
+	CouponUUID string `json:"coupon_uuid" gorm:"primaryKey"`
+	CourseUUID string `json:"course_uuid" gorm:"primaryKey"`
+}
+
+// CouponCategory limits a coupon to the courses of a category and its
+// subcategories
+type CouponCategory struct {
+	CouponUUID   string `json:"coupon_uuid" gorm:"primaryKey"`
+	CategoryUUID string `json:"category_uuid" gorm:"primaryKey"`
+}
+
+// IsValidAt checks that the coupon is active and within its validity window
+func (c *Coupon) IsValidAt(now time.Time) bool {
+	if !c.Active {
+		return false
+	}
+	if c.ValidFrom != nil && now.Before(*c.ValidFrom) {
+		return false
+	}
+	if c.ValidUntil != nil && !now.Before(*c.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// AppliesTo checks whether the coupon can be used on a course, given the
+// UUIDs of the course's category and its ancestors
+func (c *Coupon) AppliesTo(courseUUID string, categoryUUIDs []string) bool {
+	if len(c.Courses) == 0 && len(c.Categories) == 0 {
+		return true
+	}
+	for _, course := range c.Courses {
+		if course.CourseUUID == courseUUID {
+			return true
+		}
+	}
+	for _, category := range c.Categories {
+		for _, categoryUUID := range categoryUUIDs {
+			if category.CategoryUUID == categoryUUID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PercentageDiscount returns the coupon's percentage of a price, rounded half
+// up to the currency's minor unit
+func (c *Coupon) PercentageDiscount(price Money) Money {
+	return Money{
+		Amount:   (price.Amount*int64(c.PercentOff) + 50) / 100,
+		Currency: price.Currency,
+	}
+}
+
+// CouponQuote is the price of a course after a coupon's discount. The
+// discount never exceeds the price
+type CouponQuote struct {
+	Code          string `json:"code"`
+	CouponUUID    string `json:"coupon_uuid"`
+	CourseUUID    string `json:"course_uuid"`
+	OriginalPrice Money  `json:"original_price"`
+	Discount      Money  `json:"discount"`
+	FinalPrice    Money  `json:"final_price"`
+}
+
+// CouponRedemption records a coupon being used to enroll a student in a
+// course, and what they were charged
+type CouponRedemption struct {
+	AbstractBase  `gorm:"embedded"`
+	CouponUUID    string `json:"coupon_uuid" gorm:"index:idx_coupon_redemption_student;not null"`
+	StudentUUID   string `json:"student_uuid" gorm:"index:idx_coupon_redemption_student;not null"`
+	CourseUUID    string `json:"course_uuid" gorm:"index;not null"`
+	OriginalPrice Money  `json:"original_price" gorm:"embedded;embeddedPrefix:original_"`
+	Discount      Money  `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	FinalPrice    Money  `json:"final_price" gorm:"embedded;embeddedPrefix:final_"`
+}
+
+// CouponUsage summarizes how a coupon has been used: how many times, by how
+// many students, and the total discount given in each currency
+type CouponUsage struct {
+	CouponUUID     string  `json:"coupon_uuid"`
+	Code           string  `json:"code"`
+	Active         bool    `json:"active"`
+	Redemptions    uint    `json:"redemptions"`
+	MaxRedemptions uint    `json:"max_redemptions"`
+	Students       int64   `json:"students"`
+	Discounts      []Money `json:"discounts"`
+}
@@ -3,24 +3,11 @@ package domain
 import (
 	"time"
 
-	"github.com/google/uuid"
-	"gorm.io/gorm"
+	"github.com/MelvinKim/platform/entity"
 )
 
-// AbstractBase is an abstract struct that can be embedded in other structs
-type AbstractBase struct {
-	UUID      string `gorm:"primaryKey"`
-	Active    bool   `gorm:"default:true"`
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
-}
-
-// BeforeCreate ensures a UUID and createdAt data is inserted
-func (ab *AbstractBase) BeforeCreate(tx *gorm.DB) (err error) {
-	ab.UUID = uuid.New().String()
-	return
-}
+// AbstractBase is the UUID, active flag and timestamps every entity has
+type AbstractBase = entity.AbstractBase
 
 // Student ...
 type Student struct {
@@ -31,21 +18,152 @@ type Student struct {
 	Courses      []*Course `gorm:"many2many:student_courses"`
 }
 
-// Course ...
+// StudentQuery pages through students in the order they signed up, only
+// those who signed up from From and before To when they are set
+type StudentQuery struct {
+	From   *time.Time `json:"from"`
+	To     *time.Time `json:"to"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// Course is a course in the catalog. Instructor and Category hold the names
+// of the referenced instructor and category, kept alongside their UUIDs for
+// display and search. Price is the launch price in whole units of the base
+// currency, which starts the course's price list and is only kept for search
+// after that: the price list holds what the course sells at, in minor units.
+// LocalPrice is filled in when a course is shown in a requester's currency
 type Course struct {
+	AbstractBase      `gorm:"embedded"`
+	Title             string      `json:"title" `
+	Price             uint        `json:"price"`
+	Description       string      `json:"description"`
+	Instructor        string      `json:"instructor"`
+	InstructorUUID    *string     `json:"instructor_uuid" gorm:"index"`
+	InstructorProfile *Instructor `json:"instructor_profile,omitempty" gorm:"foreignKey:InstructorUUID"`
+	Category          string      `json:"category"`
+	CategoryUUID      *string     `json:"category_uuid" gorm:"index"`
+	CategoryDetails   *Category   `json:"category_details,omitempty" gorm:"foreignKey:CategoryUUID"`
+	LocalPrice        *Money      `json:"local_price,omitempty" gorm:"-"`
+	Capacity          uint        `json:"capacity"`
+	AverageRating     float64     `json:"average_rating" gorm:"index;not null;default:0"`
+	RatingCount       uint        `json:"rating_count" gorm:"not null;default:0"`
+	Students          []*Student  `gorm:"many2many:student_courses"`
+	Modules           []*Module   `json:"modules,omitempty" gorm:"foreignKey:CourseUUID"`
+}
+
+// CourseQuery pages through courses in the order they were created, only
+// those created from From and before To when they are set
+type CourseQuery struct {
+	From   *time.Time `json:"from"`
+	To     *time.Time `json:"to"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// LessonType is the kind of content a lesson delivers
+type LessonType string
+
+const (
+	LessonTypeVideo LessonType = "video"
+	LessonTypeText  LessonType = "text"
+	LessonTypeQuiz  LessonType = "quiz"
+)
+
+// IsValid checks that the lesson type is one that sudocode supports
+func (t LessonType) IsValid() bool {
+	switch t {
+	case LessonTypeVideo, LessonTypeText, LessonTypeQuiz:
+		return true
+	}
+	return false
+}
+
+// Module is an ordered section of a course's curriculum
+type Module struct {
 	AbstractBase `gorm:"embedded"`
-	Title        string     `json:"title" `
-	Price        uint       `json:"price"`
-	Description  string     `json:"description"`
-	Instructor   string     `json:"instructor"`
-	Category     string     `json:"category"`
-	Students     []*Student `gorm:"many2many:student_courses"`
+	CourseUUID   string    `json:"course_uuid" gorm:"index;not null"`
+	Title        string    `json:"title" gorm:"type:varchar(255);not null"`
+	Position     int       `json:"position" gorm:"not null"`
+	Lessons      []*Lesson `json:"lessons,omitempty" gorm:"foreignKey:ModuleUUID"`
+}
+
+// Lesson is an ordered piece of content within a module
+type Lesson struct {
+	AbstractBase    `gorm:"embedded"`
+	ModuleUUID      string     `json:"module_uuid" gorm:"index;not null"`
+	Title           string     `json:"title" gorm:"type:varchar(255);not null"`
+	Type            LessonType `json:"type" gorm:"type:varchar(20);not null"`
+	Position        int        `json:"position" gorm:"not null"`
+	DurationMinutes uint       `json:"duration_minutes"`
+	ContentURL      string     `json:"content_url,omitempty"`
+	Body            string     `json:"body,omitempty" gorm:"type:text"`
 }
 
+// EnrollmentStatus is the state of a student's enrollment in a course
+type EnrollmentStatus string
+
+const (
+	EnrollmentStatusActive    EnrollmentStatus = "active"
+	EnrollmentStatusCompleted EnrollmentStatus = "completed"
+)
+
 // StudentCourse ...
 type StudentCourse struct {
-	StudentUUID string `json:"student" gorm:"primaryKey"`
-	CourseUUID  string `json:"course" gorm:"primaryKey"`
+	StudentUUID string           `json:"student" gorm:"primaryKey"`
+	CourseUUID  string           `json:"course" gorm:"primaryKey"`
+	RunUUID     string           `json:"run" gorm:"index"`
+	Status      EnrollmentStatus `json:"status" gorm:"type:varchar(20);default:active"`
+	EnrolledAt  *time.Time       `json:"enrolled_at"`
+	CompletedAt *time.Time       `json:"completed_at"`
+}
+
+// EnrollmentQuery pages through enrollments in the order students enrolled,
+// only those made from From and before To when they are set
+type EnrollmentQuery struct {
+	From   *time.Time `json:"from"`
+	To     *time.Time `json:"to"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// Enrollment is a student's view of a course they are enrolled in
+type Enrollment struct {
+	Course               *Course          `json:"course"`
+	Run                  *CourseRun       `json:"run,omitempty"`
+	Status               EnrollmentStatus `json:"status"`
+	EnrolledAt           *time.Time       `json:"enrolled_at"`
+	CompletedAt          *time.Time       `json:"completed_at"`
+	CompletionPercentage float64          `json:"completion_percentage"`
+}
+
+// ProgressStatus is how far a student has got through a lesson
+type ProgressStatus string
+
+const (
+	ProgressStatusNotStarted ProgressStatus = "not_started"
+	ProgressStatusInProgress ProgressStatus = "in_progress"
+	ProgressStatusCompleted  ProgressStatus = "completed"
+)
+
+// IsValid checks that the progress status is a known one
+func (s ProgressStatus) IsValid() bool {
+	switch s {
+	case ProgressStatusNotStarted, ProgressStatusInProgress, ProgressStatusCompleted:
+		return true
+	}
+	return false
+}
+
+// LessonProgress tracks a student's progress through a single lesson
+type LessonProgress struct {
+	AbstractBase        `gorm:"embedded"`
+	StudentUUID         string         `json:"student_uuid" gorm:"uniqueIndex:idx_lesson_progress_student_lesson;not null"`
+	LessonUUID          string         `json:"lesson_uuid" gorm:"uniqueIndex:idx_lesson_progress_student_lesson;not null"`
+	Status              ProgressStatus `json:"status" gorm:"type:varchar(20);not null"`
+	StartedAt           *time.Time     `json:"started_at"`
+	CompletedAt         *time.Time     `json:"completed_at"`
+	LastPositionSeconds uint           `json:"last_position_seconds"`
 }
 
 // gorm:"uniqueIndex;not null"
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInUse is returned when something can't be deleted while other records
+// still depend on it
+var ErrInUse = errors.New("still in use")
+
+// NotFoundError is returned when something a request refers to does not exist
+type NotFoundError struct {
+	Kind string `json:"kind"`
+	Key  string `json:"key"`
+}
+
+// Error implements error
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s does not exist", e.Kind, e.Key)
+}
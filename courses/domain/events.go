@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Event is something that happened in the courses service that other parts
+// of sudocode may want to react to
+type Event interface {
+	EventName() string
+}
+
+// CourseCompleted is raised when a student completes every lesson of a course
+type CourseCompleted struct {
+	StudentUUID string    `json:"student_uuid"`
+	CourseUUID  string    `json:"course_uuid"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// EventName ...
+func (CourseCompleted) EventName() string {
+	return "course.completed"
+}
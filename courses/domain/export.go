@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// ExportFormat is the file format of a bulk export
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatNDJSON  ExportFormat = "ndjson"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+// ExportTable is a table that can be exported
+type ExportTable string
+
+const (
+	ExportTableStudents    ExportTable = "students"
+	ExportTableCourses     ExportTable = "courses"
+	ExportTableEnrollments ExportTable = "enrollments"
+)
+
+// ExportQuery picks what a bulk export holds: the table, the columns in the
+// order they are written, every column when Columns is empty, and the rows
+// created from From and before To when they are set. Enrollments are picked
+// by when students enrolled
+type ExportQuery struct {
+	Table   ExportTable  `json:"table"`
+	Format  ExportFormat `json:"format"`
+	Columns []string     `json:"columns"`
+	From    *time.Time   `json:"from"`
+	To      *time.Time   `json:"to"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ImportFormat is the file format of a bulk import
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// ImportStatus is where a bulk import is at
+type ImportStatus string
+
+const (
+	ImportStatusPending    ImportStatus = "pending"
+	ImportStatusProcessing ImportStatus = "processing"
+	ImportStatusCompleted  ImportStatus = "completed"
+)
+
+// Import is a file of students and the courses to enroll them in, e.g. from a
+// partner school. Its rows are stored when it is uploaded and worked through
+// in the background a batch at a time, so an import interrupted by a restart
+// carries on with the rows that are left. The worker holds the import until
+// LeasedUntil and renews the lease with every batch.
+// A dry run only checks every row, nothing is created
+type Import struct {
+	AbstractBase  `gorm:"embedded"`
+	Format        ImportFormat `json:"format" gorm:"type:varchar(10);not null"`
+	DryRun        bool         `json:"dry_run"`
+	Override      bool         `json:"override"`
+	PlanUUID      *string      `json:"plan_uuid,omitempty"`
+	Status        ImportStatus `json:"status" gorm:"type:varchar(20);index;not null"`
+	TotalRows     int          `json:"total_rows"`
+	ProcessedRows int          `json:"processed_rows"`
+	SucceededRows int          `json:"succeeded_rows"`
+	FailedRows    int          `json:"failed_rows"`
+	LeasedUntil   *time.Time   `json:"-"`
+	StartedAt     *time.Time   `json:"started_at"`
+	FinishedAt    *time.Time   `json:"finished_at"`
+}
+
+// ImportRowStatus is the outcome of a row of an import
+type ImportRowStatus string
+
+const (
+	ImportRowPending   ImportRowStatus = "pending"
+	ImportRowSucceeded ImportRowStatus = "succeeded"
+	ImportRowFailed    ImportRowStatus = "failed"
+)
+
+// ImportRow is a student and the titles of the courses to enroll them in.
+// Line is the row's line in the file, for finding failed rows in it, and
+// Message says what was done with the row or why it failed
+type ImportRow struct {
+	AbstractBase `gorm:"embedded"`
+	ImportUUID   string          `json:"-" gorm:"uniqueIndex:idx_import_row_line;not null"`
+	Line         int             `json:"line" gorm:"uniqueIndex:idx_import_row_line;not null"`
+	Email        string          `json:"email"`
+	FirstName    string          `json:"first_name"`
+	LastName     string          `json:"last_name"`
+	Courses      StringList      `json:"courses" gorm:"type:text"`
+	Status       ImportRowStatus `json:"status" gorm:"type:varchar(20);index;not null"`
+	Message      string          `json:"message,omitempty"`
+}
+
+// Normalize trims the row's fields and drops empty course titles
+func (r *ImportRow) Normalize() {
+	r.Email = strings.TrimSpace(r.Email)
+	r.FirstName = strings.TrimSpace(r.FirstName)
+	r.LastName = strings.TrimSpace(r.LastName)
+	courses := StringList{}
+	for _, title := range r.Courses {
+		if title = strings.TrimSpace(title); title != "" {
+			courses = append(courses, title)
+		}
+	}
+	r.Courses = courses
+}
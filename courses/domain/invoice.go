@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaxRate is the tax charged on payments in a currency, in basis points
+// (hundredths of a percent). Prices already include it
+type TaxRate struct {
+	AbstractBase `gorm:"embedded"`
+	Currency     string `json:"currency" gorm:"type:varchar(3);uniqueIndex;not null"`
+	Name         string `json:"name" gorm:"type:varchar(50);not null"`
+	BasisPoints  uint   `json:"basis_points" gorm:"not null"`
+}
+
+// IncludedIn returns the part of a tax inclusive amount that is tax, rounded
+// to the nearest minor unit
+func (t *TaxRate) IncludedIn(amount Money) Money {
+	divisor := int64(10000 + t.BasisPoints)
+	tax := (amount.Amount*int64(t.BasisPoints) + divisor/2) / divisor
+	return Money{Amount: tax, Currency: amount.Currency}
+}
+
+// Label describes the tax and its rate, e.g. "VAT 16%"
+func (t *TaxRate) Label() string {
+	if t.BasisPoints%100 == 0 {
+		return fmt.Sprintf("%s %d%%", t.Name, t.BasisPoints/100)
+	}
+	return fmt.Sprintf("%s %d.%02d%%", t.Name, t.BasisPoints/100, t.BasisPoints%100)
+}
+
+// InvoiceSequence hands out invoice numbers for a year. It is advanced in the
+// same transaction that creates the invoice, so numbers have no gaps
+type InvoiceSequence struct {
+	Year int  `gorm:"primaryKey;autoIncrement:false"`
+	Last uint `gorm:"not null"`
+}
+
+// Invoice is issued for every successful charge and doubles as its receipt.
+// Amounts are net of tax, Total is what the student was charged
+type Invoice struct {
+	AbstractBase `gorm:"embedded"`
+	Number       string            `json:"number" gorm:"type:varchar(20);uniqueIndex;not null"`
+	Year         int               `json:"year" gorm:"not null"`
+	Sequence     uint              `json:"sequence" gorm:"not null"`
+	StudentUUID  string            `json:"student_uuid" gorm:"index;not null"`
+	BilledTo     string            `json:"billed_to"`
+	BilledEmail  string            `json:"billed_email"`
+	ChargeUUID   string            `json:"charge_uuid" gorm:"uniqueIndex;not null"`
+	Reference    string            `json:"reference"`
+	IssuedAt     time.Time         `json:"issued_at"`
+	Subtotal     Money             `json:"subtotal" gorm:"embedded;embeddedPrefix:subtotal_"`
+	Tax          Money             `json:"tax" gorm:"embedded;embeddedPrefix:tax_"`
+	Total        Money             `json:"total" gorm:"embedded;embeddedPrefix:total_"`
+	Lines        []*InvoiceLine    `json:"lines" gorm:"foreignKey:InvoiceUUID"`
+	TaxLines     []*InvoiceTaxLine `json:"tax_lines" gorm:"foreignKey:InvoiceUUID"`
+}
+
+// InvoiceNumber formats an invoice's number, e.g. "INV-2023-000042"
+func InvoiceNumber(year int, sequence uint) string {
+	return fmt.Sprintf("INV-%d-%06d", year, sequence)
+}
+
+// InvoiceLine is something the student paid for
+type InvoiceLine struct {
+	AbstractBase `gorm:"embedded"`
+	InvoiceUUID  string `json:"-" gorm:"index;not null"`
+	Position     uint   `json:"position"`
+	Description  string `json:"description"`
+	Quantity     uint   `json:"quantity"`
+	UnitPrice    Money  `json:"unit_price" gorm:"embedded;embeddedPrefix:unit_price_"`
+	Amount       Money  `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+}
+
+// InvoiceTaxLine is a tax included in an invoice's total
+type InvoiceTaxLine struct {
+	AbstractBase `gorm:"embedded"`
+	InvoiceUUID  string `json:"-" gorm:"index;not null"`
+	Label        string `json:"label"`
+	BasisPoints  uint   `json:"basis_points"`
+	Amount       Money  `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+}
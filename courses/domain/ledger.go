@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// Ledger accounts. Assets and expenses carry debit balances, revenue and
+// liabilities credit balances. Refunds and coupon discounts are revenue given
+// back so they carry a debit balance
+const (
+	LedgerAccountCash                = "assets:cash"
+	LedgerAccountSubscriptionRevenue = "revenue:subscriptions"
+	LedgerAccountRefunds             = "revenue:refunds"
+	LedgerAccountCourseRevenue       = "revenue:courses"
+	LedgerAccountCouponDiscounts     = "revenue:coupon_discounts"
+	LedgerAccountInstructorShare     = "expenses:instructor_share"
+	LedgerAccountInstructorPayable   = "liabilities:instructor_payouts"
+)
+
+// LedgerEntry is one side of a double-entry journal. Debits are positive and
+// credits negative, so the entries of a journal sum to zero in each currency.
+// Entries are never edited, mistakes are corrected by posting a new journal
+type LedgerEntry struct {
+	AbstractBase `gorm:"embedded"`
+	JournalUUID  string    `json:"journal_uuid" gorm:"index;not null"`
+	Account      string    `json:"account" gorm:"type:varchar(100);index;not null"`
+	Amount       Money     `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	Description  string    `json:"description"`
+	ChargeUUID   string    `json:"charge_uuid,omitempty" gorm:"index"`
+	RefundUUID   string    `json:"refund_uuid,omitempty" gorm:"index"`
+	SaleUUID     string    `json:"sale_uuid,omitempty" gorm:"index"`
+	CourseUUID   string    `json:"course_uuid,omitempty" gorm:"index"`
+	PayoutUUID   string    `json:"payout_uuid,omitempty" gorm:"index"`
+	PostedAt     time.Time `json:"posted_at" gorm:"index;not null"`
+}
+
+// LedgerQuery narrows down the ledger entries to list. Empty fields match
+// every entry
+type LedgerQuery struct {
+	Account    string     `json:"account,omitempty"`
+	CourseUUID string     `json:"course_uuid,omitempty"`
+	ChargeUUID string     `json:"charge_uuid,omitempty"`
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+	Limit      int        `json:"limit"`
+	Offset     int        `json:"offset"`
+}
+
+// LedgerBalance is the balance of an account in a currency
+type LedgerBalance struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+}
+
+// Balanced reports whether journal entries sum to zero in every currency
+func Balanced(entries []*LedgerEntry) bool {
+	totals := map[string]int64{}
+	for _, entry := range entries {
+		totals[entry.Amount.Currency] += entry.Amount.Amount
+	}
+	for _, total := range totals {
+		if total != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// transfer is a journal moving an amount from one account to another
+func transfer(debit string, credit string, amount Money, description string, postedAt time.Time) []*LedgerEntry {
+	return []*LedgerEntry{
+		{Account: debit, Amount: amount, Description: description, PostedAt: postedAt},
+		{Account: credit, Amount: Money{Amount: -amount.Amount, Currency: amount.Currency}, Description: description, PostedAt: postedAt},
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BaseCurrency is the currency courses are priced in unless a price list
+// says otherwise
+const BaseCurrency = "USD"
+
+// ErrUnsupportedCurrency is returned for currencies sudocode can't price in
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// ErrCourseNotPriced is returned for a course that isn't listed in any
+// currency, so there's nothing to charge or convert
+var ErrCourseNotPriced = errors.New("course has no price")
+
+// currencyExponents are the number of minor unit digits of each supported
+// ISO-4217 currency
+var currencyExponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"CAD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KES": 2,
+	"KWD": 3,
+	"NGN": 2,
+	"RWF": 0,
+	"TZS": 2,
+	"UGX": 0,
+	"USD": 2,
+	"ZAR": 2,
+}
+
+// CurrencyExponent returns the number of minor unit digits of a currency
+func CurrencyExponent(currency string) (int, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+	return exponent, nil
+}
+
+// NormalizeCurrency upper cases a currency code and checks it is supported
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if _, err := CurrencyExponent(currency); err != nil {
+		return "", err
+	}
+	return currency, nil
+}
+
+// Money is an amount in the minor units of its currency, e.g. cents for USD,
+// so that prices never suffer from floating point rounding
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency" gorm:"type:varchar(3)"`
+}
+
+// MoneyFromMajor converts whole units of a currency into Money
+func MoneyFromMajor(units uint, currency string) (Money, error) {
+	exponent, err := CurrencyExponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	amount := int64(units)
+	for i := 0; i < exponent; i++ {
+		amount *= 10
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// String formats the amount in major units, e.g. "USD 12.50"
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Currency, m.Major())
+}
+
+// Major formats the amount in major units without the currency, e.g. "12.50"
+func (m Money) Major() string {
+	exponent, err := CurrencyExponent(m.Currency)
+	if err != nil || exponent == 0 {
+		return fmt.Sprintf("%d", m.Amount)
+	}
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	divisor := int64(1)
+	for i := 0; i < exponent; i++ {
+		divisor *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/divisor, exponent, amount%divisor)
+}
+
+// CoursePrice is an entry of a course's price list. Prices are never edited:
+// a new entry takes over from its effective date, which keeps the history
+type CoursePrice struct {
+	AbstractBase  `gorm:"embedded"`
+	CourseUUID    string    `json:"course_uuid" gorm:"index:idx_course_price_lookup;not null"`
+	Price         Money     `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	EffectiveFrom time.Time `json:"effective_from" gorm:"index:idx_course_price_lookup;not null"`
+}
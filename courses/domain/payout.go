@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPayoutAlreadyPaid is returned when a payout statement is marked as paid
+// twice
+var ErrPayoutAlreadyPaid = errors.New("payout statement is already paid")
+
+// RevenueShare is an instructor's cut of the sales of their courses, in basis
+// points (hundredths of a percent), from a point in time. An agreement for a
+// course overrides the instructor's agreement for all their courses
+type RevenueShare struct {
+	AbstractBase   `gorm:"embedded"`
+	InstructorUUID string    `json:"instructor_uuid" gorm:"index:idx_revenue_share_lookup;not null"`
+	CourseUUID     *string   `json:"course_uuid,omitempty" gorm:"index:idx_revenue_share_lookup"`
+	BasisPoints    uint      `json:"basis_points" gorm:"not null"`
+	EffectiveFrom  time.Time `json:"effective_from" gorm:"index:idx_revenue_share_lookup;not null"`
+}
+
+// Of returns the instructor's cut of an amount, rounded down to the minor unit
+func (r *RevenueShare) Of(amount Money) Money {
+	return Money{Amount: amount.Amount * int64(r.BasisPoints) / 10000, Currency: amount.Currency}
+}
+
+// ApplicableShare picks the agreement covering a sale of a course at a point
+// in time: the latest one for the course, else the latest one for all the
+// instructor's courses. It returns nil when no agreement covers the sale
+func ApplicableShare(shares []*RevenueShare, courseUUID string, at time.Time) *RevenueShare {
+	var course, general *RevenueShare
+	for _, share := range shares {
+		if share.EffectiveFrom.After(at) {
+			continue
+		}
+		if share.CourseUUID == nil || *share.CourseUUID == "" {
+			if general == nil || share.EffectiveFrom.After(general.EffectiveFrom) {
+				general = share
+			}
+			continue
+		}
+		if *share.CourseUUID == courseUUID && (course == nil || share.EffectiveFrom.After(course.EffectiveFrom)) {
+			course = share
+		}
+	}
+	if course != nil {
+		return course
+	}
+	return general
+}
+
+// PayoutStatus is where a payout statement is at
+type PayoutStatus string
+
+const (
+	PayoutStatusPending PayoutStatus = "pending"
+	PayoutStatusPaid    PayoutStatus = "paid"
+)
+
+// PayoutStatement is what an instructor earned from their course sales in a
+// month and currency. Every sale is only ever on one statement
+type PayoutStatement struct {
+	AbstractBase     `gorm:"embedded"`
+	InstructorUUID   string        `json:"instructor_uuid" gorm:"index;not null"`
+	InstructorName   string        `json:"instructor_name"`
+	Month            string        `json:"month" gorm:"type:varchar(7);index;not null"`
+	Sales            Money         `json:"sales" gorm:"embedded;embeddedPrefix:sales_"`
+	Earnings         Money         `json:"earnings" gorm:"embedded;embeddedPrefix:earnings_"`
+	Status           PayoutStatus  `json:"status" gorm:"type:varchar(20);not null"`
+	PaidAt           *time.Time    `json:"paid_at,omitempty"`
+	PaymentReference string        `json:"payment_reference,omitempty"`
+	Lines            []*PayoutLine `json:"lines,omitempty" gorm:"foreignKey:StatementUUID"`
+}
+
+// Journal is the ledger entries of a statement: the instructor's share of the
+// sales is an expense owed to them until it is paid
+func (s *PayoutStatement) Journal(postedAt time.Time) []*LedgerEntry {
+	return s.tag(transfer(LedgerAccountInstructorShare, LedgerAccountInstructorPayable, s.Earnings, fmt.Sprintf("Instructor share %s", s.Month), postedAt))
+}
+
+// PaymentJournal is the ledger entries of paying a statement out
+func (s *PayoutStatement) PaymentJournal(postedAt time.Time) []*LedgerEntry {
+	return s.tag(transfer(LedgerAccountInstructorPayable, LedgerAccountCash, s.Earnings, fmt.Sprintf("Instructor payout %s", s.Month), postedAt))
+}
+
+func (s *PayoutStatement) tag(entries []*LedgerEntry) []*LedgerEntry {
+	for _, entry := range entries {
+		entry.PayoutUUID = s.UUID
+	}
+	return entries
+}
+
+// PayoutLine is a course sale on a payout statement and the instructor's cut
+// of it. Net is what the sale earned in the ledger, after coupon discounts
+type PayoutLine struct {
+	AbstractBase   `gorm:"embedded"`
+	StatementUUID  string    `json:"-" gorm:"index;not null"`
+	SaleUUID       string    `json:"sale_uuid" gorm:"uniqueIndex;not null"`
+	InstructorUUID string    `json:"-" gorm:"-"`
+	CourseUUID     string    `json:"course_uuid" gorm:"index;not null"`
+	CourseTitle    string    `json:"course_title"`
+	SoldAt         time.Time `json:"sold_at"`
+	Net            Money     `json:"net" gorm:"embedded;embeddedPrefix:net_"`
+	BasisPoints    uint      `json:"basis_points"`
+	Amount         Money     `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+}
+
+// EarningsTotal is what an instructor has earned in a currency and how much
+// of it has been paid out
+type EarningsTotal struct {
+	Currency    string `json:"currency"`
+	Earned      Money  `json:"earned"`
+	Paid        Money  `json:"paid"`
+	Outstanding Money  `json:"outstanding"`
+}
+
+// InstructorEarnings is an instructor's payout statements and their totals
+type InstructorEarnings struct {
+	InstructorUUID string             `json:"instructor_uuid"`
+	Totals         []*EarningsTotal   `json:"totals"`
+	Statements     []*PayoutStatement `json:"statements"`
+}
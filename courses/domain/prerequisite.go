@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPrerequisiteCycle is returned when a prerequisite would make a course
+// (indirectly) require itself
+var ErrPrerequisiteCycle = errors.New("prerequisite would create a cycle")
+
+// CoursePrerequisite is an edge of the prerequisite graph: the course can only
+// be taken once the prerequisite course has been completed
+type CoursePrerequisite struct {
+	CourseUUID       string `json:"course_uuid" gorm:"primaryKey"`
+	PrerequisiteUUID string `json:"prerequisite_uuid" gorm:"primaryKey;index"`
+}
+
+// PrerequisiteTree is a course together with everything that must be
+// completed before it, recursively
+type PrerequisiteTree struct {
+	Course        *Course             `json:"course"`
+	Prerequisites []*PrerequisiteTree `json:"prerequisites"`
+}
+
+// MissingPrerequisitesError is returned when a student is assigned a course
+// without having completed all of its prerequisites
+type MissingPrerequisitesError struct {
+	CourseTitle string    `json:"course_title"`
+	Missing     []*Course `json:"missing_prerequisites"`
+}
+
+// Error implements error
+func (e *MissingPrerequisitesError) Error() string {
+	titles := make([]string, 0, len(e.Missing))
+	for _, course := range e.Missing {
+		titles = append(titles, course.Title)
+	}
+	return fmt.Sprintf(
+		"course %s requires completing %s first",
+		e.CourseTitle,
+		strings.Join(titles, ", "),
+	)
+}
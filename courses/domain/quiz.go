@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoAttemptsLeft is returned when a student has used all their attempts at
+// a quiz
+var ErrNoAttemptsLeft = errors.New("student has used all their attempts at this quiz")
+
+// QuestionType is the way a question is answered and graded
+type QuestionType string
+
+const (
+	QuestionTypeSingleChoice   QuestionType = "single_choice"
+	QuestionTypeMultipleChoice QuestionType = "multiple_choice"
+	QuestionTypeShortText      QuestionType = "short_text"
+	QuestionTypeCodeOutput     QuestionType = "code_output"
+)
+
+// IsValid checks that the question type is one that can be auto-graded
+func (t QuestionType) IsValid() bool {
+	switch t {
+	case QuestionTypeSingleChoice, QuestionTypeMultipleChoice, QuestionTypeShortText, QuestionTypeCodeOutput:
+		return true
+	}
+	return false
+}
+
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// Answers maps a question's UUID to the answers given for it
+type Answers map[string][]string
+
+// Value implements driver.Valuer
+func (a Answers) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(a)
+	return string(b), err
+}
+
+// Scan implements sql.Scanner
+func (a *Answers) Scan(value interface{}) error {
+	return scanJSON(value, a)
+}
+
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return fmt.Errorf("can't scan %T into %T", value, dest)
+	}
+}
+
+// Quiz is an auto-graded checkpoint attached to a quiz lesson
+type Quiz struct {
+	AbstractBase  `gorm:"embedded"`
+	LessonUUID    string      `json:"lesson_uuid" gorm:"uniqueIndex;not null"`
+	Title         string      `json:"title" gorm:"type:varchar(255);not null"`
+	PassThreshold float64     `json:"pass_threshold" gorm:"not null"`
+	MaxAttempts   uint        `json:"max_attempts"`
+	Questions     []*Question `json:"questions,omitempty" gorm:"foreignKey:QuizUUID"`
+}
+
+// Question is a single question of a quiz. The correct answers are never
+// serialized so that quizzes can be handed to students as they are
+type Question struct {
+	AbstractBase   `gorm:"embedded"`
+	QuizUUID       string       `json:"quiz_uuid" gorm:"index;not null"`
+	Position       int          `json:"position" gorm:"not null"`
+	Type           QuestionType `json:"type" gorm:"type:varchar(20);not null"`
+	Prompt         string       `json:"prompt" gorm:"type:text;not null"`
+	Options        StringList   `json:"options,omitempty" gorm:"type:text"`
+	CorrectAnswers StringList   `json:"-" gorm:"type:text;not null"`
+	Points         uint         `json:"points" gorm:"not null;default:1"`
+}
+
+// Attempt is a graded submission of a quiz by a student
+type Attempt struct {
+	AbstractBase `gorm:"embedded"`
+	QuizUUID     string    `json:"quiz_uuid" gorm:"index:idx_attempt_quiz_student;not null"`
+	StudentUUID  string    `json:"student_uuid" gorm:"index:idx_attempt_quiz_student;not null"`
+	Answers      Answers   `json:"answers" gorm:"type:text"`
+	Score        float64   `json:"score"`
+	Passed       bool      `json:"passed"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+}
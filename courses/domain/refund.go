@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrRefundExceedsCharge is returned when a refund is for more than what
+	// is left of its charge after earlier refunds
+	ErrRefundExceedsCharge = errors.New("refund is more than what is left of the charge")
+	// ErrCoolingOffOver is returned when a student asks for a refund of a
+	// course they enrolled in longer ago than the cooling-off period
+	ErrCoolingOffOver = errors.New("the cooling-off period is over")
+)
+
+// RefundPolicy is how refunds of dropped courses are handled. Students can
+// drop a course for a refund within CoolingOffDays of enrolling, and are
+// unenrolled from it when the refund goes through if Unenroll is set
+type RefundPolicy struct {
+	CoolingOffDays uint
+	Unenroll       bool
+}
+
+// DefaultRefundPolicy gives students two weeks to drop a course and leaves
+// their enrollment alone
+func DefaultRefundPolicy() RefundPolicy {
+	return RefundPolicy{CoolingOffDays: 14}
+}
+
+// RefundStatus is where a refund is at with the payment provider
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// Refund gives back all or part of a subscription charge. CourseUUID is set
+// when the refund is for dropping a course, which is paid for through the
+// subscription rather than on its own
+type Refund struct {
+	AbstractBase  `gorm:"embedded"`
+	ChargeUUID    string       `json:"charge_uuid" gorm:"index;not null"`
+	StudentUUID   string       `json:"student_uuid" gorm:"index;not null"`
+	CourseUUID    string       `json:"course_uuid,omitempty"`
+	Amount        Money        `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	Reason        string       `json:"reason"`
+	Status        RefundStatus `json:"status" gorm:"type:varchar(20);index;not null"`
+	Reference     string       `json:"reference,omitempty"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	Unenrolled    bool         `json:"unenrolled"`
+}
+
+// Journal is the ledger entries for a refund that went through: the money
+// leaves cash and is taken off revenue
+func (r *Refund) Journal(postedAt time.Time) []*LedgerEntry {
+	entries := transfer(LedgerAccountRefunds, LedgerAccountCash, r.Amount, "Refund: "+r.Reason, postedAt)
+	for _, entry := range entries {
+		entry.ChargeUUID = r.ChargeUUID
+		entry.RefundUUID = r.UUID
+	}
+	return entries
+}
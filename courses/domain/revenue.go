@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// CourseSale records a student enrolling in a course. Students don't pay
+// per course, their subscription covers it, so each enrollment is allocated
+// the course's list price at the time less the coupon used at checkout. A
+// sale is reversed when the student unenrolls or is refunded for the course
+type CourseSale struct {
+	AbstractBase         `gorm:"embedded"`
+	StudentUUID          string     `json:"student_uuid" gorm:"index;not null"`
+	CourseUUID           string     `json:"course_uuid" gorm:"index;not null"`
+	CategoryUUID         string     `json:"category_uuid" gorm:"index"`
+	InstructorUUID       string     `json:"instructor_uuid" gorm:"index"`
+	ListPrice            Money      `json:"list_price" gorm:"embedded;embeddedPrefix:list_price_"`
+	Discount             Money      `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+	CouponRedemptionUUID string     `json:"coupon_redemption_uuid,omitempty"`
+	SoldAt               time.Time  `json:"sold_at" gorm:"index;not null"`
+	ReversedAt           *time.Time `json:"reversed_at,omitempty" gorm:"index"`
+}
+
+// Net is what the sale is worth after its discount
+func (s *CourseSale) Net() Money {
+	return Money{Amount: s.ListPrice.Amount - s.Discount.Amount, Currency: s.ListPrice.Currency}
+}
+
+// Journal is the ledger entries allocating subscription revenue to a sale:
+// the course earns its list price, less the coupon discount given
+func (s *CourseSale) Journal() []*LedgerEntry {
+	description := "Course sale"
+	entries := []*LedgerEntry{
+		{Account: LedgerAccountCourseRevenue, Amount: Money{Amount: -s.ListPrice.Amount, Currency: s.ListPrice.Currency}},
+		{Account: LedgerAccountSubscriptionRevenue, Amount: s.Net()},
+	}
+	if s.Discount.Amount != 0 {
+		description = "Course sale with coupon"
+		entries = append(entries, &LedgerEntry{Account: LedgerAccountCouponDiscounts, Amount: s.Discount})
+	}
+	for _, entry := range entries {
+		entry.Description = description
+		entry.SaleUUID = s.UUID
+		entry.CourseUUID = s.CourseUUID
+		entry.PostedAt = s.SoldAt
+	}
+	return entries
+}
+
+// ReversalJournal is the ledger entries taking a sale's revenue back: its
+// journal with every amount negated
+func (s *CourseSale) ReversalJournal(postedAt time.Time) []*LedgerEntry {
+	entries := s.Journal()
+	for _, entry := range entries {
+		entry.Amount.Amount = -entry.Amount.Amount
+		entry.Description = "Course sale reversed"
+		entry.PostedAt = postedAt
+	}
+	return entries
+}
+
+// RevenueGroup is what the revenue report is broken down by, besides month
+type RevenueGroup string
+
+const (
+	RevenueGroupCourse     RevenueGroup = "course"
+	RevenueGroupCategory   RevenueGroup = "category"
+	RevenueGroupInstructor RevenueGroup = "instructor"
+)
+
+// Valid reports whether the revenue report can be grouped this way
+func (g RevenueGroup) Valid() bool {
+	switch g {
+	case RevenueGroupCourse, RevenueGroupCategory, RevenueGroupInstructor:
+		return true
+	}
+	return false
+}
+
+// RevenueQuery is the period and grouping of a revenue report
+type RevenueQuery struct {
+	GroupBy RevenueGroup `json:"group_by"`
+	From    *time.Time   `json:"from,omitempty"`
+	To      *time.Time   `json:"to,omitempty"`
+}
+
+// RevenueReportRow is the course revenue of a group's sales in a month, from
+// the ledger. Gross, Discounts and Net are after the sales reversed since
+type RevenueReportRow struct {
+	Month     string `json:"month"`
+	GroupUUID string `json:"group_uuid"`
+	GroupName string `json:"group_name"`
+	Sales     int64  `json:"sales"`
+	Reversals int64  `json:"reversals"`
+	Gross     Money  `json:"gross"`
+	Discounts Money  `json:"discounts"`
+	Net       Money  `json:"net"`
+}
+
+// RevenueReconciliation compares the cash the ledger holds for a month's
+// subscription charges and refunds with what the payment provider reported
+// charging and refunding. Charges and refunds count in the month they were
+// made; Difference is non zero when journals are missing or posted twice
+type RevenueReconciliation struct {
+	Month      string `json:"month"`
+	Charged    Money  `json:"charged"`
+	Refunded   Money  `json:"refunded"`
+	Collected  Money  `json:"collected"`
+	LedgerCash Money  `json:"ledger_cash"`
+	Difference Money  `json:"difference"`
+}
+
+// Reconciled reports whether the ledger agrees with the payment provider
+func (r *RevenueReconciliation) Reconciled() bool {
+	return r.Difference.Amount == 0
+}
+
+// RevenueMonth formats the month a report row covers, e.g. "2023-05"
+func RevenueMonth(t time.Time) string {
+	return fmt.Sprintf("%04d-%02d", t.Year(), t.Month())
+}
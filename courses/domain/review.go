@@ -0,0 +1,56 @@
+package domain
+
+import "errors"
+
+// ErrReviewerNotEnrolled is returned when a student reviews a course they are
+// not enrolled in
+var ErrReviewerNotEnrolled = errors.New("only students enrolled in a course can review it")
+
+// ReviewStatus is where a review is in moderation
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+// IsValid checks that the review status is one that sudocode supports
+func (s ReviewStatus) IsValid() bool {
+	switch s {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected:
+		return true
+	}
+	return false
+}
+
+// Review is a student's rating of a course they are enrolled in. A student
+// has at most one review per course, editing it sends it back to moderation.
+// Only approved reviews are shown and count towards the course's rating
+type Review struct {
+	AbstractBase `gorm:"embedded"`
+	CourseUUID   string       `json:"course_uuid" gorm:"uniqueIndex:idx_review_course_student;not null"`
+	StudentUUID  string       `json:"student_uuid" gorm:"uniqueIndex:idx_review_course_student;not null"`
+	Rating       int          `json:"rating" gorm:"not null"`
+	Text         string       `json:"text" gorm:"type:text"`
+	Status       ReviewStatus `json:"status" gorm:"type:varchar(20);index;default:pending"`
+}
+
+// CourseSort is the order courses are listed in
+type CourseSort string
+
+const (
+	CourseSortTitle  CourseSort = "title"
+	CourseSortNewest CourseSort = "newest"
+	CourseSortPrice  CourseSort = "price"
+	CourseSortRating CourseSort = "rating"
+)
+
+// IsValid checks that the sort key is one that sudocode supports
+func (s CourseSort) IsValid() bool {
+	switch s {
+	case CourseSortTitle, CourseSortNewest, CourseSortPrice, CourseSortRating:
+		return true
+	}
+	return false
+}
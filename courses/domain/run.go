@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// CourseRun is a scheduled offering of a course, also known as a cohort.
+// Students enroll into a run rather than the bare course. Evergreen runs have
+// no dates and hold self-paced enrollments
+type CourseRun struct {
+	AbstractBase `gorm:"embedded"`
+	CourseUUID   string         `json:"course_uuid" gorm:"index;not null"`
+	Name         string         `json:"name" gorm:"type:varchar(255);not null"`
+	StartDate    *time.Time     `json:"start_date,omitempty"`
+	EndDate      *time.Time     `json:"end_date,omitempty"`
+	Timezone     string         `json:"timezone" gorm:"type:varchar(64);not null;default:UTC"`
+	Instructor   string         `json:"instructor,omitempty" gorm:"type:varchar(255)"`
+	Evergreen    bool           `json:"evergreen" gorm:"not null;default:false"`
+	Sessions     []*LiveSession `json:"sessions,omitempty" gorm:"foreignKey:RunUUID"`
+}
+
+// HasEnded reports whether the run's end date has passed
+func (r *CourseRun) HasEnded(now time.Time) bool {
+	return r.EndDate != nil && r.EndDate.Before(now)
+}
+
+// LiveSession is a scheduled live class of a course run. StartsAt is stored
+// in UTC and presented in the run's timezone
+type LiveSession struct {
+	AbstractBase    `gorm:"embedded"`
+	RunUUID         string    `json:"run_uuid" gorm:"index;not null"`
+	Title           string    `json:"title" gorm:"type:varchar(255);not null"`
+	StartsAt        time.Time `json:"starts_at" gorm:"not null"`
+	DurationMinutes uint      `json:"duration_minutes" gorm:"not null"`
+}
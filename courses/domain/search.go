@@ -0,0 +1,81 @@
+package domain
+
+// PriceBand is a range of course prices used to facet search results. Min is
+// inclusive and Max exclusive, a zero Max has no upper bound
+type PriceBand struct {
+	Name string `json:"name"`
+	Min  uint   `json:"min"`
+	Max  uint   `json:"max"`
+}
+
+// Contains reports whether the price falls in the band
+func (b PriceBand) Contains(price uint) bool {
+	return price >= b.Min && (b.Max == 0 || price < b.Max)
+}
+
+// PriceBands are the bands courses are grouped into, cheapest first
+var PriceBands = []PriceBand{
+	{Name: "free", Min: 0, Max: 1},
+	{Name: "under_50", Min: 1, Max: 50},
+	{Name: "50_to_100", Min: 50, Max: 100},
+	{Name: "over_100", Min: 100},
+}
+
+// PriceBandOf returns the name of the band the price falls in
+func PriceBandOf(price uint) string {
+	for _, band := range PriceBands {
+		if band.Contains(price) {
+			return band.Name
+		}
+	}
+	return ""
+}
+
+// LookupPriceBand returns the band with the given name
+func LookupPriceBand(name string) (PriceBand, bool) {
+	for _, band := range PriceBands {
+		if band.Name == name {
+			return band, true
+		}
+	}
+	return PriceBand{}, false
+}
+
+// CourseSearchQuery is a free text course search, optionally narrowed down
+// to a category, an instructor and a price band
+type CourseSearchQuery struct {
+	Text       string `json:"q"`
+	Category   string `json:"category,omitempty"`
+	Instructor string `json:"instructor,omitempty"`
+	PriceBand  string `json:"price_band,omitempty"`
+	Limit      int    `json:"limit"`
+}
+
+// CourseSearchHit is a course matching a search with its relevance, higher
+// ranks match better
+type CourseSearchHit struct {
+	Course *Course `json:"course"`
+	Rank   float64 `json:"rank"`
+}
+
+// FacetCount is the number of matching courses sharing a value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets break down the courses matching a search's text. They ignore
+// the category, instructor and price band filters so clients can offer the
+// other values as alternatives
+type SearchFacets struct {
+	Categories  []FacetCount `json:"categories"`
+	Instructors []FacetCount `json:"instructors"`
+	PriceBands  []FacetCount `json:"price_bands"`
+}
+
+// CourseSearchResults are the best matching courses, best first, with the
+// facets of everything that matched
+type CourseSearchResults struct {
+	Hits   []*CourseSearchHit `json:"hits"`
+	Facets SearchFacets       `json:"facets"`
+}
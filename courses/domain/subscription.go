@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSubscriptionRequired is returned when a student without a subscription
+// that grants access is assigned a course
+var ErrSubscriptionRequired = errors.New("an active subscription is required")
+
+// ErrChargeSettled is returned when a charge is settled in a way its current
+// status doesn't allow, e.g. because it was settled concurrently
+var ErrChargeSettled = errors.New("charge is already settled")
+
+// DunningRetryDelays are how long to wait before each retry of a failed
+// renewal. Renewals that still fail expire once the grace period is over
+var DunningRetryDelays = []time.Duration{
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	5 * 24 * time.Hour,
+}
+
+// Plan is what a subscription costs and how often it renews
+type Plan struct {
+	AbstractBase   `gorm:"embedded"`
+	Name           string `json:"name" gorm:"type:varchar(100);uniqueIndex;not null"`
+	Price          Money  `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	IntervalMonths uint   `json:"interval_months" gorm:"not null;default:12"`
+	TrialDays      uint   `json:"trial_days"`
+	GraceDays      uint   `json:"grace_days" gorm:"not null;default:7"`
+}
+
+// SubscriptionStatus is the state of a student's subscription
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusTrial    SubscriptionStatus = "trial"
+	SubscriptionStatusActive   SubscriptionStatus = "active"
+	SubscriptionStatusPastDue  SubscriptionStatus = "past_due"
+	SubscriptionStatusCanceled SubscriptionStatus = "canceled"
+	SubscriptionStatusExpired  SubscriptionStatus = "expired"
+	// SubscriptionStatusIncomplete is a paid signup whose first charge
+	// hasn't gone through yet, which grants no access
+	SubscriptionStatusIncomplete SubscriptionStatus = "incomplete"
+)
+
+// Subscription is a student's subscription to a plan. It renews at the end
+// of each period until it is canceled. A failed renewal makes it past due:
+// the student keeps access while it is retried until the grace period ends.
+// Canceled subscriptions keep access until the end of the paid period
+type Subscription struct {
+	AbstractBase       `gorm:"embedded"`
+	StudentUUID        string             `json:"student_uuid" gorm:"index;not null"`
+	PlanUUID           string             `json:"plan_uuid" gorm:"index;not null"`
+	Plan               *Plan              `json:"plan,omitempty" gorm:"foreignKey:PlanUUID"`
+	Status             SubscriptionStatus `json:"status" gorm:"type:varchar(20);index;not null"`
+	CurrentPeriodStart time.Time          `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time          `json:"current_period_end"`
+	RenewsAt           *time.Time         `json:"renews_at"`
+	GraceUntil         *time.Time         `json:"grace_until"`
+	NextRetryAt        *time.Time         `json:"next_retry_at"`
+	RenewalAttempts    uint               `json:"renewal_attempts"`
+	CanceledAt         *time.Time         `json:"canceled_at"`
+	LeasedUntil        *time.Time         `json:"-"`
+}
+
+// HasAccess reports whether the subscription lets its student take courses
+func (s *Subscription) HasAccess(now time.Time) bool {
+	switch s.Status {
+	case SubscriptionStatusTrial, SubscriptionStatusActive, SubscriptionStatusCanceled:
+		return now.Before(s.CurrentPeriodEnd)
+	case SubscriptionStatusPastDue:
+		return s.GraceUntil != nil && now.Before(*s.GraceUntil)
+	}
+	return false
+}
+
+// ChargeStatus is the outcome of charging a student
+type ChargeStatus string
+
+const (
+	// ChargeStatusPending is a charge recorded before the provider is asked
+	// for it, whose outcome isn't known yet
+	ChargeStatusPending   ChargeStatus = "pending"
+	ChargeStatusSucceeded ChargeStatus = "succeeded"
+	ChargeStatusFailed    ChargeStatus = "failed"
+)
+
+// CanBecome reports whether a charge can move from the status to next. Pending
+// charges settle either way, and a charge that went through can still fail
+// later, e.g. when the payment is reversed by the student's bank
+func (s ChargeStatus) CanBecome(next ChargeStatus) bool {
+	switch s {
+	case ChargeStatusPending:
+		return next == ChargeStatusSucceeded || next == ChargeStatusFailed
+	case ChargeStatusSucceeded:
+		return next == ChargeStatusFailed
+	}
+	return false
+}
+
+// SubscriptionCharge records an attempt to charge a student for a period of
+// their subscription. Charges are recorded as pending before the provider is
+// asked for them and keyed by their UUID there, so an attempt interrupted
+// half way can be retried without charging twice. IdempotencyKey is the key
+// the client subscribed with, if any
+type SubscriptionCharge struct {
+	AbstractBase     `gorm:"embedded"`
+	SubscriptionUUID string       `json:"subscription_uuid" gorm:"index;not null"`
+	Amount           Money        `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	Status           ChargeStatus `json:"status" gorm:"type:varchar(20);not null"`
+	Reference        string       `json:"reference,omitempty"`
+	FailureReason    string       `json:"failure_reason,omitempty"`
+	IdempotencyKey   string       `json:"-" gorm:"type:varchar(255);uniqueIndex:idx_subscription_charges_idempotency_key,where:idempotency_key <> ''"`
+	PeriodStart      time.Time    `json:"period_start"`
+	PeriodEnd        time.Time    `json:"period_end"`
+}
+
+// Journal is the ledger entries for a charge that went through: the money
+// comes into cash as subscription revenue
+func (c *SubscriptionCharge) Journal(postedAt time.Time) []*LedgerEntry {
+	entries := transfer(LedgerAccountCash, LedgerAccountSubscriptionRevenue, c.Amount, "Subscription charge "+c.Reference, postedAt)
+	for _, entry := range entries {
+		entry.ChargeUUID = c.UUID
+	}
+	return entries
+}
+
+// ReversalJournal is the ledger entries for a charge that failed after it went
+// through, which takes its money back out of cash and revenue
+func (c *SubscriptionCharge) ReversalJournal(postedAt time.Time) []*LedgerEntry {
+	entries := transfer(LedgerAccountSubscriptionRevenue, LedgerAccountCash, c.Amount, "Failed subscription charge "+c.Reference, postedAt)
+	for _, entry := range entries {
+		entry.ChargeUUID = c.UUID
+	}
+	return entries
+}
+
+// SubscriptionStarted is raised when a student subscribes. Invoice is the
+// receipt for the signup charge, and is nil for plans that start with a trial
+type SubscriptionStarted struct {
+	StudentUUID      string   `json:"student_uuid"`
+	SubscriptionUUID string   `json:"subscription_uuid"`
+	PlanName         string   `json:"plan_name"`
+	Invoice          *Invoice `json:"invoice,omitempty"`
+}
+
+// EventName ...
+func (SubscriptionStarted) EventName() string {
+	return "subscription.started"
+}
+
+// SubscriptionPaymentFailed is raised when a subscription can't be renewed.
+// Expired is set once the subscription has run out of retries
+type SubscriptionPaymentFailed struct {
+	StudentUUID      string     `json:"student_uuid"`
+	SubscriptionUUID string     `json:"subscription_uuid"`
+	NextRetryAt      *time.Time `json:"next_retry_at"`
+	Expired          bool       `json:"expired"`
+}
+
+// EventName ...
+func (SubscriptionPaymentFailed) EventName() string {
+	return "subscription.payment_failed"
+}
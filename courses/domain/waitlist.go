@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCourseFull is returned when every seat of a course with a capacity is taken
+var ErrCourseFull = errors.New("course is full")
+
+// WaitlistEntry holds a student's place in the queue for a full course. Entries
+// are served first come, first served and removed once the student gets a seat
+type WaitlistEntry struct {
+	AbstractBase `gorm:"embedded"`
+	CourseUUID   string `json:"course_uuid" gorm:"uniqueIndex:idx_waitlist_course_student;not null"`
+	StudentUUID  string `json:"student_uuid" gorm:"uniqueIndex:idx_waitlist_course_student;not null"`
+	RunUUID      string `json:"run_uuid,omitempty"`
+	Position     int64  `json:"position" gorm:"-"`
+}
+
+// WaitlistedError is returned when a student is put on the waitlist of a full
+// course instead of being assigned it
+type WaitlistedError struct {
+	CourseTitle string         `json:"course_title"`
+	Entry       *WaitlistEntry `json:"waitlist"`
+}
+
+// Error implements error
+func (e *WaitlistedError) Error() string {
+	return fmt.Sprintf("course %s is full, student is number %d on the waitlist", e.CourseTitle, e.Entry.Position)
+}
+
+// Unwrap lets callers match the error against ErrCourseFull
+func (e *WaitlistedError) Unwrap() error {
+	return ErrCourseFull
+}
+
+// WaitlistPromoted is raised when a seat frees up and the next student on the
+// waitlist is enrolled in the course
+type WaitlistPromoted struct {
+	StudentUUID string    `json:"student_uuid"`
+	CourseUUID  string    `json:"course_uuid"`
+	PromotedAt  time.Time `json:"promoted_at"`
+}
+
+// EventName ...
+func (WaitlistPromoted) EventName() string {
+	return "waitlist.promoted"
+}
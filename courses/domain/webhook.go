@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Payment provider event types
+const (
+	PaymentEventChargeSucceeded = "charge.succeeded"
+	PaymentEventChargeFailed    = "charge.failed"
+	PaymentEventRefundSucceeded = "refund.succeeded"
+	PaymentEventRefundFailed    = "refund.failed"
+)
+
+// PaymentEvent is something the payment provider tells sudocode about a
+// charge or refund after the fact. Reference is the provider's reference of
+// the charge or refund, and IdempotencyKey the key sudocode asked for it with,
+// which identifies charges the provider never answered
+type PaymentEvent struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Reference      string    `json:"reference"`
+	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+	FailureReason  string    `json:"failure_reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// WebhookEvent records a payment provider event that has been handled, so
+// that deliveries of the same event are only acted on once
+type WebhookEvent struct {
+	AbstractBase    `gorm:"embedded"`
+	ProviderEventID string    `json:"provider_event_id" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Type            string    `json:"type" gorm:"type:varchar(50);not null"`
+	Reference       string    `json:"reference" gorm:"index"`
+	Outcome         string    `json:"outcome"`
+	ReceivedAt      time.Time `json:"received_at"`
+}
@@ -0,0 +1,114 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+const (
+	productID = "-//sudoCODE Academy//Courses//EN"
+	uidDomain = "sudocode.academy"
+
+	// maxLineOctets is the longest content line RFC 5545 allows before folding
+	maxLineOctets = 75
+
+	utcFormat  = "20060102T150405Z"
+	dateFormat = "20060102"
+)
+
+// RenderICS renders an RFC 5545 calendar with the live sessions and run end
+// deadlines of a student's enrollments. Times are written in UTC so every
+// client places them correctly, while deadlines are all-day events on the
+// date they fall on in the run's own timezone. UIDs are derived from the
+// records' UUIDs so subscribed calendars update events instead of duplicating
+// them
+func RenderICS(name string, enrollments []*domain.Enrollment, now time.Time) []byte {
+	var b bytes.Buffer
+	write := func(line string) {
+		b.WriteString(fold(line))
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:" + productID)
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	write("X-WR-CALNAME:" + escape(name))
+
+	stamp := now.UTC().Format(utcFormat)
+	for _, enrollment := range enrollments {
+		run := enrollment.Run
+		if run == nil || enrollment.Course == nil {
+			continue
+		}
+		for _, session := range run.Sessions {
+			start := session.StartsAt.UTC()
+			end := start.Add(time.Duration(session.DurationMinutes) * time.Minute)
+			write("BEGIN:VEVENT")
+			write(fmt.Sprintf("UID:session-%s@%s", session.UUID, uidDomain))
+			write("DTSTAMP:" + stamp)
+			write("DTSTART:" + start.Format(utcFormat))
+			write("DTEND:" + end.Format(utcFormat))
+			write("SUMMARY:" + escape(fmt.Sprintf("%s: %s", enrollment.Course.Title, session.Title)))
+			write("DESCRIPTION:" + escape(fmt.Sprintf("Live session of %s", run.Name)))
+			write("END:VEVENT")
+		}
+		if run.EndDate == nil {
+			continue
+		}
+
+		location, err := time.LoadLocation(run.Timezone)
+		if err != nil {
+			location = time.UTC
+		}
+		due := run.EndDate.In(location)
+		write("BEGIN:VEVENT")
+		write(fmt.Sprintf("UID:run-end-%s@%s", run.UUID, uidDomain))
+		write("DTSTAMP:" + stamp)
+		write("DTSTART;VALUE=DATE:" + due.Format(dateFormat))
+		write("DTEND;VALUE=DATE:" + due.AddDate(0, 0, 1).Format(dateFormat))
+		write("SUMMARY:" + escape(fmt.Sprintf("%s: %s ends", enrollment.Course.Title, run.Name)))
+		write("TRANSP:TRANSPARENT")
+		write("END:VEVENT")
+	}
+
+	write("END:VCALENDAR")
+	return b.Bytes()
+}
+
+// escape escapes TEXT values as described in RFC 5545 section 3.3.11
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// fold splits a content line into CRLF terminated lines of at most 75 octets,
+// continuing each with a single space. Multi-byte characters are never split
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts towards its length
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
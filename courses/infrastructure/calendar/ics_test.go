@@ -0,0 +1,73 @@
+package calendar_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/calendar"
+)
+
+func TestRenderICS(t *testing.T) {
+	nairobi, _ := time.LoadLocation("Africa/Nairobi")
+	// 23:30 UTC on the 31st is already the 1st in Nairobi
+	end := time.Date(2023, 10, 31, 23, 30, 0, 0, time.UTC)
+	run := &domain.CourseRun{
+		AbstractBase: domain.AbstractBase{UUID: "run-1"},
+		Name:         "September cohort",
+		EndDate:      &end,
+		Timezone:     "Africa/Nairobi",
+		Sessions: []*domain.LiveSession{
+			{
+				AbstractBase:    domain.AbstractBase{UUID: "session-1"},
+				Title:           "Kick-off; intros, and Q&A",
+				StartsAt:        time.Date(2023, 9, 4, 18, 0, 0, 0, nairobi),
+				DurationMinutes: 90,
+			},
+		},
+	}
+	enrollments := []*domain.Enrollment{
+		{Course: &domain.Course{Title: strings.Repeat("Concurrency in Go ", 6)}, Run: run},
+		// evergreen enrollments have nothing to put on a calendar
+		{Course: &domain.Course{Title: "Self paced"}},
+	}
+	now := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	feed := string(calendar.RenderICS("sudoCODE Academy", enrollments, now))
+	if feed != string(calendar.RenderICS("sudoCODE Academy", enrollments, now)) {
+		t.Fatal("expected rendering to be deterministic")
+	}
+	if !strings.HasSuffix(feed, "\r\n") || strings.Contains(strings.ReplaceAll(feed, "\r\n", ""), "\n") {
+		t.Fatal("expected every line to end with CRLF")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %q is longer than 75 octets", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "session UID", want: "UID:session-session-1@sudocode.academy\r\n"},
+		{name: "session start in UTC", want: "DTSTART:20230904T150000Z\r\n"},
+		{name: "session end in UTC", want: "DTEND:20230904T163000Z\r\n"},
+		{name: "escaped summary", want: `Kick-off\; intros\, and Q&A` + "\r\n"},
+		{name: "deadline UID", want: "UID:run-end-run-1@sudocode.academy\r\n"},
+		{name: "deadline on the run's local date", want: "DTSTART;VALUE=DATE:20231101\r\n"},
+		{name: "all-day deadline", want: "DTEND;VALUE=DATE:20231102\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(unfolded, tt.want) {
+				t.Errorf("expected the feed to contain %q, got:\n%s", tt.want, unfolded)
+			}
+		})
+	}
+	if got := strings.Count(unfolded, "BEGIN:VEVENT"); got != 2 {
+		t.Errorf("expected 2 events, got %d", got)
+	}
+}
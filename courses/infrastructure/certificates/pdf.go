@@ -0,0 +1,46 @@
+package certificates
+
+import (
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/pdf"
+)
+
+const (
+	pageWidth  = 842.0
+	pageHeight = 595.0
+)
+
+type pdfLine struct {
+	font string
+	size float64
+	y    float64
+	text string
+}
+
+// RenderPDF renders a single page, landscape A4 certificate using only the
+// standard PDF fonts so no font files need to be embedded
+func RenderPDF(cert *domain.Certificate, verifyURL string) []byte {
+	lines := []pdfLine{
+		{font: "F2", size: 20, y: 500, text: "sudoCODE Academy"},
+		{font: "F2", size: 36, y: 430, text: "Certificate of Completion"},
+		{font: "F1", size: 16, y: 370, text: "This certifies that"},
+		{font: "F2", size: 28, y: 325, text: cert.StudentName},
+		{font: "F1", size: 16, y: 280, text: "has successfully completed"},
+		{font: "F2", size: 24, y: 240, text: cert.CourseTitle},
+		{font: "F1", size: 14, y: 180, text: "Issued on " + cert.IssuedAt.UTC().Format("2 January 2006")},
+		{font: "F1", size: 10, y: 90, text: "Serial: " + cert.Serial},
+		{font: "F1", size: 10, y: 74, text: "Verify at " + verifyURL},
+	}
+
+	page := pdf.NewPage(pageWidth, pageHeight)
+	page.Draw("2 w 30 30 782 535 re S")
+	page.Draw("0.5 w 40 40 762 515 re S")
+	for _, line := range lines {
+		x := (pageWidth - pdf.TextWidth(line.size, line.text)) / 2
+		if x < 40 {
+			x = 40
+		}
+		page.Text(line.font, line.size, x, line.y, line.text)
+	}
+	return page.Bytes()
+}
@@ -0,0 +1,72 @@
+package certificates
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Signer defines the contract for signing and verifying certificates
+type Signer interface {
+	Sign(message []byte) string
+	Verify(message []byte, signature string) bool
+}
+
+// Ed25519Signer signs certificates with an ed25519 private key
+type Ed25519Signer struct {
+	privateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer initializes a signer from a 32 byte ed25519 seed
+func NewEd25519Signer(seed []byte) (*Ed25519Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("certificate signing key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return &Ed25519Signer{privateKey: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// NewEd25519SignerFromEnv initializes a signer from the base64 encoded seed in
+// the env variable. Without it a throwaway key is generated, so nothing it
+// signed verifies after a restart, except in prod where the key is required
+func NewEd25519SignerFromEnv(env string) (*Ed25519Signer, error) {
+	encoded := os.Getenv(env)
+	if encoded == "" {
+		if os.Getenv("ENVIRONMENT") == "prod" {
+			return nil, fmt.Errorf("%s is not set", env)
+		}
+		log.Warnf("%s is not set, a temporary key is used instead", env)
+		seed := make([]byte, ed25519.SeedSize)
+		if _, err := rand.Read(seed); err != nil {
+			return nil, fmt.Errorf("can't generate a temporary key for %s: %w", env, err)
+		}
+		return NewEd25519Signer(seed)
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode %s: %w", env, err)
+	}
+	signer, err := NewEd25519Signer(seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", env, err)
+	}
+	return signer, nil
+}
+
+// Sign returns the base64 encoded signature of the message
+func (s *Ed25519Signer) Sign(message []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.privateKey, message))
+}
+
+// Verify checks a base64 encoded signature of the message
+func (s *Ed25519Signer) Verify(message []byte, signature string) bool {
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(s.privateKey.Public().(ed25519.PublicKey), message, raw)
+}
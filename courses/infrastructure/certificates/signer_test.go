@@ -0,0 +1,106 @@
+package certificates_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
+)
+
+func TestEd25519Signer(t *testing.T) {
+	signer, err := certificates.NewEd25519Signer(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer() error = %v", err)
+	}
+	message := []byte("SC-2023-ABCDEFGHIJ|student|course|2023-06-01T00:00:00Z")
+	signature := signer.Sign(message)
+
+	tests := []struct {
+		name      string
+		message   []byte
+		signature string
+		want      bool
+	}{
+		{
+			name:      "Happy case - untouched message",
+			message:   message,
+			signature: signature,
+			want:      true,
+		},
+		{
+			name:      "Sad case - tampered message",
+			message:   []byte("SC-2023-ABCDEFGHIJ|someone-else|course|2023-06-01T00:00:00Z"),
+			signature: signature,
+			want:      false,
+		},
+		{
+			name:      "Sad case - malformed signature",
+			message:   message,
+			signature: "not base64!",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := signer.Verify(tt.message, tt.signature); got != tt.want {
+				t.Errorf("Ed25519Signer.Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := certificates.NewEd25519Signer([]byte("too short")); err == nil {
+		t.Errorf("expected an error for a short seed")
+	}
+}
+
+func TestNewEd25519SignerFromEnv(t *testing.T) {
+	t.Setenv("TEST_SIGNING_KEY", "")
+	t.Setenv("ENVIRONMENT", "test")
+	if _, err := certificates.NewEd25519SignerFromEnv("TEST_SIGNING_KEY"); err != nil {
+		t.Errorf("expected a temporary key outside prod, got %v", err)
+	}
+
+	t.Setenv("ENVIRONMENT", "prod")
+	if _, err := certificates.NewEd25519SignerFromEnv("TEST_SIGNING_KEY"); err == nil {
+		t.Errorf("expected the key to be required in prod")
+	}
+
+	t.Setenv("TEST_SIGNING_KEY", base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	signer, err := certificates.NewEd25519SignerFromEnv("TEST_SIGNING_KEY")
+	if err != nil {
+		t.Fatalf("NewEd25519SignerFromEnv() error = %v", err)
+	}
+	seeded, _ := certificates.NewEd25519Signer(make([]byte, 32))
+	if !seeded.Verify([]byte("message"), signer.Sign([]byte("message"))) {
+		t.Errorf("expected the key from the env variable to be used")
+	}
+
+	t.Setenv("TEST_SIGNING_KEY", "not base64!")
+	if _, err := certificates.NewEd25519SignerFromEnv("TEST_SIGNING_KEY"); err == nil {
+		t.Errorf("expected an invalid key to be refused")
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	pdf := certificates.RenderPDF(&domain.Certificate{
+		Serial:      "SC-2023-ABCDEFGHIJ",
+		StudentName: "Zoë (Ada) Lovelace",
+		CourseTitle: "Go 101",
+		IssuedAt:    time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	}, "https://example.com/api/v1/certificates/SC-2023-ABCDEFGHIJ/verify")
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) {
+		t.Errorf("expected a PDF header")
+	}
+	if !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Errorf("expected a PDF trailer")
+	}
+	for _, want := range []string{`Zo\353 \(Ada\) Lovelace`, "Go 101", "1 June 2023", "SC-2023-ABCDEFGHIJ"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("expected the PDF to contain %q", want)
+		}
+	}
+}
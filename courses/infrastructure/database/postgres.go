@@ -2,12 +2,19 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/repository"
+	"github.com/MelvinKim/platform/postgres"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PostgresDB sets up a database layer within the service
@@ -17,9 +24,7 @@ type PostgresDB struct {
 
 // Checkpreconditions assert all conditions required to run the service are met
 func (p *PostgresDB) Checkpreconditions() {
-	if p.DB == nil {
-		log.Fatalf("postgres database ORM has not been initialized.")
-	}
+	postgres.Checkpreconditions(p.DB)
 }
 
 // NewPostgresDB initializes a new postgres DB instance
@@ -35,28 +40,381 @@ func NewPostgresDB() *PostgresDB {
 func Migrate(db *gorm.DB) {
 	tables := []interface{}{
 		&domain.Student{},
+		&domain.Category{},
+		&domain.Instructor{},
 		&domain.Course{},
 		&domain.StudentCourse{},
+		&domain.Module{},
+		&domain.Lesson{},
+		&domain.LessonProgress{},
+		&domain.Quiz{},
+		&domain.Question{},
+		&domain.Attempt{},
+		&domain.Certificate{},
+		&domain.CoursePrerequisite{},
+		&domain.WaitlistEntry{},
+		&domain.CourseRun{},
+		&domain.LiveSession{},
+		&domain.CalendarSubscription{},
+		&domain.Review{},
+		&domain.CoursePrice{},
+		&domain.Coupon{},
+		&domain.CouponCourse{},
+		&domain.CouponCategory{},
+		&domain.CouponRedemption{},
+		&domain.Plan{},
+		&domain.Subscription{},
+		&domain.SubscriptionCharge{},
+		&domain.TaxRate{},
+		&domain.InvoiceSequence{},
+		&domain.Invoice{},
+		&domain.InvoiceLine{},
+		&domain.InvoiceTaxLine{},
+		&domain.Refund{},
+		&domain.LedgerEntry{},
+		&domain.WebhookEvent{},
+		&domain.CourseSale{},
+		&domain.RevenueShare{},
+		&domain.PayoutStatement{},
+		&domain.PayoutLine{},
+		&domain.Import{},
+		&domain.ImportRow{},
 	}
 	for _, table := range tables {
 		if err := db.AutoMigrate(table); err != nil {
 			log.Panicf("can't run db migrations on table %v in the user's service: err: %v", table, err)
 		}
 	}
+	if err := MigrateEvergreenRuns(db); err != nil {
+		log.Panicf("can't move enrollments into evergreen runs: err: %v", err)
+	}
+	if err := MigrateCourseSearch(db); err != nil {
+		log.Panicf("can't set up course search: err: %v", err)
+	}
+	if err := MigrateCatalog(db); err != nil {
+		log.Panicf("can't link courses to categories and instructors: err: %v", err)
+	}
+	if err := MigrateCoursePrices(db); err != nil {
+		log.Panicf("can't create course price lists: err: %v", err)
+	}
+	if err := MigrateLedger(db); err != nil {
+		log.Panicf("can't post past charges to the ledger: err: %v", err)
+	}
+	if err := MigrateCourseSales(db); err != nil {
+		log.Panicf("can't record past course sales: err: %v", err)
+	}
+}
+
+// MigrateCourseSales records the sales of enrollments made before sales were
+// recorded, at the list price when the student enrolled and with the coupon
+// they redeemed for the course. Enrollments in courses that weren't priced
+// when the student enrolled are left for when they are. It is safe to run on
+// every start up
+func MigrateCourseSales(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var enrollments []*domain.StudentCourse
+		err := tx.Where("NOT EXISTS (?)",
+			tx.Model(&domain.CourseSale{}).Select("1").
+				Where("course_sales.student_uuid = student_courses.student_uuid AND course_sales.course_uuid = student_courses.course_uuid"),
+		).Find(&enrollments).Error
+		if err != nil {
+			return err
+		}
+		for _, enrollment := range enrollments {
+			course := &domain.Course{}
+			if err := tx.Where("uuid = ?", enrollment.CourseUUID).Find(course).Error; err != nil {
+				return err
+			}
+			if course.UUID == "" {
+				continue
+			}
+			var redemption domain.CouponRedemption
+			err := tx.Where("student_uuid = ? AND course_uuid = ?", enrollment.StudentUUID, enrollment.CourseUUID).
+				Find(&redemption).Error
+			if err != nil {
+				return err
+			}
+			var redeemed *domain.CouponRedemption
+			if redemption.UUID != "" {
+				redeemed = &redemption
+			}
+			soldAt := time.Now()
+			if enrollment.EnrolledAt != nil {
+				soldAt = *enrollment.EnrolledAt
+			}
+			err = recordSale(tx, enrollment.StudentUUID, course, redeemed, soldAt)
+			if errors.Is(err, domain.ErrCourseNotPriced) {
+				log.Warnf("can't record the sale of %s to %s: %v", course.UUID, enrollment.StudentUUID, err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateLedger posts the journals of successful charges made before the
+// ledger existed, dated when they were charged, so account balances cover
+// every charge. It is safe to run on every start up
+func MigrateLedger(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var charges []*domain.SubscriptionCharge
+		err := tx.Where("status = ? AND NOT EXISTS (?)", domain.ChargeStatusSucceeded,
+			tx.Model(&domain.LedgerEntry{}).Select("1").Where("ledger_entries.charge_uuid = subscription_charges.uuid"),
+		).Find(&charges).Error
+		if err != nil {
+			return err
+		}
+		for _, charge := range charges {
+			postedAt := time.Now()
+			if charge.CreatedAt != nil {
+				postedAt = *charge.CreatedAt
+			}
+			if err := postJournal(tx, charge.Journal(postedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateCoursePrices starts the price list of courses that don't have one
+// with their launch price in the base currency, effective from their creation.
+// It is safe to run on every start up
+func MigrateCoursePrices(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var courses []*domain.Course
+		err := tx.Where("price > 0 AND NOT EXISTS (?)",
+			tx.Model(&domain.CoursePrice{}).Select("1").Where("course_prices.course_uuid = courses.uuid"),
+		).Find(&courses).Error
+		if err != nil {
+			return err
+		}
+		for _, course := range courses {
+			effectiveFrom := time.Unix(0, 0).UTC()
+			if course.CreatedAt != nil {
+				effectiveFrom = *course.CreatedAt
+			}
+			price, err := launchPrice(course, effectiveFrom)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(price).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// launchPrice is the price list entry for a course's launch price, which is
+// given in whole units of the base currency
+func launchPrice(course *domain.Course, effectiveFrom time.Time) (*domain.CoursePrice, error) {
+	price, err := domain.MoneyFromMajor(course.Price, domain.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CoursePrice{
+		CourseUUID:    course.UUID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom,
+	}, nil
+}
+
+// MigrateCatalog links courses created while categories and instructors were
+// plain strings to category and instructor entities. Names that only differ
+// in case, spacing or punctuation share one entity, named after their most
+// common spelling. It only touches courses that aren't linked yet, so it is
+// safe to run on every start up
+func MigrateCatalog(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		categories, err := legacySpellings(tx, "category", "category_uuid")
+		if err != nil {
+			return err
+		}
+		for _, spellings := range categories {
+			category := &domain.Category{}
+			if err := tx.Where("slug = ?", spellings.slug).Find(category).Error; err != nil {
+				return err
+			}
+			if category.UUID == "" {
+				category = &domain.Category{Name: spellings.names[0], Slug: spellings.slug}
+				if err := tx.Create(category).Error; err != nil {
+					return err
+				}
+			}
+			err := tx.Model(&domain.Course{}).
+				Where("category_uuid IS NULL AND category IN ?", spellings.names).
+				Updates(map[string]interface{}{"category_uuid": category.UUID, "category": category.Name}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		instructors, err := legacySpellings(tx, "instructor", "instructor_uuid")
+		if err != nil {
+			return err
+		}
+		var existing []*domain.Instructor
+		if err := tx.Find(&existing).Error; err != nil {
+			return err
+		}
+		bySlug := make(map[string]*domain.Instructor, len(existing))
+		for _, instructor := range existing {
+			bySlug[domain.Slugify(instructor.Name)] = instructor
+		}
+		for _, spellings := range instructors {
+			instructor, ok := bySlug[spellings.slug]
+			if !ok {
+				instructor = &domain.Instructor{Name: spellings.names[0]}
+				if err := tx.Create(instructor).Error; err != nil {
+					return err
+				}
+				bySlug[spellings.slug] = instructor
+			}
+			err := tx.Model(&domain.Course{}).
+				Where("instructor_uuid IS NULL AND instructor IN ?", spellings.names).
+				Updates(map[string]interface{}{"instructor_uuid": instructor.UUID, "instructor": instructor.Name}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// spellings are the names of a column that share a slug, most common first
+type spellings struct {
+	slug  string
+	names []string
+}
+
+// legacySpellings groups the values of a course column that isn't linked to
+// an entity yet by slug
+func legacySpellings(tx *gorm.DB, column string, foreignKey string) ([]*spellings, error) {
+	var counts []struct {
+		Name  string
+		Count int64
+	}
+	err := tx.Model(&domain.Course{}).
+		Select(fmt.Sprintf("%s AS name, COUNT(*) AS count", column)).
+		Where(fmt.Sprintf("%s IS NULL AND %s <> ''", foreignKey, column)).
+		Group(column).
+		Order("count DESC, name ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []*spellings{}
+	bySlug := map[string]*spellings{}
+	for _, count := range counts {
+		slug := domain.Slugify(count.Name)
+		if slug == "" {
+			continue
+		}
+		group, ok := bySlug[slug]
+		if !ok {
+			group = &spellings{slug: slug}
+			bySlug[slug] = group
+			groups = append(groups, group)
+		}
+		group.names = append(group.names, count.Name)
+	}
+	return groups, nil
+}
+
+// MigrateCourseSearch adds the weighted full text search vector of courses
+// and the trigram index used to tolerate typos in titles. Titles weigh the
+// most, then instructors and categories, then descriptions
+func MigrateCourseSearch(db *gorm.DB) error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english'::regconfig, coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english'::regconfig, coalesce(instructor, '')), 'B') ||
+			setweight(to_tsvector('english'::regconfig, coalesce(category, '')), 'B') ||
+			setweight(to_tsvector('english'::regconfig, coalesce(description, '')), 'C')
+		) STORED`,
+		"CREATE INDEX IF NOT EXISTS idx_courses_search_vector ON courses USING GIN (search_vector)",
+		"CREATE INDEX IF NOT EXISTS idx_courses_title_trgm ON courses USING GIN (title gin_trgm_ops)",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateEvergreenRuns moves enrollments made before course runs existed into
+// their course's evergreen run. It only touches enrollments without a run, so
+// it is safe to run on every start up
+func MigrateEvergreenRuns(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var courseUUIDs []string
+		err := tx.Model(&domain.StudentCourse{}).
+			Where("run_uuid IS NULL OR run_uuid = ''").
+			Distinct().
+			Pluck("course_uuid", &courseUUIDs).Error
+		if err != nil {
+			return err
+		}
+		for _, courseUUID := range courseUUIDs {
+			course := &domain.Course{}
+			if err := tx.Where("uuid = ?", courseUUID).First(course).Error; err != nil {
+				return err
+			}
+			run, err := enrollmentRun(tx, course, nil)
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&domain.StudentCourse{}).
+				Where("course_uuid = ? AND (run_uuid IS NULL OR run_uuid = '')", courseUUID).
+				Update("run_uuid", run.UUID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DefaultConfig is the in-cluster database the courses service uses unless
+// DB_* say otherwise
+var DefaultConfig = postgres.Config{
+	Host:     "postgresql-service.default.svc.cluster.local",
+	Port:     "5432",
+	User:     "postgres",
+	Password: "postgres",
+	Name:     "sudocode",
 }
 
 // Init initializes a new gorm instance by connecting to a postgres DB instance
 func Init() *gorm.DB {
-	dsn := "host=postgresql-service.default.svc.cluster.local port=5432 user=postgres dbname=sudocode password=postgres sslmode=disable"
+	return postgres.Connect("courses", postgres.ConfigFromEnv(DefaultConfig), Migrate)
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// Connect connects to the courses database configured in the environment and
+// migrates it, returning an error instead of exiting when it can't
+func Connect() (*PostgresDB, error) {
+	db, err := postgres.Open("courses", postgres.ConfigFromEnv(DefaultConfig), Migrate)
 	if err != nil {
-		log.Fatalf("can't open postgres db connection for the courses service: %v", err)
+		return nil, err
+	}
+	return &PostgresDB{DB: db}, nil
+}
+
+// Open connects to the courses database in config without migrating it, for
+// tools that work on the service's data
+func Open(config postgres.Config) *PostgresDB {
+	db := PostgresDB{
+		DB: postgres.Connect("courses", config, nil),
 	}
-	log.Info("Database connected successfully.")
-	Migrate(db)
-	log.Info("Database migrations ran successfully.")
-	return db
+	db.Checkpreconditions()
+	return &db
 }
 
 // CreateStudent creates a new student in sudocode acaddemy
@@ -65,18 +423,29 @@ func (p *PostgresDB) CreateStudent(
 	student *domain.Student,
 ) (*domain.Student, error) {
 	if err := p.DB.Create(student).Error; err != nil {
-		return nil, fmt.Errorf("infrastructure: can't create a new student: %v", err)
+		return nil, fmt.Errorf("%w: can't create a new student: %v", repository.ErrStorage, err)
 	}
 	return student, nil
 }
 
-// CreateCourse creates a new course in sudocode acaddemy
+// CreateCourse creates a new course in sudocode acaddemy and starts its price
+// list with its launch price, in one transaction
 func (p *PostgresDB) CreateCourse(
 	ctx context.Context,
 	course *domain.Course,
 ) (*domain.Course, error) {
-	if err := p.DB.Create(course).Error; err != nil {
-		return nil, fmt.Errorf("infrastructure: can't create a new course: %v", err)
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(course).Error; err != nil {
+			return err
+		}
+		price, err := launchPrice(course, time.Now())
+		if err != nil {
+			return err
+		}
+		return tx.Create(price).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create a new course: %v", repository.ErrStorage, err)
 	}
 	return course, nil
 }
@@ -90,8 +459,12 @@ func (p *PostgresDB) GetCourse(
 		Title: *title,
 	}
 	var course domain.Course
-	if err := p.DB.Where(filters).Find(&course).Error; err != nil {
-		return nil, fmt.Errorf("infrastructure: can't get course by title: %v err: %v", title, err)
+	err := p.DB.Preload("InstructorProfile").
+		Preload("CategoryDetails").
+		Where(filters).
+		Find(&course).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get course by title: %v err: %v", repository.ErrStorage, title, err)
 	}
 	if course.UUID == "" {
 		return nil, nil
@@ -100,32 +473,61 @@ func (p *PostgresDB) GetCourse(
 	return &course, nil
 }
 
-// AssignCourseToStudent assigns a course to a student after they have purchased them
+// AssignCourseToStudent assigns a course to a student after they have purchased them.
+// The student joins the given run of the course, or the course's evergreen run
+// when none is given. The course's row is locked while its seats are counted
+// so that concurrent assignments can't overbook it. A coupon used at checkout
+// is redeemed in the same transaction, so it is only used up when the student
+// is enrolled
 func (p *PostgresDB) AssignCourseToStudent(
 	ctx context.Context,
 	email *string,
 	courseTitle *string,
+	runUUID *string,
+	redemption *domain.CouponRedemption,
 ) (*domain.Student, error) {
 	student := &domain.Student{}
-	course := &domain.Course{}
 
 	// Find the student with the given ID
 	if err := p.DB.Where("email = ?", email).First(student).Error; err != nil {
 		return nil, err
 	}
 
-	// Find the course with the given ID
-	if err := p.DB.Where("title = ?", courseTitle).First(course).Error; err != nil {
-		return nil, err
-	}
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		// Find the course with the given ID
+		course, err := lockCourse(tx, "title = ?", *courseTitle)
+		if err != nil {
+			return err
+		}
+		full, err := courseIsFull(tx, course)
+		if err != nil {
+			return err
+		}
+		if full {
+			return domain.ErrCourseFull
+		}
 
-	// Add the course to the student's courses
-	link := domain.StudentCourse{
-		StudentUUID: student.UUID,
-		CourseUUID:  course.UUID,
+		run, err := enrollmentRun(tx, course, runUUID)
+		if err != nil {
+			return err
+		}
+		if redemption != nil {
+			redemption.StudentUUID = student.UUID
+			redemption.CourseUUID = course.UUID
+			if err := redeemCoupon(tx, redemption); err != nil {
+				return err
+			}
+		}
+
+		// Add the course to the student's courses
+		return enroll(tx, student.UUID, course, run.UUID, redemption)
+	})
+	if errors.Is(err, domain.ErrCourseFull) || errors.Is(err, domain.ErrCouponRejected) ||
+		errors.Is(err, domain.ErrCourseNotPriced) {
+		return nil, err
 	}
-	if err := p.DB.Create(link).Error; err != nil {
-		return nil, fmt.Errorf("infrastructure: can't create a new student's course: %v", err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create a new student's course: %v", repository.ErrStorage, err)
 	}
 
 	return student, nil
@@ -141,7 +543,483 @@ func (p *PostgresDB) GetStudent(
 	}
 	var student domain.Student
 	if err := p.DB.Where(filters).Find(&student).Error; err != nil {
-		return nil, fmt.Errorf("infrastructure: can't get student by email: %v err: %v", email, err)
+		return nil, fmt.Errorf("%w: can't get student by email: %v err: %v", repository.ErrStorage, email, err)
+	}
+	if student.UUID == "" {
+		return nil, nil
+	}
+
+	return &student, nil
+}
+
+// CreateModule adds a module to a course's curriculum. Modules without a
+// position are appended after the course's last module
+func (p *PostgresDB) CreateModule(
+	ctx context.Context,
+	module *domain.Module,
+) (*domain.Module, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Course{}).Where("uuid = ?", module.CourseUUID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return &domain.NotFoundError{Kind: "course", Key: module.CourseUUID}
+		}
+		if module.Position == 0 {
+			var last int
+			if err := tx.Model(&domain.Module{}).Where("course_uuid = ?", module.CourseUUID).
+				Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+				return err
+			}
+			module.Position = last + 1
+		}
+		return tx.Create(module).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create a new module: %v", repository.ErrStorage, err)
+	}
+	return module, nil
+}
+
+// CreateLesson adds a lesson to a module. Lessons without a position are
+// appended after the module's last lesson
+func (p *PostgresDB) CreateLesson(
+	ctx context.Context,
+	lesson *domain.Lesson,
+) (*domain.Lesson, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Module{}).Where("uuid = ?", lesson.ModuleUUID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return &domain.NotFoundError{Kind: "module", Key: lesson.ModuleUUID}
+		}
+		if lesson.Position == 0 {
+			var last int
+			if err := tx.Model(&domain.Lesson{}).Where("module_uuid = ?", lesson.ModuleUUID).
+				Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+				return err
+			}
+			lesson.Position = last + 1
+		}
+		return tx.Create(lesson).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create a new lesson: %v", repository.ErrStorage, err)
+	}
+	return lesson, nil
+}
+
+// GetCourseCurriculum returns a course with its modules and lessons in order
+func (p *PostgresDB) GetCourseCurriculum(
+	ctx context.Context,
+	courseUUID *string,
+) (*domain.Course, error) {
+	var course domain.Course
+	err := p.DB.
+		Preload("Modules", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Modules.Lessons", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("uuid = ?", *courseUUID).
+		Find(&course).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get curriculum for course: %v err: %v", repository.ErrStorage, *courseUUID, err)
+	}
+	if course.UUID == "" {
+		return nil, nil
+	}
+
+	return &course, nil
+}
+
+// GetModule returns a single module
+func (p *PostgresDB) GetModule(
+	ctx context.Context,
+	moduleUUID *string,
+) (*domain.Module, error) {
+	var module domain.Module
+	if err := p.DB.Where("uuid = ?", *moduleUUID).Find(&module).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get module: %v err: %v", repository.ErrStorage, *moduleUUID, err)
+	}
+	if module.UUID == "" {
+		return nil, nil
+	}
+
+	return &module, nil
+}
+
+// GetLesson returns a single lesson
+func (p *PostgresDB) GetLesson(
+	ctx context.Context,
+	lessonUUID *string,
+) (*domain.Lesson, error) {
+	var lesson domain.Lesson
+	if err := p.DB.Where("uuid = ?", *lessonUUID).Find(&lesson).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get lesson: %v err: %v", repository.ErrStorage, *lessonUUID, err)
+	}
+	if lesson.UUID == "" {
+		return nil, nil
+	}
+
+	return &lesson, nil
+}
+
+// UpdateModule saves changes to a module's title
+func (p *PostgresDB) UpdateModule(
+	ctx context.Context,
+	module *domain.Module,
+) (*domain.Module, error) {
+	if err := p.DB.Model(module).Select("title").Updates(module).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't update module: %v", repository.ErrStorage, err)
+	}
+	return module, nil
+}
+
+// UpdateLesson saves changes to a lesson's content
+func (p *PostgresDB) UpdateLesson(
+	ctx context.Context,
+	lesson *domain.Lesson,
+) (*domain.Lesson, error) {
+	err := p.DB.Model(lesson).
+		Select("title", "type", "duration_minutes", "content_url", "body").
+		Updates(lesson).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't update lesson: %v", repository.ErrStorage, err)
+	}
+	return lesson, nil
+}
+
+// reorder rewrites the positions of the rows in the given order. The UUIDs
+// must be exactly the rows that currently belong to the parent
+func reorder(tx *gorm.DB, model interface{}, parentColumn string, parentUUID string, uuids []string) error {
+	var existing []string
+	if err := tx.Model(model).Where(parentColumn+" = ?", parentUUID).Pluck("uuid", &existing).Error; err != nil {
+		return err
+	}
+	if len(existing) != len(uuids) {
+		return fmt.Errorf("expected %d items to reorder, got %d", len(existing), len(uuids))
+	}
+	known := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		known[id] = true
+	}
+	for position, id := range uuids {
+		if !known[id] {
+			return fmt.Errorf("%s does not belong to %s", id, parentUUID)
+		}
+		delete(known, id)
+		if err := tx.Model(model).Where("uuid = ?", id).Update("position", position+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReorderModules sets the order of a course's modules
+func (p *PostgresDB) ReorderModules(
+	ctx context.Context,
+	courseUUID *string,
+	moduleUUIDs []string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		return reorder(tx, &domain.Module{}, "course_uuid", *courseUUID, moduleUUIDs)
+	})
+	if err != nil {
+		return fmt.Errorf("%w: can't reorder modules: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// ReorderLessons sets the order of a module's lessons
+func (p *PostgresDB) ReorderLessons(
+	ctx context.Context,
+	moduleUUID *string,
+	lessonUUIDs []string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		return reorder(tx, &domain.Lesson{}, "module_uuid", *moduleUUID, lessonUUIDs)
+	})
+	if err != nil {
+		return fmt.Errorf("%w: can't reorder lessons: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// DeleteModule removes a module together with its lessons
+func (p *PostgresDB) DeleteModule(
+	ctx context.Context,
+	moduleUUID *string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("module_uuid = ?", *moduleUUID).Delete(&domain.Lesson{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("uuid = ?", *moduleUUID).Delete(&domain.Module{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%w: can't delete module: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// DeleteLesson removes a lesson
+func (p *PostgresDB) DeleteLesson(
+	ctx context.Context,
+	lessonUUID *string,
+) error {
+	if err := p.DB.Where("uuid = ?", *lessonUUID).Delete(&domain.Lesson{}).Error; err != nil {
+		return fmt.Errorf("%w: can't delete lesson: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// GetEnrollment returns a student's enrollment in a course
+func (p *PostgresDB) GetEnrollment(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) (*domain.StudentCourse, error) {
+	var enrollment domain.StudentCourse
+	err := p.DB.Where("student_uuid = ? AND course_uuid = ?", *studentUUID, *courseUUID).
+		Find(&enrollment).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get enrollment: %v", repository.ErrStorage, err)
+	}
+	if enrollment.StudentUUID == "" {
+		return nil, nil
+	}
+
+	return &enrollment, nil
+}
+
+// GetStudentEnrollments returns the courses a student is enrolled in
+func (p *PostgresDB) GetStudentEnrollments(
+	ctx context.Context,
+	studentUUID *string,
+) ([]*domain.Enrollment, error) {
+	var links []*domain.StudentCourse
+	if err := p.DB.Where("student_uuid = ?", *studentUUID).Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get student's enrollments: %v", repository.ErrStorage, err)
+	}
+	if len(links) == 0 {
+		return []*domain.Enrollment{}, nil
+	}
+
+	courseUUIDs := make([]string, 0, len(links))
+	for _, link := range links {
+		courseUUIDs = append(courseUUIDs, link.CourseUUID)
+	}
+	var courses []*domain.Course
+	if err := p.DB.Where("uuid IN ?", courseUUIDs).Find(&courses).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get enrolled courses: %v", repository.ErrStorage, err)
+	}
+	byUUID := make(map[string]*domain.Course, len(courses))
+	for _, course := range courses {
+		byUUID[course.UUID] = course
+	}
+
+	runUUIDs := make([]string, 0, len(links))
+	for _, link := range links {
+		if link.RunUUID != "" {
+			runUUIDs = append(runUUIDs, link.RunUUID)
+		}
+	}
+	var runs []*domain.CourseRun
+	err := p.DB.
+		Preload("Sessions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("starts_at ASC")
+		}).
+		Where("uuid IN ?", runUUIDs).
+		Find(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get enrolled course runs: %v", repository.ErrStorage, err)
+	}
+	runsByUUID := make(map[string]*domain.CourseRun, len(runs))
+	for _, run := range runs {
+		runsByUUID[run.UUID] = run
+	}
+
+	enrollments := make([]*domain.Enrollment, 0, len(links))
+	for _, link := range links {
+		course, ok := byUUID[link.CourseUUID]
+		if !ok {
+			continue
+		}
+		enrollments = append(enrollments, &domain.Enrollment{
+			Course:      course,
+			Run:         runsByUUID[link.RunUUID],
+			Status:      link.Status,
+			EnrolledAt:  link.EnrolledAt,
+			CompletedAt: link.CompletedAt,
+		})
+	}
+	return enrollments, nil
+}
+
+// GetLessonProgress returns a student's progress on a lesson
+func (p *PostgresDB) GetLessonProgress(
+	ctx context.Context,
+	studentUUID *string,
+	lessonUUID *string,
+) (*domain.LessonProgress, error) {
+	var progress domain.LessonProgress
+	err := p.DB.Where("student_uuid = ? AND lesson_uuid = ?", *studentUUID, *lessonUUID).
+		Find(&progress).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get lesson progress: %v", repository.ErrStorage, err)
+	}
+	if progress.UUID == "" {
+		return nil, nil
+	}
+
+	return &progress, nil
+}
+
+// GetCourseCompletion counts the lessons of a course and how many of them a
+// student has completed
+func (p *PostgresDB) GetCourseCompletion(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) (int64, int64, error) {
+	lessons := p.DB.Model(&domain.Lesson{}).
+		Select("lessons.uuid").
+		Joins("JOIN modules ON modules.uuid = lessons.module_uuid AND modules.deleted_at IS NULL").
+		Where("modules.course_uuid = ?", *courseUUID)
+
+	var total int64
+	if err := lessons.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, 0, fmt.Errorf("%w: can't count course lessons: %v", repository.ErrStorage, err)
+	}
+	var completed int64
+	err := p.DB.Model(&domain.LessonProgress{}).
+		Where("student_uuid = ? AND status = ? AND lesson_uuid IN (?)",
+			*studentUUID, domain.ProgressStatusCompleted, lessons.Session(&gorm.Session{})).
+		Count(&completed).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: can't count completed lessons: %v", repository.ErrStorage, err)
+	}
+	return completed, total, nil
+}
+
+// SaveLessonProgress creates or updates a student's progress on a lesson
+func (p *PostgresDB) SaveLessonProgress(
+	ctx context.Context,
+	progress *domain.LessonProgress,
+) (*domain.LessonProgress, error) {
+	if err := p.DB.Save(progress).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't save lesson progress: %v", repository.ErrStorage, err)
+	}
+	return progress, nil
+}
+
+// CompleteEnrollment marks a student's enrollment in a course as completed
+func (p *PostgresDB) CompleteEnrollment(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+	completedAt time.Time,
+) error {
+	err := p.DB.Model(&domain.StudentCourse{}).
+		Where("student_uuid = ? AND course_uuid = ?", *studentUUID, *courseUUID).
+		Updates(map[string]interface{}{
+			"status":       domain.EnrollmentStatusCompleted,
+			"completed_at": completedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("%w: can't complete enrollment: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// CreateQuiz creates a quiz together with its questions
+func (p *PostgresDB) CreateQuiz(
+	ctx context.Context,
+	quiz *domain.Quiz,
+) (*domain.Quiz, error) {
+	if err := p.DB.Create(quiz).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new quiz: %v", repository.ErrStorage, err)
+	}
+	return quiz, nil
+}
+
+// CreateAttempt saves a graded quiz attempt as long as the student has an
+// attempt left, when maxAttempts limits them. The student's enrollment in the
+// quiz's course is locked while their attempts are counted, so concurrent
+// submissions can't go over the limit, and ErrNoAttemptsLeft is returned
+// when there's none left
+func (p *PostgresDB) CreateAttempt(
+	ctx context.Context,
+	attempt *domain.Attempt,
+	courseUUID string,
+	maxAttempts uint,
+) (*domain.Attempt, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var enrollment domain.StudentCourse
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("student_uuid = ? AND course_uuid = ?", attempt.StudentUUID, courseUUID).
+			First(&enrollment).Error
+		if err != nil {
+			return err
+		}
+		if maxAttempts > 0 {
+			var count int64
+			err := tx.Model(&domain.Attempt{}).
+				Where("quiz_uuid = ? AND student_uuid = ?", attempt.QuizUUID, attempt.StudentUUID).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count >= int64(maxAttempts) {
+				return domain.ErrNoAttemptsLeft
+			}
+		}
+		return tx.Create(attempt).Error
+	})
+	if errors.Is(err, domain.ErrNoAttemptsLeft) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create a new attempt: %v", repository.ErrStorage, err)
+	}
+	return attempt, nil
+}
+
+// GetQuiz returns a quiz with its questions in order
+func (p *PostgresDB) GetQuiz(
+	ctx context.Context,
+	quizUUID *string,
+) (*domain.Quiz, error) {
+	var quiz domain.Quiz
+	err := p.DB.
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("uuid = ?", *quizUUID).
+		Find(&quiz).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get quiz: %v err: %v", repository.ErrStorage, *quizUUID, err)
+	}
+	if quiz.UUID == "" {
+		return nil, nil
+	}
+
+	return &quiz, nil
+}
+
+// GetStudentByUUID returns a single student
+func (p *PostgresDB) GetStudentByUUID(
+	ctx context.Context,
+	studentUUID *string,
+) (*domain.Student, error) {
+	var student domain.Student
+	if err := p.DB.Where("uuid = ?", *studentUUID).Find(&student).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get student: %v err: %v", repository.ErrStorage, *studentUUID, err)
 	}
 	if student.UUID == "" {
 		return nil, nil
@@ -150,13 +1028,2692 @@ func (p *PostgresDB) GetStudent(
 	return &student, nil
 }
 
+// GetCourseByUUID returns a single course
+func (p *PostgresDB) GetCourseByUUID(
+	ctx context.Context,
+	courseUUID *string,
+) (*domain.Course, error) {
+	var course domain.Course
+	if err := p.DB.Where("uuid = ?", *courseUUID).Find(&course).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get course: %v err: %v", repository.ErrStorage, *courseUUID, err)
+	}
+	if course.UUID == "" {
+		return nil, nil
+	}
+
+	return &course, nil
+}
+
+// CreateCertificate saves an issued certificate
+func (p *PostgresDB) CreateCertificate(
+	ctx context.Context,
+	certificate *domain.Certificate,
+) (*domain.Certificate, error) {
+	if err := p.DB.Create(certificate).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new certificate: %v", repository.ErrStorage, err)
+	}
+	return certificate, nil
+}
+
+// GetCertificate returns a certificate by its serial
+func (p *PostgresDB) GetCertificate(
+	ctx context.Context,
+	serial *string,
+) (*domain.Certificate, error) {
+	var certificate domain.Certificate
+	if err := p.DB.Where("serial = ?", *serial).Find(&certificate).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get certificate: %v err: %v", repository.ErrStorage, *serial, err)
+	}
+	if certificate.UUID == "" {
+		return nil, nil
+	}
+
+	return &certificate, nil
+}
+
+// GetStudentCertificate returns the certificate a student holds for a course
+func (p *PostgresDB) GetStudentCertificate(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) (*domain.Certificate, error) {
+	var certificate domain.Certificate
+	err := p.DB.Where("student_uuid = ? AND course_uuid = ?", *studentUUID, *courseUUID).
+		Find(&certificate).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get student's certificate: %v", repository.ErrStorage, err)
+	}
+	if certificate.UUID == "" {
+		return nil, nil
+	}
+
+	return &certificate, nil
+}
+
+// RevokeCertificate marks a certificate as revoked
+func (p *PostgresDB) RevokeCertificate(
+	ctx context.Context,
+	serial *string,
+	reason string,
+	revokedAt time.Time,
+) error {
+	result := p.DB.Model(&domain.Certificate{}).
+		Where("serial = ?", *serial).
+		Updates(map[string]interface{}{
+			"revoked_at":        revokedAt,
+			"revocation_reason": reason,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("%w: can't revoke certificate: %v", repository.ErrStorage, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return &domain.NotFoundError{Kind: "certificate", Key: *serial}
+	}
+	return nil
+}
+
+// AddCoursePrerequisite records that a course requires another course, unless
+// the other course already (indirectly) requires it. The check and the insert
+// run in one transaction with both courses locked, so prerequisites added
+// concurrently can't close a cycle between them
+func (p *PostgresDB) AddCoursePrerequisite(
+	ctx context.Context,
+	prerequisite *domain.CoursePrerequisite,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		// both courses are locked in the same order by everyone, so adding
+		// prerequisites concurrently can't deadlock
+		uuids := []string{prerequisite.CourseUUID, prerequisite.PrerequisiteUUID}
+		if uuids[1] < uuids[0] {
+			uuids[0], uuids[1] = uuids[1], uuids[0]
+		}
+		for _, uuid := range uuids {
+			if _, err := lockCourse(tx, "uuid = ?", uuid); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return &domain.NotFoundError{Kind: "course", Key: uuid}
+				}
+				return err
+			}
+		}
+		// prerequisites between other courses can close the same cycle, so
+		// the graph is only changed by one transaction at a time
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('course_prerequisites'))").Error; err != nil {
+			return err
+		}
+		var cycle bool
+		err := tx.Raw(`WITH RECURSIVE required(uuid) AS (
+				SELECT prerequisite_uuid FROM course_prerequisites WHERE course_uuid = ?
+				UNION
+				SELECT course_prerequisites.prerequisite_uuid FROM course_prerequisites
+				JOIN required ON course_prerequisites.course_uuid = required.uuid
+			)
+			SELECT EXISTS (SELECT 1 FROM required WHERE uuid = ?)`,
+			prerequisite.PrerequisiteUUID, prerequisite.CourseUUID).
+			Scan(&cycle).Error
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return domain.ErrPrerequisiteCycle
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(prerequisite).Error
+	})
+	var notFound *domain.NotFoundError
+	if errors.Is(err, domain.ErrPrerequisiteCycle) || errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: can't add course prerequisite: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// GetCoursePrerequisites returns the courses that directly precede a course
+func (p *PostgresDB) GetCoursePrerequisites(
+	ctx context.Context,
+	courseUUID *string,
+) ([]*domain.Course, error) {
+	var courses []*domain.Course
+	err := p.DB.Joins("JOIN course_prerequisites ON course_prerequisites.prerequisite_uuid = courses.uuid").
+		Where("course_prerequisites.course_uuid = ?", *courseUUID).
+		Order("courses.title").
+		Find(&courses).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get course prerequisites: %v", repository.ErrStorage, err)
+	}
+	return courses, nil
+}
+
+// RemoveCoursePrerequisite removes a prerequisite from a course
+func (p *PostgresDB) RemoveCoursePrerequisite(
+	ctx context.Context,
+	courseUUID *string,
+	prerequisiteUUID *string,
+) error {
+	err := p.DB.Where("course_uuid = ? AND prerequisite_uuid = ?", *courseUUID, *prerequisiteUUID).
+		Delete(&domain.CoursePrerequisite{}).Error
+	if err != nil {
+		return fmt.Errorf("%w: can't remove course prerequisite: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// JoinWaitlist queues a student for a full course. Joining twice keeps the
+// student's original place
+func (p *PostgresDB) JoinWaitlist(
+	ctx context.Context,
+	entry *domain.WaitlistEntry,
+) (*domain.WaitlistEntry, error) {
+	err := p.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_uuid"}, {Name: "student_uuid"}},
+		DoNothing: true,
+	}).Create(entry).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't join waitlist: %v", repository.ErrStorage, err)
+	}
+	return p.GetWaitlistEntry(ctx, &entry.CourseUUID, &entry.StudentUUID)
+}
+
+// GetWaitlist returns a course's waitlist in the order students will be served
+func (p *PostgresDB) GetWaitlist(
+	ctx context.Context,
+	courseUUID *string,
+) ([]*domain.WaitlistEntry, error) {
+	var entries []*domain.WaitlistEntry
+	err := p.DB.Where("course_uuid = ?", *courseUUID).
+		Order("created_at ASC, uuid ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get waitlist: %v", repository.ErrStorage, err)
+	}
+	for i, entry := range entries {
+		entry.Position = int64(i + 1)
+	}
+	return entries, nil
+}
+
+// GetWaitlistEntry returns a student's place on a course's waitlist
+func (p *PostgresDB) GetWaitlistEntry(
+	ctx context.Context,
+	courseUUID *string,
+	studentUUID *string,
+) (*domain.WaitlistEntry, error) {
+	var entry domain.WaitlistEntry
+	err := p.DB.Where("course_uuid = ? AND student_uuid = ?", *courseUUID, *studentUUID).
+		Find(&entry).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get waitlist entry: %v", repository.ErrStorage, err)
+	}
+	if entry.UUID == "" {
+		return nil, nil
+	}
+
+	var ahead int64
+	err = p.DB.Model(&domain.WaitlistEntry{}).
+		Where("course_uuid = ? AND (created_at < ? OR (created_at = ? AND uuid < ?))",
+			*courseUUID, entry.CreatedAt, entry.CreatedAt, entry.UUID).
+		Count(&ahead).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get waitlist position: %v", repository.ErrStorage, err)
+	}
+	entry.Position = ahead + 1
+	return &entry, nil
+}
+
+// UnenrollStudent removes a student from a course and reverses the sale of
+// their enrollment
+func (p *PostgresDB) UnenrollStudent(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		// held so the seat isn't counted while it is being freed
+		_, err := lockCourse(tx, "uuid = ?", *courseUUID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &domain.NotFoundError{Kind: "course", Key: *courseUUID}
+		}
+		if err != nil {
+			return err
+		}
+		result := tx.Where("student_uuid = ? AND course_uuid = ?", *studentUUID, *courseUUID).
+			Delete(&domain.StudentCourse{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &domain.NotFoundError{Kind: "enrollment", Key: *studentUUID + " in course " + *courseUUID}
+		}
+		return reverseSale(tx, *studentUUID, *courseUUID, time.Now())
+	})
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: can't unenroll student: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// PromoteWaitlistEntry enrolls a waitlisted student into the run they are
+// waiting for and takes them off the waitlist. The course's row is locked
+// while its seats are counted, and ErrCourseFull is returned when there's no
+// seat left. An entry that has left the waitlist in the meantime isn't found
+func (p *PostgresDB) PromoteWaitlistEntry(
+	ctx context.Context,
+	entry *domain.WaitlistEntry,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, "uuid = ?", entry.CourseUUID)
+		if err != nil {
+			return err
+		}
+		full, err := courseIsFull(tx, course)
+		if err != nil {
+			return err
+		}
+		if full {
+			return domain.ErrCourseFull
+		}
+		var waiting domain.WaitlistEntry
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uuid = ?", entry.UUID).
+			Find(&waiting).Error
+		if err != nil {
+			return err
+		}
+		if waiting.UUID == "" {
+			return &domain.NotFoundError{Kind: "waitlist entry", Key: entry.UUID}
+		}
+		run, err := enrollmentRun(tx, course, &waiting.RunUUID)
+		if err != nil {
+			return err
+		}
+		return enroll(tx, waiting.StudentUUID, course, run.UUID, nil)
+	})
+	var notFound *domain.NotFoundError
+	if errors.Is(err, domain.ErrCourseFull) || errors.Is(err, domain.ErrCourseNotPriced) || errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: can't promote waitlisted student: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// LeaveWaitlist removes a student from a course's waitlist
+func (p *PostgresDB) LeaveWaitlist(
+	ctx context.Context,
+	courseUUID *string,
+	studentUUID *string,
+) error {
+	err := p.DB.Unscoped().
+		Where("course_uuid = ? AND student_uuid = ?", *courseUUID, *studentUUID).
+		Delete(&domain.WaitlistEntry{}).Error
+	if err != nil {
+		return fmt.Errorf("%w: can't leave waitlist: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// lockCourse loads a course and holds its row lock until the transaction
+// ends, which serializes seat counting for the course
+func lockCourse(tx *gorm.DB, query string, args ...interface{}) (*domain.Course, error) {
+	course := &domain.Course{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(query, args...).First(course).Error
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// courseIsFull reports whether every seat of a course is taken. Courses
+// without a capacity never fill up
+func courseIsFull(tx *gorm.DB, course *domain.Course) (bool, error) {
+	if course.Capacity == 0 {
+		return false, nil
+	}
+	var taken int64
+	err := tx.Model(&domain.StudentCourse{}).
+		Where("course_uuid = ? AND status = ?", course.UUID, domain.EnrollmentStatusActive).
+		Count(&taken).Error
+	if err != nil {
+		return false, err
+	}
+	return taken >= int64(course.Capacity), nil
+}
+
+// enroll links a student to a course run, records the sale and removes them
+// from the course's waitlist
+func enroll(
+	tx *gorm.DB,
+	studentUUID string,
+	course *domain.Course,
+	runUUID string,
+	redemption *domain.CouponRedemption,
+) error {
+	now := time.Now()
+	link := domain.StudentCourse{
+		StudentUUID: studentUUID,
+		CourseUUID:  course.UUID,
+		RunUUID:     runUUID,
+		Status:      domain.EnrollmentStatusActive,
+		EnrolledAt:  &now,
+	}
+	if err := tx.Create(link).Error; err != nil {
+		return err
+	}
+	if err := recordSale(tx, studentUUID, course, redemption, now); err != nil {
+		return err
+	}
+	return tx.Unscoped().
+		Where("course_uuid = ? AND student_uuid = ?", course.UUID, studentUUID).
+		Delete(&domain.WaitlistEntry{}).Error
+}
+
+// recordSale records a student's enrollment in a course at the course's list
+// price at the time, or the price the coupon used at checkout was applied to,
+// and allocates it its revenue in the ledger. Free courses earn nothing so
+// they don't post a journal
+func recordSale(
+	tx *gorm.DB,
+	studentUUID string,
+	course *domain.Course,
+	redemption *domain.CouponRedemption,
+	soldAt time.Time,
+) error {
+	sale := &domain.CourseSale{
+		StudentUUID: studentUUID,
+		CourseUUID:  course.UUID,
+		SoldAt:      soldAt,
+	}
+	if course.CategoryUUID != nil {
+		sale.CategoryUUID = *course.CategoryUUID
+	}
+	if course.InstructorUUID != nil {
+		sale.InstructorUUID = *course.InstructorUUID
+	}
+	if redemption != nil {
+		sale.ListPrice = redemption.OriginalPrice
+		sale.Discount = redemption.Discount
+		sale.CouponRedemptionUUID = redemption.UUID
+	} else {
+		price, err := listPrice(tx, course, soldAt)
+		if err != nil {
+			return err
+		}
+		sale.ListPrice = price
+		sale.Discount = domain.Money{Currency: price.Currency}
+	}
+	if err := tx.Create(sale).Error; err != nil {
+		return err
+	}
+	if sale.ListPrice.Amount == 0 {
+		return nil
+	}
+	return postJournal(tx, sale.Journal())
+}
+
+// reverseSale takes back the revenue of a student's latest sale of a course
+// that isn't reversed yet. Sales are only reversed once, so unenrolling and
+// refunding the same enrollment doesn't reverse it twice
+func reverseSale(tx *gorm.DB, studentUUID string, courseUUID string, reversedAt time.Time) error {
+	var sale domain.CourseSale
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("student_uuid = ? AND course_uuid = ? AND reversed_at IS NULL", studentUUID, courseUUID).
+		Order("sold_at DESC").
+		Limit(1).
+		Find(&sale).Error
+	if err != nil {
+		return err
+	}
+	if sale.UUID == "" {
+		return nil
+	}
+	if err := tx.Model(&sale).Update("reversed_at", reversedAt).Error; err != nil {
+		return err
+	}
+	if sale.ListPrice.Amount == 0 {
+		return nil
+	}
+	return postJournal(tx, sale.ReversalJournal(reversedAt))
+}
+
+// listPrice returns a course's price in the base currency at a point in time.
+// A course that isn't listed in the base currency then returns
+// ErrCourseNotPriced, so a sale is never recorded without its price
+func listPrice(tx *gorm.DB, course *domain.Course, at time.Time) (domain.Money, error) {
+	var price domain.CoursePrice
+	err := tx.Where("course_uuid = ? AND price_currency = ? AND effective_from <= ?", course.UUID, domain.BaseCurrency, at).
+		Order("effective_from DESC").
+		Limit(1).
+		Find(&price).Error
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if price.UUID == "" {
+		return domain.Money{}, fmt.Errorf("%w: %s in %s", domain.ErrCourseNotPriced, course.UUID, domain.BaseCurrency)
+	}
+	return price.Price, nil
+}
+
+// enrollmentRun returns the run a student enrolling in a course joins: the
+// requested run, or the course's evergreen run which is created on first use
+func enrollmentRun(tx *gorm.DB, course *domain.Course, runUUID *string) (*domain.CourseRun, error) {
+	run := &domain.CourseRun{}
+	if runUUID != nil && *runUUID != "" {
+		err := tx.Where("uuid = ? AND course_uuid = ?", *runUUID, course.UUID).First(run).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("run %s is not a run of course %s", *runUUID, course.Title)
+		}
+		return run, err
+	}
+
+	if err := tx.Where("course_uuid = ? AND evergreen = ?", course.UUID, true).Find(run).Error; err != nil {
+		return nil, err
+	}
+	if run.UUID != "" {
+		return run, nil
+	}
+	run = &domain.CourseRun{
+		CourseUUID: course.UUID,
+		Name:       fmt.Sprintf("%s (self-paced)", course.Title),
+		Timezone:   "UTC",
+		Evergreen:  true,
+	}
+	if err := tx.Create(run).Error; err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// CreateCourseRun schedules a new run of a course
+func (p *PostgresDB) CreateCourseRun(
+	ctx context.Context,
+	run *domain.CourseRun,
+) (*domain.CourseRun, error) {
+	if err := p.DB.Create(run).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new course run: %v", repository.ErrStorage, err)
+	}
+	return run, nil
+}
+
+// CreateLiveSession schedules a live session of a course run
+func (p *PostgresDB) CreateLiveSession(
+	ctx context.Context,
+	session *domain.LiveSession,
+) (*domain.LiveSession, error) {
+	if err := p.DB.Create(session).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new live session: %v", repository.ErrStorage, err)
+	}
+	return session, nil
+}
+
+// GetCourseRun returns a course run with its live sessions in order
+func (p *PostgresDB) GetCourseRun(
+	ctx context.Context,
+	runUUID *string,
+) (*domain.CourseRun, error) {
+	var run domain.CourseRun
+	err := p.DB.
+		Preload("Sessions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("starts_at ASC")
+		}).
+		Where("uuid = ?", *runUUID).
+		Find(&run).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get course run: %v err: %v", repository.ErrStorage, *runUUID, err)
+	}
+	if run.UUID == "" {
+		return nil, nil
+	}
+
+	return &run, nil
+}
+
+// GetUpcomingCourseRuns returns the scheduled runs of a course that have not
+// ended, soonest first
+func (p *PostgresDB) GetUpcomingCourseRuns(
+	ctx context.Context,
+	courseUUID *string,
+	after time.Time,
+) ([]*domain.CourseRun, error) {
+	var runs []*domain.CourseRun
+	err := p.DB.
+		Where("course_uuid = ? AND evergreen = ? AND (end_date IS NULL OR end_date >= ?)", *courseUUID, false, after).
+		Order("start_date ASC").
+		Find(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get upcoming course runs: %v", repository.ErrStorage, err)
+	}
+	return runs, nil
+}
+
+// SaveCalendarSubscription creates or replaces a student's calendar token
+func (p *PostgresDB) SaveCalendarSubscription(
+	ctx context.Context,
+	subscription *domain.CalendarSubscription,
+) (*domain.CalendarSubscription, error) {
+	if err := p.DB.Save(subscription).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't save calendar subscription: %v", repository.ErrStorage, err)
+	}
+	return subscription, nil
+}
+
+// GetCalendarSubscription returns a student's calendar token
+func (p *PostgresDB) GetCalendarSubscription(
+	ctx context.Context,
+	studentUUID *string,
+) (*domain.CalendarSubscription, error) {
+	var subscription domain.CalendarSubscription
+	if err := p.DB.Where("student_uuid = ?", *studentUUID).Find(&subscription).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get calendar subscription: %v", repository.ErrStorage, err)
+	}
+	if subscription.StudentUUID == "" {
+		return nil, nil
+	}
+
+	return &subscription, nil
+}
+
+// currentListPrice is the SQL expression for a course's current price in the
+// base currency, in minor units, as its price list has it. It is NULL for a
+// course that isn't priced in the base currency
+var currentListPrice = fmt.Sprintf(`(SELECT course_prices.price_amount FROM course_prices
+	WHERE course_prices.course_uuid = courses.uuid AND course_prices.price_currency = '%s'
+	AND course_prices.effective_from <= NOW()
+	ORDER BY course_prices.effective_from DESC LIMIT 1)`, domain.BaseCurrency)
+
+// courseOrders maps each listing sort key to its ORDER BY clause. Ties fall
+// back to the title so pages are stable, and unpriced courses come last when
+// sorting by price
+var courseOrders = map[domain.CourseSort]string{
+	domain.CourseSortTitle:  "title ASC",
+	domain.CourseSortNewest: "created_at DESC, title ASC",
+	domain.CourseSortPrice:  currentListPrice + " ASC NULLS LAST, title ASC",
+	domain.CourseSortRating: "average_rating DESC, rating_count DESC, title ASC",
+}
+
+// ListCourses returns every course in the given order
+func (p *PostgresDB) ListCourses(
+	ctx context.Context,
+	sort domain.CourseSort,
+) ([]*domain.Course, error) {
+	order, ok := courseOrders[sort]
+	if !ok {
+		order = courseOrders[domain.CourseSortTitle]
+	}
+	var courses []*domain.Course
+	if err := p.DB.Order(order).Find(&courses).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't list courses: %v", repository.ErrStorage, err)
+	}
+	return courses, nil
+}
+
+// SaveReview creates a student's review of a course or replaces the one they
+// already wrote, then refreshes the course's rating
+func (p *PostgresDB) SaveReview(
+	ctx context.Context,
+	review *domain.Review,
+) (*domain.Review, error) {
+	var saved domain.Review
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockCourse(tx, "uuid = ?", review.CourseUUID); err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "course_uuid"}, {Name: "student_uuid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "text", "status", "updated_at"}),
+		}).Create(review).Error
+		if err != nil {
+			return err
+		}
+		// on conflict the stored row keeps its own UUID, so read it back
+		err = tx.Where("course_uuid = ? AND student_uuid = ?", review.CourseUUID, review.StudentUUID).
+			First(&saved).Error
+		if err != nil {
+			return err
+		}
+		return refreshCourseRating(tx, review.CourseUUID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't save review: %v", repository.ErrStorage, err)
+	}
+	return &saved, nil
+}
+
+// ModerateReview changes a review's moderation status and refreshes the
+// course's rating
+func (p *PostgresDB) ModerateReview(
+	ctx context.Context,
+	reviewUUID *string,
+	status domain.ReviewStatus,
+) (*domain.Review, error) {
+	var review domain.Review
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("uuid = ?", *reviewUUID).First(&review).Error; err != nil {
+			return err
+		}
+		if _, err := lockCourse(tx, "uuid = ?", review.CourseUUID); err != nil {
+			return err
+		}
+		if err := tx.Model(&review).Update("status", status).Error; err != nil {
+			return err
+		}
+		return refreshCourseRating(tx, review.CourseUUID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't moderate review: %v", repository.ErrStorage, err)
+	}
+	return &review, nil
+}
+
+// refreshCourseRating recomputes a course's denormalized rating from its
+// approved reviews. Callers hold the course's row lock so concurrent reviews
+// can't overwrite each other's totals
+func refreshCourseRating(tx *gorm.DB, courseUUID string) error {
+	var summary struct {
+		Average float64
+		Count   uint
+	}
+	err := tx.Model(&domain.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("course_uuid = ? AND status = ?", courseUUID, domain.ReviewStatusApproved).
+		Scan(&summary).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&domain.Course{}).
+		Where("uuid = ?", courseUUID).
+		Updates(map[string]interface{}{
+			"average_rating": summary.Average,
+			"rating_count":   summary.Count,
+		}).Error
+}
+
+// GetReview returns a review by its UUID
+func (p *PostgresDB) GetReview(
+	ctx context.Context,
+	reviewUUID *string,
+) (*domain.Review, error) {
+	var review domain.Review
+	if err := p.DB.Where("uuid = ?", *reviewUUID).Find(&review).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get review: %v", repository.ErrStorage, err)
+	}
+	if review.UUID == "" {
+		return nil, nil
+	}
+
+	return &review, nil
+}
+
+// GetCourseReviews returns a course's reviews with the given moderation
+// status, newest first
+func (p *PostgresDB) GetCourseReviews(
+	ctx context.Context,
+	courseUUID *string,
+	status domain.ReviewStatus,
+) ([]*domain.Review, error) {
+	var reviews []*domain.Review
+	err := p.DB.Where("course_uuid = ? AND status = ?", *courseUUID, status).
+		Order("updated_at DESC").
+		Find(&reviews).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get course reviews: %v", repository.ErrStorage, err)
+	}
+	return reviews, nil
+}
+
+// SearchCourses returns the courses matching a query, best first. The query
+// is matched against the courses' search vectors, and against their titles by
+// trigram similarity so misspelt words still find courses
+func (p *PostgresDB) SearchCourses(
+	ctx context.Context,
+	query *domain.CourseSearchQuery,
+) (*domain.CourseSearchResults, error) {
+	matching := func(db *gorm.DB) *gorm.DB {
+		return db.Model(&domain.Course{}).
+			Where("search_vector @@ websearch_to_tsquery('english'::regconfig, ?) OR ? <% title", query.Text, query.Text)
+	}
+	filtered := func(db *gorm.DB) *gorm.DB {
+		if query.Category != "" {
+			db = db.Where("LOWER(category) = LOWER(?)", query.Category)
+		}
+		if query.Instructor != "" {
+			db = db.Where("LOWER(instructor) = LOWER(?)", query.Instructor)
+		}
+		if band, ok := domain.LookupPriceBand(query.PriceBand); ok {
+			db = db.Where(currentListPrice+" >= ?", bandAmount(band.Min))
+			if band.Max != 0 {
+				db = db.Where(currentListPrice+" < ?", bandAmount(band.Max))
+			}
+		}
+		return db
+	}
+
+	var ranked []struct {
+		UUID string
+		Rank float64
+	}
+	err := p.DB.Scopes(matching, filtered).
+		Select(
+			"uuid, ts_rank(search_vector, websearch_to_tsquery('english'::regconfig, ?)) + word_similarity(?, title) AS rank",
+			query.Text, query.Text,
+		).
+		Order("rank DESC, title ASC").
+		Limit(query.Limit).
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't search courses: %v", repository.ErrStorage, err)
+	}
+	uuids := make([]string, 0, len(ranked))
+	for _, row := range ranked {
+		uuids = append(uuids, row.UUID)
+	}
+	var courses []*domain.Course
+	if err := p.DB.Where("uuid IN ?", uuids).Find(&courses).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get matching courses: %v", repository.ErrStorage, err)
+	}
+	byUUID := make(map[string]*domain.Course, len(courses))
+	for _, course := range courses {
+		byUUID[course.UUID] = course
+	}
+	hits := make([]*domain.CourseSearchHit, 0, len(ranked))
+	for _, row := range ranked {
+		if course, ok := byUUID[row.UUID]; ok {
+			hits = append(hits, &domain.CourseSearchHit{Course: course, Rank: row.Rank})
+		}
+	}
+
+	results := &domain.CourseSearchResults{Hits: hits}
+	if results.Facets.Categories, err = p.facet(matching, "category"); err != nil {
+		return nil, err
+	}
+	if results.Facets.Instructors, err = p.facet(matching, "instructor"); err != nil {
+		return nil, err
+	}
+	bands, err := p.facet(matching, priceBandCase())
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(bands))
+	for _, band := range bands {
+		counts[band.Value] = band.Count
+	}
+	results.Facets.PriceBands = []domain.FacetCount{}
+	for _, band := range domain.PriceBands {
+		if count := counts[band.Name]; count > 0 {
+			results.Facets.PriceBands = append(results.Facets.PriceBands, domain.FacetCount{Value: band.Name, Count: count})
+		}
+	}
+	return results, nil
+}
+
+// facet counts the courses in scope by the value of an expression, most
+// common first
+func (p *PostgresDB) facet(
+	scope func(*gorm.DB) *gorm.DB,
+	expression string,
+) ([]domain.FacetCount, error) {
+	facets := []domain.FacetCount{}
+	err := p.DB.Scopes(scope).
+		Select(fmt.Sprintf("%s AS value, COUNT(*) AS count", expression)).
+		Where(fmt.Sprintf("%s <> ''", expression)).
+		Group("value").
+		Order("count DESC, value ASC").
+		Scan(&facets).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't count search facets: %v", repository.ErrStorage, err)
+	}
+	return facets, nil
+}
+
+// priceBandCase builds the SQL expression naming the price band of a course
+// by its current list price. Unpriced courses aren't in any band
+func priceBandCase() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, band := range domain.PriceBands {
+		if band.Max == 0 {
+			fmt.Fprintf(&b, " WHEN %s >= %d THEN '%s'", currentListPrice, bandAmount(band.Min), band.Name)
+			continue
+		}
+		fmt.Fprintf(&b, " WHEN %s >= %d AND %s < %d THEN '%s'",
+			currentListPrice, bandAmount(band.Min), currentListPrice, bandAmount(band.Max), band.Name)
+	}
+	b.WriteString(" ELSE '' END")
+	return b.String()
+}
+
+// bandAmount converts a price band bound, in whole units of the base
+// currency, into the minor units price lists are kept in
+func bandAmount(units uint) int64 {
+	price, err := domain.MoneyFromMajor(units, domain.BaseCurrency)
+	if err != nil {
+		// the base currency is always supported
+		panic(err)
+	}
+	return price.Amount
+}
+
+// CreateCategory creates a new catalog category
+func (p *PostgresDB) CreateCategory(
+	ctx context.Context,
+	category *domain.Category,
+) (*domain.Category, error) {
+	if err := p.DB.Create(category).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new category: %v", repository.ErrStorage, err)
+	}
+	return category, nil
+}
+
+// GetCategory returns a category with its direct subcategories
+func (p *PostgresDB) GetCategory(
+	ctx context.Context,
+	categoryUUID *string,
+) (*domain.Category, error) {
+	var category domain.Category
+	err := p.DB.Preload("Children", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC")
+	}).Where("uuid = ?", *categoryUUID).Find(&category).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get category: %v", repository.ErrStorage, err)
+	}
+	if category.UUID == "" {
+		return nil, nil
+	}
+
+	return &category, nil
+}
+
+// GetCategoryBySlug returns the category with the given slug
+func (p *PostgresDB) GetCategoryBySlug(
+	ctx context.Context,
+	slug string,
+) (*domain.Category, error) {
+	var category domain.Category
+	if err := p.DB.Where("slug = ?", slug).Find(&category).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get category by slug: %v", repository.ErrStorage, err)
+	}
+	if category.UUID == "" {
+		return nil, nil
+	}
+
+	return &category, nil
+}
+
+// ListCategories returns every category by name
+func (p *PostgresDB) ListCategories(
+	ctx context.Context,
+) ([]*domain.Category, error) {
+	var categories []*domain.Category
+	if err := p.DB.Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't list categories: %v", repository.ErrStorage, err)
+	}
+	return categories, nil
+}
+
+// UpdateCategory updates a category and the category name of its courses
+func (p *PostgresDB) UpdateCategory(
+	ctx context.Context,
+	category *domain.Category,
+) (*domain.Category, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(category).
+			Select("name", "slug", "parent_uuid").
+			Updates(category)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &domain.NotFoundError{Kind: "category", Key: category.UUID}
+		}
+		return tx.Model(&domain.Course{}).
+			Where("category_uuid = ?", category.UUID).
+			Update("category", category.Name).Error
+	})
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't update category: %v", repository.ErrStorage, err)
+	}
+	return category, nil
+}
+
+// DeleteCategory deletes a category no course or subcategory uses. Categories
+// are removed for good so their slug can be reused
+func (p *PostgresDB) DeleteCategory(
+	ctx context.Context,
+	categoryUUID *string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var courses, children int64
+		if err := tx.Model(&domain.Course{}).Where("category_uuid = ?", *categoryUUID).Count(&courses).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Category{}).Where("parent_uuid = ?", *categoryUUID).Count(&children).Error; err != nil {
+			return err
+		}
+		if courses > 0 || children > 0 {
+			return fmt.Errorf("%w: category is used by %d courses and %d subcategories", domain.ErrInUse, courses, children)
+		}
+		return tx.Unscoped().Where("uuid = ?", *categoryUUID).Delete(&domain.Category{}).Error
+	})
+	if errors.Is(err, domain.ErrInUse) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: can't delete category: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// CreateInstructor creates a new instructor profile
+func (p *PostgresDB) CreateInstructor(
+	ctx context.Context,
+	instructor *domain.Instructor,
+) (*domain.Instructor, error) {
+	if err := p.DB.Create(instructor).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new instructor: %v", repository.ErrStorage, err)
+	}
+	return instructor, nil
+}
+
+// GetInstructor returns an instructor's profile
+func (p *PostgresDB) GetInstructor(
+	ctx context.Context,
+	instructorUUID *string,
+) (*domain.Instructor, error) {
+	var instructor domain.Instructor
+	if err := p.DB.Where("uuid = ?", *instructorUUID).Find(&instructor).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get instructor: %v", repository.ErrStorage, err)
+	}
+	if instructor.UUID == "" {
+		return nil, nil
+	}
+
+	return &instructor, nil
+}
+
+// FindInstructorByName returns the instructor with the given name, ignoring
+// case and surrounding spaces
+func (p *PostgresDB) FindInstructorByName(
+	ctx context.Context,
+	name string,
+) (*domain.Instructor, error) {
+	var instructor domain.Instructor
+	err := p.DB.Where("LOWER(TRIM(name)) = LOWER(TRIM(?))", name).
+		Order("created_at ASC").
+		Limit(1).
+		Find(&instructor).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't find instructor by name: %v", repository.ErrStorage, err)
+	}
+	if instructor.UUID == "" {
+		return nil, nil
+	}
+
+	return &instructor, nil
+}
+
+// ListInstructors returns every instructor by name
+func (p *PostgresDB) ListInstructors(
+	ctx context.Context,
+) ([]*domain.Instructor, error) {
+	var instructors []*domain.Instructor
+	if err := p.DB.Order("name ASC").Find(&instructors).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't list instructors: %v", repository.ErrStorage, err)
+	}
+	return instructors, nil
+}
+
+// UpdateInstructor updates an instructor's profile and the instructor name
+// of their courses
+func (p *PostgresDB) UpdateInstructor(
+	ctx context.Context,
+	instructor *domain.Instructor,
+) (*domain.Instructor, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(instructor).
+			Select("name", "bio", "avatar_url", "user_uuid").
+			Updates(instructor)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &domain.NotFoundError{Kind: "instructor", Key: instructor.UUID}
+		}
+		return tx.Model(&domain.Course{}).
+			Where("instructor_uuid = ?", instructor.UUID).
+			Update("instructor", instructor.Name).Error
+	})
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't update instructor: %v", repository.ErrStorage, err)
+	}
+	return instructor, nil
+}
+
+// DeleteInstructor deletes an instructor who doesn't teach any course.
+// Instructors are removed for good so their user can be linked again
+func (p *PostgresDB) DeleteInstructor(
+	ctx context.Context,
+	instructorUUID *string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var courses int64
+		if err := tx.Model(&domain.Course{}).Where("instructor_uuid = ?", *instructorUUID).Count(&courses).Error; err != nil {
+			return err
+		}
+		if courses > 0 {
+			return fmt.Errorf("%w: instructor teaches %d courses", domain.ErrInUse, courses)
+		}
+		return tx.Unscoped().Where("uuid = ?", *instructorUUID).Delete(&domain.Instructor{}).Error
+	})
+	if errors.Is(err, domain.ErrInUse) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: can't delete instructor: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// CreateCoursePrice adds an entry to a course's price list
+func (p *PostgresDB) CreateCoursePrice(
+	ctx context.Context,
+	price *domain.CoursePrice,
+) (*domain.CoursePrice, error) {
+	if err := p.DB.Create(price).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new course price: %v", repository.ErrStorage, err)
+	}
+	return price, nil
+}
+
+// GetCurrentCoursePrices returns the price of a course in each currency it is
+// listed in, as of the given time
+func (p *PostgresDB) GetCurrentCoursePrices(
+	ctx context.Context,
+	courseUUID *string,
+	at time.Time,
+) ([]*domain.CoursePrice, error) {
+	var prices []*domain.CoursePrice
+	err := p.DB.Clauses(clause.Select{Expression: clause.Expr{SQL: "DISTINCT ON (price_currency) *"}}).
+		Where("course_uuid = ? AND effective_from <= ?", *courseUUID, at).
+		Order("price_currency ASC, effective_from DESC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get current course prices: %v", repository.ErrStorage, err)
+	}
+	return prices, nil
+}
+
+// GetCoursePriceHistory returns every price a course has had or is scheduled
+// to have, oldest first, in one currency or in all of them when none is given
+func (p *PostgresDB) GetCoursePriceHistory(
+	ctx context.Context,
+	courseUUID *string,
+	currency string,
+) ([]*domain.CoursePrice, error) {
+	query := p.DB.Where("course_uuid = ?", *courseUUID)
+	if currency != "" {
+		query = query.Where("price_currency = ?", currency)
+	}
+	var prices []*domain.CoursePrice
+	if err := query.Order("effective_from ASC, price_currency ASC").Find(&prices).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get course price history: %v", repository.ErrStorage, err)
+	}
+	return prices, nil
+}
+
+// CreateCoupon creates a coupon together with the courses and categories it
+// is limited to
+func (p *PostgresDB) CreateCoupon(
+	ctx context.Context,
+	coupon *domain.Coupon,
+) (*domain.Coupon, error) {
+	if err := p.DB.Create(coupon).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new coupon: %v", repository.ErrStorage, err)
+	}
+	return coupon, nil
+}
+
+// GetCoupon returns a coupon with the courses and categories it is limited to
+func (p *PostgresDB) GetCoupon(
+	ctx context.Context,
+	couponUUID *string,
+) (*domain.Coupon, error) {
+	var coupon domain.Coupon
+	err := p.DB.Preload("Courses").Preload("Categories").
+		Where("uuid = ?", *couponUUID).
+		Find(&coupon).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get coupon: %v", repository.ErrStorage, err)
+	}
+	if coupon.UUID == "" {
+		return nil, nil
+	}
+
+	return &coupon, nil
+}
+
+// GetCouponByCode returns the coupon with the given code
+func (p *PostgresDB) GetCouponByCode(
+	ctx context.Context,
+	code string,
+) (*domain.Coupon, error) {
+	var coupon domain.Coupon
+	err := p.DB.Preload("Courses").Preload("Categories").
+		Where("code = ?", code).
+		Find(&coupon).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get coupon by code: %v", repository.ErrStorage, err)
+	}
+	if coupon.UUID == "" {
+		return nil, nil
+	}
+
+	return &coupon, nil
+}
+
+// ListCoupons returns every coupon by code
+func (p *PostgresDB) ListCoupons(
+	ctx context.Context,
+) ([]*domain.Coupon, error) {
+	var coupons []*domain.Coupon
+	err := p.DB.Preload("Courses").Preload("Categories").
+		Order("code ASC").
+		Find(&coupons).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list coupons: %v", repository.ErrStorage, err)
+	}
+	return coupons, nil
+}
+
+// CountCouponRedemptions returns how many times a student has used a coupon
+func (p *PostgresDB) CountCouponRedemptions(
+	ctx context.Context,
+	couponUUID *string,
+	studentUUID *string,
+) (int64, error) {
+	var count int64
+	err := p.DB.Model(&domain.CouponRedemption{}).
+		Where("coupon_uuid = ? AND student_uuid = ?", *couponUUID, *studentUUID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("%w: can't count coupon redemptions: %v", repository.ErrStorage, err)
+	}
+	return count, nil
+}
+
+// GetCouponUsage reports the redemptions of every coupon, including
+// deactivated ones
+func (p *PostgresDB) GetCouponUsage(
+	ctx context.Context,
+) ([]*domain.CouponUsage, error) {
+	var coupons []*domain.Coupon
+	if err := p.DB.Order("code ASC").Find(&coupons).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't list coupons: %v", repository.ErrStorage, err)
+	}
+
+	var students []struct {
+		CouponUUID string
+		Students   int64
+	}
+	err := p.DB.Model(&domain.CouponRedemption{}).
+		Select("coupon_uuid, COUNT(DISTINCT student_uuid) AS students").
+		Group("coupon_uuid").
+		Scan(&students).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't count coupon students: %v", repository.ErrStorage, err)
+	}
+	var discounts []struct {
+		CouponUUID string
+		Currency   string
+		Amount     int64
+	}
+	err = p.DB.Model(&domain.CouponRedemption{}).
+		Select("coupon_uuid, discount_currency AS currency, SUM(discount_amount) AS amount").
+		Group("coupon_uuid, discount_currency").
+		Order("discount_currency ASC").
+		Scan(&discounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't sum coupon discounts: %v", repository.ErrStorage, err)
+	}
+
+	usage := make([]*domain.CouponUsage, 0, len(coupons))
+	byCoupon := map[string]*domain.CouponUsage{}
+	for _, coupon := range coupons {
+		report := &domain.CouponUsage{
+			CouponUUID:     coupon.UUID,
+			Code:           coupon.Code,
+			Active:         coupon.Active,
+			Redemptions:    coupon.Redemptions,
+			MaxRedemptions: coupon.MaxRedemptions,
+			Discounts:      []domain.Money{},
+		}
+		usage = append(usage, report)
+		byCoupon[coupon.UUID] = report
+	}
+	for _, row := range students {
+		if report, ok := byCoupon[row.CouponUUID]; ok {
+			report.Students = row.Students
+		}
+	}
+	for _, row := range discounts {
+		if report, ok := byCoupon[row.CouponUUID]; ok {
+			report.Discounts = append(report.Discounts, domain.Money{Amount: row.Amount, Currency: row.Currency})
+		}
+	}
+	return usage, nil
+}
+
+// DeactivateCoupon stops a coupon from being used. Its redemptions are kept
+// for the usage report
+func (p *PostgresDB) DeactivateCoupon(
+	ctx context.Context,
+	couponUUID *string,
+) error {
+	result := p.DB.Model(&domain.Coupon{}).
+		Where("uuid = ?", *couponUUID).
+		Update("active", false)
+	if result.Error != nil {
+		return fmt.Errorf("%w: can't deactivate coupon: %v", repository.ErrStorage, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return &domain.NotFoundError{Kind: "coupon", Key: *couponUUID}
+	}
+	return nil
+}
+
+// redeemCoupon records a coupon being used by a student. The coupon's row is
+// locked while its limits are checked and its counter is incremented, so
+// concurrent checkouts can't redeem it more often than allowed
+func redeemCoupon(tx *gorm.DB, redemption *domain.CouponRedemption) error {
+	coupon := &domain.Coupon{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("uuid = ?", redemption.CouponUUID).
+		First(coupon).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrCouponNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !coupon.IsValidAt(time.Now()) {
+		return domain.ErrCouponInactive
+	}
+	if coupon.MaxRedemptions > 0 && coupon.Redemptions >= coupon.MaxRedemptions {
+		return domain.ErrCouponExhausted
+	}
+	if coupon.PerStudentLimit > 0 {
+		var used int64
+		err := tx.Model(&domain.CouponRedemption{}).
+			Where("coupon_uuid = ? AND student_uuid = ?", coupon.UUID, redemption.StudentUUID).
+			Count(&used).Error
+		if err != nil {
+			return err
+		}
+		if used >= int64(coupon.PerStudentLimit) {
+			return domain.ErrCouponLimitReached
+		}
+	}
+
+	err = tx.Model(coupon).UpdateColumn("redemptions", gorm.Expr("redemptions + ?", 1)).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(redemption).Error
+}
+
+// CreatePlan creates a subscription plan
+func (p *PostgresDB) CreatePlan(
+	ctx context.Context,
+	plan *domain.Plan,
+) (*domain.Plan, error) {
+	if err := p.DB.Create(plan).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create a new plan: %v", repository.ErrStorage, err)
+	}
+	return plan, nil
+}
+
+// GetPlan returns a subscription plan
+func (p *PostgresDB) GetPlan(
+	ctx context.Context,
+	planUUID *string,
+) (*domain.Plan, error) {
+	var plan domain.Plan
+	if err := p.DB.Where("uuid = ?", *planUUID).Find(&plan).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get plan: %v", repository.ErrStorage, err)
+	}
+	if plan.UUID == "" {
+		return nil, nil
+	}
+
+	return &plan, nil
+}
+
+// ListPlans returns every subscription plan by name
+func (p *PostgresDB) ListPlans(
+	ctx context.Context,
+) ([]*domain.Plan, error) {
+	var plans []*domain.Plan
+	if err := p.DB.Order("name ASC").Find(&plans).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't list plans: %v", repository.ErrStorage, err)
+	}
+	return plans, nil
+}
+
+// CreateSubscription subscribes a student to a plan, recording the pending
+// signup charge with it when there is one
+func (p *PostgresDB) CreateSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	charge *domain.SubscriptionCharge,
+) (*domain.Subscription, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(subscription).Error; err != nil {
+			return err
+		}
+		if charge == nil {
+			return nil
+		}
+		charge.SubscriptionUUID = subscription.UUID
+		return tx.Create(charge).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create a new subscription: %v", repository.ErrStorage, err)
+	}
+	return subscription, nil
+}
+
+// GetSubscription returns a subscription with its plan
+func (p *PostgresDB) GetSubscription(
+	ctx context.Context,
+	subscriptionUUID *string,
+) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	if err := p.DB.Preload("Plan").Where("uuid = ?", *subscriptionUUID).Find(&subscription).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get subscription: %v", repository.ErrStorage, err)
+	}
+	if subscription.UUID == "" {
+		return nil, nil
+	}
+
+	return &subscription, nil
+}
+
+// GetStudentSubscription returns a student's latest subscription with its
+// plan
+func (p *PostgresDB) GetStudentSubscription(
+	ctx context.Context,
+	studentUUID *string,
+) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	err := p.DB.Preload("Plan").
+		Where("student_uuid = ?", *studentUUID).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&subscription).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get student's subscription: %v", repository.ErrStorage, err)
+	}
+	if subscription.UUID == "" {
+		return nil, nil
+	}
+
+	return &subscription, nil
+}
+
+// UpdateSubscription saves a subscription's state
+func (p *PostgresDB) UpdateSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+) (*domain.Subscription, error) {
+	if err := p.DB.Omit(clause.Associations).Save(subscription).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't update subscription: %v", repository.ErrStorage, err)
+	}
+	return subscription, nil
+}
+
+// CreateSubscriptionCharge records an attempt to charge for a subscription,
+// posting the journal of charges that went through
+func (p *PostgresDB) CreateSubscriptionCharge(
+	ctx context.Context,
+	charge *domain.SubscriptionCharge,
+) (*domain.SubscriptionCharge, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(charge).Error; err != nil {
+			return err
+		}
+		if charge.Status != domain.ChargeStatusSucceeded {
+			return nil
+		}
+		return postJournal(tx, charge.Journal(time.Now()))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't record subscription charge: %v", repository.ErrStorage, err)
+	}
+	return charge, nil
+}
+
+// ClaimDueSubscriptions leases up to limit of the subscriptions the renewal
+// scheduler has to act on: those to renew or retry, and those whose paid
+// period or grace period is over. Subscriptions leased by another scheduler
+// are skipped, so each is renewed by one of them. A lease runs out on its
+// own, so subscriptions that couldn't be processed are claimed again later
+func (p *PostgresDB) ClaimDueSubscriptions(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]*domain.Subscription, error) {
+	var subscriptions []*domain.Subscription
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		due := p.DB.Where("status IN ? AND renews_at <= ?", []domain.SubscriptionStatus{
+			domain.SubscriptionStatusTrial,
+			domain.SubscriptionStatusActive,
+		}, now).
+			Or("status = ? AND (next_retry_at <= ? OR grace_until <= ?)", domain.SubscriptionStatusPastDue, now, now).
+			Or("status = ? AND current_period_end <= ?", domain.SubscriptionStatusCanceled, now)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Plan").
+			Where(due).
+			Where("leased_until IS NULL OR leased_until < ?", now).
+			Order("current_period_end ASC").
+			Limit(limit).
+			Find(&subscriptions).Error
+		if err != nil || len(subscriptions) == 0 {
+			return err
+		}
+		leasedUntil := now.Add(lease)
+		uuids := make([]string, len(subscriptions))
+		for i, subscription := range subscriptions {
+			subscription.LeasedUntil = &leasedUntil
+			uuids[i] = subscription.UUID
+		}
+		return tx.Model(&domain.Subscription{}).
+			Where("uuid IN ?", uuids).
+			Update("leased_until", leasedUntil).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't claim due subscriptions: %v", repository.ErrStorage, err)
+	}
+	return subscriptions, nil
+}
+
+// SettleSubscriptionCharge saves the outcome of a charge, posting its journal
+// when it succeeds and reversing it when it fails after it succeeded. The
+// stored charge is locked while it is moved on, and ErrChargeSettled is
+// returned when its status doesn't allow the move, so each outcome is only
+// acted on once
+func (p *PostgresDB) SettleSubscriptionCharge(
+	ctx context.Context,
+	charge *domain.SubscriptionCharge,
+) (*domain.SubscriptionCharge, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var stored domain.SubscriptionCharge
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uuid = ?", charge.UUID).
+			First(&stored).Error
+		if err != nil {
+			return err
+		}
+		if !stored.Status.CanBecome(charge.Status) {
+			return domain.ErrChargeSettled
+		}
+		// Updates writes the new values into stored
+		previous := stored.Status
+		err = tx.Model(&stored).Updates(map[string]interface{}{
+			"status":         charge.Status,
+			"reference":      charge.Reference,
+			"failure_reason": charge.FailureReason,
+		}).Error
+		if err != nil {
+			return err
+		}
+		switch {
+		case charge.Status == domain.ChargeStatusSucceeded:
+			return postJournal(tx, charge.Journal(time.Now()))
+		case previous == domain.ChargeStatusSucceeded:
+			return postJournal(tx, charge.ReversalJournal(time.Now()))
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrChargeSettled) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't settle subscription charge: %v", repository.ErrStorage, err)
+	}
+	return charge, nil
+}
+
+// GetSubscriptionChargeByKey returns the charge a client subscribed with the
+// idempotency key for
+func (p *PostgresDB) GetSubscriptionChargeByKey(
+	ctx context.Context,
+	key string,
+) (*domain.SubscriptionCharge, error) {
+	var charge domain.SubscriptionCharge
+	if err := p.DB.Where("idempotency_key = ?", key).Find(&charge).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get subscription charge: %v", repository.ErrStorage, err)
+	}
+	if charge.UUID == "" {
+		return nil, nil
+	}
+
+	return &charge, nil
+}
+
+// GetPendingSubscriptionCharge returns the charge of a subscription whose
+// outcome isn't known yet, if there is one
+func (p *PostgresDB) GetPendingSubscriptionCharge(
+	ctx context.Context,
+	subscriptionUUID *string,
+) (*domain.SubscriptionCharge, error) {
+	var charge domain.SubscriptionCharge
+	err := p.DB.Where("subscription_uuid = ? AND status = ?", *subscriptionUUID, domain.ChargeStatusPending).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&charge).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get pending subscription charge: %v", repository.ErrStorage, err)
+	}
+	if charge.UUID == "" {
+		return nil, nil
+	}
+
+	return &charge, nil
+}
+
+// GetSubscriptionCharges returns the charges of a subscription, newest first
+func (p *PostgresDB) GetSubscriptionCharges(
+	ctx context.Context,
+	subscriptionUUID *string,
+) ([]*domain.SubscriptionCharge, error) {
+	var charges []*domain.SubscriptionCharge
+	err := p.DB.Where("subscription_uuid = ?", *subscriptionUUID).
+		Order("created_at DESC").
+		Find(&charges).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get subscription charges: %v", repository.ErrStorage, err)
+	}
+	return charges, nil
+}
+
+// CreateInvoice numbers and creates an invoice with its lines. The year's
+// sequence is advanced in the invoice's transaction, which holds the
+// sequence row until it commits, so concurrent invoices can't skip or reuse
+// a number
+func (p *PostgresDB) CreateInvoice(
+	ctx context.Context,
+	invoice *domain.Invoice,
+) (*domain.Invoice, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		sequence := domain.InvoiceSequence{Year: invoice.Year, Last: 1}
+		err := tx.Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "year"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"last": gorm.Expr("invoice_sequences.last + 1")}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "last"}}},
+		).Create(&sequence).Error
+		if err != nil {
+			return err
+		}
+		invoice.Sequence = sequence.Last
+		invoice.Number = domain.InvoiceNumber(invoice.Year, sequence.Last)
+		return tx.Create(invoice).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create invoice: %v", repository.ErrStorage, err)
+	}
+	return invoice, nil
+}
+
+// ListUninvoicedCharges returns the successful subscription charges settled
+// before the given time that have no invoice, oldest first
+func (p *PostgresDB) ListUninvoicedCharges(
+	ctx context.Context,
+	settledBefore time.Time,
+) ([]*domain.SubscriptionCharge, error) {
+	var charges []*domain.SubscriptionCharge
+	err := p.DB.Where("status = ? AND updated_at < ? AND NOT EXISTS (?)", domain.ChargeStatusSucceeded, settledBefore,
+		p.DB.Model(&domain.Invoice{}).Select("1").Where("invoices.charge_uuid = subscription_charges.uuid"),
+	).Order("updated_at ASC").Find(&charges).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list uninvoiced charges: %v", repository.ErrStorage, err)
+	}
+	return charges, nil
+}
+
+// GetInvoice returns an invoice with its lines
+func (p *PostgresDB) GetInvoice(
+	ctx context.Context,
+	invoiceUUID *string,
+) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := p.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Preload("TaxLines").
+		Where("uuid = ?", *invoiceUUID).
+		Find(&invoice).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get invoice: %v", repository.ErrStorage, err)
+	}
+	if invoice.UUID == "" {
+		return nil, nil
+	}
+
+	return &invoice, nil
+}
+
+// ListStudentInvoices returns a student's invoices, newest first
+func (p *PostgresDB) ListStudentInvoices(
+	ctx context.Context,
+	studentUUID *string,
+) ([]*domain.Invoice, error) {
+	var invoices []*domain.Invoice
+	err := p.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Preload("TaxLines").
+		Where("student_uuid = ?", *studentUUID).
+		Order("year DESC, sequence DESC").
+		Find(&invoices).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list student's invoices: %v", repository.ErrStorage, err)
+	}
+	return invoices, nil
+}
+
+// SetTaxRate sets the tax charged in a currency, replacing its current rate
+func (p *PostgresDB) SetTaxRate(
+	ctx context.Context,
+	rate *domain.TaxRate,
+) (*domain.TaxRate, error) {
+	err := p.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "basis_points", "updated_at"}),
+	}).Create(rate).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't set tax rate: %v", repository.ErrStorage, err)
+	}
+	// on conflict the stored row keeps its own UUID, so read it back
+	return p.GetTaxRate(ctx, rate.Currency)
+}
+
+// GetTaxRate returns the tax charged in a currency, or nil when it is untaxed
+func (p *PostgresDB) GetTaxRate(
+	ctx context.Context,
+	currency string,
+) (*domain.TaxRate, error) {
+	var rate domain.TaxRate
+	if err := p.DB.Where("currency = ?", currency).Find(&rate).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get tax rate: %v", repository.ErrStorage, err)
+	}
+	if rate.UUID == "" {
+		return nil, nil
+	}
+
+	return &rate, nil
+}
+
+// ListTaxRates returns the tax rates of every taxed currency
+func (p *PostgresDB) ListTaxRates(
+	ctx context.Context,
+) ([]*domain.TaxRate, error) {
+	var rates []*domain.TaxRate
+	if err := p.DB.Order("currency ASC").Find(&rates).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't list tax rates: %v", repository.ErrStorage, err)
+	}
+	return rates, nil
+}
+
+// GetSubscriptionCharge returns a subscription charge
+func (p *PostgresDB) GetSubscriptionCharge(
+	ctx context.Context,
+	chargeUUID *string,
+) (*domain.SubscriptionCharge, error) {
+	var charge domain.SubscriptionCharge
+	if err := p.DB.Where("uuid = ?", *chargeUUID).Find(&charge).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get subscription charge: %v", repository.ErrStorage, err)
+	}
+	if charge.UUID == "" {
+		return nil, nil
+	}
+
+	return &charge, nil
+}
+
+// CreateRefund creates a refund once it is sure the charge covers it. The
+// charge's row is locked while its refunds are added up, so concurrent
+// refunds can't give back more than was charged
+func (p *PostgresDB) CreateRefund(
+	ctx context.Context,
+	refund *domain.Refund,
+) (*domain.Refund, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var charge domain.SubscriptionCharge
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uuid = ?", refund.ChargeUUID).
+			First(&charge).Error
+		if err != nil {
+			return err
+		}
+		var refunded int64
+		err = tx.Model(&domain.Refund{}).
+			Select("COALESCE(SUM(amount_amount), 0)").
+			Where("charge_uuid = ? AND status <> ?", refund.ChargeUUID, domain.RefundStatusFailed).
+			Scan(&refunded).Error
+		if err != nil {
+			return err
+		}
+		if refunded+refund.Amount.Amount > charge.Amount.Amount {
+			return domain.ErrRefundExceedsCharge
+		}
+		return tx.Create(refund).Error
+	})
+	if errors.Is(err, domain.ErrRefundExceedsCharge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create refund: %v", repository.ErrStorage, err)
+	}
+	return refund, nil
+}
+
+// GetChargeRefunds returns the refunds of a charge, oldest first
+func (p *PostgresDB) GetChargeRefunds(
+	ctx context.Context,
+	chargeUUID *string,
+) ([]*domain.Refund, error) {
+	var refunds []*domain.Refund
+	err := p.DB.Where("charge_uuid = ?", *chargeUUID).
+		Order("created_at ASC").
+		Find(&refunds).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get charge's refunds: %v", repository.ErrStorage, err)
+	}
+	return refunds, nil
+}
+
+// UpdateRefund saves a refund's progress with the payment provider. The
+// refund's journal is posted in the same transaction when it first succeeds,
+// and the sale of the course it is for, if any, is reversed
+func (p *PostgresDB) UpdateRefund(
+	ctx context.Context,
+	refund *domain.Refund,
+) (*domain.Refund, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var stored domain.Refund
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uuid = ?", refund.UUID).
+			First(&stored).Error
+		if err != nil {
+			return err
+		}
+		// Updates writes the new values into stored
+		previous := stored.Status
+		err = tx.Model(&stored).Updates(map[string]interface{}{
+			"status":         refund.Status,
+			"reference":      refund.Reference,
+			"failure_reason": refund.FailureReason,
+			"unenrolled":     refund.Unenrolled,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if previous == domain.RefundStatusSucceeded || refund.Status != domain.RefundStatusSucceeded {
+			return nil
+		}
+		now := time.Now()
+		if err := postJournal(tx, refund.Journal(now)); err != nil {
+			return err
+		}
+		if refund.CourseUUID == "" {
+			return nil
+		}
+		return reverseSale(tx, refund.StudentUUID, refund.CourseUUID, now)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't update refund: %v", repository.ErrStorage, err)
+	}
+	return refund, nil
+}
+
+// GetLedgerBalances returns the balance of every ledger account in each
+// currency it holds
+func (p *PostgresDB) GetLedgerBalances(
+	ctx context.Context,
+) ([]*domain.LedgerBalance, error) {
+	var balances []*domain.LedgerBalance
+	err := p.DB.Model(&domain.LedgerEntry{}).
+		Select("account, amount_currency AS currency, SUM(amount_amount) AS balance").
+		Group("account, amount_currency").
+		Order("account ASC, amount_currency ASC").
+		Scan(&balances).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get ledger balances: %v", repository.ErrStorage, err)
+	}
+	return balances, nil
+}
+
+// postJournal posts the entries of a journal, refusing ones that don't
+// balance so the ledger always sums to zero
+func postJournal(tx *gorm.DB, entries []*domain.LedgerEntry) error {
+	if !domain.Balanced(entries) {
+		return fmt.Errorf("journal does not balance")
+	}
+	journalUUID := uuid.NewString()
+	for _, entry := range entries {
+		entry.JournalUUID = journalUUID
+	}
+	return tx.Create(&entries).Error
+}
+
+// RecordWebhookEvent records a handled payment provider event. An event that
+// was already recorded keeps its first outcome
+func (p *PostgresDB) RecordWebhookEvent(
+	ctx context.Context,
+	event *domain.WebhookEvent,
+) (*domain.WebhookEvent, error) {
+	err := p.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider_event_id"}},
+		DoNothing: true,
+	}).Create(event).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't record webhook event: %v", repository.ErrStorage, err)
+	}
+	return p.GetWebhookEvent(ctx, event.ProviderEventID)
+}
+
+// GetWebhookEvent returns a handled payment provider event by the provider's
+// event ID, or nil when it hasn't been handled
+func (p *PostgresDB) GetWebhookEvent(
+	ctx context.Context,
+	providerEventID string,
+) (*domain.WebhookEvent, error) {
+	var event domain.WebhookEvent
+	if err := p.DB.Where("provider_event_id = ?", providerEventID).Find(&event).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get webhook event: %v", repository.ErrStorage, err)
+	}
+	if event.UUID == "" {
+		return nil, nil
+	}
+
+	return &event, nil
+}
+
+// GetRefundByReference returns the refund with the payment provider's
+// reference
+func (p *PostgresDB) GetRefundByReference(
+	ctx context.Context,
+	reference string,
+) (*domain.Refund, error) {
+	var refund domain.Refund
+	if err := p.DB.Where("reference = ?", reference).Find(&refund).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get refund: %v", repository.ErrStorage, err)
+	}
+	if refund.UUID == "" {
+		return nil, nil
+	}
+
+	return &refund, nil
+}
+
+// ListUnsentRefunds returns the pending refunds created before the given time
+// that the payment provider never acknowledged, oldest first
+func (p *PostgresDB) ListUnsentRefunds(
+	ctx context.Context,
+	createdBefore time.Time,
+) ([]*domain.Refund, error) {
+	var refunds []*domain.Refund
+	err := p.DB.Where("status = ? AND reference = '' AND created_at < ?", domain.RefundStatusPending, createdBefore).
+		Order("created_at ASC").
+		Find(&refunds).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list unsent refunds: %v", repository.ErrStorage, err)
+	}
+	return refunds, nil
+}
+
+// GetSubscriptionChargeByReference returns the subscription charge with the
+// payment provider's reference
+func (p *PostgresDB) GetSubscriptionChargeByReference(
+	ctx context.Context,
+	reference string,
+) (*domain.SubscriptionCharge, error) {
+	var charge domain.SubscriptionCharge
+	if err := p.DB.Where("reference = ?", reference).Find(&charge).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get subscription charge: %v", repository.ErrStorage, err)
+	}
+	if charge.UUID == "" {
+		return nil, nil
+	}
+
+	return &charge, nil
+}
+
+// ListLedgerEntries returns the ledger entries matching a query, newest first
+func (p *PostgresDB) ListLedgerEntries(
+	ctx context.Context,
+	query domain.LedgerQuery,
+) ([]*domain.LedgerEntry, error) {
+	db := p.DB.Model(&domain.LedgerEntry{})
+	if query.Account != "" {
+		db = db.Where("account = ?", query.Account)
+	}
+	if query.CourseUUID != "" {
+		db = db.Where("course_uuid = ?", query.CourseUUID)
+	}
+	if query.ChargeUUID != "" {
+		db = db.Where("charge_uuid = ?", query.ChargeUUID)
+	}
+	if query.From != nil {
+		db = db.Where("posted_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("posted_at < ?", *query.To)
+	}
+	var entries []*domain.LedgerEntry
+	err := db.Order("posted_at DESC, journal_uuid ASC, account ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list ledger entries: %v", repository.ErrStorage, err)
+	}
+	return entries, nil
+}
+
+// GetRevenueReport sums course revenue per month and course, category or
+// instructor. Gross and discounts come from the sales' journals in the ledger,
+// reversals included, so reversed sales add nothing
+func (p *PostgresDB) GetRevenueReport(
+	ctx context.Context,
+	query domain.RevenueQuery,
+) ([]*domain.RevenueReportRow, error) {
+	column := map[domain.RevenueGroup]string{
+		domain.RevenueGroupCourse:     "course_sales.course_uuid",
+		domain.RevenueGroupCategory:   "course_sales.category_uuid",
+		domain.RevenueGroupInstructor: "course_sales.instructor_uuid",
+	}[query.GroupBy]
+	if column == "" {
+		return nil, fmt.Errorf("%w: can't group revenue by %q", repository.ErrStorage, query.GroupBy)
+	}
+
+	journals := p.DB.Model(&domain.LedgerEntry{}).
+		Select("sale_uuid, "+
+			"-SUM(CASE WHEN account = ? THEN amount_amount ELSE 0 END) AS gross, "+
+			"SUM(CASE WHEN account = ? THEN amount_amount ELSE 0 END) AS discounts",
+			domain.LedgerAccountCourseRevenue, domain.LedgerAccountCouponDiscounts).
+		Where("sale_uuid <> ''").
+		Group("sale_uuid")
+	db := p.DB.Model(&domain.CourseSale{}).
+		Select("to_char(date_trunc('month', course_sales.sold_at), 'YYYY-MM') AS month, "+
+			column+" AS group_uuid, "+
+			"course_sales.list_price_currency AS currency, "+
+			"COUNT(*) AS sales, "+
+			"COUNT(course_sales.reversed_at) AS reversals, "+
+			"COALESCE(SUM(journals.gross), 0) AS gross, "+
+			"COALESCE(SUM(journals.discounts), 0) AS discounts").
+		Joins("LEFT JOIN (?) AS journals ON journals.sale_uuid = course_sales.uuid", journals)
+	if query.From != nil {
+		db = db.Where("course_sales.sold_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("course_sales.sold_at < ?", *query.To)
+	}
+	var totals []struct {
+		Month     string
+		GroupUUID string
+		Currency  string
+		Sales     int64
+		Reversals int64
+		Gross     int64
+		Discounts int64
+	}
+	err := db.Group("1, 2, 3").Order("1, 2, 3").Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't sum course revenue: %v", repository.ErrStorage, err)
+	}
+
+	names, err := p.revenueGroupNames(query.GroupBy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't name revenue groups: %v", repository.ErrStorage, err)
+	}
+	rows := make([]*domain.RevenueReportRow, 0, len(totals))
+	for _, total := range totals {
+		name, ok := names[total.GroupUUID]
+		if !ok {
+			name = "Unassigned"
+		}
+		money := func(amount int64) domain.Money {
+			return domain.Money{Amount: amount, Currency: total.Currency}
+		}
+		rows = append(rows, &domain.RevenueReportRow{
+			Month:     total.Month,
+			GroupUUID: total.GroupUUID,
+			GroupName: name,
+			Sales:     total.Sales,
+			Reversals: total.Reversals,
+			Gross:     money(total.Gross),
+			Discounts: money(total.Discounts),
+			Net:       money(total.Gross - total.Discounts),
+		})
+	}
+	return rows, nil
+}
+
+// GetRevenueReconciliation compares, per month and currency, the cash posted
+// to the ledger for subscription charges and refunds with the charges and
+// refunds the payment provider reported as succeeded. The ledger side is
+// summed per charge and refund, whenever it was posted, so a charge that
+// failed after it succeeded nets out on both sides
+func (p *PostgresDB) GetRevenueReconciliation(
+	ctx context.Context,
+	from *time.Time,
+	to *time.Time,
+) ([]*domain.RevenueReconciliation, error) {
+	type total struct {
+		Month    string
+		Currency string
+		Provider int64
+		Ledger   int64
+	}
+	sum := func(model interface{}, table string, key string, cash string, status string) ([]total, error) {
+		journals := p.DB.Model(&domain.LedgerEntry{}).
+			Select(key+", SUM(amount_amount) AS cash").
+			Where("account = ? AND "+cash, domain.LedgerAccountCash).
+			Group(key)
+		db := p.DB.Model(model).
+			Select("to_char(date_trunc('month', "+table+".created_at), 'YYYY-MM') AS month, "+
+				table+".amount_currency AS currency, "+
+				"SUM(CASE WHEN "+table+".status = ? THEN "+table+".amount_amount ELSE 0 END) AS provider, "+
+				"COALESCE(SUM(journals.cash), 0) AS ledger", status).
+			Joins("LEFT JOIN (?) AS journals ON journals."+key+" = "+table+".uuid", journals)
+		if from != nil {
+			db = db.Where(table+".created_at >= ?", *from)
+		}
+		if to != nil {
+			db = db.Where(table+".created_at < ?", *to)
+		}
+		var totals []total
+		err := db.Group("1, 2").Scan(&totals).Error
+		return totals, err
+	}
+	charges, err := sum(&domain.SubscriptionCharge{}, "subscription_charges", "charge_uuid", "charge_uuid <> '' AND refund_uuid = ''", string(domain.ChargeStatusSucceeded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't sum subscription charges: %v", repository.ErrStorage, err)
+	}
+	refunds, err := sum(&domain.Refund{}, "refunds", "refund_uuid", "refund_uuid <> ''", string(domain.RefundStatusSucceeded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't sum refunds: %v", repository.ErrStorage, err)
+	}
+
+	rows := map[string]*domain.RevenueReconciliation{}
+	row := func(t total) *domain.RevenueReconciliation {
+		key := t.Month + " " + t.Currency
+		if rows[key] == nil {
+			zero := domain.Money{Currency: t.Currency}
+			rows[key] = &domain.RevenueReconciliation{Month: t.Month, Charged: zero, Refunded: zero, LedgerCash: zero}
+		}
+		return rows[key]
+	}
+	for _, t := range charges {
+		r := row(t)
+		r.Charged.Amount += t.Provider
+		r.LedgerCash.Amount += t.Ledger
+	}
+	for _, t := range refunds {
+		r := row(t)
+		r.Refunded.Amount += t.Provider
+		r.LedgerCash.Amount += t.Ledger
+	}
+	reconciliations := make([]*domain.RevenueReconciliation, 0, len(rows))
+	for _, r := range rows {
+		r.Collected = domain.Money{Amount: r.Charged.Amount - r.Refunded.Amount, Currency: r.Charged.Currency}
+		r.Difference = domain.Money{Amount: r.LedgerCash.Amount - r.Collected.Amount, Currency: r.Charged.Currency}
+		reconciliations = append(reconciliations, r)
+	}
+	sort.Slice(reconciliations, func(i, j int) bool {
+		a, b := reconciliations[i], reconciliations[j]
+		if a.Month != b.Month {
+			return a.Month < b.Month
+		}
+		return a.Charged.Currency < b.Charged.Currency
+	})
+	return reconciliations, nil
+}
+
+// revenueGroupNames maps the courses, categories or instructors revenue can
+// be grouped by to their names, including deleted ones
+func (p *PostgresDB) revenueGroupNames(groupBy domain.RevenueGroup) (map[string]string, error) {
+	var named []struct {
+		UUID string
+		Name string
+	}
+	var err error
+	switch groupBy {
+	case domain.RevenueGroupCourse:
+		err = p.DB.Unscoped().Model(&domain.Course{}).Select("uuid, title AS name").Scan(&named).Error
+	case domain.RevenueGroupCategory:
+		err = p.DB.Unscoped().Model(&domain.Category{}).Select("uuid, name").Scan(&named).Error
+	case domain.RevenueGroupInstructor:
+		err = p.DB.Unscoped().Model(&domain.Instructor{}).Select("uuid, name").Scan(&named).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(named))
+	for _, n := range named {
+		names[n.UUID] = n.Name
+	}
+	return names, nil
+}
+
+// CreateRevenueShare records a revenue share agreement with an instructor
+func (p *PostgresDB) CreateRevenueShare(
+	ctx context.Context,
+	share *domain.RevenueShare,
+) (*domain.RevenueShare, error) {
+	if err := p.DB.Create(share).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't create revenue share: %v", repository.ErrStorage, err)
+	}
+	return share, nil
+}
+
+// ListRevenueShares returns an instructor's revenue share agreements, latest
+// first
+func (p *PostgresDB) ListRevenueShares(
+	ctx context.Context,
+	instructorUUID *string,
+) ([]*domain.RevenueShare, error) {
+	var shares []*domain.RevenueShare
+	err := p.DB.Where("instructor_uuid = ?", *instructorUUID).
+		Order("effective_from DESC").
+		Find(&shares).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list revenue shares: %v", repository.ErrStorage, err)
+	}
+	return shares, nil
+}
+
+// ListUnpaidSales returns the sales of instructors' courses made in a period
+// that aren't on a payout statement yet, oldest first. Their net is what they
+// earned in the ledger
+func (p *PostgresDB) ListUnpaidSales(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*domain.PayoutLine, error) {
+	journals := p.DB.Model(&domain.LedgerEntry{}).
+		Select("sale_uuid, -SUM(amount_amount) AS net").
+		Where("sale_uuid <> '' AND account IN ?",
+			[]string{domain.LedgerAccountCourseRevenue, domain.LedgerAccountCouponDiscounts}).
+		Group("sale_uuid")
+	var sales []struct {
+		SaleUUID       string
+		InstructorUUID string
+		CourseUUID     string
+		CourseTitle    string
+		SoldAt         time.Time
+		Currency       string
+		Net            int64
+	}
+	err := p.DB.Model(&domain.CourseSale{}).
+		Select("course_sales.uuid AS sale_uuid, course_sales.instructor_uuid, course_sales.course_uuid, "+
+			"courses.title AS course_title, course_sales.sold_at, course_sales.list_price_currency AS currency, "+
+			"COALESCE(journals.net, 0) AS net").
+		Joins("LEFT JOIN courses ON courses.uuid = course_sales.course_uuid").
+		Joins("LEFT JOIN (?) AS journals ON journals.sale_uuid = course_sales.uuid", journals).
+		Where("course_sales.instructor_uuid <> '' AND course_sales.sold_at >= ? AND course_sales.sold_at < ?", from, to).
+		Where("NOT EXISTS (?)",
+			p.DB.Model(&domain.PayoutLine{}).Select("1").Where("payout_lines.sale_uuid = course_sales.uuid"),
+		).
+		Order("course_sales.sold_at ASC").
+		Scan(&sales).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list unpaid sales: %v", repository.ErrStorage, err)
+	}
+	lines := make([]*domain.PayoutLine, 0, len(sales))
+	for _, sale := range sales {
+		lines = append(lines, &domain.PayoutLine{
+			SaleUUID:       sale.SaleUUID,
+			InstructorUUID: sale.InstructorUUID,
+			CourseUUID:     sale.CourseUUID,
+			CourseTitle:    sale.CourseTitle,
+			SoldAt:         sale.SoldAt,
+			Net:            domain.Money{Amount: sale.Net, Currency: sale.Currency},
+		})
+	}
+	return lines, nil
+}
+
+// CreatePayoutStatement creates a payout statement with its lines and posts
+// what the instructor is owed to the ledger
+func (p *PostgresDB) CreatePayoutStatement(
+	ctx context.Context,
+	statement *domain.PayoutStatement,
+) (*domain.PayoutStatement, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(statement).Error; err != nil {
+			return err
+		}
+		if statement.Earnings.Amount == 0 {
+			return nil
+		}
+		return postJournal(tx, statement.Journal(time.Now()))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create payout statement: %v", repository.ErrStorage, err)
+	}
+	return statement, nil
+}
+
+// GetPayoutStatement returns a payout statement with its lines, or nil when
+// it does not exist
+func (p *PostgresDB) GetPayoutStatement(
+	ctx context.Context,
+	statementUUID *string,
+) (*domain.PayoutStatement, error) {
+	var statement domain.PayoutStatement
+	err := p.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sold_at ASC")
+	}).
+		Where("uuid = ?", *statementUUID).
+		Find(&statement).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't get payout statement: %v", repository.ErrStorage, err)
+	}
+	if statement.UUID == "" {
+		return nil, nil
+	}
+
+	return &statement, nil
+}
+
+// ListPayoutStatements returns payout statements without their lines, newest
+// first, optionally only an instructor's or a month's
+func (p *PostgresDB) ListPayoutStatements(
+	ctx context.Context,
+	instructorUUID string,
+	month string,
+) ([]*domain.PayoutStatement, error) {
+	query := p.DB.Model(&domain.PayoutStatement{})
+	if instructorUUID != "" {
+		query = query.Where("instructor_uuid = ?", instructorUUID)
+	}
+	if month != "" {
+		query = query.Where("month = ?", month)
+	}
+	var statements []*domain.PayoutStatement
+	if err := query.Order("month DESC, created_at DESC").Find(&statements).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't list payout statements: %v", repository.ErrStorage, err)
+	}
+	return statements, nil
+}
+
+// MarkPayoutPaid records that a payout statement was paid out and posts the
+// payment to the ledger. A statement can only be paid once
+func (p *PostgresDB) MarkPayoutPaid(
+	ctx context.Context,
+	statementUUID *string,
+	reference string,
+	paidAt time.Time,
+) (*domain.PayoutStatement, error) {
+	var statement domain.PayoutStatement
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uuid = ?", *statementUUID).
+			First(&statement).Error
+		if err != nil {
+			return err
+		}
+		if statement.Status == domain.PayoutStatusPaid {
+			return domain.ErrPayoutAlreadyPaid
+		}
+		err = tx.Model(&statement).Updates(map[string]interface{}{
+			"status":            domain.PayoutStatusPaid,
+			"paid_at":           paidAt,
+			"payment_reference": reference,
+		}).Error
+		if err != nil {
+			return err
+		}
+		statement.Status = domain.PayoutStatusPaid
+		statement.PaidAt = &paidAt
+		statement.PaymentReference = reference
+		if statement.Earnings.Amount == 0 {
+			return nil
+		}
+		return postJournal(tx, statement.PaymentJournal(paidAt))
+	})
+	if errors.Is(err, domain.ErrPayoutAlreadyPaid) {
+		return nil, err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't mark payout statement as paid: %v", repository.ErrStorage, err)
+	}
+	return &statement, nil
+}
+
+// period keeps the rows whose column falls from from and before to, when
+// they are set
+func period(column string, from, to *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if from != nil {
+			db = db.Where(column+" >= ?", *from)
+		}
+		if to != nil {
+			db = db.Where(column+" < ?", *to)
+		}
+		return db
+	}
+}
+
+// ListStudents pages through students in the order they signed up, within
+// the query's period
+func (p *PostgresDB) ListStudents(
+	ctx context.Context,
+	query *domain.StudentQuery,
+) ([]*domain.Student, error) {
+	var students []*domain.Student
+	err := p.DB.Scopes(period("created_at", query.From, query.To)).
+		Order("created_at ASC, uuid ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&students).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list students: %v", repository.ErrStorage, err)
+	}
+	return students, nil
+}
+
+// DeleteStudent soft deletes a student who isn't enrolled in any course and
+// has no subscription that is still running, so their payments stay on record.
+// The student is taken off every waitlist
+func (p *PostgresDB) DeleteStudent(
+	ctx context.Context,
+	studentUUID *string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var enrollments, subscriptions int64
+		if err := tx.Model(&domain.StudentCourse{}).Where("student_uuid = ?", *studentUUID).Count(&enrollments).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&domain.Subscription{}).
+			Where("student_uuid = ? AND status NOT IN ?", *studentUUID, []domain.SubscriptionStatus{
+				domain.SubscriptionStatusCanceled, domain.SubscriptionStatusExpired,
+			}).
+			Count(&subscriptions).Error
+		if err != nil {
+			return err
+		}
+		if enrollments > 0 || subscriptions > 0 {
+			return fmt.Errorf("%w: student is enrolled in %d courses and has %d running subscriptions", domain.ErrInUse, enrollments, subscriptions)
+		}
+		if err := tx.Unscoped().Where("student_uuid = ?", *studentUUID).Delete(&domain.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("uuid = ?", *studentUUID).Delete(&domain.Student{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &domain.NotFoundError{Kind: "student", Key: *studentUUID}
+		}
+		return nil
+	})
+	var notFound *domain.NotFoundError
+	if errors.Is(err, domain.ErrInUse) || errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: can't delete student: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// DeleteCourse soft deletes a course no student is enrolled in, along with
+// its waitlist
+func (p *PostgresDB) DeleteCourse(
+	ctx context.Context,
+	courseUUID *string,
+) error {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var enrollments int64
+		if err := tx.Model(&domain.StudentCourse{}).Where("course_uuid = ?", *courseUUID).Count(&enrollments).Error; err != nil {
+			return err
+		}
+		if enrollments > 0 {
+			return fmt.Errorf("%w: course has %d enrolled students", domain.ErrInUse, enrollments)
+		}
+		if err := tx.Unscoped().Where("course_uuid = ?", *courseUUID).Delete(&domain.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("uuid = ?", *courseUUID).Delete(&domain.Course{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &domain.NotFoundError{Kind: "course", Key: *courseUUID}
+		}
+		return nil
+	})
+	var notFound *domain.NotFoundError
+	if errors.Is(err, domain.ErrInUse) || errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: can't delete course: %v", repository.ErrStorage, err)
+	}
+	return nil
+}
+
+// ListEnrollments pages through every student's enrollments in the order
+// they enrolled, within the query's period
+func (p *PostgresDB) ListEnrollments(
+	ctx context.Context,
+	query *domain.EnrollmentQuery,
+) ([]*domain.StudentCourse, error) {
+	var enrollments []*domain.StudentCourse
+	err := p.DB.Scopes(period("enrolled_at", query.From, query.To)).
+		Order("enrolled_at ASC, student_uuid ASC, course_uuid ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&enrollments).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't list enrollments: %v", repository.ErrStorage, err)
+	}
+	return enrollments, nil
+}
+
+// PageCourses pages through the courses created in a period, oldest first
+func (p *PostgresDB) PageCourses(
+	ctx context.Context,
+	query *domain.CourseQuery,
+) ([]*domain.Course, error) {
+	var courses []*domain.Course
+	err := p.DB.Scopes(period("created_at", query.From, query.To)).
+		Order("created_at ASC, uuid ASC").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&courses).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't page courses: %v", repository.ErrStorage, err)
+	}
+	return courses, nil
+}
+
+// CreateImport stores an import with its rows, ready to be processed
+func (p *PostgresDB) CreateImport(
+	ctx context.Context,
+	imp *domain.Import,
+	rows []*domain.ImportRow,
+) (*domain.Import, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(imp).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			row.ImportUUID = imp.UUID
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't create import: %v", repository.ErrStorage, err)
+	}
+	return imp, nil
+}
+
+// GetImport returns an import
+func (p *PostgresDB) GetImport(
+	ctx context.Context,
+	importUUID *string,
+) (*domain.Import, error) {
+	var imp domain.Import
+	if err := p.DB.Where("uuid = ?", *importUUID).Find(&imp).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get import %s: %v", repository.ErrStorage, *importUUID, err)
+	}
+	if imp.UUID == "" {
+		return nil, nil
+	}
+	return &imp, nil
+}
+
+// GetImportRows returns an import's rows with a status in the order they are
+// in the file, all of them when limit is 0
+func (p *PostgresDB) GetImportRows(
+	ctx context.Context,
+	importUUID *string,
+	status domain.ImportRowStatus,
+	limit int,
+) ([]*domain.ImportRow, error) {
+	query := p.DB.Where("import_uuid = ? AND status = ?", *importUUID, status).Order("line ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var rows []*domain.ImportRow
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%w: can't get rows of import %s: %v", repository.ErrStorage, *importUUID, err)
+	}
+	return rows, nil
+}
+
+// ClaimImport leases the oldest unfinished import no one else holds, so a
+// single worker processes it. Imports whose worker stopped renewing its lease,
+// e.g. because the service crashed, are claimed again. It returns nil when
+// there's nothing to process
+func (p *PostgresDB) ClaimImport(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+) (*domain.Import, error) {
+	var imp domain.Import
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND (leased_until IS NULL OR leased_until < ?)", []domain.ImportStatus{
+				domain.ImportStatusPending, domain.ImportStatusProcessing,
+			}, now).
+			Order("created_at ASC").
+			Limit(1).
+			Find(&imp).Error
+		if err != nil || imp.UUID == "" {
+			return err
+		}
+		leasedUntil := now.Add(lease)
+		imp.Status, imp.LeasedUntil = domain.ImportStatusProcessing, &leasedUntil
+		if imp.StartedAt == nil {
+			imp.StartedAt = &now
+		}
+		return tx.Model(&imp).Select("status", "leased_until", "started_at").Updates(&imp).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't claim an import: %v", repository.ErrStorage, err)
+	}
+	if imp.UUID == "" {
+		return nil, nil
+	}
+	return &imp, nil
+}
+
+// SaveImportRows records the outcome of a batch of an import's rows, renews
+// the import's lease and recounts its progress from its rows. The import is
+// completed once none of its rows is pending
+func (p *PostgresDB) SaveImportRows(
+	ctx context.Context,
+	imp *domain.Import,
+	rows []*domain.ImportRow,
+	now time.Time,
+	lease time.Duration,
+) (*domain.Import, error) {
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := tx.Model(row).Select("status", "message").Updates(row).Error; err != nil {
+				return err
+			}
+		}
+		var counts []struct {
+			Status domain.ImportRowStatus
+			Total  int
+		}
+		err := tx.Model(&domain.ImportRow{}).
+			Select("status, COUNT(*) AS total").
+			Where("import_uuid = ?", imp.UUID).
+			Group("status").
+			Scan(&counts).Error
+		if err != nil {
+			return err
+		}
+		imp.SucceededRows, imp.FailedRows, imp.ProcessedRows = 0, 0, 0
+		pending := 0
+		for _, count := range counts {
+			switch count.Status {
+			case domain.ImportRowSucceeded:
+				imp.SucceededRows = count.Total
+			case domain.ImportRowFailed:
+				imp.FailedRows = count.Total
+			default:
+				pending += count.Total
+			}
+		}
+		imp.ProcessedRows = imp.SucceededRows + imp.FailedRows
+		leasedUntil := now.Add(lease)
+		imp.LeasedUntil = &leasedUntil
+		if pending == 0 {
+			imp.Status, imp.LeasedUntil, imp.FinishedAt = domain.ImportStatusCompleted, nil, &now
+		}
+		return tx.Model(imp).
+			Select("status", "processed_rows", "succeeded_rows", "failed_rows", "leased_until", "finished_at").
+			Updates(imp).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't save rows of import %s: %v", repository.ErrStorage, imp.UUID, err)
+	}
+	return imp, nil
+}
+
 // CreateStudentProfile creates a new student profile in sudoCODE academy
 // func (p *PostgresDB) CreateStudentProfile(
 // 	ctx context.Context,
 // 	studentProfile *domain.StudentProfile,
 // ) (*domain.StudentProfile, error) {
 // 	if err := p.DB.Create(studentProfile).Error; err != nil {
-// 		return nil, fmt.Errorf("infrastructure: can't create a new student profile: %v", err)
+// 		return nil, fmt.Errorf("%w: can't create a new student profile: %v", repository.ErrStorage, err)
 // 	}
 // 	return studentProfile, nil
 // }
@@ -171,7 +3728,7 @@ func (p *PostgresDB) GetStudent(
 // 	}
 // 	var studentProfile domain.StudentProfile
 // 	if err := p.DB.Where(filters).Find(&studentProfile).Error; err != nil {
-// 		return nil, fmt.Errorf("infrastructure: can't get student profile with UUID %v err: %v", studentUUID, err)
+// 		return nil, fmt.Errorf("%w: can't get student profile with UUID %v err: %v", repository.ErrStorage, studentUUID, err)
 // 	}
 // 	if studentProfile.UUID == "" {
 // 		return nil, nil
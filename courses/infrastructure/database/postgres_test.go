@@ -2,10 +2,13 @@ package database_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/MelvinKim/courses/domain"
 	"github.com/MelvinKim/courses/infrastructure/database"
+	"github.com/MelvinKim/courses/repository"
 	"github.com/brianvoe/gofakeit/v6"
 )
 
@@ -187,7 +190,7 @@ func TestPostgresDB_AssignCourseToStudent(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := p.AssignCourseToStudent(tt.args.ctx, tt.args.email, tt.args.courseTitle)
+			_, err := p.AssignCourseToStudent(tt.args.ctx, tt.args.email, tt.args.courseTitle, nil, nil)
 			if err != nil != tt.wantErr {
 				t.Errorf("PostgresDB.AssignCourseToStudent() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -204,3 +207,168 @@ func TestPostgresDB_AssignCourseToStudent(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresDB_GetCourseCurriculum(t *testing.T) {
+	ctx := context.Background()
+	p := database.NewPostgresDB()
+	course := &domain.Course{
+		Title:       gofakeit.LastName(),
+		Price:       gofakeit.UintRange(10, 50),
+		Description: "A nice course",
+		Instructor:  gofakeit.Name(),
+		Category:    gofakeit.CarMaker(),
+	}
+	course, err := p.CreateCourse(ctx, course)
+	if err != nil {
+		t.Fatalf("error while creating test course: %v", err)
+	}
+	first, err := p.CreateModule(ctx, &domain.Module{CourseUUID: course.UUID, Title: "Basics"})
+	if err != nil {
+		t.Fatalf("error while creating test module: %v", err)
+	}
+	second, err := p.CreateModule(ctx, &domain.Module{CourseUUID: course.UUID, Title: "Concurrency"})
+	if err != nil {
+		t.Fatalf("error while creating test module: %v", err)
+	}
+	_, err = p.CreateLesson(ctx, &domain.Lesson{
+		ModuleUUID: second.UUID,
+		Title:      "Goroutines",
+		Type:       domain.LessonTypeVideo,
+		ContentURL: gofakeit.URL(),
+	})
+	if err != nil {
+		t.Fatalf("error while creating test lesson: %v", err)
+	}
+	if err := p.ReorderModules(ctx, &course.UUID, []string{second.UUID, first.UUID}); err != nil {
+		t.Fatalf("error while reordering test modules: %v", err)
+	}
+
+	type args struct {
+		ctx        context.Context
+		courseUUID *string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Happy case",
+			args: args{
+				ctx:        ctx,
+				courseUUID: &course.UUID,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			curriculum, err := p.GetCourseCurriculum(tt.args.ctx, tt.args.courseUUID)
+			if err != nil != tt.wantErr {
+				t.Errorf("PostgresDB.GetCourseCurriculum() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && curriculum != nil {
+				if len(curriculum.Modules) != 2 {
+					t.Fatalf("expected 2 modules, got %d", len(curriculum.Modules))
+				}
+				if curriculum.Modules[0].UUID != second.UUID {
+					t.Fatalf("expected the reordered module to come first")
+				}
+				if len(curriculum.Modules[0].Lessons) != 1 {
+					t.Fatalf("expected 1 lesson, got %d", len(curriculum.Modules[0].Lessons))
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresDB_AddCoursePrerequisite(t *testing.T) {
+	ctx := context.Background()
+	p := database.NewPostgresDB()
+	var courses []*domain.Course
+	for i := 0; i < 3; i++ {
+		course, err := p.CreateCourse(ctx, &domain.Course{
+			Title:       gofakeit.UUID(),
+			Price:       gofakeit.UintRange(10, 50),
+			Description: "A nice course",
+			Instructor:  gofakeit.Name(),
+			Category:    gofakeit.CarMaker(),
+		})
+		if err != nil {
+			t.Fatalf("error while creating test course: %v", err)
+		}
+		courses = append(courses, course)
+	}
+	basics, golang, concurrency := courses[0], courses[1], courses[2]
+	for _, edge := range [][2]*domain.Course{{golang, basics}, {concurrency, golang}} {
+		err := p.AddCoursePrerequisite(ctx, &domain.CoursePrerequisite{CourseUUID: edge[0].UUID, PrerequisiteUUID: edge[1].UUID})
+		if err != nil {
+			t.Fatalf("error while adding test prerequisite: %v", err)
+		}
+	}
+
+	err := p.AddCoursePrerequisite(ctx, &domain.CoursePrerequisite{CourseUUID: basics.UUID, PrerequisiteUUID: concurrency.UUID})
+	if !errors.Is(err, domain.ErrPrerequisiteCycle) {
+		t.Errorf("PostgresDB.AddCoursePrerequisite() error = %v, want %v", err, domain.ErrPrerequisiteCycle)
+	}
+	missing := gofakeit.UUID()
+	err = p.AddCoursePrerequisite(ctx, &domain.CoursePrerequisite{CourseUUID: basics.UUID, PrerequisiteUUID: missing})
+	var notFound *domain.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("PostgresDB.AddCoursePrerequisite() error = %v, want the missing course not found", err)
+	}
+}
+
+func TestPostgresDB_RevokeCertificate(t *testing.T) {
+	ctx := context.Background()
+	p := database.NewPostgresDB()
+	serial := "SC-2023-" + gofakeit.LetterN(10)
+
+	err := p.RevokeCertificate(ctx, &serial, "issued in error", time.Now())
+	var notFound *domain.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("PostgresDB.RevokeCertificate() error = %v, want the certificate not found", err)
+	}
+}
+
+func TestPostgresDB_Delete_NotFound(t *testing.T) {
+	ctx := context.Background()
+	p := database.NewPostgresDB()
+	missing := gofakeit.UUID()
+
+	var notFound *domain.NotFoundError
+	if err := p.DeleteStudent(ctx, &missing); !errors.As(err, &notFound) || errors.Is(err, repository.ErrStorage) {
+		t.Errorf("PostgresDB.DeleteStudent() error = %v, want the student not found", err)
+	}
+	if err := p.DeleteCourse(ctx, &missing); !errors.As(err, &notFound) || errors.Is(err, repository.ErrStorage) {
+		t.Errorf("PostgresDB.DeleteCourse() error = %v, want the course not found", err)
+	}
+}
+
+func TestPostgresDB_UpdateCatalog_NotFound(t *testing.T) {
+	ctx := context.Background()
+	p := database.NewPostgresDB()
+
+	var notFound *domain.NotFoundError
+	category := &domain.Category{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Name: "Backend", Slug: "backend-" + gofakeit.LetterN(8)}
+	if _, err := p.UpdateCategory(ctx, category); !errors.As(err, &notFound) || errors.Is(err, repository.ErrStorage) {
+		t.Errorf("PostgresDB.UpdateCategory() error = %v, want the category not found", err)
+	}
+	instructor := &domain.Instructor{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Name: gofakeit.Name()}
+	if _, err := p.UpdateInstructor(ctx, instructor); !errors.As(err, &notFound) || errors.Is(err, repository.ErrStorage) {
+		t.Errorf("PostgresDB.UpdateInstructor() error = %v, want the instructor not found", err)
+	}
+}
+
+func TestPostgresDB_UnenrollStudent_NotEnrolled(t *testing.T) {
+	ctx := context.Background()
+	p := database.NewPostgresDB()
+	studentUUID, courseUUID := gofakeit.UUID(), gofakeit.UUID()
+
+	err := p.UnenrollStudent(ctx, &studentUUID, &courseUUID)
+	var notFound *domain.NotFoundError
+	if !errors.As(err, &notFound) || errors.Is(err, repository.ErrStorage) {
+		t.Errorf("PostgresDB.UnenrollStudent() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// Hook reacts to a published event
+type Hook func(ctx context.Context, event domain.Event) error
+
+// HookPublisher publishes events through another publisher and then runs the
+// hooks registered for the event's name. A failing hook is logged and does not
+// fail the publish, since the event has already gone out
+type HookPublisher struct {
+	next  Publisher
+	hooks map[string][]Hook
+}
+
+// NewHookPublisher initializes a new HookPublisher
+func NewHookPublisher(next Publisher) *HookPublisher {
+	return &HookPublisher{
+		next:  next,
+		hooks: map[string][]Hook{},
+	}
+}
+
+// On registers a hook for events with the given name
+func (h *HookPublisher) On(eventName string, hook Hook) *HookPublisher {
+	h.hooks[eventName] = append(h.hooks[eventName], hook)
+	return h
+}
+
+// Publish publishes the event and runs its hooks
+func (h *HookPublisher) Publish(
+	ctx context.Context,
+	event domain.Event,
+) error {
+	if err := h.next.Publish(ctx, event); err != nil {
+		return err
+	}
+	for _, hook := range h.hooks[event.EventName()] {
+		if err := hook(ctx, event); err != nil {
+			log.WithFields(log.Fields{
+				"event": event.EventName(),
+				"error": err,
+			}).Error("event hook failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/events"
+	"github.com/MelvinKim/courses/infrastructure/events/mock"
+)
+
+func TestHookPublisher_Publish(t *testing.T) {
+	ctx := context.Background()
+	next := mock.NewMockPublisher()
+	var published []string
+	next.MockPublish = func(ctx context.Context, event domain.Event) error {
+		published = append(published, event.EventName())
+		return nil
+	}
+
+	var hooked []string
+	publisher := events.NewHookPublisher(next).
+		On("waitlist.promoted", func(ctx context.Context, event domain.Event) error {
+			hooked = append(hooked, event.(domain.WaitlistPromoted).StudentUUID)
+			return nil
+		}).
+		On("waitlist.promoted", func(ctx context.Context, event domain.Event) error {
+			return fmt.Errorf("notifications are down")
+		})
+
+	if err := publisher.Publish(ctx, domain.WaitlistPromoted{StudentUUID: "student", PromotedAt: time.Now()}); err != nil {
+		t.Fatalf("HookPublisher.Publish() error = %v", err)
+	}
+	if err := publisher.Publish(ctx, domain.CourseCompleted{StudentUUID: "other"}); err != nil {
+		t.Fatalf("HookPublisher.Publish() error = %v", err)
+	}
+	if len(published) != 2 {
+		t.Errorf("expected both events to be published, got %v", published)
+	}
+	if len(hooked) != 1 || hooked[0] != "student" {
+		t.Errorf("expected the hook to run once for the promoted student, got %v", hooked)
+	}
+}
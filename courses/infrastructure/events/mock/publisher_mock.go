@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// MockPublisher mocks the events publisher
+type MockPublisher struct {
+	MockPublish func(
+		ctx context.Context,
+		event domain.Event,
+	) error
+}
+
+// NewMockPublisher initializes a new MockPublisher
+func NewMockPublisher() *MockPublisher {
+	return &MockPublisher{
+		MockPublish: func(ctx context.Context, event domain.Event) error {
+			return nil
+		},
+	}
+}
+
+// Publish mocks Publish
+func (m *MockPublisher) Publish(
+	ctx context.Context,
+	event domain.Event,
+) error {
+	return m.MockPublish(ctx, event)
+}
@@ -0,0 +1,38 @@
+package events
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// Publisher defines the contract for broadcasting domain events
+type Publisher interface {
+	Publish(
+		ctx context.Context,
+		event domain.Event,
+	) error
+}
+
+// LogPublisher publishes events to the service logs. It stands in until the
+// services are wired to a message broker
+type LogPublisher struct{}
+
+// NewLogPublisher initializes a new LogPublisher
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+// Publish logs the event
+func (l *LogPublisher) Publish(
+	ctx context.Context,
+	event domain.Event,
+) error {
+	log.WithFields(log.Fields{
+		"event":   event.EventName(),
+		"payload": event,
+	}).Info("event published")
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package exports writes the students, courses and enrollments of bulk
+// exports as CSV, NDJSON or Parquet, a row at a time
+package exports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// Type is the type of a column's values
+type Type int
+
+const (
+	// String columns hold strings
+	String Type = iota
+	// Int columns hold int64s
+	Int
+	// Float columns hold float64s
+	Float
+	// Bool columns hold bools
+	Bool
+	// Timestamp columns hold times, written in UTC
+	Timestamp
+)
+
+// Column is a column of an exported table. Its value for a row is nil when
+// the row has none
+type Column struct {
+	Name  string
+	Type  Type
+	value func(row interface{}) interface{}
+}
+
+// Value returns the column's value for a row of its table
+func (c Column) Value(row interface{}) interface{} {
+	return c.value(row)
+}
+
+// Table is a table that can be exported, with its columns in their default
+// order
+type Table struct {
+	Name    domain.ExportTable
+	Columns []Column
+}
+
+// Select returns the named columns in the order given, or every column when
+// no name is given
+func (t *Table) Select(names []string) ([]Column, error) {
+	if len(names) == 0 {
+		return t.Columns, nil
+	}
+	columns := make([]Column, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		column, ok := t.column(name)
+		if !ok {
+			return nil, fmt.Errorf("%s have no %q column, use any of %s", t.Name, name, strings.Join(t.ColumnNames(), ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("column %q is selected twice", name)
+		}
+		seen[name] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// ColumnNames returns the names of the table's columns
+func (t *Table) ColumnNames() []string {
+	names := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+func (t *Table) column(name string) (Column, bool) {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+// Tables are the tables that can be exported
+var Tables = map[domain.ExportTable]*Table{
+	domain.ExportTableStudents:    Students,
+	domain.ExportTableCourses:     Courses,
+	domain.ExportTableEnrollments: Enrollments,
+}
+
+// Students exports domain.Student rows
+var Students = &Table{
+	Name: domain.ExportTableStudents,
+	Columns: []Column{
+		{"uuid", String, func(row interface{}) interface{} { return row.(*domain.Student).UUID }},
+		{"email", String, func(row interface{}) interface{} { return row.(*domain.Student).Email }},
+		{"first_name", String, func(row interface{}) interface{} { return row.(*domain.Student).FirstName }},
+		{"last_name", String, func(row interface{}) interface{} { return row.(*domain.Student).LastName }},
+		{"active", Bool, func(row interface{}) interface{} { return row.(*domain.Student).Active }},
+		{"created_at", Timestamp, func(row interface{}) interface{} { return timestamp(row.(*domain.Student).CreatedAt) }},
+		{"updated_at", Timestamp, func(row interface{}) interface{} { return timestamp(row.(*domain.Student).UpdatedAt) }},
+	},
+}
+
+// Courses exports domain.Course rows
+var Courses = &Table{
+	Name: domain.ExportTableCourses,
+	Columns: []Column{
+		{"uuid", String, func(row interface{}) interface{} { return row.(*domain.Course).UUID }},
+		{"title", String, func(row interface{}) interface{} { return row.(*domain.Course).Title }},
+		{"description", String, func(row interface{}) interface{} { return row.(*domain.Course).Description }},
+		{"price", Int, func(row interface{}) interface{} { return int64(row.(*domain.Course).Price) }},
+		{"instructor", String, func(row interface{}) interface{} { return row.(*domain.Course).Instructor }},
+		{"instructor_uuid", String, func(row interface{}) interface{} { return text(row.(*domain.Course).InstructorUUID) }},
+		{"category", String, func(row interface{}) interface{} { return row.(*domain.Course).Category }},
+		{"category_uuid", String, func(row interface{}) interface{} { return text(row.(*domain.Course).CategoryUUID) }},
+		{"capacity", Int, func(row interface{}) interface{} { return int64(row.(*domain.Course).Capacity) }},
+		{"average_rating", Float, func(row interface{}) interface{} { return row.(*domain.Course).AverageRating }},
+		{"rating_count", Int, func(row interface{}) interface{} { return int64(row.(*domain.Course).RatingCount) }},
+		{"active", Bool, func(row interface{}) interface{} { return row.(*domain.Course).Active }},
+		{"created_at", Timestamp, func(row interface{}) interface{} { return timestamp(row.(*domain.Course).CreatedAt) }},
+		{"updated_at", Timestamp, func(row interface{}) interface{} { return timestamp(row.(*domain.Course).UpdatedAt) }},
+	},
+}
+
+// Enrollments exports domain.StudentCourse rows
+var Enrollments = &Table{
+	Name: domain.ExportTableEnrollments,
+	Columns: []Column{
+		{"student_uuid", String, func(row interface{}) interface{} { return row.(*domain.StudentCourse).StudentUUID }},
+		{"course_uuid", String, func(row interface{}) interface{} { return row.(*domain.StudentCourse).CourseUUID }},
+		{"run_uuid", String, func(row interface{}) interface{} { return optional(row.(*domain.StudentCourse).RunUUID) }},
+		{"status", String, func(row interface{}) interface{} { return string(row.(*domain.StudentCourse).Status) }},
+		{"enrolled_at", Timestamp, func(row interface{}) interface{} { return timestamp(row.(*domain.StudentCourse).EnrolledAt) }},
+		{"completed_at", Timestamp, func(row interface{}) interface{} { return timestamp(row.(*domain.StudentCourse).CompletedAt) }},
+	},
+}
+
+// timestamp returns a time in UTC, or nil for none
+func timestamp(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// text returns a string, or nil for none
+func text(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// optional returns a string, or nil when it is empty
+func optional(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
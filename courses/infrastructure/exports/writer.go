@@ -0,0 +1,180 @@
+package exports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// ParquetRowGroupSize bounds how much of a Parquet file is held in memory
+// before it is written out as a row group
+const ParquetRowGroupSize = 16 << 20
+
+// Writer writes the rows of a table in a file format. Close must be called
+// once every row is written to finish the file
+type Writer interface {
+	Write(row interface{}) error
+	Close() error
+}
+
+// ContentTypes are the media types of the formats
+var ContentTypes = map[domain.ExportFormat]string{
+	domain.ExportFormatCSV:     "text/csv",
+	domain.ExportFormatNDJSON:  "application/x-ndjson",
+	domain.ExportFormatParquet: "application/vnd.apache.parquet",
+}
+
+// NewWriter starts a file of the columns in a format. CSV files start with a
+// header naming the columns. NDJSON files have an object per row with the
+// columns as keys, in order. Parquet files have an optional column for each
+// column, compressed with Snappy, with timestamps in microseconds since the
+// epoch
+func NewWriter(format domain.ExportFormat, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case domain.ExportFormatCSV:
+		return newCSVWriter(w, columns)
+	case domain.ExportFormatNDJSON:
+		return newNDJSONWriter(w, columns)
+	case domain.ExportFormatParquet:
+		return newParquetWriter(w, columns)
+	}
+	return nil, fmt.Errorf("exports must be %s, %s or %s, not %q", domain.ExportFormatCSV, domain.ExportFormatNDJSON, domain.ExportFormatParquet, format)
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []Column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	for i, column := range columns {
+		cw.record[i] = column.Name
+	}
+	return cw, cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Write(row interface{}) error {
+	for i, column := range cw.columns {
+		switch value := column.Value(row).(type) {
+		case nil:
+			cw.record[i] = ""
+		case string:
+			cw.record[i] = value
+		case int64:
+			cw.record[i] = strconv.FormatInt(value, 10)
+		case float64:
+			cw.record[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		case bool:
+			cw.record[i] = strconv.FormatBool(value)
+		case time.Time:
+			cw.record[i] = value.Format(time.RFC3339Nano)
+		default:
+			return fmt.Errorf("can't write %s of type %T", column.Name, value)
+		}
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	keys    [][]byte
+}
+
+func newNDJSONWriter(w io.Writer, columns []Column) (*ndjsonWriter, error) {
+	nw := &ndjsonWriter{w: bufio.NewWriter(w), columns: columns, keys: make([][]byte, len(columns))}
+	for i, column := range columns {
+		key, err := json.Marshal(column.Name)
+		if err != nil {
+			return nil, err
+		}
+		nw.keys[i] = key
+	}
+	return nw, nil
+}
+
+// Write writes the row's object key by key, since maps would lose the order
+// of the columns
+func (nw *ndjsonWriter) Write(row interface{}) error {
+	_ = nw.w.WriteByte('{')
+	for i, column := range nw.columns {
+		if i > 0 {
+			_ = nw.w.WriteByte(',')
+		}
+		value, err := json.Marshal(column.Value(row))
+		if err != nil {
+			return fmt.Errorf("can't write %s: %w", column.Name, err)
+		}
+		_, _ = nw.w.Write(nw.keys[i])
+		_ = nw.w.WriteByte(':')
+		_, _ = nw.w.Write(value)
+	}
+	_, err := nw.w.WriteString("}\n")
+	return err
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.w.Flush()
+}
+
+type parquetWriter struct {
+	w       *writer.CSVWriter
+	columns []Column
+}
+
+// parquetTypes are the Parquet schema of each type of column
+var parquetTypes = map[Type]string{
+	String:    "type=BYTE_ARRAY, convertedtype=UTF8",
+	Int:       "type=INT64",
+	Float:     "type=DOUBLE",
+	Bool:      "type=BOOLEAN",
+	Timestamp: "type=INT64, convertedtype=TIMESTAMP_MICROS",
+}
+
+func newParquetWriter(w io.Writer, columns []Column) (*parquetWriter, error) {
+	schema := make([]string, len(columns))
+	for i, column := range columns {
+		schema[i] = fmt.Sprintf("name=%s, %s, repetitiontype=OPTIONAL", column.Name, parquetTypes[column.Type])
+	}
+	pw, err := writer.NewCSVWriterFromWriter(schema, w, 1)
+	if err != nil {
+		return nil, fmt.Errorf("can't start Parquet file: %w", err)
+	}
+	pw.RowGroupSize = ParquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	return &parquetWriter{w: pw, columns: columns}, nil
+}
+
+// Write adds the row to the row group being built, which holds on to the
+// row's record until the group is written out
+func (pw *parquetWriter) Write(row interface{}) error {
+	record := make([]interface{}, len(pw.columns))
+	for i, column := range pw.columns {
+		value := column.Value(row)
+		if t, ok := value.(time.Time); ok {
+			value = t.UnixMicro()
+		}
+		record[i] = value
+	}
+	return pw.w.Write(record)
+}
+
+func (pw *parquetWriter) Close() error {
+	return pw.w.WriteStop()
+}
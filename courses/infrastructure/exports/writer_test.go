@@ -0,0 +1,134 @@
+package exports_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/exports"
+)
+
+var (
+	enrolledAt = time.Date(2023, 5, 1, 9, 30, 0, 0, time.FixedZone("EAT", 3*60*60))
+	rows       = []interface{}{
+		&domain.StudentCourse{StudentUUID: "s1", CourseUUID: "c1", RunUUID: "r1", Status: domain.EnrollmentStatusActive, EnrolledAt: &enrolledAt},
+		&domain.StudentCourse{StudentUUID: "s2", CourseUUID: "c1", Status: domain.EnrollmentStatusCompleted},
+	}
+)
+
+func export(t *testing.T, format domain.ExportFormat, columns []string) []byte {
+	t.Helper()
+	selected, err := exports.Enrollments.Select(columns)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	var buf bytes.Buffer
+	w, err := exports.NewWriter(format, &buf, selected)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestTable_Select(t *testing.T) {
+	columns, err := exports.Students.Select([]string{"email", " uuid"})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(columns) != 2 || columns[0].Name != "email" || columns[1].Name != "uuid" {
+		t.Errorf("expected email then uuid, got %+v", columns)
+	}
+	if columns, _ := exports.Students.Select(nil); len(columns) != len(exports.Students.Columns) {
+		t.Errorf("expected every column when none is selected, got %d", len(columns))
+	}
+	for _, names := range [][]string{{"password"}, {"email", "email"}} {
+		if _, err := exports.Students.Select(names); err == nil {
+			t.Errorf("expected %q to be refused", names)
+		}
+	}
+}
+
+func TestNewWriter_CSV(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(export(t, domain.ExportFormatCSV, []string{"student_uuid", "run_uuid", "enrolled_at"}))).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV: %v", err)
+	}
+	want := [][]string{
+		{"student_uuid", "run_uuid", "enrolled_at"},
+		{"s1", "r1", "2023-05-01T06:30:00Z"},
+		{"s2", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("expected %q, got %q", want, records)
+	}
+}
+
+func TestNewWriter_NDJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(export(t, domain.ExportFormatNDJSON, []string{"status", "student_uuid", "completed_at"}))), "\n")
+	want := []string{
+		`{"status":"active","student_uuid":"s1","completed_at":null}`,
+		`{"status":"completed","student_uuid":"s2","completed_at":null}`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("expected the columns in the order selected, got %q", lines)
+	}
+}
+
+func TestNewWriter_Parquet(t *testing.T) {
+	file, err := buffer.NewBufferFile(export(t, domain.ExportFormatParquet, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		t.Fatalf("expected a valid Parquet file: %v", err)
+	}
+	defer pr.ReadStop()
+	if pr.GetNumRows() != int64(len(rows)) {
+		t.Fatalf("expected %d rows, got %d", len(rows), pr.GetNumRows())
+	}
+	names := []string{}
+	for _, info := range pr.SchemaHandler.Infos[1:] {
+		names = append(names, info.ExName)
+	}
+	if !reflect.DeepEqual(names, exports.Enrollments.ColumnNames()) {
+		t.Errorf("expected the columns %q, got %q", exports.Enrollments.ColumnNames(), names)
+	}
+
+	students, _, _, err := pr.ReadColumnByIndex(0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(students, []interface{}{"s1", "s2"}) {
+		t.Errorf("unexpected student_uuid column %v", students)
+	}
+	enrolled, _, _, err := pr.ReadColumnByIndex(4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(enrolled, []interface{}{enrolledAt.UnixMicro(), nil}) {
+		t.Errorf("expected enrolled_at in microseconds and null when missing, got %v", enrolled)
+	}
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := exports.NewWriter("xlsx", &buf, exports.Students.Columns); err == nil || buf.Len() > 0 {
+		t.Errorf("expected the format to be refused before anything is written")
+	}
+}
@@ -0,0 +1,157 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// ErrRateUnavailable is returned when there is no exchange rate between two
+// currencies
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// RateProvider defines the contract for looking up exchange rates. A rate is
+// how many units of the target currency one unit of the source currency buys
+type RateProvider interface {
+	Rate(ctx context.Context, from string, to string) (*big.Rat, error)
+}
+
+// StaticRates is a RateProvider serving a fixed table of rates, for local
+// development and tests. Inverse rates are derived, and currencies without a
+// direct rate are converted through the base currency
+type StaticRates struct {
+	mu    sync.RWMutex
+	rates map[string]map[string]*big.Rat
+}
+
+// NewStaticRates creates an empty rate table
+func NewStaticRates() *StaticRates {
+	return &StaticRates{rates: map[string]map[string]*big.Rat{}}
+}
+
+// NewStaticRatesFromEnv loads rates from the FX_RATES env variable, a comma
+// separated list of pairs such as "USD/KES=129.5,USD/EUR=0.92"
+func NewStaticRatesFromEnv() *StaticRates {
+	rates := NewStaticRates()
+	table := os.Getenv("FX_RATES")
+	if table == "" {
+		log.Warn("FX_RATES is not set, course prices are only shown in the currencies they are listed in")
+		return rates
+	}
+	for _, entry := range strings.Split(table, ",") {
+		pair, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		from, to, okPair := strings.Cut(pair, "/")
+		rate, okRate := new(big.Rat).SetString(value)
+		if !ok || !okPair || !okRate {
+			log.Fatalf("invalid FX_RATES entry %q, expected FROM/TO=RATE", entry)
+		}
+		if err := rates.Set(from, to, rate); err != nil {
+			log.Fatalf("invalid FX_RATES entry %q: %v", entry, err)
+		}
+	}
+	return rates
+}
+
+// Set records the rate from one currency to another
+func (s *StaticRates) Set(from string, to string, rate *big.Rat) error {
+	from, err := domain.NormalizeCurrency(from)
+	if err != nil {
+		return err
+	}
+	to, err = domain.NormalizeCurrency(to)
+	if err != nil {
+		return err
+	}
+	if rate.Sign() <= 0 {
+		return fmt.Errorf("rate from %s to %s must be positive", from, to)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(from, to, rate)
+	s.set(to, from, new(big.Rat).Inv(rate))
+	return nil
+}
+
+func (s *StaticRates) set(from string, to string, rate *big.Rat) {
+	if s.rates[from] == nil {
+		s.rates[from] = map[string]*big.Rat{}
+	}
+	s.rates[from][to] = rate
+}
+
+// Rate returns the rate from one currency to another
+func (s *StaticRates) Rate(ctx context.Context, from string, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if rate, ok := s.rates[from][to]; ok {
+		return rate, nil
+	}
+	first, okFirst := s.rates[from][domain.BaseCurrency]
+	second, okSecond := s.rates[domain.BaseCurrency][to]
+	if okFirst && okSecond {
+		return new(big.Rat).Mul(first, second), nil
+	}
+	return nil, fmt.Errorf("%w: %s to %s", ErrRateUnavailable, from, to)
+}
+
+// Convert converts money into another currency, rounding half away from zero
+// to the target currency's minor unit
+func Convert(
+	ctx context.Context,
+	rates RateProvider,
+	money domain.Money,
+	to string,
+) (domain.Money, error) {
+	if money.Currency == to {
+		return money, nil
+	}
+	fromExponent, err := domain.CurrencyExponent(money.Currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	toExponent, err := domain.CurrencyExponent(to)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	rate, err := rates.Rate(ctx, money.Currency, to)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	// minor units of the source -> major units -> major units of the target
+	// -> minor units of the target
+	amount := new(big.Rat).SetInt64(money.Amount)
+	amount.Mul(amount, rate)
+	scale := new(big.Rat).SetFrac(pow10(toExponent), pow10(fromExponent))
+	amount.Mul(amount, scale)
+	return domain.Money{Amount: round(amount).Int64(), Currency: to}, nil
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// round rounds a rational to the nearest integer, halves away from zero
+func round(r *big.Rat) *big.Int {
+	numerator := new(big.Int).Abs(r.Num())
+	doubled := new(big.Int).Mul(numerator, big.NewInt(2))
+	doubled.Add(doubled, r.Denom())
+	quotient := new(big.Int).Quo(doubled, new(big.Int).Mul(r.Denom(), big.NewInt(2)))
+	if r.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return quotient
+}
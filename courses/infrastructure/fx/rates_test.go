@@ -0,0 +1,124 @@
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+)
+
+func newRates(t *testing.T) *fx.StaticRates {
+	rates := fx.NewStaticRates()
+	for _, r := range []struct {
+		from, to, rate string
+	}{
+		{"USD", "KES", "129.5"},
+		{"USD", "EUR", "0.92"},
+		{"USD", "JPY", "149.37"},
+		{"USD", "KWD", "0.3075"},
+	} {
+		rate, _ := new(big.Rat).SetString(r.rate)
+		if err := rates.Set(r.from, r.to, rate); err != nil {
+			t.Fatalf("can't set test rate %s/%s: %v", r.from, r.to, err)
+		}
+	}
+	return rates
+}
+
+func TestConvert(t *testing.T) {
+	ctx := context.Background()
+	rates := newRates(t)
+
+	tests := []struct {
+		name    string
+		money   domain.Money
+		to      string
+		want    int64
+		wantErr error
+	}{
+		{
+			name:  "Happy case - same currency",
+			money: domain.Money{Amount: 1999, Currency: "USD"},
+			to:    "USD",
+			want:  1999,
+		},
+		{
+			name:  "Happy case - direct rate",
+			money: domain.Money{Amount: 1000, Currency: "USD"},
+			to:    "KES",
+			want:  129500,
+		},
+		{
+			name:  "Happy case - halves round away from zero",
+			money: domain.Money{Amount: 25, Currency: "USD"},
+			to:    "EUR",
+			want:  23,
+		},
+		{
+			name:  "Happy case - currency without minor units",
+			money: domain.Money{Amount: 1999, Currency: "USD"},
+			to:    "JPY",
+			want:  2986,
+		},
+		{
+			name:  "Happy case - currency with three decimals",
+			money: domain.Money{Amount: 1000, Currency: "USD"},
+			to:    "KWD",
+			want:  3075,
+		},
+		{
+			name:  "Happy case - inverse rate",
+			money: domain.Money{Amount: 129500, Currency: "KES"},
+			to:    "USD",
+			want:  1000,
+		},
+		{
+			name:  "Happy case - cross rate through the base currency",
+			money: domain.Money{Amount: 9200, Currency: "EUR"},
+			to:    "KES",
+			want:  1295000,
+		},
+		{
+			name:    "Sad case - no rate",
+			money:   domain.Money{Amount: 1000, Currency: "USD"},
+			to:      "ZAR",
+			wantErr: fx.ErrRateUnavailable,
+		},
+		{
+			name:    "Sad case - unsupported currency",
+			money:   domain.Money{Amount: 1000, Currency: "USD"},
+			to:      "XYZ",
+			wantErr: domain.ErrUnsupportedCurrency,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fx.Convert(ctx, rates, tt.money, tt.to)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if got.Amount != tt.want || got.Currency != tt.to {
+				t.Errorf("Convert() = %v, want %d %s", got, tt.want, tt.to)
+			}
+		})
+	}
+}
+
+func TestStaticRates_Set(t *testing.T) {
+	rates := fx.NewStaticRates()
+	if err := rates.Set("USD", "KES", big.NewRat(0, 1)); err == nil {
+		t.Errorf("expected a zero rate to be rejected")
+	}
+	if err := rates.Set("USD", "XYZ", big.NewRat(1, 1)); !errors.Is(err, domain.ErrUnsupportedCurrency) {
+		t.Errorf("expected an unsupported currency to be rejected, got %v", err)
+	}
+}
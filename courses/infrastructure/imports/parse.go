@@ -0,0 +1,166 @@
+// Package imports reads the files of bulk imports into rows and writes the
+// report of the rows that failed
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// MaxRows is the most rows a single import can have
+const MaxRows = 10000
+
+// CourseSeparator separates the course titles in a CSV cell
+const CourseSeparator = ";"
+
+// ErrTooManyRows is returned for files with more than MaxRows rows
+var ErrTooManyRows = fmt.Errorf("imports can have at most %d rows", MaxRows)
+
+// columns maps the CSV header names accepted to row fields
+var columns = map[string]func(row *domain.ImportRow, value string){
+	"email":      func(row *domain.ImportRow, value string) { row.Email = value },
+	"first_name": func(row *domain.ImportRow, value string) { row.FirstName = value },
+	"last_name":  func(row *domain.ImportRow, value string) { row.LastName = value },
+	"courses":    func(row *domain.ImportRow, value string) { row.Courses = splitCourses(value) },
+	"course":     func(row *domain.ImportRow, value string) { row.Courses = splitCourses(value) },
+}
+
+// Parse reads the rows of an import file. Rows are numbered by their line in
+// the file. A CSV file starts with a header naming its columns, email,
+// first_name, last_name and courses, in any order; courses holds titles
+// separated by semicolons. NDJSON files have an object per line with the same
+// fields, courses being an array of titles.
+// NDJSON lines that aren't valid JSON are returned as failed rows, while a
+// CSV file that can't be read is refused as a whole
+func Parse(format domain.ImportFormat, r io.Reader) ([]*domain.ImportRow, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return parseCSV(r)
+	case domain.ImportFormatNDJSON:
+		return parseNDJSON(r)
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+func parseCSV(r io.Reader) ([]*domain.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read CSV header: %w", err)
+	}
+	setters := make([]func(row *domain.ImportRow, value string), len(header))
+	found := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		setters[i] = columns[name]
+		found[name] = true
+	}
+	for _, name := range []string{"email", "first_name", "last_name"} {
+		if !found[name] {
+			return nil, fmt.Errorf("the CSV header has no %s column", name)
+		}
+	}
+
+	rows := []*domain.ImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't read CSV: %w", err)
+		}
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+		line, _ := reader.FieldPos(0)
+		row := &domain.ImportRow{Line: line, Courses: domain.StringList{}}
+		for i, value := range record {
+			if i < len(setters) && setters[i] != nil {
+				setters[i](row, value)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// ndjsonRow is a line of an NDJSON import. Courses may also be a single
+// string of titles separated by semicolons, as in CSV files
+type ndjsonRow struct {
+	Email     string          `json:"email"`
+	FirstName string          `json:"first_name"`
+	LastName  string          `json:"last_name"`
+	Courses   json.RawMessage `json:"courses"`
+}
+
+func parseNDJSON(r io.Reader) ([]*domain.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	rows := []*domain.ImportRow{}
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+		row := &domain.ImportRow{Line: line, Courses: domain.StringList{}}
+		rows = append(rows, row)
+
+		var parsed ndjsonRow
+		if err := json.Unmarshal(text, &parsed); err != nil {
+			row.Status, row.Message = domain.ImportRowFailed, fmt.Sprintf("invalid JSON: %v", err)
+			continue
+		}
+		row.Email, row.FirstName, row.LastName = parsed.Email, parsed.FirstName, parsed.LastName
+		courses, err := parseCourses(parsed.Courses)
+		if err != nil {
+			row.Status, row.Message = domain.ImportRowFailed, err.Error()
+			continue
+		}
+		row.Courses = courses
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read NDJSON: %w", err)
+	}
+	return rows, nil
+}
+
+// parseCourses reads an NDJSON row's courses, an array of titles or a string
+// of titles separated by semicolons
+func parseCourses(raw json.RawMessage) (domain.StringList, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return domain.StringList{}, nil
+	}
+	var titles []string
+	if err := json.Unmarshal(raw, &titles); err == nil {
+		return titles, nil
+	}
+	var joined string
+	if err := json.Unmarshal(raw, &joined); err == nil {
+		return splitCourses(joined), nil
+	}
+	return nil, fmt.Errorf("courses must be an array of course titles")
+}
+
+func splitCourses(value string) domain.StringList {
+	if strings.TrimSpace(value) == "" {
+		return domain.StringList{}
+	}
+	return strings.Split(value, CourseSeparator)
+}
@@ -0,0 +1,113 @@
+package imports_test
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/imports"
+)
+
+func TestParse_CSV(t *testing.T) {
+	file := "\ufeffEmail,First_Name,last_name,courses,notes\n" +
+		"jane@example.com,Jane,Doe,\"Go 101;Postgres, the basics\",vip\n" +
+		"\n" +
+		"tom@example.com, Tom ,Kariuki\n"
+
+	rows, err := imports.Parse(domain.ImportFormatCSV, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].Line != 2 || rows[0].Email != "jane@example.com" || rows[0].LastName != "Doe" {
+		t.Errorf("unexpected first row %+v", rows[0])
+	}
+	if !reflect.DeepEqual(rows[0].Courses, domain.StringList{"Go 101", "Postgres, the basics"}) {
+		t.Errorf("expected the courses to be split on semicolons, got %q", rows[0].Courses)
+	}
+	if rows[1].Line != 4 || rows[1].FirstName != "Tom " || len(rows[1].Courses) != 0 {
+		t.Errorf("expected a row without courses on line 4, got %+v", rows[1])
+	}
+}
+
+func TestParse_CSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{name: "empty file", file: ""},
+		{name: "missing column", file: "email,first_name\njane@example.com,Jane\n"},
+		{name: "broken quotes", file: "email,first_name,last_name\n\"jane@example.com,Jane,Doe\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := imports.Parse(domain.ImportFormatCSV, strings.NewReader(tt.file)); err == nil {
+				t.Errorf("expected the file to be refused")
+			}
+		})
+	}
+}
+
+func TestParse_NDJSON(t *testing.T) {
+	file := `{"email": "jane@example.com", "first_name": "Jane", "last_name": "Doe", "courses": ["Go 101"]}
+
+{"email": "tom@example.com", "first_name": "Tom", "last_name": "Kariuki", "courses": "Go 101;Docker"}
+{"email": "broken
+{"email": "amina@example.com", "first_name": "Amina", "last_name": "Hassan", "courses": 7}
+`
+	rows, err := imports.Parse(domain.ImportFormatNDJSON, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+	if rows[0].Line != 1 || rows[0].Status != "" || !reflect.DeepEqual(rows[0].Courses, domain.StringList{"Go 101"}) {
+		t.Errorf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].Line != 3 || !reflect.DeepEqual(rows[1].Courses, domain.StringList{"Go 101", "Docker"}) {
+		t.Errorf("expected courses given as a string to be split, got %+v", rows[1])
+	}
+	for _, row := range rows[2:] {
+		if row.Status != domain.ImportRowFailed || row.Message == "" {
+			t.Errorf("expected line %d to fail, got %+v", row.Line, row)
+		}
+	}
+}
+
+func TestParse_TooManyRows(t *testing.T) {
+	var file strings.Builder
+	file.WriteString("email,first_name,last_name\n")
+	for i := 0; i <= imports.MaxRows; i++ {
+		fmt.Fprintf(&file, "student%d@example.com,Student,%d\n", i, i)
+	}
+	if _, err := imports.Parse(domain.ImportFormatCSV, strings.NewReader(file.String())); !errors.Is(err, imports.ErrTooManyRows) {
+		t.Errorf("expected ErrTooManyRows, got %v", err)
+	}
+}
+
+func TestRenderErrorReport(t *testing.T) {
+	report, err := imports.RenderErrorReport([]*domain.ImportRow{
+		{Line: 3, Email: "tom@example", FirstName: "Tom", Courses: domain.StringList{"Go 101", "Docker"}, Message: "invalid email"},
+	})
+	if err != nil {
+		t.Fatalf("RenderErrorReport() error = %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(report))).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV: %v", err)
+	}
+	want := [][]string{
+		{"line", "email", "first_name", "last_name", "courses", "error"},
+		{"3", "tom@example", "Tom", "", "Go 101;Docker", "invalid email"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("expected %q, got %q", want, records)
+	}
+}
@@ -0,0 +1,35 @@
+package imports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// RenderErrorReport writes the failed rows of an import as CSV, in the same
+// columns an import file has plus the line each row was on and why it failed,
+// so the rows can be fixed and imported again
+func RenderErrorReport(rows []*domain.ImportRow) ([]byte, error) {
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	_ = writer.Write([]string{"line", "email", "first_name", "last_name", "courses", "error"})
+	for _, row := range rows {
+		_ = writer.Write([]string{
+			strconv.Itoa(row.Line),
+			row.Email,
+			row.FirstName,
+			row.LastName,
+			strings.Join(row.Courses, CourseSeparator),
+			row.Message,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("can't render import error report: %w", err)
+	}
+	return out.Bytes(), nil
+}
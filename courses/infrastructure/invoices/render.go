@@ -0,0 +1,104 @@
+package invoices
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/pdf"
+)
+
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+	rightEdge  = pageWidth - margin
+)
+
+var receiptTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; max-width: 640px; margin: 40px auto; color: #222; }
+table { width: 100%; border-collapse: collapse; }
+th, td { padding: 6px 0; text-align: left; }
+.amount { text-align: right; }
+.total td { border-top: 1px solid #222; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>sudoCODE Academy</h1>
+<h2>Receipt {{.Number}}</h2>
+<p>Issued on {{.IssuedAt.UTC.Format "2 January 2006"}}<br>
+Billed to {{.BilledTo}}{{if .BilledEmail}} &lt;{{.BilledEmail}}&gt;{{end}}<br>
+Payment reference {{.Reference}}</p>
+<table>
+<tr><th>Description</th><th class="amount">Qty</th><th class="amount">Unit price</th><th class="amount">Amount</th></tr>
+{{range .Lines}}<tr><td>{{.Description}}</td><td class="amount">{{.Quantity}}</td><td class="amount">{{.UnitPrice}}</td><td class="amount">{{.Amount}}</td></tr>
+{{end}}<tr><td colspan="3">Subtotal</td><td class="amount">{{.Subtotal}}</td></tr>
+{{range .TaxLines}}<tr><td colspan="3">{{.Label}}</td><td class="amount">{{.Amount}}</td></tr>
+{{end}}<tr class="total"><td colspan="3">Total paid</td><td class="amount">{{.Total}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// RenderHTML renders an invoice as an HTML receipt
+func RenderHTML(invoice *domain.Invoice) ([]byte, error) {
+	var out bytes.Buffer
+	if err := receiptTemplate.Execute(&out, invoice); err != nil {
+		return nil, fmt.Errorf("can't render receipt %s: %w", invoice.Number, err)
+	}
+	return out.Bytes(), nil
+}
+
+// RenderPDF renders an invoice as a single page, portrait A4 receipt
+func RenderPDF(invoice *domain.Invoice) []byte {
+	page := pdf.NewPage(pageWidth, pageHeight)
+	page.Text("F2", 20, margin, 780, "sudoCODE Academy")
+	page.Text("F2", 16, margin, 745, "Receipt "+invoice.Number)
+	page.Text("F1", 10, margin, 720, "Issued on "+invoice.IssuedAt.UTC().Format("2 January 2006"))
+	billedTo := "Billed to " + invoice.BilledTo
+	if invoice.BilledEmail != "" {
+		billedTo += " <" + invoice.BilledEmail + ">"
+	}
+	page.Text("F1", 10, margin, 705, billedTo)
+	page.Text("F1", 10, margin, 690, "Payment reference "+invoice.Reference)
+
+	y := 650.0
+	page.Text("F2", 10, margin, y, "Description")
+	right(page, "F2", 10, 360, y, "Qty")
+	right(page, "F2", 10, 450, y, "Unit price")
+	right(page, "F2", 10, rightEdge, y, "Amount")
+	page.Draw(fmt.Sprintf("0.5 w %.0f %.0f m %.0f %.0f l S", margin, y-6, rightEdge, y-6))
+	for _, line := range invoice.Lines {
+		y -= 20
+		page.Text("F1", 10, margin, y, line.Description)
+		right(page, "F1", 10, 360, y, fmt.Sprintf("%d", line.Quantity))
+		right(page, "F1", 10, 450, y, line.UnitPrice.String())
+		right(page, "F1", 10, rightEdge, y, line.Amount.String())
+	}
+
+	y -= 30
+	page.Text("F1", 10, 300, y, "Subtotal")
+	right(page, "F1", 10, rightEdge, y, invoice.Subtotal.String())
+	for _, tax := range invoice.TaxLines {
+		y -= 16
+		page.Text("F1", 10, 300, y, tax.Label)
+		right(page, "F1", 10, rightEdge, y, tax.Amount.String())
+	}
+	y -= 10
+	page.Draw(fmt.Sprintf("1 w 300 %.0f m %.0f %.0f l S", y, rightEdge, y))
+	y -= 16
+	page.Text("F2", 11, 300, y, "Total paid")
+	right(page, "F2", 11, rightEdge, y, invoice.Total.String())
+	return page.Bytes()
+}
+
+// right writes text that ends at x
+func right(page *pdf.Page, font string, size float64, x float64, y float64, text string) {
+	page.Text(font, size, x-pdf.TextWidth(size, text), y, text)
+}
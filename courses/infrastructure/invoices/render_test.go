@@ -0,0 +1,60 @@
+package invoices_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/invoices"
+)
+
+func testInvoice() *domain.Invoice {
+	return &domain.Invoice{
+		Number:      domain.InvoiceNumber(2023, 42),
+		BilledTo:    "Ada <Lovelace>",
+		BilledEmail: "ada@example.com",
+		Reference:   "ch_123",
+		IssuedAt:    time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+		Subtotal:    domain.Money{Amount: 8534, Currency: "KES"},
+		Tax:         domain.Money{Amount: 1366, Currency: "KES"},
+		Total:       domain.Money{Amount: 9900, Currency: "KES"},
+		Lines: []*domain.InvoiceLine{{
+			Description: "Annual subscription (1 May 2023 to 1 May 2024)",
+			Quantity:    1,
+			UnitPrice:   domain.Money{Amount: 8534, Currency: "KES"},
+			Amount:      domain.Money{Amount: 8534, Currency: "KES"},
+		}},
+		TaxLines: []*domain.InvoiceTaxLine{{
+			Label:       "VAT 16%",
+			BasisPoints: 1600,
+			Amount:      domain.Money{Amount: 1366, Currency: "KES"},
+		}},
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	html, err := invoices.RenderHTML(testInvoice())
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	receipt := string(html)
+	for _, want := range []string{"INV-2023-000042", "KES 85.34", "VAT 16%", "KES 99.00", "1 May 2023", "Ada &lt;Lovelace&gt;"} {
+		if !strings.Contains(receipt, want) {
+			t.Errorf("expected the receipt to contain %q", want)
+		}
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	pdf := invoices.RenderPDF(testInvoice())
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) {
+		t.Fatalf("expected a PDF document")
+	}
+	for _, want := range []string{"Receipt INV-2023-000042", "(KES 99.00)", "(VAT 16%)", "%%EOF"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("expected the receipt to contain %q", want)
+		}
+	}
+}
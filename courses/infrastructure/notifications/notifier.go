@@ -0,0 +1,117 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/invoices"
+)
+
+// Attachment is a file sent along with a notification
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Notifier defines the contract for telling a student about something that
+// happened to them
+type Notifier interface {
+	Notify(
+		ctx context.Context,
+		studentUUID string,
+		subject string,
+		message string,
+		attachments ...Attachment,
+	) error
+}
+
+// LogNotifier writes notifications to the service logs. It stands in until
+// the notifications service is available
+type LogNotifier struct{}
+
+// NewLogNotifier initializes a new LogNotifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the notification
+func (l *LogNotifier) Notify(
+	ctx context.Context,
+	studentUUID string,
+	subject string,
+	message string,
+	attachments ...Attachment,
+) error {
+	filenames := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		filenames = append(filenames, attachment.Filename)
+	}
+	log.WithFields(log.Fields{
+		"student":     studentUUID,
+		"subject":     subject,
+		"attachments": filenames,
+	}).Info(message)
+	return nil
+}
+
+// WaitlistPromotedHook tells students that they got a seat in a course they
+// were waitlisted for
+func WaitlistPromotedHook(notifier Notifier) func(ctx context.Context, event domain.Event) error {
+	return func(ctx context.Context, event domain.Event) error {
+		promoted, ok := event.(domain.WaitlistPromoted)
+		if !ok {
+			return fmt.Errorf("expected a %s event, got %s", domain.WaitlistPromoted{}.EventName(), event.EventName())
+		}
+		return notifier.Notify(
+			ctx,
+			promoted.StudentUUID,
+			"You got a seat",
+			fmt.Sprintf("A seat opened up and you are now enrolled in course %s", promoted.CourseUUID),
+		)
+	}
+}
+
+// SubscriptionPaymentFailedHook tells students that their subscription
+// couldn't be renewed, and whether it will be retried
+func SubscriptionPaymentFailedHook(notifier Notifier) func(ctx context.Context, event domain.Event) error {
+	return func(ctx context.Context, event domain.Event) error {
+		failed, ok := event.(domain.SubscriptionPaymentFailed)
+		if !ok {
+			return fmt.Errorf("expected a %s event, got %s", domain.SubscriptionPaymentFailed{}.EventName(), event.EventName())
+		}
+		message := "We couldn't renew your subscription and it has expired. Subscribe again to keep learning"
+		if !failed.Expired {
+			message = "We couldn't renew your subscription. Please update your payment details, you keep access to your courses while we retry"
+			if failed.NextRetryAt != nil {
+				message = fmt.Sprintf("%s. We will try again on %s", message, failed.NextRetryAt.Format("2 January 2006"))
+			}
+		}
+		return notifier.Notify(ctx, failed.StudentUUID, "Your subscription payment failed", message)
+	}
+}
+
+// SubscriptionStartedHook welcomes students to sudocode when they subscribe,
+// with the receipt of their first payment attached
+func SubscriptionStartedHook(notifier Notifier) func(ctx context.Context, event domain.Event) error {
+	return func(ctx context.Context, event domain.Event) error {
+		started, ok := event.(domain.SubscriptionStarted)
+		if !ok {
+			return fmt.Errorf("expected a %s event, got %s", domain.SubscriptionStarted{}.EventName(), event.EventName())
+		}
+		message := fmt.Sprintf("Welcome to sudoCODE Academy! Your %s subscription is active and you can now enroll in courses", started.PlanName)
+		if started.Invoice == nil {
+			return notifier.Notify(ctx, started.StudentUUID, "Welcome to sudoCODE Academy", message)
+		}
+		receipt := Attachment{
+			Filename:    started.Invoice.Number + ".pdf",
+			ContentType: "application/pdf",
+			Content:     invoices.RenderPDF(started.Invoice),
+		}
+		message = fmt.Sprintf("%s. Your receipt %s is attached", message, started.Invoice.Number)
+		return notifier.Notify(ctx, started.StudentUUID, "Welcome to sudoCODE Academy", message, receipt)
+	}
+}
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// MockProvider mocks the payment provider
+type MockProvider struct {
+	MockCharge func(
+		ctx context.Context,
+		studentUUID string,
+		amount domain.Money,
+		description string,
+		idempotencyKey string,
+	) (string, error)
+	MockRefund func(
+		ctx context.Context,
+		chargeReference string,
+		amount domain.Money,
+		reason string,
+		idempotencyKey string,
+	) (string, domain.RefundStatus, error)
+}
+
+// NewMockProvider initializes a new MockProvider
+func NewMockProvider() *MockProvider {
+	return &MockProvider{
+		MockCharge: func(ctx context.Context, studentUUID string, amount domain.Money, description string, idempotencyKey string) (string, error) {
+			return "ch_test", nil
+		},
+		MockRefund: func(ctx context.Context, chargeReference string, amount domain.Money, reason string, idempotencyKey string) (string, domain.RefundStatus, error) {
+			return "re_test", domain.RefundStatusSucceeded, nil
+		},
+	}
+}
+
+// Charge mocks Charge
+func (m *MockProvider) Charge(
+	ctx context.Context,
+	studentUUID string,
+	amount domain.Money,
+	description string,
+	idempotencyKey string,
+) (string, error) {
+	return m.MockCharge(ctx, studentUUID, amount, description, idempotencyKey)
+}
+
+// Refund mocks Refund
+func (m *MockProvider) Refund(
+	ctx context.Context,
+	chargeReference string,
+	amount domain.Money,
+	reason string,
+	idempotencyKey string,
+) (string, domain.RefundStatus, error) {
+	return m.MockRefund(ctx, chargeReference, amount, reason, idempotencyKey)
+}
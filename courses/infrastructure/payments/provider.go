@@ -0,0 +1,82 @@
+package payments
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// ErrDeclined is returned when the payment provider refuses a charge, e.g.
+// for insufficient funds, or a refund, as opposed to the provider being
+// unreachable
+var ErrDeclined = errors.New("payment declined")
+
+// Provider defines the contract for charging and refunding students through
+// a payment provider. Charges and refunds with the same idempotency key are
+// only made once, so they can safely be retried. Refunds that the provider
+// settles later come back pending
+type Provider interface {
+	Charge(
+		ctx context.Context,
+		studentUUID string,
+		amount domain.Money,
+		description string,
+		idempotencyKey string,
+	) (string, error)
+	Refund(
+		ctx context.Context,
+		chargeReference string,
+		amount domain.Money,
+		reason string,
+		idempotencyKey string,
+	) (string, domain.RefundStatus, error)
+}
+
+// LogProvider approves every charge and refund and writes it to the service logs. It
+// stands in until the payments service is available
+type LogProvider struct{}
+
+// NewLogProvider initializes a new LogProvider
+func NewLogProvider() *LogProvider {
+	return &LogProvider{}
+}
+
+// Charge logs the charge and returns a made up reference for it
+func (l *LogProvider) Charge(
+	ctx context.Context,
+	studentUUID string,
+	amount domain.Money,
+	description string,
+	idempotencyKey string,
+) (string, error) {
+	reference := "log_" + uuid.NewString()
+	log.WithFields(log.Fields{
+		"student":         studentUUID,
+		"amount":          amount.String(),
+		"idempotency_key": idempotencyKey,
+		"reference":       reference,
+	}).Info(description)
+	return reference, nil
+}
+
+// Refund logs the refund and settles it straight away
+func (l *LogProvider) Refund(
+	ctx context.Context,
+	chargeReference string,
+	amount domain.Money,
+	reason string,
+	idempotencyKey string,
+) (string, domain.RefundStatus, error) {
+	reference := "log_re_" + uuid.NewString()
+	log.WithFields(log.Fields{
+		"charge":          chargeReference,
+		"amount":          amount.String(),
+		"idempotency_key": idempotencyKey,
+		"reference":       reference,
+	}).Info("refund: " + reason)
+	return reference, domain.RefundStatusSucceeded, nil
+}
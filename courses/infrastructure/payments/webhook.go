@@ -0,0 +1,106 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// SignatureHeader is the header payment providers sign webhooks in
+const SignatureHeader = "X-Payments-Signature"
+
+// DefaultWebhookTolerance is how old a webhook can be before it is refused as
+// a possible replay
+const DefaultWebhookTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned for webhooks that weren't signed with
+	// the shared secret
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrStaleWebhook is returned for webhooks signed too long ago, which may
+	// be replays of captured requests
+	ErrStaleWebhook = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// WebhookVerifier checks that webhooks come from the payment provider. The
+// signature header looks like "t=1700000000,v1=<hex>", where v1 is the
+// HMAC-SHA256 of "<t>.<body>" under the shared secret
+type WebhookVerifier struct {
+	secret    []byte
+	tolerance time.Duration
+}
+
+// NewWebhookVerifier initializes a new WebhookVerifier
+func NewWebhookVerifier(secret string, tolerance time.Duration) *WebhookVerifier {
+	return &WebhookVerifier{secret: []byte(secret), tolerance: tolerance}
+}
+
+// Verify checks a webhook's signature and timestamp and parses its event
+func (v *WebhookVerifier) Verify(
+	header string,
+	body []byte,
+	now time.Time,
+) (*domain.PaymentEvent, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return nil, fmt.Errorf("%w: malformed %s header", ErrInvalidSignature, SignatureHeader)
+	}
+	expected := v.sign(timestamp, body)
+	valid := false
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return nil, ErrStaleWebhook
+	}
+
+	var event domain.PaymentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("can't parse webhook event: %w", err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, fmt.Errorf("webhook event needs an id and a type")
+	}
+	return &event, nil
+}
+
+// Sign returns the signature header for a webhook body sent at the given
+// time, as the payment provider would
+func (v *WebhookVerifier) Sign(body []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(v.sign(timestamp, body)))
+}
+
+func (v *WebhookVerifier) sign(timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
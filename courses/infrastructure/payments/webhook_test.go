@@ -0,0 +1,88 @@
+package payments_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+)
+
+func TestWebhookVerifier_Verify(t *testing.T) {
+	now := time.Now()
+	verifier := payments.NewWebhookVerifier("whsec_test", 5*time.Minute)
+	body := []byte(`{"id":"evt_1","type":"refund.succeeded","reference":"re_1"}`)
+
+	tests := []struct {
+		name      string
+		header    string
+		body      []byte
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name:   "Happy case",
+			header: verifier.Sign(body, now),
+			body:   body,
+		},
+		{
+			name:   "Happy case - within tolerance",
+			header: verifier.Sign(body, now.Add(-4*time.Minute)),
+			body:   body,
+		},
+		{
+			name:      "Sad case - another secret",
+			header:    payments.NewWebhookVerifier("whsec_other", time.Minute).Sign(body, now),
+			body:      body,
+			wantErrIs: payments.ErrInvalidSignature,
+		},
+		{
+			name:      "Sad case - tampered body",
+			header:    verifier.Sign(body, now),
+			body:      []byte(`{"id":"evt_1","type":"refund.failed","reference":"re_1"}`),
+			wantErrIs: payments.ErrInvalidSignature,
+		},
+		{
+			name:      "Sad case - replayed",
+			header:    verifier.Sign(body, now.Add(-10*time.Minute)),
+			body:      body,
+			wantErrIs: payments.ErrStaleWebhook,
+		},
+		{
+			name:      "Sad case - from the future",
+			header:    verifier.Sign(body, now.Add(10*time.Minute)),
+			body:      body,
+			wantErrIs: payments.ErrStaleWebhook,
+		},
+		{
+			name:      "Sad case - malformed header",
+			header:    "v1=abc",
+			body:      body,
+			wantErrIs: payments.ErrInvalidSignature,
+		},
+		{
+			name:    "Sad case - event without an ID",
+			header:  verifier.Sign([]byte(`{"type":"refund.succeeded"}`), now),
+			body:    []byte(`{"type":"refund.succeeded"}`),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := verifier.Verify(tt.header, tt.body, now)
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("WebhookVerifier.Verify() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if (err != nil) != (tt.wantErr || tt.wantErrIs != nil) {
+				t.Fatalf("WebhookVerifier.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if event.ID != "evt_1" || event.Type != domain.PaymentEventRefundSucceeded || event.Reference != "re_1" {
+				t.Errorf("unexpected event %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package payouts
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"percent": percent,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Payout statement {{.Month}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; max-width: 760px; margin: 40px auto; color: #222; }
+table { width: 100%; border-collapse: collapse; }
+th, td { padding: 6px 0; text-align: left; }
+.amount { text-align: right; }
+.total td { border-top: 1px solid #222; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>sudoCODE Academy</h1>
+<h2>Payout statement {{.Month}}</h2>
+<p>Instructor {{.InstructorName}}<br>
+{{if .PaidAt}}Paid on {{.PaidAt.UTC.Format "2 January 2006"}}{{if .PaymentReference}}, reference {{.PaymentReference}}{{end}}{{else}}Not paid yet{{end}}</p>
+<table>
+<tr><th>Date</th><th>Course</th><th class="amount">Sale</th><th class="amount">Share</th><th class="amount">Earned</th></tr>
+{{range .Lines}}<tr><td>{{.SoldAt.UTC.Format "2 Jan 2006"}}</td><td>{{.CourseTitle}}</td><td class="amount">{{.Net}}</td><td class="amount">{{percent .BasisPoints}}</td><td class="amount">{{.Amount}}</td></tr>
+{{end}}<tr class="total"><td colspan="2">Total</td><td class="amount">{{.Sales}}</td><td></td><td class="amount">{{.Earnings}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// RenderHTML renders a payout statement as an HTML page
+func RenderHTML(statement *domain.PayoutStatement) ([]byte, error) {
+	var out bytes.Buffer
+	if err := statementTemplate.Execute(&out, statement); err != nil {
+		return nil, fmt.Errorf("can't render payout statement %s: %w", statement.UUID, err)
+	}
+	return out.Bytes(), nil
+}
+
+// RenderCSV renders the lines of a payout statement as CSV, one sale per row
+func RenderCSV(statement *domain.PayoutStatement) ([]byte, error) {
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	_ = writer.Write([]string{"month", "sold_at", "sale_uuid", "course_uuid", "course_title", "currency", "sale", "share_percent", "earned"})
+	for _, line := range statement.Lines {
+		_ = writer.Write([]string{
+			statement.Month,
+			line.SoldAt.UTC().Format("2006-01-02"),
+			line.SaleUUID,
+			line.CourseUUID,
+			line.CourseTitle,
+			line.Net.Currency,
+			line.Net.Major(),
+			strconv.FormatFloat(float64(line.BasisPoints)/100, 'f', -1, 64),
+			line.Amount.Major(),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("can't render payout statement %s: %w", statement.UUID, err)
+	}
+	return out.Bytes(), nil
+}
+
+// percent formats basis points as a percentage, e.g. "12.5%"
+func percent(basisPoints uint) string {
+	return strconv.FormatFloat(float64(basisPoints)/100, 'f', -1, 64) + "%"
+}
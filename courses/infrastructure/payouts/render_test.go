@@ -0,0 +1,72 @@
+package payouts_test
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/payouts"
+)
+
+func testStatement() *domain.PayoutStatement {
+	return &domain.PayoutStatement{
+		InstructorName: "Grace <Hopper>",
+		Month:          "2023-05",
+		Sales:          domain.Money{Amount: 8575, Currency: "USD"},
+		Earnings:       domain.Money{Amount: 5145, Currency: "USD"},
+		Status:         domain.PayoutStatusPending,
+		Lines: []*domain.PayoutLine{
+			{
+				SaleUUID:    "sale-1",
+				CourseTitle: "Go, for real",
+				SoldAt:      time.Date(2023, 5, 3, 10, 0, 0, 0, time.UTC),
+				Net:         domain.Money{Amount: 4900, Currency: "USD"},
+				BasisPoints: 6000,
+				Amount:      domain.Money{Amount: 2940, Currency: "USD"},
+			},
+			{
+				SaleUUID:    "sale-2",
+				CourseTitle: "Go, for real",
+				SoldAt:      time.Date(2023, 5, 20, 10, 0, 0, 0, time.UTC),
+				Net:         domain.Money{Amount: 3675, Currency: "USD"},
+				BasisPoints: 6000,
+				Amount:      domain.Money{Amount: 2205, Currency: "USD"},
+			},
+		},
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	html, err := payouts.RenderHTML(testStatement())
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	statement := string(html)
+	for _, want := range []string{"2023-05", "Grace &lt;Hopper&gt;", "USD 49.00", "60%", "USD 51.45", "Not paid yet"} {
+		if !strings.Contains(statement, want) {
+			t.Errorf("expected the statement to contain %q", want)
+		}
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	out, err := payouts.RenderCSV(testStatement())
+	if err != nil {
+		t.Fatalf("RenderCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV, got %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected a header and a row per sale, got %d rows", len(rows))
+	}
+	want := []string{"2023-05", "2023-05-03", "sale-1", "", "Go, for real", "USD", "49.00", "60", "29.40"}
+	for i, cell := range want {
+		if rows[1][i] != cell {
+			t.Errorf("expected column %s to be %q, got %q", rows[0][i], cell, rows[1][i])
+		}
+	}
+}
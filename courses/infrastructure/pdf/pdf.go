@@ -0,0 +1,93 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// averageGlyphWidth approximates Helvetica's glyph width as a share of the
+// font size, which is close enough to center or right align short lines
+const averageGlyphWidth = 0.5
+
+// Page is a single page document drawn with the standard Helvetica fonts, so
+// no font files need to be embedded. F1 is the regular font and F2 the bold
+type Page struct {
+	Width   float64
+	Height  float64
+	content bytes.Buffer
+}
+
+// NewPage starts an empty page of the given size in points
+func NewPage(width float64, height float64) *Page {
+	return &Page{Width: width, Height: height}
+}
+
+// Draw appends raw content stream operators, such as lines and rectangles
+func (p *Page) Draw(operators string) {
+	p.content.WriteString(operators)
+	p.content.WriteByte('\n')
+}
+
+// Text writes a line of text with its baseline starting at x, y
+func (p *Page) Text(font string, size float64, x float64, y float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(text))
+}
+
+// TextWidth estimates how wide a line of text is drawn
+func TextWidth(size float64, text string) float64 {
+	return float64(utf8.RuneCountInString(text)) * size * averageGlyphWidth
+}
+
+// Bytes renders the page as a PDF document
+func (p *Page) Bytes() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+			p.Width, p.Height,
+		),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()),
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// escape encodes text as a Latin-1 PDF literal string, replacing the
+// characters the standard fonts can't draw
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
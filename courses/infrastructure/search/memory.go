@@ -0,0 +1,236 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// field weights, mirroring the A, B and C weights postgres ranks with
+const (
+	titleWeight       = 1.0
+	instructorWeight  = 0.4
+	categoryWeight    = 0.4
+	descriptionWeight = 0.2
+)
+
+// MemoryIndex is an in-memory course search index for tests and local
+// development. It matches every word of the query against the words of a
+// course, allowing prefixes and a typo or two, instead of postgres' stemming
+// and trigram similarity
+type MemoryIndex struct {
+	mu      sync.RWMutex
+	courses map[string]*domain.Course
+}
+
+// NewMemoryIndex creates an index holding the given courses
+func NewMemoryIndex(courses ...*domain.Course) *MemoryIndex {
+	index := &MemoryIndex{courses: map[string]*domain.Course{}}
+	for _, course := range courses {
+		index.Index(course)
+	}
+	return index
+}
+
+// Index adds a course to the index or replaces its previous version
+func (m *MemoryIndex) Index(course *domain.Course) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.courses[course.UUID] = course
+}
+
+// SearchCourses returns the courses matching a query, best first
+func (m *MemoryIndex) SearchCourses(
+	ctx context.Context,
+	query *domain.CourseSearchQuery,
+) (*domain.CourseSearchResults, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	terms := words(query.Text)
+	categories := map[string]int64{}
+	instructors := map[string]int64{}
+	bands := map[string]int64{}
+	hits := []*domain.CourseSearchHit{}
+	for _, course := range m.courses {
+		rank, ok := score(terms, course)
+		if !ok {
+			continue
+		}
+		categories[course.Category]++
+		instructors[course.Instructor]++
+		bands[domain.PriceBandOf(course.Price)]++
+		if matchesFilters(query, course) {
+			hits = append(hits, &domain.CourseSearchHit{Course: course, Rank: rank})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Course.Title < hits[j].Course.Title
+	})
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+
+	priceBands := []domain.FacetCount{}
+	for _, band := range domain.PriceBands {
+		if count := bands[band.Name]; count > 0 {
+			priceBands = append(priceBands, domain.FacetCount{Value: band.Name, Count: count})
+		}
+	}
+	return &domain.CourseSearchResults{
+		Hits: hits,
+		Facets: domain.SearchFacets{
+			Categories:  facetCounts(categories),
+			Instructors: facetCounts(instructors),
+			PriceBands:  priceBands,
+		},
+	}, nil
+}
+
+// score ranks a course against the query's words. Every word has to match
+// one of the course's fields
+func score(terms []string, course *domain.Course) (float64, bool) {
+	if len(terms) == 0 {
+		return 0, false
+	}
+	fields := []struct {
+		words  []string
+		weight float64
+	}{
+		{words(course.Title), titleWeight},
+		{words(course.Instructor), instructorWeight},
+		{words(course.Category), categoryWeight},
+		{words(course.Description), descriptionWeight},
+	}
+
+	total := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for _, field := range fields {
+			if s := field.weight * matchWord(term, field.words); s > best {
+				best = s
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// matchWord scores how well a query word matches a list of words: 1 for an
+// exact match, less for prefixes and typos and 0 when nothing is close
+func matchWord(term string, candidates []string) float64 {
+	best := 0.0
+	for _, word := range candidates {
+		switch {
+		case word == term:
+			return 1
+		case len(term) >= 3 && strings.HasPrefix(word, term):
+			best = maxFloat(best, 0.75)
+		case distance(term, word) <= typos(term):
+			best = maxFloat(best, 0.5)
+		}
+	}
+	return best
+}
+
+// typos is the number of edits tolerated for a word of that length
+func typos(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// distance is the Levenshtein distance between two words
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func matchesFilters(query *domain.CourseSearchQuery, course *domain.Course) bool {
+	if query.Category != "" && !strings.EqualFold(query.Category, course.Category) {
+		return false
+	}
+	if query.Instructor != "" && !strings.EqualFold(query.Instructor, course.Instructor) {
+		return false
+	}
+	if query.PriceBand != "" {
+		band, ok := domain.LookupPriceBand(query.PriceBand)
+		if !ok || !band.Contains(course.Price) {
+			return false
+		}
+	}
+	return true
+}
+
+// facetCounts orders facet values by count, then alphabetically
+func facetCounts(counts map[string]int64) []domain.FacetCount {
+	facets := make([]domain.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		if value == "" {
+			continue
+		}
+		facets = append(facets, domain.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
+
+// words splits text into lower case words
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,111 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/search"
+)
+
+func newCourse(uuid, title, instructor, category, description string, price uint) *domain.Course {
+	return &domain.Course{
+		AbstractBase: domain.AbstractBase{UUID: uuid},
+		Title:        title,
+		Instructor:   instructor,
+		Category:     category,
+		Description:  description,
+		Price:        price,
+	}
+}
+
+func TestMemoryIndex_SearchCourses(t *testing.T) {
+	index := search.NewMemoryIndex(
+		newCourse("go", "Golang for backend engineers", "Melvin Kim", "backend", "Build APIs", 80),
+		newCourse("k8s", "Kubernetes in production", "Jane Doe", "devops", "Deploy golang services", 150),
+		newCourse("react", "React from scratch", "Jane Doe", "frontend", "Components and hooks", 0),
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		query     *domain.CourseSearchQuery
+		wantHits  []string
+		wantBands int
+	}{
+		{
+			name:      "Happy case - title outranks description",
+			query:     &domain.CourseSearchQuery{Text: "golang"},
+			wantHits:  []string{"go", "k8s"},
+			wantBands: 2,
+		},
+		{
+			name:      "Happy case - typo in the title",
+			query:     &domain.CourseSearchQuery{Text: "kubernets"},
+			wantHits:  []string{"k8s"},
+			wantBands: 1,
+		},
+		{
+			name:      "Happy case - every word must match",
+			query:     &domain.CourseSearchQuery{Text: "jane react"},
+			wantHits:  []string{"react"},
+			wantBands: 1,
+		},
+		{
+			name:      "Happy case - filters narrow hits but not facets",
+			query:     &domain.CourseSearchQuery{Text: "golang", PriceBand: "over_100"},
+			wantHits:  []string{"k8s"},
+			wantBands: 2,
+		},
+		{
+			name:     "Sad case - nothing matches",
+			query:    &domain.CourseSearchQuery{Text: "haskell"},
+			wantHits: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.SearchCourses(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("MemoryIndex.SearchCourses() error = %v", err)
+			}
+			if len(results.Hits) != len(tt.wantHits) {
+				t.Fatalf("expected %d hits, got %d", len(tt.wantHits), len(results.Hits))
+			}
+			for i, hit := range results.Hits {
+				if hit.Course.UUID != tt.wantHits[i] {
+					t.Errorf("expected hit %d to be %s, got %s", i, tt.wantHits[i], hit.Course.UUID)
+				}
+			}
+			if len(results.Facets.PriceBands) != tt.wantBands {
+				t.Errorf("expected %d price band facets, got %v", tt.wantBands, results.Facets.PriceBands)
+			}
+		})
+	}
+}
+
+func TestMemoryIndex_Facets(t *testing.T) {
+	index := search.NewMemoryIndex(
+		newCourse("a", "Go basics", "Jane Doe", "backend", "", 0),
+		newCourse("b", "Go concurrency", "Jane Doe", "backend", "", 40),
+		newCourse("c", "Go for the web", "Melvin Kim", "frontend", "", 40),
+	)
+
+	results, err := index.SearchCourses(context.Background(), &domain.CourseSearchQuery{Text: "go"})
+	if err != nil {
+		t.Fatalf("MemoryIndex.SearchCourses() error = %v", err)
+	}
+	want := []domain.FacetCount{{Value: "backend", Count: 2}, {Value: "frontend", Count: 1}}
+	if len(results.Facets.Categories) != len(want) {
+		t.Fatalf("expected category facets %v, got %v", want, results.Facets.Categories)
+	}
+	for i := range want {
+		if results.Facets.Categories[i] != want[i] {
+			t.Errorf("expected category facets %v, got %v", want, results.Facets.Categories)
+		}
+	}
+	bands := results.Facets.PriceBands
+	if len(bands) != 2 || bands[0].Value != "free" || bands[1] != (domain.FacetCount{Value: "under_50", Count: 2}) {
+		t.Errorf("expected price bands in band order, got %v", bands)
+	}
+}
@@ -2,18 +2,57 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/MelvinKim/courses/presentation"
 )
 
-const PORT = 9000
+const (
+	PORT      = 9000
+	GRPC_PORT = 9090
+)
 
 func main() {
 	ctx := context.Background()
 
-	srv := presentation.PrepareServer(ctx, PORT)
+	// the usecase, with its database connection, is shared by everything
+	i, err := presentation.NewInteractor()
+	if err != nil {
+		log.Errorf("start up error: %v", err)
+		return
+	}
+
+	grpcSrv, err := presentation.PrepareGRPCServer(ctx, i)
+	if err != nil {
+		log.Errorf("gRPC server start up error: %v", err)
+		return
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", GRPC_PORT))
+	if err != nil {
+		log.Errorf("unable to listen on port %d: %v", GRPC_PORT, err)
+		return
+	}
+	go func() {
+		log.Infof("gRPC server running at port %d", GRPC_PORT)
+		if err := grpcSrv.Serve(l); err != nil {
+			log.Errorf("gRPC server error: %v", err)
+		}
+	}()
+
+	if err := presentation.StartRenewalScheduler(ctx, i); err != nil {
+		log.Errorf("subscription renewal scheduler start up error: %v", err)
+		return
+	}
+
+	if err := presentation.StartImportWorker(ctx, i); err != nil {
+		log.Errorf("import worker start up error: %v", err)
+		return
+	}
+
+	srv := presentation.PrepareServer(ctx, PORT, i)
 
 	if err := srv.ListenAndServe(); err != nil {
 		log.Errorf("server start up error: %v", err)
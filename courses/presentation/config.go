@@ -1,38 +1,68 @@
 package presentation
 
 import (
-	"compress/gzip"
 	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
 	"github.com/MelvinKim/courses/infrastructure/database"
+	"github.com/MelvinKim/courses/infrastructure/events"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	"github.com/MelvinKim/courses/infrastructure/notifications"
+	"github.com/MelvinKim/courses/infrastructure/payments"
 	"github.com/MelvinKim/courses/presentation/interactor"
 	"github.com/MelvinKim/courses/presentation/rest"
+	"github.com/MelvinKim/courses/presentation/rpc"
 	"github.com/MelvinKim/courses/usecase"
-	"github.com/gorilla/handlers"
+	"github.com/MelvinKim/platform/interceptors"
+	"github.com/MelvinKim/platform/server"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
-const (
-	serverTimeoutSeconds = 120
-)
-
-var allowedHeaders = []string{
-	"Authorization", "Accept", "Accept-Charset", "Accept-Language",
-	"Accept-Encoding", "Origin", "Host", "User-Agent", "Content-Length",
-	"Content-Type", " X-Authorization", " Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers",
-}
-
-// Router sets up the gorilla Mux router
-func Router(ctx context.Context) (*mux.Router, error) {
-	create := database.NewPostgresDB()
-	get := database.NewPostgresDB()
-	users := usecase.NewUsecase(create, get)
+// NewInteractor wires the courses usecase to its postgres repositories. It
+// connects to and migrates the database, so it is built once at start up and
+// shared by the REST and gRPC servers and the background workers
+func NewInteractor() (*interactor.Interactor, error) {
+	db, err := database.Connect()
+	if err != nil {
+		return nil, err
+	}
+	create, get, update, delete := db, db, db, db
+	notifier := notifications.NewLogNotifier()
+	publisher := events.NewHookPublisher(events.NewLogPublisher()).
+		On(domain.WaitlistPromoted{}.EventName(), notifications.WaitlistPromotedHook(notifier)).
+		On(domain.SubscriptionStarted{}.EventName(), notifications.SubscriptionStartedHook(notifier)).
+		On(domain.SubscriptionPaymentFailed{}.EventName(), notifications.SubscriptionPaymentFailedHook(notifier))
+	signer, err := certificates.NewEd25519SignerFromEnv("CERTIFICATE_SIGNING_KEY")
+	if err != nil {
+		return nil, err
+	}
+	users := usecase.NewUsecase(create, get, update, delete, publisher, signer, get, fx.NewStaticRatesFromEnv(), payments.NewLogProvider())
+	refundPolicy, err := refundPolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	users.RefundPolicy = refundPolicy
+	users.Webhooks, err = webhookVerifierFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	users.TokenSigner, err = certificates.NewEd25519SignerFromEnv("ACCESS_TOKEN_SIGNING_KEY")
+	if err != nil {
+		return nil, err
+	}
+	users.AdminToken = os.Getenv("ADMIN_API_TOKEN")
+	if users.AdminToken == "" {
+		log.Warn("ADMIN_API_TOKEN is not set, no access tokens can be issued until it is")
+	}
 
 	i, err := interactor.NewUsersInteractor(
 		users,
@@ -40,54 +70,294 @@ func Router(ctx context.Context) (*mux.Router, error) {
 	if err != nil {
 		return nil, fmt.Errorf("can't instantiate a new service: %w", err)
 	}
+	return i, nil
+}
 
+// refundPolicyFromEnv reads the refund policy from REFUND_COOLING_OFF_DAYS,
+// two weeks when it is not set, and REFUND_UNENROLLS, which unenrolls
+// students from the courses they are refunded for when it is "true"
+func refundPolicyFromEnv() (domain.RefundPolicy, error) {
+	policy := domain.DefaultRefundPolicy()
+	if value := os.Getenv("REFUND_COOLING_OFF_DAYS"); value != "" {
+		days, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return policy, fmt.Errorf("invalid REFUND_COOLING_OFF_DAYS %q", value)
+		}
+		policy.CoolingOffDays = uint(days)
+	}
+	if value := os.Getenv("REFUND_UNENROLLS"); value != "" {
+		unenroll, err := strconv.ParseBool(value)
+		if err != nil {
+			return policy, fmt.Errorf("invalid REFUND_UNENROLLS %q", value)
+		}
+		policy.Unenroll = unenroll
+	}
+	return policy, nil
+}
+
+// webhookVerifierFromEnv verifies payment webhooks with the shared secret in
+// PAYMENTS_WEBHOOK_SECRET, allowing them to be PAYMENTS_WEBHOOK_TOLERANCE old,
+// e.g. "2m". Webhooks are refused when no secret is set
+func webhookVerifierFromEnv() (*payments.WebhookVerifier, error) {
+	secret := os.Getenv("PAYMENTS_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Warn("PAYMENTS_WEBHOOK_SECRET is not set, payment webhooks will be refused")
+		return nil, nil
+	}
+	tolerance := payments.DefaultWebhookTolerance
+	if value := os.Getenv("PAYMENTS_WEBHOOK_TOLERANCE"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid PAYMENTS_WEBHOOK_TOLERANCE %q", value)
+		}
+		tolerance = parsed
+	}
+	return payments.NewWebhookVerifier(secret, tolerance), nil
+}
+
+// Router sets up the gorilla Mux router
+func Router(ctx context.Context, i *interactor.Interactor) *mux.Router {
 	h := rest.NewPresentationHandlers(i)
 
 	r := mux.NewRouter()
 
+	// money, coupons, prices, moderation and certificate revocations are for
+	// admins only. Students manage their own subscriptions, invoices,
+	// enrollments and reviews
+	admin := []domain.Role{domain.RoleAdmin}
+	instructor := []domain.Role{domain.RoleAdmin, domain.RoleInstructor}
+	student := []domain.Role{domain.RoleAdmin, domain.RoleStudent}
+
 	userRoutes := r.PathPrefix("/api/v1").Subrouter()
 	userRoutes.Path("/users").Methods(http.MethodPost).HandlerFunc(h.CreateStudent())
 	userRoutes.Path("/user").Methods(http.MethodGet).HandlerFunc(h.GetStudent())
+	userRoutes.Path("/courses").Methods(http.MethodGet).HandlerFunc(h.ListCourses())
+	userRoutes.Path("/courses/search").Methods(http.MethodGet).HandlerFunc(h.SearchCourses())
 	userRoutes.Path("/courses").Methods(http.MethodPost).HandlerFunc(h.CreateCourse())
 	userRoutes.Path("/course").Methods(http.MethodGet).HandlerFunc(h.GetCourse())
 	userRoutes.Path("/assign_course").Methods(http.MethodPost).HandlerFunc(h.AssignCourseToStudent())
 
-	return r, nil
+	userRoutes.Path("/categories").Methods(http.MethodGet).HandlerFunc(h.ListCategories())
+	userRoutes.Path("/categories").Methods(http.MethodPost).HandlerFunc(h.CreateCategory())
+	userRoutes.Path("/categories/{uuid}").Methods(http.MethodGet).HandlerFunc(h.GetCategory())
+	userRoutes.Path("/categories/{uuid}").Methods(http.MethodPut).HandlerFunc(h.UpdateCategory())
+	userRoutes.Path("/categories/{uuid}").Methods(http.MethodDelete).HandlerFunc(h.DeleteCategory())
+	userRoutes.Path("/instructors").Methods(http.MethodGet).HandlerFunc(h.ListInstructors())
+	userRoutes.Path("/instructors").Methods(http.MethodPost).HandlerFunc(h.CreateInstructor())
+	userRoutes.Path("/instructors/{uuid}").Methods(http.MethodGet).HandlerFunc(h.GetInstructor())
+	userRoutes.Path("/instructors/{uuid}").Methods(http.MethodPut).HandlerFunc(h.UpdateInstructor())
+	userRoutes.Path("/instructors/{uuid}").Methods(http.MethodDelete).HandlerFunc(h.DeleteInstructor())
+
+	userRoutes.Path("/courses/{uuid}/prices").Methods(http.MethodGet).HandlerFunc(h.GetCoursePrices())
+	userRoutes.Path("/courses/{uuid}/prices").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.SetCoursePrice(), admin...))
+	userRoutes.Path("/courses/{uuid}/prices/history").Methods(http.MethodGet).HandlerFunc(h.GetCoursePriceHistory())
+
+	userRoutes.Path("/plans").Methods(http.MethodGet).HandlerFunc(h.ListPlans())
+	userRoutes.Path("/plans").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.CreatePlan(), admin...))
+	userRoutes.Path("/students/{uuid}/subscription").Methods(http.MethodGet).HandlerFunc(h.GetStudentSubscription())
+	userRoutes.Path("/students/{uuid}/subscription").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.Subscribe(), student...))
+	userRoutes.Path("/subscriptions/{uuid}/cancel").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.CancelSubscription(), student...))
+	userRoutes.Path("/subscriptions/{uuid}/charges").Methods(http.MethodGet).HandlerFunc(h.GetSubscriptionCharges())
+	userRoutes.Path("/tax_rates").Methods(http.MethodGet).HandlerFunc(h.ListTaxRates())
+	userRoutes.Path("/tax_rates/{currency}").Methods(http.MethodPut).HandlerFunc(h.RequireRole(h.SetTaxRate(), admin...))
+	userRoutes.Path("/students/{uuid}/invoices").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.ListStudentInvoices(), student...))
+	userRoutes.Path("/invoices/{uuid}").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetInvoice(), student...))
+	userRoutes.Path("/invoices/{uuid}/receipt.pdf").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.DownloadReceiptPDF(), student...))
+	userRoutes.Path("/invoices/{uuid}/receipt.html").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.DownloadReceiptHTML(), student...))
+	userRoutes.Path("/payments/{uuid}/refunds").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetPaymentRefunds(), admin...))
+	userRoutes.Path("/payments/{uuid}/refunds").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.RefundPayment(), admin...))
+	userRoutes.Path("/ledger/balances").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetLedgerBalances(), admin...))
+	userRoutes.Path("/ledger/entries").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.ListLedgerEntries(), admin...))
+	userRoutes.Path("/reports/revenue").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetRevenueReport(), admin...))
+	userRoutes.Path("/reports/revenue/reconciliation").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetRevenueReconciliation(), admin...))
+
+	// payouts are restricted by role: admins run them, instructors only see
+	// their own earnings. Only admins import and export students
+	userRoutes.Path("/access_tokens").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.IssueAccessToken(), admin...))
+	userRoutes.Path("/instructors/{uuid}/revenue_shares").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.SetRevenueShare(), admin...))
+	userRoutes.Path("/instructors/{uuid}/revenue_shares").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.ListRevenueShares(), instructor...))
+	userRoutes.Path("/instructors/{uuid}/earnings").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetInstructorEarnings(), instructor...))
+	userRoutes.Path("/payouts/statements").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.GeneratePayoutStatements(), admin...))
+	userRoutes.Path("/payouts/statements").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.ListPayoutStatements(), admin...))
+	userRoutes.Path("/payouts/statements/{uuid}").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetPayoutStatement(), instructor...))
+	userRoutes.Path("/payouts/statements/{uuid}/statement.html").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.DownloadPayoutStatementHTML(), instructor...))
+	userRoutes.Path("/payouts/statements/{uuid}/statement.csv").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.DownloadPayoutStatementCSV(), instructor...))
+	userRoutes.Path("/payouts/statements/{uuid}/paid").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.MarkPayoutPaid(), admin...))
+	userRoutes.Path("/imports").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.CreateImport(), admin...))
+	userRoutes.Path("/imports/{uuid}").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetImport(), admin...))
+	userRoutes.Path("/imports/{uuid}/errors.csv").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.DownloadImportErrors(), admin...))
+	userRoutes.Path("/exports/{table}").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.ExportTable(), admin...))
+	userRoutes.Path("/payments/webhooks").Methods(http.MethodPost).HandlerFunc(h.ReceivePaymentWebhook())
+
+	userRoutes.Path("/coupons").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.ListCoupons(), admin...))
+	userRoutes.Path("/coupons").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.CreateCoupon(), admin...))
+	userRoutes.Path("/coupons/validate").Methods(http.MethodPost).HandlerFunc(h.ValidateCoupon())
+	userRoutes.Path("/coupons/report").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetCouponUsage(), admin...))
+	userRoutes.Path("/coupons/{uuid}").Methods(http.MethodGet).HandlerFunc(h.RequireRole(h.GetCoupon(), admin...))
+	userRoutes.Path("/coupons/{uuid}").Methods(http.MethodDelete).HandlerFunc(h.RequireRole(h.DeactivateCoupon(), admin...))
+
+	userRoutes.Path("/courses/{uuid}/curriculum").Methods(http.MethodGet).HandlerFunc(h.GetCourseCurriculum())
+	userRoutes.Path("/courses/{uuid}/modules").Methods(http.MethodPost).HandlerFunc(h.CreateModule())
+	userRoutes.Path("/courses/{uuid}/modules/order").Methods(http.MethodPut).HandlerFunc(h.ReorderModules())
+	userRoutes.Path("/modules/{uuid}").Methods(http.MethodPut).HandlerFunc(h.UpdateModule())
+	userRoutes.Path("/modules/{uuid}").Methods(http.MethodDelete).HandlerFunc(h.DeleteModule())
+	userRoutes.Path("/modules/{uuid}/lessons").Methods(http.MethodPost).HandlerFunc(h.CreateLesson())
+	userRoutes.Path("/modules/{uuid}/lessons/order").Methods(http.MethodPut).HandlerFunc(h.ReorderLessons())
+	userRoutes.Path("/lessons/{uuid}").Methods(http.MethodPut).HandlerFunc(h.UpdateLesson())
+	userRoutes.Path("/lessons/{uuid}").Methods(http.MethodDelete).HandlerFunc(h.DeleteLesson())
+
+	userRoutes.Path("/courses/{uuid}/prerequisites").Methods(http.MethodGet).HandlerFunc(h.GetPrerequisiteTree())
+	userRoutes.Path("/courses/{uuid}/prerequisites").Methods(http.MethodPost).HandlerFunc(h.AddCoursePrerequisite())
+	userRoutes.Path("/courses/{uuid}/prerequisites/{prerequisiteUUID}").Methods(http.MethodDelete).HandlerFunc(h.RemoveCoursePrerequisite())
+
+	userRoutes.Path("/students/{uuid}/lessons/{lessonUUID}/progress").Methods(http.MethodPost).HandlerFunc(h.RecordLessonProgress())
+	userRoutes.Path("/students/{uuid}/enrollments").Methods(http.MethodGet).HandlerFunc(h.GetStudentEnrollments())
+	userRoutes.Path("/students/{uuid}/courses/{courseUUID}").Methods(http.MethodDelete).HandlerFunc(h.RequireRole(h.UnenrollStudent(), student...))
+
+	userRoutes.Path("/courses/{uuid}/waitlist").Methods(http.MethodGet).HandlerFunc(h.GetWaitlist())
+	userRoutes.Path("/courses/{uuid}/waitlist/{studentUUID}").Methods(http.MethodGet).HandlerFunc(h.GetWaitlistPosition())
+	userRoutes.Path("/courses/{uuid}/waitlist/{studentUUID}").Methods(http.MethodDelete).HandlerFunc(h.LeaveWaitlist())
+
+	userRoutes.Path("/courses/{uuid}/runs").Methods(http.MethodGet).HandlerFunc(h.GetUpcomingCourseRuns())
+	userRoutes.Path("/courses/{uuid}/runs").Methods(http.MethodPost).HandlerFunc(h.CreateCourseRun())
+	userRoutes.Path("/runs/{uuid}").Methods(http.MethodGet).HandlerFunc(h.GetCourseRun())
+	userRoutes.Path("/runs/{uuid}/sessions").Methods(http.MethodPost).HandlerFunc(h.AddLiveSession())
+	userRoutes.Path("/runs/{uuid}/enrollments").Methods(http.MethodPost).HandlerFunc(h.EnrollInRun())
+
+	userRoutes.Path("/students/{uuid}/calendar/token").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.IssueCalendarToken(), student...))
+	userRoutes.Path("/students/{uuid}/calendar.ics").Methods(http.MethodGet).HandlerFunc(h.GetStudentCalendar())
+
+	userRoutes.Path("/students/{uuid}/courses/{courseUUID}/review").Methods(http.MethodPut).HandlerFunc(h.RequireRole(h.ReviewCourse(), student...))
+	userRoutes.Path("/courses/{uuid}/reviews").Methods(http.MethodGet).HandlerFunc(h.GetCourseReviews())
+	userRoutes.Path("/reviews/{uuid}/moderation").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.ModerateReview(), admin...))
+
+	userRoutes.Path("/lessons/{uuid}/quiz").Methods(http.MethodPost).HandlerFunc(h.CreateQuiz())
+	userRoutes.Path("/quizzes/{uuid}").Methods(http.MethodGet).HandlerFunc(h.GetQuiz())
+	userRoutes.Path("/quizzes/{uuid}/attempts").Methods(http.MethodPost).HandlerFunc(h.SubmitAttempt())
+
+	userRoutes.Path("/students/{uuid}/courses/{courseUUID}/certificate").Methods(http.MethodPost).HandlerFunc(h.IssueCertificate())
+	userRoutes.Path("/certificates/{serial}/verify").Methods(http.MethodGet).HandlerFunc(h.VerifyCertificate())
+	userRoutes.Path("/certificates/{serial}/pdf").Methods(http.MethodGet).HandlerFunc(h.DownloadCertificatePDF())
+	userRoutes.Path("/certificates/{serial}/revoke").Methods(http.MethodPost).HandlerFunc(h.RequireRole(h.RevokeCertificate(), admin...))
+
+	return r
 }
 
 // PrepareServer starts up a server
 func PrepareServer(
 	ctx context.Context,
 	port int,
+	i *interactor.Interactor,
 ) *http.Server {
-	// start up  the router
-	r, err := Router(ctx)
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("Server startup error")
+	return server.New(Router(ctx, i), server.Options{
+		Port:    port,
+		Methods: []string{http.MethodOptions, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		// imports are uploaded as CSV or NDJSON files
+		ContentTypes: []string{
+			"application/json",
+			"application/x-www-form-urlencoded",
+			"text/csv",
+			"application/x-ndjson",
+		},
+	})
+}
+
+// StartRenewalScheduler renews due subscriptions, sends refunds the payment
+// provider never acknowledged again and issues missing invoices in the
+// background until the context is done. It runs every SUBSCRIPTION_RENEWAL_INTERVAL, e.g. "15m",
+// and hourly when that is not set
+func StartRenewalScheduler(ctx context.Context, i *interactor.Interactor) error {
+	interval := time.Hour
+	if value := os.Getenv("SUBSCRIPTION_RENEWAL_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid SUBSCRIPTION_RENEWAL_INTERVAL %q", value)
+		}
+		interval = parsed
 	}
 
-	// start the server
-	addr := fmt.Sprintf(":%d", port)
-	h := handlers.CompressHandlerLevel(r, gzip.BestCompression)
-
-	h = handlers.CORS(
-		handlers.AllowedHeaders(allowedHeaders),
-		handlers.AllowCredentials(),
-		handlers.AllowedMethods([]string{"OPTIONS", "GET", "POST"}),
-	)(h)
-	h = handlers.CombinedLoggingHandler(os.Stdout, h)
-	h = handlers.ContentTypeHandler(
-		h,
-		"application/json",
-		"application/x-www-form-urlencoded",
-	)
-	srv := &http.Server{
-		Handler:      h,
-		Addr:         addr,
-		WriteTimeout: serverTimeoutSeconds * time.Second,
-		ReadTimeout:  serverTimeoutSeconds * time.Second,
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			renewed, err := i.Courses.RenewSubscriptions(ctx, time.Now())
+			if err != nil {
+				log.WithFields(log.Fields{"error": err}).Error("subscription renewal error")
+			}
+			if renewed > 0 {
+				log.Infof("processed %d due subscriptions", renewed)
+			}
+			refunded, err := i.Courses.RetryRefunds(ctx, time.Now())
+			if err != nil {
+				log.WithFields(log.Fields{"error": err}).Error("refund retry error")
+			}
+			if refunded > 0 {
+				log.Infof("sent %d unsent refunds again", refunded)
+			}
+			invoiced, err := i.Courses.IssueMissingInvoices(ctx, time.Now())
+			if err != nil {
+				log.WithFields(log.Fields{"error": err}).Error("invoicing error")
+			}
+			if invoiced > 0 {
+				log.Infof("issued %d missing invoices", invoiced)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Infof("subscription renewals running every %v", interval)
+	return nil
+}
+
+// StartImportWorker processes bulk imports in the background until the
+// context is done, checking for new ones every IMPORT_POLL_INTERVAL, e.g.
+// "30s", and every 10 seconds when that is not set. Imports cut short by a
+// restart are picked up where they stopped
+func StartImportWorker(ctx context.Context, i *interactor.Interactor) error {
+	interval := 10 * time.Second
+	if value := os.Getenv("IMPORT_POLL_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid IMPORT_POLL_INTERVAL %q", value)
+		}
+		interval = parsed
 	}
-	log.Infof("Server running at port %v", addr)
-	return srv
 
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			processed, err := i.Courses.ProcessImports(ctx, time.Now)
+			if err != nil {
+				log.WithFields(log.Fields{"error": err}).Error("import processing error")
+			}
+			if processed > 0 {
+				log.Infof("processed %d import rows", processed)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Infof("imports processed every %v", interval)
+	return nil
+}
+
+// PrepareGRPCServer sets up the gRPC server used for service-to-service calls.
+// Callers authenticate with the token in the GRPC_AUTH_TOKEN env variable, and
+// the server refuses to start without one unless GRPC_INSECURE=true
+func PrepareGRPCServer(
+	ctx context.Context,
+	i *interactor.Interactor,
+) (*grpc.Server, error) {
+	auth, err := interceptors.AuthFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewGRPCServer(i, auth), nil
 }
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+type principalKey struct{}
+
+// principalFromContext returns the caller authenticated by RequireRole
+func principalFromContext(ctx context.Context) *domain.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*domain.Principal)
+	return principal
+}
+
+// RequireRole only lets callers with a valid bearer access token for one of
+// the roles through, and stores who they are in the request's context
+func (p PresentationHandlersImpl) RequireRole(next http.HandlerFunc, roles ...domain.Role) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := p.authorize(w, r, roles...)
+		if !ok {
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	}
+}
+
+// authorize authenticates the request's bearer access token and checks it is
+// for one of the roles, answering the request itself when it isn't
+func (p PresentationHandlersImpl) authorize(w http.ResponseWriter, r *http.Request, roles ...domain.Role) (*domain.Principal, bool) {
+	token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+
+	principal, err := p.interactor.Courses.Authenticate(r.Context(), token)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		web.JSON(w, map[string]string{"error": domain.ErrUnauthenticated.Error()}, http.StatusUnauthorized)
+		return nil, false
+	}
+	if !principal.HasRole(roles...) {
+		web.JSON(w, map[string]string{"error": domain.ErrForbidden.Error()}, http.StatusForbidden)
+		return nil, false
+	}
+	return principal, true
+}
+
+func (p PresentationHandlersImpl) IssueAccessToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.AccessTokenPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		token, err := p.interactor.Courses.IssueAccessToken(
+			ctx,
+			domain.Role(payload.Role),
+			payload.Subject,
+			time.Duration(payload.TTLHours)*time.Hour,
+		)
+		if err != nil {
+			msg := fmt.Sprintf("error issuing access token: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, token, http.StatusCreated)
+	}
+}
+
+// accessErrorResponse writes the response for an error that may be a caller
+// acting outside of their role
+func accessErrorResponse(w http.ResponseWriter, prefix string, err error) {
+	if errors.Is(err, domain.ErrForbidden) {
+		web.JSON(w, map[string]string{"error": err.Error()}, http.StatusForbidden)
+		return
+	}
+	msg := fmt.Sprintf("%s: %v", prefix, err)
+	web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+}
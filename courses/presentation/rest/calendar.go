@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) IssueCalendarToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		studentUUID := mux.Vars(r)["uuid"]
+
+		subscription, err := p.interactor.Courses.IssueCalendarToken(ctx, principalFromContext(ctx), &studentUUID)
+		if err != nil {
+			accessErrorResponse(w, "error issuing calendar token", err)
+			return
+		}
+
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		feedURL := fmt.Sprintf(
+			"%s://%s/api/v1/students/%s/calendar.ics?token=%s",
+			scheme, r.Host, url.PathEscape(studentUUID), url.QueryEscape(subscription.Token),
+		)
+		web.JSON(w, map[string]interface{}{
+			"subscription": subscription,
+			"url":          feedURL,
+		}, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) GetStudentCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		studentUUID := mux.Vars(r)["uuid"]
+
+		feed, err := p.interactor.Courses.GetStudentCalendar(ctx, &studentUUID, r.URL.Query().Get("token"))
+		if errors.Is(err, domain.ErrInvalidCalendarToken) {
+			// don't tell a wrong token apart from an unknown student
+			web.JSON(w, map[string]string{"error": "calendar not found"}, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			msg := fmt.Sprintf("error getting calendar: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="sudocode.ics"`)
+		w.Header().Set("Cache-Control", "private, max-age=900")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(feed)
+	}
+}
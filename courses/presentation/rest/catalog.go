@@ -0,0 +1,236 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+// catalogErrorResponse writes the response for an error updating or deleting
+// a category or an instructor
+func catalogErrorResponse(w http.ResponseWriter, prefix string, err error) {
+	msg := fmt.Sprintf("%s: %v", prefix, err)
+	status := http.StatusBadRequest
+	var notFound *domain.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInUse):
+		status = http.StatusConflict
+	}
+	web.JSON(w, map[string]string{"error": msg}, status)
+}
+
+// optional turns an empty payload field into a nil reference
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func (p PresentationHandlersImpl) CreateCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.CategoryPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		category, err := p.interactor.Courses.CreateCategory(ctx, &domain.Category{
+			Name:       payload.Name,
+			Slug:       payload.Slug,
+			ParentUUID: optional(payload.ParentUUID),
+		})
+		if err != nil {
+			msg := fmt.Sprintf("error creating category: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, category, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) ListCategories() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		categories, err := p.interactor.Courses.ListCategories(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error listing categories: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, categories, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		categoryUUID := mux.Vars(r)["uuid"]
+
+		category, err := p.interactor.Courses.GetCategory(ctx, &categoryUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting category: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if category == nil {
+			msg := fmt.Sprintf("category %s not found", categoryUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, category, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) UpdateCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.CategoryPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		category := &domain.Category{
+			Name:       payload.Name,
+			Slug:       payload.Slug,
+			ParentUUID: optional(payload.ParentUUID),
+		}
+		category.UUID = mux.Vars(r)["uuid"]
+		updatedCategory, err := p.interactor.Courses.UpdateCategory(ctx, category)
+		if err != nil {
+			catalogErrorResponse(w, "error updating category", err)
+			return
+		}
+
+		web.JSON(w, updatedCategory, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DeleteCategory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		categoryUUID := mux.Vars(r)["uuid"]
+
+		if err := p.interactor.Courses.DeleteCategory(ctx, &categoryUUID); err != nil {
+			catalogErrorResponse(w, "error deleting category", err)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "category deleted"}, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) CreateInstructor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.InstructorPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		instructor, err := p.interactor.Courses.CreateInstructor(ctx, &domain.Instructor{
+			Name:      payload.Name,
+			Bio:       payload.Bio,
+			AvatarURL: payload.AvatarURL,
+			UserUUID:  optional(payload.UserUUID),
+		})
+		if err != nil {
+			msg := fmt.Sprintf("error creating instructor: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, instructor, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) ListInstructors() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		instructors, err := p.interactor.Courses.ListInstructors(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error listing instructors: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, instructors, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetInstructor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		instructorUUID := mux.Vars(r)["uuid"]
+
+		instructor, err := p.interactor.Courses.GetInstructor(ctx, &instructorUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting instructor: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if instructor == nil {
+			msg := fmt.Sprintf("instructor %s not found", instructorUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, instructor, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) UpdateInstructor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.InstructorPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		instructor := &domain.Instructor{
+			Name:      payload.Name,
+			Bio:       payload.Bio,
+			AvatarURL: payload.AvatarURL,
+			UserUUID:  optional(payload.UserUUID),
+		}
+		instructor.UUID = mux.Vars(r)["uuid"]
+		updatedInstructor, err := p.interactor.Courses.UpdateInstructor(ctx, instructor)
+		if err != nil {
+			catalogErrorResponse(w, "error updating instructor", err)
+			return
+		}
+
+		web.JSON(w, updatedInstructor, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DeleteInstructor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		instructorUUID := mux.Vars(r)["uuid"]
+
+		if err := p.interactor.Courses.DeleteInstructor(ctx, &instructorUUID); err != nil {
+			catalogErrorResponse(w, "error deleting instructor", err)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "instructor deleted"}, http.StatusOK)
+	}
+}
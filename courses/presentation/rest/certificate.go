@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) IssueCertificate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		studentUUID, courseUUID := vars["uuid"], vars["courseUUID"]
+
+		certificate, err := p.interactor.Courses.IssueCertificate(ctx, &studentUUID, &courseUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error issuing certificate: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, certificate, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) VerifyCertificate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		serial := mux.Vars(r)["serial"]
+
+		verification, err := p.interactor.Courses.VerifyCertificate(ctx, &serial)
+		if err != nil {
+			msg := fmt.Sprintf("error verifying certificate: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if verification.Certificate == nil {
+			web.JSON(w, verification, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, verification, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) RevokeCertificate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.CertificateRevocationPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		serial := mux.Vars(r)["serial"]
+		certificate, err := p.interactor.Courses.RevokeCertificate(ctx, &serial, payload.Reason)
+		if err != nil {
+			msg := fmt.Sprintf("error revoking certificate: %v", err)
+			status := http.StatusBadRequest
+			var notFound *domain.NotFoundError
+			if errors.As(err, &notFound) {
+				status = http.StatusNotFound
+			}
+			web.JSON(w, map[string]string{"error": msg}, status)
+			return
+		}
+
+		web.JSON(w, certificate, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DownloadCertificatePDF() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		serial := mux.Vars(r)["serial"]
+
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		verifyURL := fmt.Sprintf("%s://%s/api/v1/certificates/%s/verify", scheme, r.Host, serial)
+
+		pdf, err := p.interactor.Courses.RenderCertificatePDF(ctx, &serial, verifyURL)
+		if err != nil {
+			msg := fmt.Sprintf("error rendering certificate: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if pdf == nil {
+			msg := fmt.Sprintf("certificate %s not found", serial)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", serial+".pdf"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(pdf)
+	}
+}
@@ -0,0 +1,146 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) CreateCoupon() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.CouponPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		coupon := &domain.Coupon{
+			Code:            payload.Code,
+			DiscountType:    domain.DiscountType(payload.DiscountType),
+			PercentOff:      payload.PercentOff,
+			AmountOff:       domain.Money{Amount: payload.AmountOff, Currency: payload.Currency},
+			ValidFrom:       payload.ValidFrom,
+			ValidUntil:      payload.ValidUntil,
+			MaxRedemptions:  payload.MaxRedemptions,
+			PerStudentLimit: payload.PerStudentLimit,
+		}
+		for _, courseUUID := range payload.CourseUUIDs {
+			coupon.Courses = append(coupon.Courses, &domain.CouponCourse{CourseUUID: courseUUID})
+		}
+		for _, categoryUUID := range payload.CategoryUUIDs {
+			coupon.Categories = append(coupon.Categories, &domain.CouponCategory{CategoryUUID: categoryUUID})
+		}
+
+		createdCoupon, err := p.interactor.Courses.CreateCoupon(ctx, coupon)
+		if err != nil {
+			msg := fmt.Sprintf("error creating coupon: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, createdCoupon, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) ListCoupons() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		coupons, err := p.interactor.Courses.ListCoupons(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error listing coupons: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, coupons, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetCoupon() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		couponUUID := mux.Vars(r)["uuid"]
+
+		coupon, err := p.interactor.Courses.GetCoupon(ctx, &couponUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting coupon: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if coupon == nil {
+			msg := fmt.Sprintf("coupon %s not found", couponUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, coupon, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DeactivateCoupon() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		couponUUID := mux.Vars(r)["uuid"]
+
+		if err := p.interactor.Courses.DeactivateCoupon(ctx, &couponUUID); err != nil {
+			msg := fmt.Sprintf("error deactivating coupon: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "coupon deactivated"}, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) ValidateCoupon() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.CouponValidationPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		quote, err := p.interactor.Courses.QuoteCoupon(
+			ctx,
+			payload.Code,
+			&payload.CourseUUID,
+			payload.StudentUUID,
+			payload.Currency,
+		)
+		if errors.Is(err, domain.ErrCouponRejected) {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusUnprocessableEntity)
+			return
+		}
+		if err != nil {
+			msg := fmt.Sprintf("error validating coupon: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, quote, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetCouponUsage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		usage, err := p.interactor.Courses.GetCouponUsage(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error getting coupon usage: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, usage, http.StatusOK)
+	}
+}
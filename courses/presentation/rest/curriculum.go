@@ -0,0 +1,209 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) GetCourseCurriculum() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		courseUUID := mux.Vars(r)["uuid"]
+
+		course, err := p.interactor.Courses.GetCourseCurriculum(ctx, &courseUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting course curriculum: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if course == nil {
+			msg := fmt.Sprintf("course %s not found", courseUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, course, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) CreateModule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.ModulePayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		module := domain.Module{
+			CourseUUID: mux.Vars(r)["uuid"],
+			Title:      payload.Title,
+			Position:   payload.Position,
+		}
+		createdModule, err := p.interactor.Courses.CreateModule(ctx, &module)
+		if err != nil {
+			msg := fmt.Sprintf("error creating module: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, createdModule, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) UpdateModule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.ModulePayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		module := domain.Module{
+			Title: payload.Title,
+		}
+		module.UUID = mux.Vars(r)["uuid"]
+		updatedModule, err := p.interactor.Courses.UpdateModule(ctx, &module)
+		if err != nil {
+			msg := fmt.Sprintf("error updating module: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, updatedModule, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DeleteModule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		moduleUUID := mux.Vars(r)["uuid"]
+
+		if err := p.interactor.Courses.DeleteModule(ctx, &moduleUUID); err != nil {
+			msg := fmt.Sprintf("error deleting module: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "module deleted"}, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) ReorderModules() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.ReorderPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		courseUUID := mux.Vars(r)["uuid"]
+		if err := p.interactor.Courses.ReorderModules(ctx, &courseUUID, payload.UUIDs); err != nil {
+			msg := fmt.Sprintf("error reordering modules: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "modules reordered"}, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) CreateLesson() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.LessonPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		lesson := domain.Lesson{
+			ModuleUUID:      mux.Vars(r)["uuid"],
+			Title:           payload.Title,
+			Type:            domain.LessonType(payload.Type),
+			Position:        payload.Position,
+			DurationMinutes: payload.DurationMinutes,
+			ContentURL:      payload.ContentURL,
+			Body:            payload.Body,
+		}
+		createdLesson, err := p.interactor.Courses.CreateLesson(ctx, &lesson)
+		if err != nil {
+			msg := fmt.Sprintf("error creating lesson: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, createdLesson, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) UpdateLesson() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.LessonPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		lesson := domain.Lesson{
+			Title:           payload.Title,
+			Type:            domain.LessonType(payload.Type),
+			DurationMinutes: payload.DurationMinutes,
+			ContentURL:      payload.ContentURL,
+			Body:            payload.Body,
+		}
+		lesson.UUID = mux.Vars(r)["uuid"]
+		updatedLesson, err := p.interactor.Courses.UpdateLesson(ctx, &lesson)
+		if err != nil {
+			msg := fmt.Sprintf("error updating lesson: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, updatedLesson, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DeleteLesson() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		lessonUUID := mux.Vars(r)["uuid"]
+
+		if err := p.interactor.Courses.DeleteLesson(ctx, &lessonUUID); err != nil {
+			msg := fmt.Sprintf("error deleting lesson: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "lesson deleted"}, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) ReorderLessons() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.ReorderPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		moduleUUID := mux.Vars(r)["uuid"]
+		if err := p.interactor.Courses.ReorderLessons(ctx, &moduleUUID, payload.UUIDs); err != nil {
+			msg := fmt.Sprintf("error reordering lessons: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "lessons reordered"}, http.StatusOK)
+	}
+}
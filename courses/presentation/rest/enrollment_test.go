@@ -0,0 +1,128 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	paymentsmock "github.com/MelvinKim/courses/infrastructure/payments/mock"
+	"github.com/MelvinKim/courses/infrastructure/search"
+	"github.com/MelvinKim/courses/presentation/interactor"
+	"github.com/MelvinKim/courses/presentation/rest"
+	"github.com/MelvinKim/courses/repository/mock"
+	"github.com/MelvinKim/courses/usecase"
+)
+
+const testAdminToken = "admin-token"
+
+// newTestHandlers returns handlers backed by a usecase over mock repositories
+func newTestHandlers(t *testing.T, create *mock.MockCreateRepository, get *mock.MockGetRepository) (rest.PresentationHandlers, *usecase.Usecase) {
+	t.Helper()
+	signer, err := certificates.NewEd25519Signer(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("can't create signer: %v", err)
+	}
+	u := usecase.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), signer, search.NewMemoryIndex(), fx.NewStaticRates(), paymentsmock.NewMockProvider())
+	u.AdminToken = testAdminToken
+	u.TokenSigner = signer
+	return rest.NewPresentationHandlers(&interactor.Interactor{Courses: u}), u
+}
+
+func TestHandlersInterfacesImpl_Override(t *testing.T) {
+	student := &domain.Student{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Email: gofakeit.Email()}
+	runUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name       string
+		override   bool
+		role       domain.Role
+		wantStatus int
+	}{
+		{
+			name:     "Happy case - no override needs no token",
+			override: false,
+		},
+		{
+			name:     "Happy case - admin overrides",
+			override: true,
+			role:     domain.RoleAdmin,
+		},
+		{
+			name:       "Sad case - override without a token",
+			override:   true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Sad case - student overrides",
+			override:   true,
+			role:       domain.RoleStudent,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		for endpoint, payload := range map[string]map[string]interface{}{
+			"/assign_course":                    {"email": student.Email, "course_title": "Go 101", "override": tt.override},
+			"/runs/" + runUUID + "/enrollments": {"email": student.Email, "override": tt.override},
+		} {
+			endpoint, payload := endpoint, payload
+			t.Run(tt.name+" "+endpoint, func(t *testing.T) {
+				get := mock.NewMockGetRepository()
+				get.MockGetStudent = func(ctx context.Context, email *string) (*domain.Student, error) {
+					return student, nil
+				}
+				get.MockGetCourseRun = func(ctx context.Context, uuid *string) (*domain.CourseRun, error) {
+					return &domain.CourseRun{AbstractBase: domain.AbstractBase{UUID: *uuid}, Name: "Cohort 1", Evergreen: true}, nil
+				}
+				create := mock.NewMockCreateRepository()
+				assigned := false
+				create.MockAssignCourseToStudent = func(ctx context.Context, email, courseTitle, runUUID *string, redemption *domain.CouponRedemption) (*domain.Student, error) {
+					assigned = true
+					return student, nil
+				}
+				h, u := newTestHandlers(t, create, get)
+				router := mux.NewRouter()
+				router.Path("/assign_course").HandlerFunc(h.AssignCourseToStudent())
+				router.Path("/runs/{uuid}/enrollments").HandlerFunc(h.EnrollInRun())
+
+				body, _ := json.Marshal(payload)
+				r := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+				switch tt.role {
+				case domain.RoleAdmin:
+					r.Header.Set("Authorization", "Bearer "+testAdminToken)
+				case domain.RoleStudent:
+					token, err := u.IssueAccessToken(context.Background(), domain.RoleStudent, student.UUID, time.Hour)
+					if err != nil {
+						t.Fatalf("can't issue token: %v", err)
+					}
+					r.Header.Set("Authorization", "Bearer "+token.Token)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, r)
+
+				if tt.wantStatus != 0 {
+					if w.Code != tt.wantStatus {
+						t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body)
+					}
+					if assigned {
+						t.Errorf("expected nothing to be assigned")
+					}
+					return
+				}
+				if w.Code == http.StatusUnauthorized || w.Code == http.StatusForbidden || !assigned {
+					t.Errorf("expected the student to be assigned, got %d: %s", w.Code, w.Body)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/exports"
+	"github.com/MelvinKim/platform/web"
+)
+
+// exportResponse sends the export's headers when the first of it is written,
+// so that a refused query can still be answered with an error
+type exportResponse struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (e *exportResponse) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", e.contentType)
+		e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+func (p PresentationHandlersImpl) ExportTable() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		params := r.URL.Query()
+		query := &domain.ExportQuery{
+			Table:  domain.ExportTable(mux.Vars(r)["table"]),
+			Format: domain.ExportFormat(params.Get("format")),
+		}
+		if query.Format == "" {
+			query.Format = domain.ExportFormatCSV
+		}
+		if columns := params.Get("columns"); columns != "" {
+			query.Columns = strings.Split(columns, ",")
+		}
+		var err error
+		if query.From, query.To, err = periodParams(params); err != nil {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+			return
+		}
+		compressed := false
+		if value := params.Get("gzip"); value != "" {
+			if compressed, err = strconv.ParseBool(value); err != nil {
+				web.JSON(w, map[string]string{"error": "gzip must be true or false"}, http.StatusBadRequest)
+				return
+			}
+		}
+
+		response := &exportResponse{
+			w:           w,
+			contentType: exports.ContentTypes[query.Format],
+			filename:    fmt.Sprintf("%s.%s", query.Table, query.Format),
+		}
+		var out io.Writer = response
+		var zw *gzip.Writer
+		if compressed {
+			response.contentType = "application/gzip"
+			response.filename += ".gz"
+			zw = gzip.NewWriter(response)
+			out = zw
+		}
+
+		written, err := p.interactor.Courses.Export(ctx, query, out)
+		if err != nil && !response.started {
+			msg := fmt.Sprintf("error exporting %s: %v", query.Table, err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			// the file is cut short, which is all the client can be told now
+			log.WithFields(log.Fields{"table": query.Table, "rows": written}).Errorf("export failed: %v", err)
+			return
+		}
+		if zw != nil {
+			err = zw.Close()
+		} else {
+			// an export of no rows may have nothing to write at all
+			_, err = response.Write(nil)
+		}
+		if err != nil {
+			log.WithFields(log.Fields{"table": query.Table, "rows": written}).Errorf("export failed: %v", err)
+		}
+	}
+}
@@ -1,13 +1,14 @@
 package rest
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/MelvinKim/courses/application/common/dto"
 	"github.com/MelvinKim/courses/domain"
 	"github.com/MelvinKim/courses/presentation/interactor"
+	"github.com/MelvinKim/platform/web"
 )
 
 // PresentationHandlers represents all the REST API logic
@@ -17,6 +18,96 @@ type PresentationHandlers interface {
 	AssignCourseToStudent() http.HandlerFunc
 	GetStudent() http.HandlerFunc
 	GetCourse() http.HandlerFunc
+	GetCourseCurriculum() http.HandlerFunc
+	CreateModule() http.HandlerFunc
+	UpdateModule() http.HandlerFunc
+	DeleteModule() http.HandlerFunc
+	ReorderModules() http.HandlerFunc
+	CreateLesson() http.HandlerFunc
+	UpdateLesson() http.HandlerFunc
+	DeleteLesson() http.HandlerFunc
+	ReorderLessons() http.HandlerFunc
+	AddCoursePrerequisite() http.HandlerFunc
+	RemoveCoursePrerequisite() http.HandlerFunc
+	GetPrerequisiteTree() http.HandlerFunc
+	UnenrollStudent() http.HandlerFunc
+	GetWaitlist() http.HandlerFunc
+	GetWaitlistPosition() http.HandlerFunc
+	LeaveWaitlist() http.HandlerFunc
+	CreateCourseRun() http.HandlerFunc
+	GetUpcomingCourseRuns() http.HandlerFunc
+	GetCourseRun() http.HandlerFunc
+	AddLiveSession() http.HandlerFunc
+	EnrollInRun() http.HandlerFunc
+	IssueCalendarToken() http.HandlerFunc
+	GetStudentCalendar() http.HandlerFunc
+	ReviewCourse() http.HandlerFunc
+	GetCourseReviews() http.HandlerFunc
+	ModerateReview() http.HandlerFunc
+	ListCourses() http.HandlerFunc
+	SearchCourses() http.HandlerFunc
+	CreateCategory() http.HandlerFunc
+	ListCategories() http.HandlerFunc
+	GetCategory() http.HandlerFunc
+	UpdateCategory() http.HandlerFunc
+	DeleteCategory() http.HandlerFunc
+	CreateInstructor() http.HandlerFunc
+	ListInstructors() http.HandlerFunc
+	GetInstructor() http.HandlerFunc
+	UpdateInstructor() http.HandlerFunc
+	DeleteInstructor() http.HandlerFunc
+	SetCoursePrice() http.HandlerFunc
+	GetCoursePrices() http.HandlerFunc
+	GetCoursePriceHistory() http.HandlerFunc
+	CreateCoupon() http.HandlerFunc
+	ListCoupons() http.HandlerFunc
+	GetCoupon() http.HandlerFunc
+	DeactivateCoupon() http.HandlerFunc
+	ValidateCoupon() http.HandlerFunc
+	GetCouponUsage() http.HandlerFunc
+	CreatePlan() http.HandlerFunc
+	ListPlans() http.HandlerFunc
+	Subscribe() http.HandlerFunc
+	GetStudentSubscription() http.HandlerFunc
+	CancelSubscription() http.HandlerFunc
+	GetSubscriptionCharges() http.HandlerFunc
+	SetTaxRate() http.HandlerFunc
+	ListTaxRates() http.HandlerFunc
+	ListStudentInvoices() http.HandlerFunc
+	GetInvoice() http.HandlerFunc
+	DownloadReceiptPDF() http.HandlerFunc
+	DownloadReceiptHTML() http.HandlerFunc
+	RefundPayment() http.HandlerFunc
+	GetPaymentRefunds() http.HandlerFunc
+	GetLedgerBalances() http.HandlerFunc
+	ListLedgerEntries() http.HandlerFunc
+	GetRevenueReport() http.HandlerFunc
+	GetRevenueReconciliation() http.HandlerFunc
+	RequireRole(next http.HandlerFunc, roles ...domain.Role) http.HandlerFunc
+	IssueAccessToken() http.HandlerFunc
+	SetRevenueShare() http.HandlerFunc
+	ListRevenueShares() http.HandlerFunc
+	GetInstructorEarnings() http.HandlerFunc
+	GeneratePayoutStatements() http.HandlerFunc
+	ListPayoutStatements() http.HandlerFunc
+	GetPayoutStatement() http.HandlerFunc
+	DownloadPayoutStatementHTML() http.HandlerFunc
+	DownloadPayoutStatementCSV() http.HandlerFunc
+	MarkPayoutPaid() http.HandlerFunc
+	CreateImport() http.HandlerFunc
+	GetImport() http.HandlerFunc
+	DownloadImportErrors() http.HandlerFunc
+	ExportTable() http.HandlerFunc
+	ReceivePaymentWebhook() http.HandlerFunc
+	RecordLessonProgress() http.HandlerFunc
+	GetStudentEnrollments() http.HandlerFunc
+	CreateQuiz() http.HandlerFunc
+	GetQuiz() http.HandlerFunc
+	SubmitAttempt() http.HandlerFunc
+	IssueCertificate() http.HandlerFunc
+	VerifyCertificate() http.HandlerFunc
+	RevokeCertificate() http.HandlerFunc
+	DownloadCertificatePDF() http.HandlerFunc
 }
 
 // PresentationHandlersImpl represents the usecase implementation object
@@ -31,20 +122,45 @@ func NewPresentationHandlers(
 	return &PresentationHandlersImpl{i}
 }
 
-func jsonResponse(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
+// assignmentErrorResponse writes the response for a failed course assignment.
+// Missing prerequisites are listed and a waitlisted student gets their place
+func assignmentErrorResponse(w http.ResponseWriter, prefix string, err error) {
+	var missing *domain.MissingPrerequisitesError
+	if errors.As(err, &missing) {
+		web.JSON(w, map[string]interface{}{
+			"error":                 fmt.Sprintf("%s: %v", prefix, err),
+			"missing_prerequisites": missing.Missing,
+		}, http.StatusConflict)
+		return
+	}
+	var waitlisted *domain.WaitlistedError
+	if errors.As(err, &waitlisted) {
+		web.JSON(w, map[string]interface{}{
+			"message":  err.Error(),
+			"waitlist": waitlisted.Entry,
+		}, http.StatusAccepted)
+		return
+	}
+	if errors.Is(err, domain.ErrCouponRejected) {
+		msg := fmt.Sprintf("%s: %v", prefix, err)
+		web.JSON(w, map[string]string{"error": msg}, http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, domain.ErrSubscriptionRequired) {
+		msg := fmt.Sprintf("%s: %v", prefix, err)
+		web.JSON(w, map[string]string{"error": msg}, http.StatusPaymentRequired)
+		return
+	}
+	msg := fmt.Sprintf("%s: %v", prefix, err)
+	web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
 }
 
 func (p PresentationHandlersImpl) CreateStudent() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		payload := &dto.StudentCreationPayload{}
-		err := json.NewDecoder(r.Body).Decode(payload)
-		if err != nil {
-			msg := fmt.Sprintf("error unmarshalling request body to struct: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
 			return
 		}
 
@@ -56,11 +172,11 @@ func (p PresentationHandlersImpl) CreateStudent() http.HandlerFunc {
 		createdStudent, err := p.interactor.Courses.CreateStudent(ctx, &student)
 		if err != nil {
 			msg := fmt.Sprintf("error creating student: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
 			return
 		}
 
-		jsonResponse(w, createdStudent, http.StatusCreated)
+		web.JSON(w, createdStudent, http.StatusCreated)
 	}
 }
 
@@ -68,28 +184,29 @@ func (p PresentationHandlersImpl) CreateCourse() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		payload := &dto.CourseCreationPayload{}
-		err := json.NewDecoder(r.Body).Decode(payload)
-		if err != nil {
-			msg := fmt.Sprintf("error unmarshalling request body to struct: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
 			return
 		}
 
 		course := domain.Course{
-			Title:       payload.Title,
-			Price:       payload.Price,
-			Description: payload.Description,
-			Instructor:  payload.Instructor,
-			Category:    payload.Category,
+			Title:          payload.Title,
+			Price:          payload.Price,
+			Description:    payload.Description,
+			Instructor:     payload.Instructor,
+			InstructorUUID: optional(payload.InstructorUUID),
+			Category:       payload.Category,
+			CategoryUUID:   optional(payload.CategoryUUID),
+			Capacity:       payload.Capacity,
 		}
 		createdStudent, err := p.interactor.Courses.CreateCourse(ctx, &course)
 		if err != nil {
 			msg := fmt.Sprintf("error creating course: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
 			return
 		}
 
-		jsonResponse(w, createdStudent, http.StatusCreated)
+		web.JSON(w, createdStudent, http.StatusCreated)
 	}
 }
 
@@ -97,20 +214,23 @@ func (p PresentationHandlersImpl) AssignCourseToStudent() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		payload := &dto.StudentCourseAssigningPayload{}
-		err := json.NewDecoder(r.Body).Decode(payload)
-		if err != nil {
-			msg := fmt.Sprintf("error unmarshalling request body to struct: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
 			return
 		}
-		student, err := p.interactor.Courses.AssignCourseToStudent(ctx, &payload.Email, &payload.CourseTitle)
+		// skipping the enrollment checks is for admins only
+		if payload.Override {
+			if _, ok := p.authorize(w, r, domain.RoleAdmin); !ok {
+				return
+			}
+		}
+		student, err := p.interactor.Courses.AssignCourseToStudent(ctx, &payload.Email, &payload.CourseTitle, payload.Override, payload.CouponCode)
 		if err != nil {
-			msg := fmt.Sprintf("error assigning course to student: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			assignmentErrorResponse(w, "error assigning course to student", err)
 			return
 		}
 
-		jsonResponse(w, student, http.StatusCreated)
+		web.JSON(w, student, http.StatusCreated)
 	}
 }
 
@@ -118,10 +238,8 @@ func (p PresentationHandlersImpl) GetStudent() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		payload := &dto.GetStudentPayload{}
-		err := json.NewDecoder(r.Body).Decode(payload)
-		if err != nil {
-			msg := fmt.Sprintf("error unmarshalling request boy to struct: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
 			return
 		}
 
@@ -129,11 +247,11 @@ func (p PresentationHandlersImpl) GetStudent() http.HandlerFunc {
 		student, err := p.interactor.Courses.GetStudent(ctx, &email)
 		if err != nil {
 			msg := fmt.Sprintf("error getting student: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
 			return
 		}
 
-		jsonResponse(w, student, http.StatusOK)
+		web.JSON(w, student, http.StatusOK)
 	}
 }
 
@@ -141,20 +259,25 @@ func (p PresentationHandlersImpl) GetCourse() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		payload := &dto.GetCoursePayload{}
-		err := json.NewDecoder(r.Body).Decode(payload)
-		if err != nil {
-			msg := fmt.Sprintf("error unmarshalling request boy to struct: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
 			return
 		}
 
 		course, err := p.interactor.Courses.GetCourse(ctx, &payload.CourseTitle)
 		if err != nil {
 			msg := fmt.Sprintf("error getting course: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
 			return
 		}
+		if course != nil {
+			if err := p.localizePrices(r, course); err != nil {
+				msg := fmt.Sprintf("error pricing course: %v", err)
+				web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+				return
+			}
+		}
 
-		jsonResponse(w, course, http.StatusOK)
+		web.JSON(w, course, http.StatusOK)
 	}
 }
@@ -27,7 +27,11 @@ var serverErr error
 func startTestServer(ctx context.Context) (*http.Server, string, error) {
 	// prepare the server
 	port := randomPort()
-	srv := presentation.PrepareServer(ctx, port)
+	i, err := presentation.NewInteractor()
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to set up the service: %w", err)
+	}
+	srv := presentation.PrepareServer(ctx, port, i)
 	baseURL := fmt.Sprintf("http://localhost:%d", port)
 	fmt.Println("base url: ", baseURL)
 	if srv == nil {
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+// maxImportBytes caps the size of import files
+const maxImportBytes = 10 << 20
+
+// importFormats maps the content types import files are sent as to formats
+var importFormats = map[string]domain.ImportFormat{
+	"text/csv":             domain.ImportFormatCSV,
+	"application/x-ndjson": domain.ImportFormatNDJSON,
+}
+
+func (p PresentationHandlersImpl) CreateImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format, ok := importFormats[contentType]
+		if !ok {
+			msg := "imports must be sent as text/csv or application/x-ndjson"
+			web.JSON(w, map[string]string{"error": msg}, http.StatusUnsupportedMediaType)
+			return
+		}
+		imp := &domain.Import{Format: format}
+		params := r.URL.Query()
+		for name, flag := range map[string]*bool{"dry_run": &imp.DryRun, "override": &imp.Override} {
+			if value := params.Get(name); value != "" {
+				parsed, err := strconv.ParseBool(value)
+				if err != nil {
+					msg := fmt.Sprintf("%s must be true or false", name)
+					web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+					return
+				}
+				*flag = parsed
+			}
+		}
+		if planUUID := params.Get("plan_uuid"); planUUID != "" {
+			imp.PlanUUID = &planUUID
+		}
+
+		created, err := p.interactor.Courses.CreateImport(ctx, imp, http.MaxBytesReader(w, r.Body, maxImportBytes))
+		if err != nil {
+			msg := fmt.Sprintf("error creating import: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Location", "/api/v1/imports/"+created.UUID)
+		web.JSON(w, created, http.StatusAccepted)
+	}
+}
+
+func (p PresentationHandlersImpl) GetImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		importUUID := mux.Vars(r)["uuid"]
+
+		imp, err := p.interactor.Courses.GetImport(ctx, &importUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting import: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if imp == nil {
+			msg := fmt.Sprintf("import %s not found", importUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, imp, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DownloadImportErrors() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		importUUID := mux.Vars(r)["uuid"]
+
+		report, err := p.interactor.Courses.GetImportErrorReport(ctx, &importUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error rendering import errors: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if report == nil {
+			msg := fmt.Sprintf("import %s not found", importUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "import-"+importUUID+"-errors.csv"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(report)
+	}
+}
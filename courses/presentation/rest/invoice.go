@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) SetTaxRate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.TaxRatePayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		rate, err := p.interactor.Courses.SetTaxRate(ctx, &domain.TaxRate{
+			Currency:    mux.Vars(r)["currency"],
+			Name:        payload.Name,
+			BasisPoints: payload.BasisPoints,
+		})
+		if err != nil {
+			msg := fmt.Sprintf("error setting tax rate: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, rate, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) ListTaxRates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		rates, err := p.interactor.Courses.ListTaxRates(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error listing tax rates: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, rates, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) ListStudentInvoices() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		studentUUID := mux.Vars(r)["uuid"]
+
+		invoices, err := p.interactor.Courses.ListStudentInvoices(ctx, principalFromContext(ctx), &studentUUID)
+		if err != nil {
+			accessErrorResponse(w, "error listing invoices", err)
+			return
+		}
+
+		web.JSON(w, invoices, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetInvoice() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		invoiceUUID := mux.Vars(r)["uuid"]
+
+		invoice, err := p.interactor.Courses.GetInvoice(ctx, principalFromContext(ctx), &invoiceUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting invoice: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if invoice == nil {
+			msg := fmt.Sprintf("invoice %s not found", invoiceUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, invoice, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DownloadReceiptPDF() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		invoiceUUID := mux.Vars(r)["uuid"]
+
+		receipt, err := p.interactor.Courses.RenderReceiptPDF(ctx, principalFromContext(ctx), &invoiceUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error rendering receipt: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if receipt == nil {
+			msg := fmt.Sprintf("invoice %s not found", invoiceUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "receipt-"+invoiceUUID+".pdf"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(receipt)
+	}
+}
+
+func (p PresentationHandlersImpl) DownloadReceiptHTML() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		invoiceUUID := mux.Vars(r)["uuid"]
+
+		receipt, err := p.interactor.Courses.RenderReceiptHTML(ctx, principalFromContext(ctx), &invoiceUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error rendering receipt: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if receipt == nil {
+			msg := fmt.Sprintf("invoice %s not found", invoiceUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(receipt)
+	}
+}
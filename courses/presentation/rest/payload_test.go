@@ -0,0 +1,151 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/repository/mock"
+	"github.com/MelvinKim/platform/validation"
+)
+
+func TestHandlersInterfacesImpl_PayloadErrors(t *testing.T) {
+	chargeUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name       string
+		endpoint   string
+		body       string
+		wantFields map[string]string
+		wantError  string
+		valid      bool
+	}{
+		{
+			name:     "Happy case - valid student",
+			endpoint: "/users",
+			body:     `{"first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com"}`,
+			valid:    true,
+		},
+		{
+			name:     "Sad case - student fails validation",
+			endpoint: "/users",
+			body:     `{"first_name": "", "last_name": "Lovelace", "email": "not an email"}`,
+			wantFields: map[string]string{
+				"first_name": "required",
+				"email":      "email",
+			},
+		},
+		{
+			name:     "Sad case - course fails validation",
+			endpoint: "/courses",
+			body:     `{"title": "Go", "price": 0, "description": "Concurrency", "category": "backend", "instructor_uuid": "ada"}`,
+			wantFields: map[string]string{
+				"title":           "min",
+				"price":           "required",
+				"instructor_uuid": "uuid",
+			},
+		},
+		{
+			name:     "Sad case - refund fails validation",
+			endpoint: "/payments/" + chargeUUID + "/refunds",
+			body:     `{"amount": 2500, "currency": "US", "course_uuid": "go-101"}`,
+			wantFields: map[string]string{
+				"reason":      "required",
+				"currency":    "len",
+				"course_uuid": "uuid",
+			},
+		},
+		{
+			name:      "Sad case - student with an unknown field",
+			endpoint:  "/users",
+			body:      `{"first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com", "role": "admin"}`,
+			wantError: `json: unknown field "role"`,
+		},
+		{
+			name:      "Sad case - refund with an unknown field",
+			endpoint:  "/payments/" + chargeUUID + "/refunds",
+			body:      `{"amount": 2500, "reason": "dropped course", "refund_everything": true}`,
+			wantError: `json: unknown field "refund_everything"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			create := mock.NewMockCreateRepository()
+			create.MockCreateStudent = func(ctx context.Context, student *domain.Student) (*domain.Student, error) {
+				reached = true
+				return student, nil
+			}
+			create.MockCreateCourse = func(ctx context.Context, course *domain.Course) (*domain.Course, error) {
+				reached = true
+				return course, nil
+			}
+			get := mock.NewMockGetRepository()
+			get.MockGetSubscriptionCharge = func(ctx context.Context, uuid *string) (*domain.SubscriptionCharge, error) {
+				reached = true
+				return nil, nil
+			}
+			h, _ := newTestHandlers(t, create, get)
+			router := mux.NewRouter()
+			router.Path("/users").HandlerFunc(h.CreateStudent())
+			router.Path("/courses").HandlerFunc(h.CreateCourse())
+			router.Path("/payments/{uuid}/refunds").HandlerFunc(h.RefundPayment())
+
+			r := httptest.NewRequest(http.MethodPost, tt.endpoint, bytes.NewReader([]byte(tt.body)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if tt.valid {
+				if w.Code != http.StatusCreated || !reached {
+					t.Errorf("expected the student to be created, got %d: %s", w.Code, w.Body)
+				}
+				return
+			}
+			if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != "application/json" {
+				t.Fatalf("expected a JSON bad request, got %d %s: %s", w.Code, w.Header().Get("Content-Type"), w.Body)
+			}
+			if reached {
+				t.Errorf("expected a refused payload not to reach the usecase")
+			}
+			var body struct {
+				Error  string                      `json:"error"`
+				Errors validation.ValidationErrors `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("expected a JSON body, got %v", err)
+			}
+			if tt.wantError != "" {
+				if !strings.Contains(body.Error, tt.wantError) || len(body.Errors) != 0 {
+					t.Errorf("expected the error to mention %q, got %+v", tt.wantError, body)
+				}
+				return
+			}
+			if body.Error != "" {
+				t.Errorf("expected field errors only, got error %q", body.Error)
+			}
+			got := map[string]string{}
+			for _, violation := range body.Errors {
+				if violation.Message == "" {
+					t.Errorf("expected a message for %s", violation.Field)
+				}
+				got[violation.Field] = violation.Code
+			}
+			if len(got) != len(tt.wantFields) {
+				t.Errorf("expected errors for %v, got %+v", tt.wantFields, body.Errors)
+			}
+			for field, code := range tt.wantFields {
+				if got[field] != code {
+					t.Errorf("expected %s to fail %q, got %q", field, code, got[field])
+				}
+			}
+		})
+	}
+}
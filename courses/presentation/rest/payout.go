@@ -0,0 +1,205 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) SetRevenueShare() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.RevenueSharePayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		share := &domain.RevenueShare{
+			InstructorUUID: mux.Vars(r)["uuid"],
+			CourseUUID:     optional(payload.CourseUUID),
+			BasisPoints:    payload.BasisPoints,
+		}
+		if payload.EffectiveFrom != nil {
+			share.EffectiveFrom = *payload.EffectiveFrom
+		}
+		share, err := p.interactor.Courses.SetRevenueShare(ctx, share)
+		if err != nil {
+			msg := fmt.Sprintf("error setting revenue share: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, share, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) ListRevenueShares() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		instructorUUID := mux.Vars(r)["uuid"]
+
+		shares, err := p.interactor.Courses.ListRevenueShares(ctx, principalFromContext(ctx), &instructorUUID)
+		if err != nil {
+			accessErrorResponse(w, "error listing revenue shares", err)
+			return
+		}
+
+		web.JSON(w, shares, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetInstructorEarnings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		instructorUUID := mux.Vars(r)["uuid"]
+
+		earnings, err := p.interactor.Courses.GetInstructorEarnings(ctx, principalFromContext(ctx), &instructorUUID)
+		if err != nil {
+			accessErrorResponse(w, "error getting earnings", err)
+			return
+		}
+
+		web.JSON(w, earnings, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GeneratePayoutStatements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.PayoutStatementsPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		statements, err := p.interactor.Courses.GeneratePayoutStatements(ctx, payload.Month)
+		if err != nil {
+			msg := fmt.Sprintf("error generating payout statements: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, statements, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) ListPayoutStatements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		params := r.URL.Query()
+
+		statements, err := p.interactor.Courses.ListPayoutStatements(ctx, params.Get("instructor_uuid"), params.Get("month"))
+		if err != nil {
+			msg := fmt.Sprintf("error listing payout statements: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, statements, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetPayoutStatement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		statementUUID := mux.Vars(r)["uuid"]
+
+		statement, err := p.interactor.Courses.GetPayoutStatement(ctx, principalFromContext(ctx), &statementUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting payout statement: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if statement == nil {
+			msg := fmt.Sprintf("payout statement %s not found", statementUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, statement, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) DownloadPayoutStatementHTML() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		statementUUID := mux.Vars(r)["uuid"]
+
+		page, err := p.interactor.Courses.RenderPayoutStatementHTML(ctx, principalFromContext(ctx), &statementUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error rendering payout statement: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if page == nil {
+			msg := fmt.Sprintf("payout statement %s not found", statementUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(page)
+	}
+}
+
+func (p PresentationHandlersImpl) DownloadPayoutStatementCSV() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		statementUUID := mux.Vars(r)["uuid"]
+
+		sheet, err := p.interactor.Courses.RenderPayoutStatementCSV(ctx, principalFromContext(ctx), &statementUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error rendering payout statement: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if sheet == nil {
+			msg := fmt.Sprintf("payout statement %s not found", statementUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "payout-"+statementUUID+".csv"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(sheet)
+	}
+}
+
+func (p PresentationHandlersImpl) MarkPayoutPaid() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.PayoutPaidPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		statementUUID := mux.Vars(r)["uuid"]
+		statement, err := p.interactor.Courses.MarkPayoutPaid(ctx, &statementUUID, payload.Reference)
+		if errors.Is(err, domain.ErrPayoutAlreadyPaid) {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusConflict)
+			return
+		}
+		if err != nil {
+			msg := fmt.Sprintf("error marking payout statement as paid: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if statement == nil {
+			msg := fmt.Sprintf("payout statement %s not found", statementUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, statement, http.StatusOK)
+	}
+}
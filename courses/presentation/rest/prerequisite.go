@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) AddCoursePrerequisite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.PrerequisitePayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		courseUUID := mux.Vars(r)["uuid"]
+		if err := p.interactor.Courses.AddCoursePrerequisite(ctx, &courseUUID, &payload.PrerequisiteUUID); err != nil {
+			msg := fmt.Sprintf("error adding course prerequisite: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "prerequisite added"}, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) RemoveCoursePrerequisite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		courseUUID, prerequisiteUUID := vars["uuid"], vars["prerequisiteUUID"]
+
+		if err := p.interactor.Courses.RemoveCoursePrerequisite(ctx, &courseUUID, &prerequisiteUUID); err != nil {
+			msg := fmt.Sprintf("error removing course prerequisite: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "prerequisite removed"}, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetPrerequisiteTree() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		courseUUID := mux.Vars(r)["uuid"]
+
+		tree, err := p.interactor.Courses.GetPrerequisiteTree(ctx, &courseUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting course prerequisites: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if tree == nil {
+			msg := fmt.Sprintf("course %s not found", courseUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, tree, http.StatusOK)
+	}
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+// localizePrices shows courses in the currency asked for with the currency
+// query parameter, if any. Courses that aren't priced yet are shown without
+// a local price
+func (p PresentationHandlersImpl) localizePrices(r *http.Request, courses ...*domain.Course) error {
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		return nil
+	}
+	for _, course := range courses {
+		err := p.interactor.Courses.LocalizeCoursePrice(r.Context(), course, currency)
+		if err != nil && !errors.Is(err, domain.ErrCourseNotPriced) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p PresentationHandlersImpl) SetCoursePrice() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.CoursePricePayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		courseUUID := mux.Vars(r)["uuid"]
+		price, err := p.interactor.Courses.SetCoursePrice(
+			ctx,
+			&courseUUID,
+			domain.Money{Amount: payload.Amount, Currency: payload.Currency},
+			payload.EffectiveFrom,
+		)
+		if err != nil {
+			msg := fmt.Sprintf("error setting course price: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, price, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) GetCoursePrices() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		courseUUID := mux.Vars(r)["uuid"]
+
+		prices, err := p.interactor.Courses.GetCoursePrices(ctx, &courseUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting course prices: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, prices, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetCoursePriceHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		courseUUID := mux.Vars(r)["uuid"]
+
+		prices, err := p.interactor.Courses.GetCoursePriceHistory(ctx, &courseUUID, r.URL.Query().Get("currency"))
+		if err != nil {
+			msg := fmt.Sprintf("error getting course price history: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, prices, http.StatusOK)
+	}
+}
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) RecordLessonProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.LessonProgressPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		vars := mux.Vars(r)
+		progress := domain.LessonProgress{
+			StudentUUID:         vars["uuid"],
+			LessonUUID:          vars["lessonUUID"],
+			Status:              domain.ProgressStatus(payload.Status),
+			LastPositionSeconds: payload.LastPositionSeconds,
+		}
+		savedProgress, err := p.interactor.Courses.RecordLessonProgress(ctx, &progress)
+		if err != nil {
+			msg := fmt.Sprintf("error recording lesson progress: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, savedProgress, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetStudentEnrollments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		studentUUID := mux.Vars(r)["uuid"]
+
+		enrollments, err := p.interactor.Courses.GetStudentEnrollments(ctx, &studentUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting student's enrollments: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, enrollments, http.StatusOK)
+	}
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) CreateQuiz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.QuizCreationPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		quiz := domain.Quiz{
+			LessonUUID:    mux.Vars(r)["uuid"],
+			Title:         payload.Title,
+			PassThreshold: payload.PassThreshold,
+			MaxAttempts:   payload.MaxAttempts,
+		}
+		for _, q := range payload.Questions {
+			quiz.Questions = append(quiz.Questions, &domain.Question{
+				Type:           domain.QuestionType(q.Type),
+				Prompt:         q.Prompt,
+				Options:        q.Options,
+				CorrectAnswers: q.CorrectAnswers,
+				Points:         q.Points,
+			})
+		}
+		createdQuiz, err := p.interactor.Courses.CreateQuiz(ctx, &quiz)
+		if err != nil {
+			msg := fmt.Sprintf("error creating quiz: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, createdQuiz, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) GetQuiz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		quizUUID := mux.Vars(r)["uuid"]
+
+		quiz, err := p.interactor.Courses.GetQuiz(ctx, &quizUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting quiz: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if quiz == nil {
+			msg := fmt.Sprintf("quiz %s not found", quizUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, quiz, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) SubmitAttempt() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.AttemptPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		attempt := domain.Attempt{
+			QuizUUID:    mux.Vars(r)["uuid"],
+			StudentUUID: payload.StudentUUID,
+			Answers:     payload.Answers,
+		}
+		gradedAttempt, err := p.interactor.Courses.SubmitAttempt(ctx, &attempt)
+		if err != nil {
+			msg := fmt.Sprintf("error submitting quiz attempt: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, gradedAttempt, http.StatusCreated)
+	}
+}
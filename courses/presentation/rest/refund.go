@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) RefundPayment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.RefundPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		chargeUUID := mux.Vars(r)["uuid"]
+		refund, err := p.interactor.Courses.RefundPayment(ctx, &chargeUUID, &domain.Refund{
+			Amount:     domain.Money{Amount: payload.Amount, Currency: payload.Currency},
+			Reason:     payload.Reason,
+			CourseUUID: payload.CourseUUID,
+		})
+		if err != nil {
+			msg := fmt.Sprintf("error refunding payment: %v", err)
+			status := http.StatusBadRequest
+			if errors.Is(err, domain.ErrRefundExceedsCharge) || errors.Is(err, domain.ErrCoolingOffOver) {
+				status = http.StatusUnprocessableEntity
+			}
+			web.JSON(w, map[string]string{"error": msg}, status)
+			return
+		}
+		if refund == nil {
+			msg := fmt.Sprintf("payment %s not found, only subscription charges can be refunded", chargeUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, refund, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) GetPaymentRefunds() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		chargeUUID := mux.Vars(r)["uuid"]
+
+		refunds, err := p.interactor.Courses.GetPaymentRefunds(ctx, &chargeUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting refunds: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, refunds, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetLedgerBalances() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		balances, err := p.interactor.Courses.GetLedgerBalances(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error getting ledger balances: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, balances, http.StatusOK)
+	}
+}
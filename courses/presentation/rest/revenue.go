@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) ListLedgerEntries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		params := r.URL.Query()
+		query := domain.LedgerQuery{
+			Account:    params.Get("account"),
+			CourseUUID: params.Get("course_uuid"),
+			ChargeUUID: params.Get("charge_uuid"),
+		}
+		var err error
+		if query.From, query.To, err = periodParams(params); err != nil {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+			return
+		}
+		for name, value := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+			if params.Get(name) == "" {
+				continue
+			}
+			n, err := strconv.Atoi(params.Get(name))
+			if err != nil {
+				web.JSON(w, map[string]string{"error": name + " must be a number"}, http.StatusBadRequest)
+				return
+			}
+			*value = n
+		}
+
+		entries, err := p.interactor.Courses.ListLedgerEntries(ctx, query)
+		if err != nil {
+			msg := fmt.Sprintf("error listing ledger entries: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, entries, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetRevenueReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		params := r.URL.Query()
+		query := domain.RevenueQuery{GroupBy: domain.RevenueGroup(params.Get("group_by"))}
+		var err error
+		if query.From, query.To, err = periodParams(params); err != nil {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+			return
+		}
+
+		rows, err := p.interactor.Courses.GetRevenueReport(ctx, query)
+		if err != nil {
+			msg := fmt.Sprintf("error getting revenue report: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		if params.Get("format") != "csv" {
+			web.JSON(w, rows, http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="revenue.csv"`)
+		w.WriteHeader(http.StatusOK)
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{
+			"month", "group_uuid", "group_name", "currency", "sales", "reversals",
+			"gross", "discounts", "net",
+		})
+		for _, row := range rows {
+			_ = writer.Write([]string{
+				row.Month,
+				row.GroupUUID,
+				row.GroupName,
+				row.Gross.Currency,
+				strconv.FormatInt(row.Sales, 10),
+				strconv.FormatInt(row.Reversals, 10),
+				row.Gross.Major(),
+				row.Discounts.Major(),
+				row.Net.Major(),
+			})
+		}
+		writer.Flush()
+	}
+}
+
+func (p PresentationHandlersImpl) GetRevenueReconciliation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		params := r.URL.Query()
+		var query domain.RevenueQuery
+		var err error
+		if query.From, query.To, err = periodParams(params); err != nil {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+			return
+		}
+
+		rows, err := p.interactor.Courses.GetRevenueReconciliation(ctx, query)
+		if err != nil {
+			msg := fmt.Sprintf("error reconciling revenue: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		if params.Get("format") != "csv" {
+			web.JSON(w, rows, http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="reconciliation.csv"`)
+		w.WriteHeader(http.StatusOK)
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{
+			"month", "currency", "charged", "refunded", "collected", "ledger_cash", "difference", "reconciled",
+		})
+		for _, row := range rows {
+			_ = writer.Write([]string{
+				row.Month,
+				row.Charged.Currency,
+				row.Charged.Major(),
+				row.Refunded.Major(),
+				row.Collected.Major(),
+				row.LedgerCash.Major(),
+				row.Difference.Major(),
+				strconv.FormatBool(row.Reconciled()),
+			})
+		}
+		writer.Flush()
+	}
+}
+
+// periodParams reads the from and to query parameters, given as dates or
+// RFC 3339 timestamps. The period includes from and ends before to
+func periodParams(params url.Values) (*time.Time, *time.Time, error) {
+	var period [2]*time.Time
+	for i, name := range []string{"from", "to"} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, nil, fmt.Errorf("%s must be a date like 2006-01-02 or an RFC 3339 timestamp", name)
+			}
+		}
+		period[i] = &t
+	}
+	return period[0], period[1], nil
+}
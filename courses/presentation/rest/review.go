@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) ReviewCourse() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.ReviewPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		vars := mux.Vars(r)
+		review, err := p.interactor.Courses.ReviewCourse(ctx, principalFromContext(ctx), &domain.Review{
+			StudentUUID: vars["uuid"],
+			CourseUUID:  vars["courseUUID"],
+			Rating:      payload.Rating,
+			Text:        payload.Text,
+		})
+		if errors.Is(err, domain.ErrReviewerNotEnrolled) {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			accessErrorResponse(w, "error reviewing course", err)
+			return
+		}
+
+		web.JSON(w, review, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetCourseReviews() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		courseUUID := mux.Vars(r)["uuid"]
+		status := domain.ReviewStatus(r.URL.Query().Get("status"))
+
+		reviews, err := p.interactor.Courses.GetCourseReviews(ctx, &courseUUID, status)
+		if err != nil {
+			msg := fmt.Sprintf("error getting course reviews: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, reviews, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) ModerateReview() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.ReviewModerationPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		reviewUUID := mux.Vars(r)["uuid"]
+		review, err := p.interactor.Courses.ModerateReview(ctx, &reviewUUID, domain.ReviewStatus(payload.Status))
+		if err != nil {
+			msg := fmt.Sprintf("error moderating review: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if review == nil {
+			msg := fmt.Sprintf("review %s not found", reviewUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, review, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) ListCourses() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sort := domain.CourseSort(r.URL.Query().Get("sort"))
+
+		courses, err := p.interactor.Courses.ListCourses(ctx, sort)
+		if err != nil {
+			msg := fmt.Sprintf("error listing courses: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if err := p.localizePrices(r, courses...); err != nil {
+			msg := fmt.Sprintf("error pricing courses: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, courses, http.StatusOK)
+	}
+}
@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) CreateCourseRun() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.CourseRunPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		run := domain.CourseRun{
+			CourseUUID: mux.Vars(r)["uuid"],
+			Name:       payload.Name,
+			StartDate:  &payload.StartDate,
+			EndDate:    &payload.EndDate,
+			Timezone:   payload.Timezone,
+			Instructor: payload.Instructor,
+		}
+		createdRun, err := p.interactor.Courses.CreateCourseRun(ctx, &run)
+		if err != nil {
+			msg := fmt.Sprintf("error creating course run: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, createdRun, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) GetUpcomingCourseRuns() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		courseUUID := mux.Vars(r)["uuid"]
+
+		runs, err := p.interactor.Courses.GetUpcomingCourseRuns(ctx, &courseUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting course runs: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, runs, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetCourseRun() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		runUUID := mux.Vars(r)["uuid"]
+
+		run, err := p.interactor.Courses.GetCourseRun(ctx, &runUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting course run: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if run == nil {
+			msg := fmt.Sprintf("course run %s not found", runUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, run, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) AddLiveSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.LiveSessionPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		session := domain.LiveSession{
+			RunUUID:         mux.Vars(r)["uuid"],
+			Title:           payload.Title,
+			StartsAt:        payload.StartsAt,
+			DurationMinutes: payload.DurationMinutes,
+		}
+		createdSession, err := p.interactor.Courses.AddLiveSession(ctx, &session)
+		if err != nil {
+			msg := fmt.Sprintf("error adding live session: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, createdSession, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) EnrollInRun() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.RunEnrollmentPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		// skipping the enrollment checks is for admins only
+		if payload.Override {
+			if _, ok := p.authorize(w, r, domain.RoleAdmin); !ok {
+				return
+			}
+		}
+		runUUID := mux.Vars(r)["uuid"]
+		student, err := p.interactor.Courses.EnrollInRun(ctx, &payload.Email, &runUUID, payload.Override, payload.CouponCode)
+		if err != nil {
+			assignmentErrorResponse(w, "error enrolling student", err)
+			return
+		}
+
+		web.JSON(w, student, http.StatusCreated)
+	}
+}
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) SearchCourses() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		params := r.URL.Query()
+		query := &domain.CourseSearchQuery{
+			Text:       params.Get("q"),
+			Category:   params.Get("category"),
+			Instructor: params.Get("instructor"),
+			PriceBand:  params.Get("price_band"),
+		}
+		if limit := params.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil {
+				web.JSON(w, map[string]string{"error": "limit must be a number"}, http.StatusBadRequest)
+				return
+			}
+			query.Limit = n
+		}
+
+		results, err := p.interactor.Courses.SearchCourses(ctx, query)
+		if err != nil {
+			msg := fmt.Sprintf("error searching courses: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, results, http.StatusOK)
+	}
+}
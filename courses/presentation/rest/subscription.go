@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) CreatePlan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.PlanPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		plan, err := p.interactor.Courses.CreatePlan(ctx, &domain.Plan{
+			Name:           payload.Name,
+			Price:          domain.Money{Amount: payload.Price, Currency: payload.Currency},
+			IntervalMonths: payload.IntervalMonths,
+			TrialDays:      payload.TrialDays,
+			GraceDays:      payload.GraceDays,
+		})
+		if err != nil {
+			msg := fmt.Sprintf("error creating plan: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, plan, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) ListPlans() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		plans, err := p.interactor.Courses.ListPlans(ctx)
+		if err != nil {
+			msg := fmt.Sprintf("error listing plans: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, plans, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) Subscribe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		payload := &dto.SubscriptionPayload{}
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
+			return
+		}
+
+		studentUUID := mux.Vars(r)["uuid"]
+		// clients retry a signup safely with the same Idempotency-Key
+		idempotencyKey := r.Header.Get("Idempotency-Key")
+		subscription, err := p.interactor.Courses.Subscribe(ctx, principalFromContext(ctx), &studentUUID, &payload.PlanUUID, idempotencyKey)
+		if err != nil {
+			accessErrorResponse(w, "error subscribing student", err)
+			return
+		}
+
+		web.JSON(w, subscription, http.StatusCreated)
+	}
+}
+
+func (p PresentationHandlersImpl) GetStudentSubscription() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		studentUUID := mux.Vars(r)["uuid"]
+
+		subscription, err := p.interactor.Courses.GetStudentSubscription(ctx, &studentUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting subscription: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if subscription == nil {
+			msg := fmt.Sprintf("student %s has no subscription", studentUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, subscription, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) CancelSubscription() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		subscriptionUUID := mux.Vars(r)["uuid"]
+
+		subscription, err := p.interactor.Courses.CancelSubscription(ctx, principalFromContext(ctx), &subscriptionUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error canceling subscription: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if subscription == nil {
+			msg := fmt.Sprintf("subscription %s not found", subscriptionUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, subscription, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetSubscriptionCharges() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		subscriptionUUID := mux.Vars(r)["uuid"]
+
+		charges, err := p.interactor.Courses.GetSubscriptionCharges(ctx, &subscriptionUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting subscription charges: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, charges, http.StatusOK)
+	}
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/platform/web"
+)
+
+func (p PresentationHandlersImpl) UnenrollStudent() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		studentUUID, courseUUID := vars["uuid"], vars["courseUUID"]
+
+		err := p.interactor.Courses.UnenrollStudent(ctx, principalFromContext(ctx), &studentUUID, &courseUUID)
+		var notFound *domain.NotFoundError
+		if errors.As(err, &notFound) {
+			web.JSON(w, map[string]string{"error": err.Error()}, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			accessErrorResponse(w, "error unenrolling student", err)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "student unenrolled"}, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetWaitlist() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		courseUUID := mux.Vars(r)["uuid"]
+
+		waitlist, err := p.interactor.Courses.GetWaitlist(ctx, &courseUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting waitlist: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, waitlist, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) GetWaitlistPosition() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		courseUUID, studentUUID := vars["uuid"], vars["studentUUID"]
+
+		entry, err := p.interactor.Courses.GetWaitlistPosition(ctx, &courseUUID, &studentUUID)
+		if err != nil {
+			msg := fmt.Sprintf("error getting waitlist position: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		if entry == nil {
+			msg := fmt.Sprintf("student %s is not on the waitlist of course %s", studentUUID, courseUUID)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusNotFound)
+			return
+		}
+
+		web.JSON(w, entry, http.StatusOK)
+	}
+}
+
+func (p PresentationHandlersImpl) LeaveWaitlist() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		vars := mux.Vars(r)
+		courseUUID, studentUUID := vars["uuid"], vars["studentUUID"]
+
+		if err := p.interactor.Courses.LeaveWaitlist(ctx, &courseUUID, &studentUUID); err != nil {
+			msg := fmt.Sprintf("error leaving waitlist: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		web.JSON(w, map[string]string{"message": "student removed from the waitlist"}, http.StatusOK)
+	}
+}
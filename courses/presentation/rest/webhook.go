@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/MelvinKim/courses/infrastructure/payments"
+	"github.com/MelvinKim/platform/web"
+)
+
+// maxWebhookBytes caps the size of webhook bodies, which are read whole to
+// check their signature
+const maxWebhookBytes = 1 << 20
+
+func (p PresentationHandlersImpl) ReceivePaymentWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+		if err != nil {
+			msg := fmt.Sprintf("error reading webhook: %v", err)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+
+		event, err := p.interactor.Courses.HandlePaymentWebhook(ctx, r.Header.Get(payments.SignatureHeader), body)
+		if err != nil {
+			msg := fmt.Sprintf("error handling webhook: %v", err)
+			status := http.StatusBadRequest
+			if errors.Is(err, payments.ErrInvalidSignature) || errors.Is(err, payments.ErrStaleWebhook) {
+				status = http.StatusUnauthorized
+			}
+			web.JSON(w, map[string]string{"error": msg}, status)
+			return
+		}
+
+		web.JSON(w, event, http.StatusOK)
+	}
+}
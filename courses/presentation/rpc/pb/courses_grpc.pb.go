@@ -0,0 +1,257 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: courses.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	CoursesService_CreateStudent_FullMethodName         = "/courses.v1.CoursesService/CreateStudent"
+	CoursesService_CreateCourse_FullMethodName          = "/courses.v1.CoursesService/CreateCourse"
+	CoursesService_AssignCourseToStudent_FullMethodName = "/courses.v1.CoursesService/AssignCourseToStudent"
+	CoursesService_GetStudent_FullMethodName            = "/courses.v1.CoursesService/GetStudent"
+	CoursesService_GetCourse_FullMethodName             = "/courses.v1.CoursesService/GetCourse"
+)
+
+// CoursesServiceClient is the client API for CoursesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CoursesServiceClient interface {
+	CreateStudent(ctx context.Context, in *CreateStudentRequest, opts ...grpc.CallOption) (*Student, error)
+	CreateCourse(ctx context.Context, in *CreateCourseRequest, opts ...grpc.CallOption) (*Course, error)
+	AssignCourseToStudent(ctx context.Context, in *AssignCourseToStudentRequest, opts ...grpc.CallOption) (*Student, error)
+	GetStudent(ctx context.Context, in *GetStudentRequest, opts ...grpc.CallOption) (*Student, error)
+	GetCourse(ctx context.Context, in *GetCourseRequest, opts ...grpc.CallOption) (*Course, error)
+}
+
+type coursesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCoursesServiceClient(cc grpc.ClientConnInterface) CoursesServiceClient {
+	return &coursesServiceClient{cc}
+}
+
+func (c *coursesServiceClient) CreateStudent(ctx context.Context, in *CreateStudentRequest, opts ...grpc.CallOption) (*Student, error) {
+	out := new(Student)
+	err := c.cc.Invoke(ctx, CoursesService_CreateStudent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coursesServiceClient) CreateCourse(ctx context.Context, in *CreateCourseRequest, opts ...grpc.CallOption) (*Course, error) {
+	out := new(Course)
+	err := c.cc.Invoke(ctx, CoursesService_CreateCourse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coursesServiceClient) AssignCourseToStudent(ctx context.Context, in *AssignCourseToStudentRequest, opts ...grpc.CallOption) (*Student, error) {
+	out := new(Student)
+	err := c.cc.Invoke(ctx, CoursesService_AssignCourseToStudent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coursesServiceClient) GetStudent(ctx context.Context, in *GetStudentRequest, opts ...grpc.CallOption) (*Student, error) {
+	out := new(Student)
+	err := c.cc.Invoke(ctx, CoursesService_GetStudent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coursesServiceClient) GetCourse(ctx context.Context, in *GetCourseRequest, opts ...grpc.CallOption) (*Course, error) {
+	out := new(Course)
+	err := c.cc.Invoke(ctx, CoursesService_GetCourse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CoursesServiceServer is the server API for CoursesService service.
+// All implementations must embed UnimplementedCoursesServiceServer
+// for forward compatibility
+type CoursesServiceServer interface {
+	CreateStudent(context.Context, *CreateStudentRequest) (*Student, error)
+	CreateCourse(context.Context, *CreateCourseRequest) (*Course, error)
+	AssignCourseToStudent(context.Context, *AssignCourseToStudentRequest) (*Student, error)
+	GetStudent(context.Context, *GetStudentRequest) (*Student, error)
+	GetCourse(context.Context, *GetCourseRequest) (*Course, error)
+	mustEmbedUnimplementedCoursesServiceServer()
+}
+
+// UnimplementedCoursesServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCoursesServiceServer struct {
+}
+
+func (UnimplementedCoursesServiceServer) CreateStudent(context.Context, *CreateStudentRequest) (*Student, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateStudent not implemented")
+}
+func (UnimplementedCoursesServiceServer) CreateCourse(context.Context, *CreateCourseRequest) (*Course, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCourse not implemented")
+}
+func (UnimplementedCoursesServiceServer) AssignCourseToStudent(context.Context, *AssignCourseToStudentRequest) (*Student, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignCourseToStudent not implemented")
+}
+func (UnimplementedCoursesServiceServer) GetStudent(context.Context, *GetStudentRequest) (*Student, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStudent not implemented")
+}
+func (UnimplementedCoursesServiceServer) GetCourse(context.Context, *GetCourseRequest) (*Course, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCourse not implemented")
+}
+func (UnimplementedCoursesServiceServer) mustEmbedUnimplementedCoursesServiceServer() {}
+
+// UnsafeCoursesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CoursesServiceServer will
+// result in compilation errors.
+type UnsafeCoursesServiceServer interface {
+	mustEmbedUnimplementedCoursesServiceServer()
+}
+
+func RegisterCoursesServiceServer(s grpc.ServiceRegistrar, srv CoursesServiceServer) {
+	s.RegisterService(&CoursesService_ServiceDesc, srv)
+}
+
+func _CoursesService_CreateStudent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStudentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoursesServiceServer).CreateStudent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoursesService_CreateStudent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoursesServiceServer).CreateStudent(ctx, req.(*CreateStudentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoursesService_CreateCourse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCourseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoursesServiceServer).CreateCourse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoursesService_CreateCourse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoursesServiceServer).CreateCourse(ctx, req.(*CreateCourseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoursesService_AssignCourseToStudent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignCourseToStudentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoursesServiceServer).AssignCourseToStudent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoursesService_AssignCourseToStudent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoursesServiceServer).AssignCourseToStudent(ctx, req.(*AssignCourseToStudentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoursesService_GetStudent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStudentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoursesServiceServer).GetStudent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoursesService_GetStudent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoursesServiceServer).GetStudent(ctx, req.(*GetStudentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoursesService_GetCourse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCourseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoursesServiceServer).GetCourse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoursesService_GetCourse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoursesServiceServer).GetCourse(ctx, req.(*GetCourseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CoursesService_ServiceDesc is the grpc.ServiceDesc for CoursesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CoursesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "courses.v1.CoursesService",
+	HandlerType: (*CoursesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateStudent",
+			Handler:    _CoursesService_CreateStudent_Handler,
+		},
+		{
+			MethodName: "CreateCourse",
+			Handler:    _CoursesService_CreateCourse_Handler,
+		},
+		{
+			MethodName: "AssignCourseToStudent",
+			Handler:    _CoursesService_AssignCourseToStudent_Handler,
+		},
+		{
+			MethodName: "GetStudent",
+			Handler:    _CoursesService_GetStudent_Handler,
+		},
+		{
+			MethodName: "GetCourse",
+			Handler:    _CoursesService_GetCourse_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "courses.proto",
+}
@@ -0,0 +1,295 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/presentation/interactor"
+	"github.com/MelvinKim/courses/presentation/rpc/pb"
+	"github.com/MelvinKim/courses/repository"
+	"github.com/MelvinKim/platform/interceptors"
+	"github.com/MelvinKim/platform/validation"
+)
+
+// CoursesServer serves the courses usecase over gRPC
+type CoursesServer struct {
+	pb.UnimplementedCoursesServiceServer
+	interactor *interactor.Interactor
+}
+
+// NewCoursesServer initializes a new gRPC courses server
+func NewCoursesServer(
+	i *interactor.Interactor,
+) *CoursesServer {
+	return &CoursesServer{interactor: i}
+}
+
+// NewGRPCServer sets up a gRPC server with the courses service, the standard
+// health service and the request ID, logging, metrics and auth interceptors.
+// auth is usually interceptors.AuthFromEnv's
+func NewGRPCServer(
+	i *interactor.Interactor,
+	auth grpc.UnaryServerInterceptor,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		interceptors.RequestIDInterceptor(),
+		interceptors.LoggingInterceptor(),
+		interceptors.MetricsInterceptor("courses"),
+		auth,
+	))
+	srv := grpc.NewServer(opts...)
+	pb.RegisterCoursesServiceServer(srv, NewCoursesServer(i))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.CoursesService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(srv, healthServer)
+	return srv
+}
+
+// invalidArgument converts payload validation errors to an InvalidArgument
+// status carrying a BadRequest detail per violated field
+func invalidArgument(err error) error {
+	var violations validation.ValidationErrors
+	if !errors.As(err, &violations) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Message,
+		})
+	}
+	st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(badRequest)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
+
+// usecaseError converts an error of the usecase to the status that matches
+// it: a missing record is NotFound, a domain rule the request breaks is
+// FailedPrecondition, a failed database call is Internal and anything else is
+// a request the usecase refused as invalid
+func usecaseError(action string, err error) error {
+	var missing *domain.MissingPrerequisitesError
+	var waitlisted *domain.WaitlistedError
+	var notFound *domain.NotFoundError
+	switch {
+	case errors.As(err, &missing):
+		return missingPrerequisites(missing)
+	case errors.As(err, &waitlisted):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.As(err, &notFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrSubscriptionRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrCouponRejected), errors.Is(err, domain.ErrCourseFull),
+		errors.Is(err, domain.ErrCourseNotPriced):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, repository.ErrStorage):
+		log.WithFields(log.Fields{"error": err}).Errorf("error %s", action)
+		return status.Errorf(codes.Internal, "error %s", action)
+	}
+	return status.Errorf(codes.InvalidArgument, "error %s: %v", action, err)
+}
+
+func missingPrerequisites(err *domain.MissingPrerequisitesError) error {
+	failure := &errdetails.PreconditionFailure{}
+	for _, course := range err.Missing {
+		failure.Violations = append(failure.Violations, &errdetails.PreconditionFailure_Violation{
+			Type:        "PREREQUISITE",
+			Subject:     course.UUID,
+			Description: fmt.Sprintf("course %s must be completed first", course.Title),
+		})
+	}
+	st, detailErr := status.New(codes.FailedPrecondition, err.Error()).WithDetails(failure)
+	if detailErr != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return st.Err()
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// optional turns an empty request field into a nil reference
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// value dereferences an optional field, nil becomes empty
+func value(field *string) string {
+	if field == nil {
+		return ""
+	}
+	return *field
+}
+
+func studentToProto(student *domain.Student) *pb.Student {
+	return &pb.Student{
+		Uuid:      student.UUID,
+		FirstName: student.FirstName,
+		LastName:  student.LastName,
+		Email:     student.Email,
+		CreatedAt: timestamp(student.CreatedAt),
+		UpdatedAt: timestamp(student.UpdatedAt),
+	}
+}
+
+func courseToProto(course *domain.Course) *pb.Course {
+	return &pb.Course{
+		Uuid:           course.UUID,
+		Title:          course.Title,
+		Price:          uint64(course.Price),
+		Description:    course.Description,
+		Instructor:     course.Instructor,
+		Category:       course.Category,
+		Capacity:       uint32(course.Capacity),
+		AverageRating:  course.AverageRating,
+		RatingCount:    uint32(course.RatingCount),
+		CategoryUuid:   value(course.CategoryUUID),
+		InstructorUuid: value(course.InstructorUUID),
+		CreatedAt:      timestamp(course.CreatedAt),
+		UpdatedAt:      timestamp(course.UpdatedAt),
+	}
+}
+
+// CreateStudent creates a new sudocode student
+func (s *CoursesServer) CreateStudent(
+	ctx context.Context,
+	req *pb.CreateStudentRequest,
+) (*pb.Student, error) {
+	payload := &dto.StudentCreationPayload{
+		FirstName: req.GetFirstName(),
+		LastName:  req.GetLastName(),
+		Email:     req.GetEmail(),
+	}
+	if err := validation.Validate(payload); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	student := domain.Student{
+		FirstName: payload.FirstName,
+		LastName:  payload.LastName,
+		Email:     payload.Email,
+	}
+	createdStudent, err := s.interactor.Courses.CreateStudent(ctx, &student)
+	if err != nil {
+		return nil, usecaseError("creating student", err)
+	}
+	return studentToProto(createdStudent), nil
+}
+
+// CreateCourse creates a new sudocode course
+func (s *CoursesServer) CreateCourse(
+	ctx context.Context,
+	req *pb.CreateCourseRequest,
+) (*pb.Course, error) {
+	payload := &dto.CourseCreationPayload{
+		Title:          req.GetTitle(),
+		Price:          uint(req.GetPrice()),
+		Description:    req.GetDescription(),
+		Instructor:     req.GetInstructor(),
+		InstructorUUID: req.GetInstructorUuid(),
+		Category:       req.GetCategory(),
+		CategoryUUID:   req.GetCategoryUuid(),
+		Capacity:       uint(req.GetCapacity()),
+	}
+	if err := validation.Validate(payload); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	course := domain.Course{
+		Title:          payload.Title,
+		Price:          payload.Price,
+		Description:    payload.Description,
+		Instructor:     payload.Instructor,
+		InstructorUUID: optional(payload.InstructorUUID),
+		Category:       payload.Category,
+		CategoryUUID:   optional(payload.CategoryUUID),
+		Capacity:       payload.Capacity,
+	}
+	createdCourse, err := s.interactor.Courses.CreateCourse(ctx, &course)
+	if err != nil {
+		return nil, usecaseError("creating course", err)
+	}
+	return courseToProto(createdCourse), nil
+}
+
+// AssignCourseToStudent assigns a student a course
+func (s *CoursesServer) AssignCourseToStudent(
+	ctx context.Context,
+	req *pb.AssignCourseToStudentRequest,
+) (*pb.Student, error) {
+	payload := &dto.StudentCourseAssigningPayload{
+		Email:       req.GetEmail(),
+		CourseTitle: req.GetCourseTitle(),
+		Override:    req.GetOverride(),
+		CouponCode:  req.GetCouponCode(),
+	}
+	if err := validation.Validate(payload); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	student, err := s.interactor.Courses.AssignCourseToStudent(ctx, &payload.Email, &payload.CourseTitle, req.GetOverride(), payload.CouponCode)
+	if err != nil {
+		return nil, usecaseError("assigning course to student", err)
+	}
+	return studentToProto(student), nil
+}
+
+// GetStudent gets a student by their email address
+func (s *CoursesServer) GetStudent(
+	ctx context.Context,
+	req *pb.GetStudentRequest,
+) (*pb.Student, error) {
+	email := req.GetEmail()
+	student, err := s.interactor.Courses.GetStudent(ctx, &email)
+	if err != nil {
+		return nil, usecaseError("getting student", err)
+	}
+	if student == nil {
+		return nil, status.Errorf(codes.NotFound, "student with email %s not found", email)
+	}
+	return studentToProto(student), nil
+}
+
+// GetCourse gets a course by its title
+func (s *CoursesServer) GetCourse(
+	ctx context.Context,
+	req *pb.GetCourseRequest,
+) (*pb.Course, error) {
+	title := req.GetTitle()
+	course, err := s.interactor.Courses.GetCourse(ctx, &title)
+	if err != nil {
+		return nil, usecaseError("getting course", err)
+	}
+	if course == nil {
+		return nil, status.Errorf(codes.NotFound, "course %s not found", title)
+	}
+	return courseToProto(course), nil
+}
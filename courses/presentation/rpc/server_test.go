@@ -0,0 +1,218 @@
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	paymentsmock "github.com/MelvinKim/courses/infrastructure/payments/mock"
+	"github.com/MelvinKim/courses/infrastructure/search"
+	"github.com/MelvinKim/courses/presentation/interactor"
+	"github.com/MelvinKim/courses/presentation/rpc"
+	"github.com/MelvinKim/courses/presentation/rpc/pb"
+	"github.com/MelvinKim/courses/repository"
+	"github.com/MelvinKim/courses/repository/mock"
+	"github.com/MelvinKim/courses/usecase"
+	"github.com/MelvinKim/platform/interceptors"
+	"github.com/brianvoe/gofakeit/v6"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testAuthToken = "test-token"
+	// brokenEmail is the email of a student the database fails to get
+	brokenEmail = "broken@example.com"
+)
+
+var listener *bufconn.Listener
+
+func TestMain(m *testing.M) {
+	// setup
+	mockCreate := mock.NewMockCreateRepository()
+	mockGet := mock.NewMockGetRepository()
+	mockCreate.MockCreateStudent = func(ctx context.Context, student *domain.Student) (*domain.Student, error) {
+		student.UUID = gofakeit.UUID()
+		return student, nil
+	}
+	mockGet.MockGetCourse = func(ctx context.Context, title *string) (*domain.Course, error) {
+		return nil, nil
+	}
+	mockGet.MockGetStudent = func(ctx context.Context, email *string) (*domain.Student, error) {
+		if *email == brokenEmail {
+			return nil, fmt.Errorf("%w: can't get student by email: connection refused", repository.ErrStorage)
+		}
+		return nil, nil
+	}
+	signer, err := certificates.NewEd25519Signer(make([]byte, 32))
+	if err != nil {
+		log.Fatalf("unable to create test signer: %s", err)
+	}
+	i, err := interactor.NewUsersInteractor(usecase.NewUsecase(mockCreate, mockGet, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), signer, search.NewMemoryIndex(), fx.NewStaticRates(), paymentsmock.NewMockProvider()))
+	if err != nil {
+		log.Fatalf("unable to create test interactor: %s", err)
+	}
+
+	listener = bufconn.Listen(1024 * 1024)
+	srv := rpc.NewGRPCServer(i, interceptors.AuthInterceptor(testAuthToken))
+	go func() {
+		if err := srv.Serve(listener); err != nil {
+			log.Printf("serve error: %s", err)
+		}
+	}()
+
+	// run the tests
+	code := m.Run()
+
+	// cleanup here
+	srv.Stop()
+	os.Exit(code)
+}
+
+func dial(ctx context.Context, t *testing.T) *grpc.ClientConn {
+	conn, err := grpc.DialContext(
+		ctx,
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func authenticated(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testAuthToken)
+}
+
+func TestCoursesServer_CreateStudent(t *testing.T) {
+	ctx := context.Background()
+	client := pb.NewCoursesServiceClient(dial(ctx, t))
+
+	type args struct {
+		ctx context.Context
+		req *pb.CreateStudentRequest
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantCode codes.Code
+	}{
+		{
+			name: "Happy case",
+			args: args{
+				ctx: authenticated(ctx),
+				req: &pb.CreateStudentRequest{
+					FirstName: gofakeit.FirstName(),
+					LastName:  gofakeit.LastName(),
+					Email:     gofakeit.Email(),
+				},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Sad case - invalid payload",
+			args: args{
+				ctx: authenticated(ctx),
+				req: &pb.CreateStudentRequest{
+					Email: "not-an-email",
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Sad case - missing token",
+			args: args{
+				ctx: ctx,
+				req: &pb.CreateStudentRequest{
+					FirstName: gofakeit.FirstName(),
+					LastName:  gofakeit.LastName(),
+					Email:     gofakeit.Email(),
+				},
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+			student, err := client.CreateStudent(tt.args.ctx, tt.args.req, grpc.Header(&header))
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CoursesServer.CreateStudent() code = %v, want %v (err: %v)", status.Code(err), tt.wantCode, err)
+			}
+			if len(header.Get(interceptors.RequestIDHeader)) == 0 {
+				t.Errorf("expected a %s response header", interceptors.RequestIDHeader)
+			}
+			if tt.wantCode == codes.OK && student.GetUuid() == "" {
+				t.Errorf("expected student to have a valid UUID")
+			}
+		})
+	}
+}
+
+func TestCoursesServer_GetCourse(t *testing.T) {
+	ctx := authenticated(context.Background())
+	client := pb.NewCoursesServiceClient(dial(ctx, t))
+
+	_, err := client.GetCourse(ctx, &pb.GetCourseRequest{Title: gofakeit.LastName()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("CoursesServer.GetCourse() code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
+func TestCoursesServer_AssignCourseToStudent(t *testing.T) {
+	ctx := authenticated(context.Background())
+	client := pb.NewCoursesServiceClient(dial(ctx, t))
+
+	tests := []struct {
+		name     string
+		email    string
+		wantCode codes.Code
+	}{
+		{name: "Sad case - student does not exist", email: gofakeit.Email(), wantCode: codes.NotFound},
+		{name: "Sad case - database failure", email: brokenEmail, wantCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.AssignCourseToStudent(ctx, &pb.AssignCourseToStudentRequest{Email: tt.email, CourseTitle: gofakeit.LastName()})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("CoursesServer.AssignCourseToStudent() code = %v, want %v (err: %v)", status.Code(err), tt.wantCode, err)
+			}
+			if tt.wantCode == codes.Internal && strings.Contains(status.Convert(err).Message(), "connection refused") {
+				t.Errorf("expected database errors not to be passed on to callers")
+			}
+		})
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	client := grpc_health_v1.NewHealthClient(dial(ctx, t))
+
+	resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: pb.CoursesService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		t.Fatalf("Health.Check() error = %v", err)
+	}
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v", resp.GetStatus())
+	}
+}
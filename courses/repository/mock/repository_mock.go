@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/MelvinKim/courses/domain"
 )
@@ -20,7 +21,109 @@ type MockCreateRepository struct {
 		ctx context.Context,
 		email *string,
 		courseTitle *string,
+		runUUID *string,
+		redemption *domain.CouponRedemption,
 	) (*domain.Student, error)
+	MockCreateModule func(
+		ctx context.Context,
+		module *domain.Module,
+	) (*domain.Module, error)
+	MockCreateLesson func(
+		ctx context.Context,
+		lesson *domain.Lesson,
+	) (*domain.Lesson, error)
+	MockCreateQuiz func(
+		ctx context.Context,
+		quiz *domain.Quiz,
+	) (*domain.Quiz, error)
+	MockCreateAttempt func(
+		ctx context.Context,
+		attempt *domain.Attempt,
+		courseUUID string,
+		maxAttempts uint,
+	) (*domain.Attempt, error)
+	MockCreateCertificate func(
+		ctx context.Context,
+		certificate *domain.Certificate,
+	) (*domain.Certificate, error)
+	MockAddCoursePrerequisite func(
+		ctx context.Context,
+		prerequisite *domain.CoursePrerequisite,
+	) error
+	MockJoinWaitlist func(
+		ctx context.Context,
+		entry *domain.WaitlistEntry,
+	) (*domain.WaitlistEntry, error)
+	MockCreateCourseRun func(
+		ctx context.Context,
+		run *domain.CourseRun,
+	) (*domain.CourseRun, error)
+	MockCreateLiveSession func(
+		ctx context.Context,
+		session *domain.LiveSession,
+	) (*domain.LiveSession, error)
+	MockCreateCategory func(
+		ctx context.Context,
+		category *domain.Category,
+	) (*domain.Category, error)
+	MockCreateInstructor func(
+		ctx context.Context,
+		instructor *domain.Instructor,
+	) (*domain.Instructor, error)
+	MockCreateCoursePrice func(
+		ctx context.Context,
+		price *domain.CoursePrice,
+	) (*domain.CoursePrice, error)
+	MockCreateCoupon func(
+		ctx context.Context,
+		coupon *domain.Coupon,
+	) (*domain.Coupon, error)
+	MockCreatePlan func(
+		ctx context.Context,
+		plan *domain.Plan,
+	) (*domain.Plan, error)
+	MockCreateSubscription func(
+		ctx context.Context,
+		subscription *domain.Subscription,
+		charge *domain.SubscriptionCharge,
+	) (*domain.Subscription, error)
+	MockCreateSubscriptionCharge func(
+		ctx context.Context,
+		charge *domain.SubscriptionCharge,
+	) (*domain.SubscriptionCharge, error)
+	MockCreateInvoice func(
+		ctx context.Context,
+		invoice *domain.Invoice,
+	) (*domain.Invoice, error)
+	MockSetTaxRate func(
+		ctx context.Context,
+		rate *domain.TaxRate,
+	) (*domain.TaxRate, error)
+	MockCreateRefund func(
+		ctx context.Context,
+		refund *domain.Refund,
+	) (*domain.Refund, error)
+	MockRecordWebhookEvent func(
+		ctx context.Context,
+		event *domain.WebhookEvent,
+	) (*domain.WebhookEvent, error)
+	MockCreateRevenueShare func(
+		ctx context.Context,
+		share *domain.RevenueShare,
+	) (*domain.RevenueShare, error)
+	MockCreatePayoutStatement func(
+		ctx context.Context,
+		statement *domain.PayoutStatement,
+	) (*domain.PayoutStatement, error)
+	MockCreateImport func(
+		ctx context.Context,
+		imp *domain.Import,
+		rows []*domain.ImportRow,
+	) (*domain.Import, error)
+	MockPromoteWaitlistEntry func(
+		ctx context.Context,
+		entry *domain.WaitlistEntry,
+	) error
 }
 
 // NewMockCreateRepository initializes a new MockCreateRepository
@@ -32,9 +135,81 @@ func NewMockCreateRepository() *MockCreateRepository {
 		MockCreateCourse: func(ctx context.Context, course *domain.Course) (*domain.Course, error) {
 			return &domain.Course{}, nil
 		},
-		MockAssignCourseToStudent: func(ctx context.Context, email, courseTitle *string) (*domain.Student, error) {
+		MockAssignCourseToStudent: func(ctx context.Context, email, courseTitle, runUUID *string, redemption *domain.CouponRedemption) (*domain.Student, error) {
 			return &domain.Student{}, nil
 		},
+		MockCreateModule: func(ctx context.Context, module *domain.Module) (*domain.Module, error) {
+			return module, nil
+		},
+		MockCreateLesson: func(ctx context.Context, lesson *domain.Lesson) (*domain.Lesson, error) {
+			return lesson, nil
+		},
+		MockCreateQuiz: func(ctx context.Context, quiz *domain.Quiz) (*domain.Quiz, error) {
+			return quiz, nil
+		},
+		MockCreateAttempt: func(ctx context.Context, attempt *domain.Attempt, courseUUID string, maxAttempts uint) (*domain.Attempt, error) {
+			return attempt, nil
+		},
+		MockCreateCertificate: func(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error) {
+			return certificate, nil
+		},
+		MockAddCoursePrerequisite: func(ctx context.Context, prerequisite *domain.CoursePrerequisite) error {
+			return nil
+		},
+		MockJoinWaitlist: func(ctx context.Context, entry *domain.WaitlistEntry) (*domain.WaitlistEntry, error) {
+			return entry, nil
+		},
+		MockCreateCourseRun: func(ctx context.Context, run *domain.CourseRun) (*domain.CourseRun, error) {
+			return run, nil
+		},
+		MockCreateLiveSession: func(ctx context.Context, session *domain.LiveSession) (*domain.LiveSession, error) {
+			return session, nil
+		},
+		MockCreateCategory: func(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+			return category, nil
+		},
+		MockCreateInstructor: func(ctx context.Context, instructor *domain.Instructor) (*domain.Instructor, error) {
+			return instructor, nil
+		},
+		MockCreateCoursePrice: func(ctx context.Context, price *domain.CoursePrice) (*domain.CoursePrice, error) {
+			return price, nil
+		},
+		MockCreateCoupon: func(ctx context.Context, coupon *domain.Coupon) (*domain.Coupon, error) {
+			return coupon, nil
+		},
+		MockCreatePlan: func(ctx context.Context, plan *domain.Plan) (*domain.Plan, error) {
+			return plan, nil
+		},
+		MockCreateSubscription: func(ctx context.Context, subscription *domain.Subscription, charge *domain.SubscriptionCharge) (*domain.Subscription, error) {
+			return subscription, nil
+		},
+		MockCreateSubscriptionCharge: func(ctx context.Context, charge *domain.SubscriptionCharge) (*domain.SubscriptionCharge, error) {
+			return charge, nil
+		},
+		MockCreateInvoice: func(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+			return invoice, nil
+		},
+		MockSetTaxRate: func(ctx context.Context, rate *domain.TaxRate) (*domain.TaxRate, error) {
+			return rate, nil
+		},
+		MockCreateRefund: func(ctx context.Context, refund *domain.Refund) (*domain.Refund, error) {
+			return refund, nil
+		},
+		MockRecordWebhookEvent: func(ctx context.Context, event *domain.WebhookEvent) (*domain.WebhookEvent, error) {
+			return event, nil
+		},
+		MockCreateRevenueShare: func(ctx context.Context, share *domain.RevenueShare) (*domain.RevenueShare, error) {
+			return share, nil
+		},
+		MockCreatePayoutStatement: func(ctx context.Context, statement *domain.PayoutStatement) (*domain.PayoutStatement, error) {
+			return statement, nil
+		},
+		MockCreateImport: func(ctx context.Context, imp *domain.Import, rows []*domain.ImportRow) (*domain.Import, error) {
+			return imp, nil
+		},
+		MockPromoteWaitlistEntry: func(ctx context.Context, entry *domain.WaitlistEntry) error {
+			return nil
+		},
 	}
 }
 
@@ -59,8 +234,206 @@ func (c *MockCreateRepository) AssignCourseToStudent(
 	ctx context.Context,
 	email *string,
 	courseTitle *string,
+	runUUID *string,
+	redemption *domain.CouponRedemption,
 ) (*domain.Student, error) {
-	return c.MockAssignCourseToStudent(ctx, email, courseTitle)
+	return c.MockAssignCourseToStudent(ctx, email, courseTitle, runUUID, redemption)
+}
+
+// CreateModule mocks CreateModule
+func (c *MockCreateRepository) CreateModule(
+	ctx context.Context,
+	module *domain.Module,
+) (*domain.Module, error) {
+	return c.MockCreateModule(ctx, module)
+}
+
+// CreateLesson mocks CreateLesson
+func (c *MockCreateRepository) CreateLesson(
+	ctx context.Context,
+	lesson *domain.Lesson,
+) (*domain.Lesson, error) {
+	return c.MockCreateLesson(ctx, lesson)
+}
+
+// CreateQuiz mocks CreateQuiz
+func (c *MockCreateRepository) CreateQuiz(
+	ctx context.Context,
+	quiz *domain.Quiz,
+) (*domain.Quiz, error) {
+	return c.MockCreateQuiz(ctx, quiz)
+}
+
+// CreateAttempt mocks CreateAttempt
+func (c *MockCreateRepository) CreateAttempt(
+	ctx context.Context,
+	attempt *domain.Attempt,
+	courseUUID string,
+	maxAttempts uint,
+) (*domain.Attempt, error) {
+	return c.MockCreateAttempt(ctx, attempt, courseUUID, maxAttempts)
+}
+
+// CreateCertificate mocks CreateCertificate
+func (c *MockCreateRepository) CreateCertificate(
+	ctx context.Context,
+	certificate *domain.Certificate,
+) (*domain.Certificate, error) {
+	return c.MockCreateCertificate(ctx, certificate)
+}
+
+// AddCoursePrerequisite mocks AddCoursePrerequisite
+func (c *MockCreateRepository) AddCoursePrerequisite(
+	ctx context.Context,
+	prerequisite *domain.CoursePrerequisite,
+) error {
+	return c.MockAddCoursePrerequisite(ctx, prerequisite)
+}
+
+// JoinWaitlist mocks JoinWaitlist
+func (c *MockCreateRepository) JoinWaitlist(
+	ctx context.Context,
+	entry *domain.WaitlistEntry,
+) (*domain.WaitlistEntry, error) {
+	return c.MockJoinWaitlist(ctx, entry)
+}
+
+// CreateCourseRun mocks CreateCourseRun
+func (c *MockCreateRepository) CreateCourseRun(
+	ctx context.Context,
+	run *domain.CourseRun,
+) (*domain.CourseRun, error) {
+	return c.MockCreateCourseRun(ctx, run)
+}
+
+// CreateLiveSession mocks CreateLiveSession
+func (c *MockCreateRepository) CreateLiveSession(
+	ctx context.Context,
+	session *domain.LiveSession,
+) (*domain.LiveSession, error) {
+	return c.MockCreateLiveSession(ctx, session)
+}
+
+// CreateCategory mocks CreateCategory
+func (c *MockCreateRepository) CreateCategory(
+	ctx context.Context,
+	category *domain.Category,
+) (*domain.Category, error) {
+	return c.MockCreateCategory(ctx, category)
+}
+
+// CreateInstructor mocks CreateInstructor
+func (c *MockCreateRepository) CreateInstructor(
+	ctx context.Context,
+	instructor *domain.Instructor,
+) (*domain.Instructor, error) {
+	return c.MockCreateInstructor(ctx, instructor)
+}
+
+// CreateCoursePrice mocks CreateCoursePrice
+func (c *MockCreateRepository) CreateCoursePrice(
+	ctx context.Context,
+	price *domain.CoursePrice,
+) (*domain.CoursePrice, error) {
+	return c.MockCreateCoursePrice(ctx, price)
+}
+
+// CreateCoupon mocks CreateCoupon
+func (c *MockCreateRepository) CreateCoupon(
+	ctx context.Context,
+	coupon *domain.Coupon,
+) (*domain.Coupon, error) {
+	return c.MockCreateCoupon(ctx, coupon)
+}
+
+// CreatePlan mocks CreatePlan
+func (c *MockCreateRepository) CreatePlan(
+	ctx context.Context,
+	plan *domain.Plan,
+) (*domain.Plan, error) {
+	return c.MockCreatePlan(ctx, plan)
+}
+
+// CreateSubscription mocks CreateSubscription
+func (c *MockCreateRepository) CreateSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	charge *domain.SubscriptionCharge,
+) (*domain.Subscription, error) {
+	return c.MockCreateSubscription(ctx, subscription, charge)
+}
+
+// CreateSubscriptionCharge mocks CreateSubscriptionCharge
+func (c *MockCreateRepository) CreateSubscriptionCharge(
+	ctx context.Context,
+	charge *domain.SubscriptionCharge,
+) (*domain.SubscriptionCharge, error) {
+	return c.MockCreateSubscriptionCharge(ctx, charge)
+}
+
+// CreateInvoice mocks CreateInvoice
+func (c *MockCreateRepository) CreateInvoice(
+	ctx context.Context,
+	invoice *domain.Invoice,
+) (*domain.Invoice, error) {
+	return c.MockCreateInvoice(ctx, invoice)
+}
+
+// SetTaxRate mocks SetTaxRate
+func (c *MockCreateRepository) SetTaxRate(
+	ctx context.Context,
+	rate *domain.TaxRate,
+) (*domain.TaxRate, error) {
+	return c.MockSetTaxRate(ctx, rate)
+}
+
+// CreateRefund mocks CreateRefund
+func (c *MockCreateRepository) CreateRefund(
+	ctx context.Context,
+	refund *domain.Refund,
+) (*domain.Refund, error) {
+	return c.MockCreateRefund(ctx, refund)
+}
+
+// RecordWebhookEvent mocks RecordWebhookEvent
+func (c *MockCreateRepository) RecordWebhookEvent(
+	ctx context.Context,
+	event *domain.WebhookEvent,
+) (*domain.WebhookEvent, error) {
+	return c.MockRecordWebhookEvent(ctx, event)
+}
+
+// CreateRevenueShare mocks CreateRevenueShare
+func (c *MockCreateRepository) CreateRevenueShare(
+	ctx context.Context,
+	share *domain.RevenueShare,
+) (*domain.RevenueShare, error) {
+	return c.MockCreateRevenueShare(ctx, share)
+}
+
+// CreatePayoutStatement mocks CreatePayoutStatement
+func (c *MockCreateRepository) CreatePayoutStatement(
+	ctx context.Context,
+	statement *domain.PayoutStatement,
+) (*domain.PayoutStatement, error) {
+	return c.MockCreatePayoutStatement(ctx, statement)
+}
+
+// CreateImport mocks CreateImport
+func (c *MockCreateRepository) CreateImport(
+	ctx context.Context,
+	imp *domain.Import,
+	rows []*domain.ImportRow,
+) (*domain.Import, error) {
+	return c.MockCreateImport(ctx, imp, rows)
+}
+
+// PromoteWaitlistEntry mocks PromoteWaitlistEntry
+func (c *MockCreateRepository) PromoteWaitlistEntry(
+	ctx context.Context,
+	entry *domain.WaitlistEntry,
+) error {
+	return c.MockPromoteWaitlistEntry(ctx, entry)
 }
 
 // MockGetRepository mocks the database get repository
@@ -73,6 +446,274 @@ type MockGetRepository struct {
 		ctx context.Context,
 		title *string,
 	) (*domain.Course, error)
+	MockGetCourseCurriculum func(
+		ctx context.Context,
+		courseUUID *string,
+	) (*domain.Course, error)
+	MockGetModule func(
+		ctx context.Context,
+		moduleUUID *string,
+	) (*domain.Module, error)
+	MockGetLesson func(
+		ctx context.Context,
+		lessonUUID *string,
+	) (*domain.Lesson, error)
+	MockGetEnrollment func(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) (*domain.StudentCourse, error)
+	MockGetStudentEnrollments func(
+		ctx context.Context,
+		studentUUID *string,
+	) ([]*domain.Enrollment, error)
+	MockGetLessonProgress func(
+		ctx context.Context,
+		studentUUID *string,
+		lessonUUID *string,
+	) (*domain.LessonProgress, error)
+	MockGetCourseCompletion func(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) (int64, int64, error)
+	MockGetQuiz func(
+		ctx context.Context,
+		quizUUID *string,
+	) (*domain.Quiz, error)
+	MockGetStudentByUUID func(
+		ctx context.Context,
+		studentUUID *string,
+	) (*domain.Student, error)
+	MockGetCourseByUUID func(
+		ctx context.Context,
+		courseUUID *string,
+	) (*domain.Course, error)
+	MockGetCertificate func(
+		ctx context.Context,
+		serial *string,
+	) (*domain.Certificate, error)
+	MockGetStudentCertificate func(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) (*domain.Certificate, error)
+	MockGetCoursePrerequisites func(
+		ctx context.Context,
+		courseUUID *string,
+	) ([]*domain.Course, error)
+	MockGetWaitlist func(
+		ctx context.Context,
+		courseUUID *string,
+	) ([]*domain.WaitlistEntry, error)
+	MockGetWaitlistEntry func(
+		ctx context.Context,
+		courseUUID *string,
+		studentUUID *string,
+	) (*domain.WaitlistEntry, error)
+	MockGetCourseRun func(
+		ctx context.Context,
+		runUUID *string,
+	) (*domain.CourseRun, error)
+	MockGetUpcomingCourseRuns func(
+		ctx context.Context,
+		courseUUID *string,
+		after time.Time,
+	) ([]*domain.CourseRun, error)
+	MockGetCalendarSubscription func(
+		ctx context.Context,
+		studentUUID *string,
+	) (*domain.CalendarSubscription, error)
+	MockListCourses func(
+		ctx context.Context,
+		sort domain.CourseSort,
+	) ([]*domain.Course, error)
+	MockGetReview func(
+		ctx context.Context,
+		reviewUUID *string,
+	) (*domain.Review, error)
+	MockGetCourseReviews func(
+		ctx context.Context,
+		courseUUID *string,
+		status domain.ReviewStatus,
+	) ([]*domain.Review, error)
+	MockGetCategory func(
+		ctx context.Context,
+		categoryUUID *string,
+	) (*domain.Category, error)
+	MockGetCategoryBySlug func(
+		ctx context.Context,
+		slug string,
+	) (*domain.Category, error)
+	MockListCategories func(
+		ctx context.Context,
+	) ([]*domain.Category, error)
+	MockGetInstructor func(
+		ctx context.Context,
+		instructorUUID *string,
+	) (*domain.Instructor, error)
+	MockFindInstructorByName func(
+		ctx context.Context,
+		name string,
+	) (*domain.Instructor, error)
+	MockListInstructors func(
+		ctx context.Context,
+	) ([]*domain.Instructor, error)
+	MockGetCurrentCoursePrices func(
+		ctx context.Context,
+		courseUUID *string,
+		at time.Time,
+	) ([]*domain.CoursePrice, error)
+	MockGetCoursePriceHistory func(
+		ctx context.Context,
+		courseUUID *string,
+		currency string,
+	) ([]*domain.CoursePrice, error)
+	MockGetCoupon func(
+		ctx context.Context,
+		couponUUID *string,
+	) (*domain.Coupon, error)
+	MockGetCouponByCode func(
+		ctx context.Context,
+		code string,
+	) (*domain.Coupon, error)
+	MockListCoupons func(
+		ctx context.Context,
+	) ([]*domain.Coupon, error)
+	MockCountCouponRedemptions func(
+		ctx context.Context,
+		couponUUID *string,
+		studentUUID *string,
+	) (int64, error)
+	MockGetCouponUsage func(
+		ctx context.Context,
+	) ([]*domain.CouponUsage, error)
+	MockGetPlan func(
+		ctx context.Context,
+		planUUID *string,
+	) (*domain.Plan, error)
+	MockListPlans func(
+		ctx context.Context,
+	) ([]*domain.Plan, error)
+	MockGetSubscription func(
+		ctx context.Context,
+		subscriptionUUID *string,
+	) (*domain.Subscription, error)
+	MockGetStudentSubscription func(
+		ctx context.Context,
+		studentUUID *string,
+	) (*domain.Subscription, error)
+	MockGetSubscriptionCharges func(
+		ctx context.Context,
+		subscriptionUUID *string,
+	) ([]*domain.SubscriptionCharge, error)
+	MockGetInvoice func(
+		ctx context.Context,
+		invoiceUUID *string,
+	) (*domain.Invoice, error)
+	MockListStudentInvoices func(
+		ctx context.Context,
+		studentUUID *string,
+	) ([]*domain.Invoice, error)
+	MockGetTaxRate func(
+		ctx context.Context,
+		currency string,
+	) (*domain.TaxRate, error)
+	MockListTaxRates func(
+		ctx context.Context,
+	) ([]*domain.TaxRate, error)
+	MockGetSubscriptionCharge func(
+		ctx context.Context,
+		chargeUUID *string,
+	) (*domain.SubscriptionCharge, error)
+	MockGetChargeRefunds func(
+		ctx context.Context,
+		chargeUUID *string,
+	) ([]*domain.Refund, error)
+	MockGetLedgerBalances func(
+		ctx context.Context,
+	) ([]*domain.LedgerBalance, error)
+	MockGetWebhookEvent func(
+		ctx context.Context,
+		providerEventID string,
+	) (*domain.WebhookEvent, error)
+	MockGetRefundByReference func(
+		ctx context.Context,
+		reference string,
+	) (*domain.Refund, error)
+	MockGetSubscriptionChargeByReference func(
+		ctx context.Context,
+		reference string,
+	) (*domain.SubscriptionCharge, error)
+	MockListLedgerEntries func(
+		ctx context.Context,
+		query domain.LedgerQuery,
+	) ([]*domain.LedgerEntry, error)
+	MockGetRevenueReport func(
+		ctx context.Context,
+		query domain.RevenueQuery,
+	) ([]*domain.RevenueReportRow, error)
+	MockListRevenueShares func(
+		ctx context.Context,
+		instructorUUID *string,
+	) ([]*domain.RevenueShare, error)
+	MockListUnpaidSales func(
+		ctx context.Context,
+		from time.Time,
+		to time.Time,
+	) ([]*domain.PayoutLine, error)
+	MockGetPayoutStatement func(
+		ctx context.Context,
+		statementUUID *string,
+	) (*domain.PayoutStatement, error)
+	MockListPayoutStatements func(
+		ctx context.Context,
+		instructorUUID string,
+		month string,
+	) ([]*domain.PayoutStatement, error)
+	MockListStudents func(
+		ctx context.Context,
+		query *domain.StudentQuery,
+	) ([]*domain.Student, error)
+	MockListEnrollments func(
+		ctx context.Context,
+		query *domain.EnrollmentQuery,
+	) ([]*domain.StudentCourse, error)
+	MockGetImport func(
+		ctx context.Context,
+		importUUID *string,
+	) (*domain.Import, error)
+	MockGetImportRows func(
+		ctx context.Context,
+		importUUID *string,
+		status domain.ImportRowStatus,
+		limit int,
+	) ([]*domain.ImportRow, error)
+	MockPageCourses func(
+		ctx context.Context,
+		query *domain.CourseQuery,
+	) ([]*domain.Course, error)
+	MockGetSubscriptionChargeByKey func(
+		ctx context.Context,
+		key string,
+	) (*domain.SubscriptionCharge, error)
+	MockGetPendingSubscriptionCharge func(
+		ctx context.Context,
+		subscriptionUUID *string,
+	) (*domain.SubscriptionCharge, error)
+	MockGetRevenueReconciliation func(
+		ctx context.Context,
+		from *time.Time,
+		to *time.Time,
+	) ([]*domain.RevenueReconciliation, error)
+	MockListUnsentRefunds func(
+		ctx context.Context,
+		createdBefore time.Time,
+	) ([]*domain.Refund, error)
+	MockListUninvoicedCharges func(
+		ctx context.Context,
+		settledBefore time.Time,
+	) ([]*domain.SubscriptionCharge, error)
 }
 
 // NewMockGetRepository initializes a new MockGetRepository
@@ -84,6 +725,201 @@ func NewMockGetRepository() *MockGetRepository {
 		MockGetCourse: func(ctx context.Context, title *string) (*domain.Course, error) {
 			return &domain.Course{}, nil
 		},
+		MockGetCourseCurriculum: func(ctx context.Context, courseUUID *string) (*domain.Course, error) {
+			return &domain.Course{}, nil
+		},
+		MockGetModule: func(ctx context.Context, moduleUUID *string) (*domain.Module, error) {
+			return &domain.Module{}, nil
+		},
+		MockGetLesson: func(ctx context.Context, lessonUUID *string) (*domain.Lesson, error) {
+			return &domain.Lesson{}, nil
+		},
+		MockGetEnrollment: func(ctx context.Context, studentUUID, courseUUID *string) (*domain.StudentCourse, error) {
+			return &domain.StudentCourse{}, nil
+		},
+		MockGetStudentEnrollments: func(ctx context.Context, studentUUID *string) ([]*domain.Enrollment, error) {
+			return []*domain.Enrollment{}, nil
+		},
+		MockGetLessonProgress: func(ctx context.Context, studentUUID, lessonUUID *string) (*domain.LessonProgress, error) {
+			return nil, nil
+		},
+		MockGetCourseCompletion: func(ctx context.Context, studentUUID, courseUUID *string) (int64, int64, error) {
+			return 0, 0, nil
+		},
+		MockGetQuiz: func(ctx context.Context, quizUUID *string) (*domain.Quiz, error) {
+			return &domain.Quiz{}, nil
+		},
+		MockGetStudentByUUID: func(ctx context.Context, studentUUID *string) (*domain.Student, error) {
+			return &domain.Student{}, nil
+		},
+		MockGetCourseByUUID: func(ctx context.Context, courseUUID *string) (*domain.Course, error) {
+			return &domain.Course{}, nil
+		},
+		MockGetCertificate: func(ctx context.Context, serial *string) (*domain.Certificate, error) {
+			return nil, nil
+		},
+		MockGetStudentCertificate: func(ctx context.Context, studentUUID, courseUUID *string) (*domain.Certificate, error) {
+			return nil, nil
+		},
+		MockGetCoursePrerequisites: func(ctx context.Context, courseUUID *string) ([]*domain.Course, error) {
+			return nil, nil
+		},
+		MockGetWaitlist: func(ctx context.Context, courseUUID *string) ([]*domain.WaitlistEntry, error) {
+			return nil, nil
+		},
+		MockGetWaitlistEntry: func(ctx context.Context, courseUUID, studentUUID *string) (*domain.WaitlistEntry, error) {
+			return nil, nil
+		},
+		MockGetCourseRun: func(ctx context.Context, runUUID *string) (*domain.CourseRun, error) {
+			return nil, nil
+		},
+		MockGetUpcomingCourseRuns: func(ctx context.Context, courseUUID *string, after time.Time) ([]*domain.CourseRun, error) {
+			return nil, nil
+		},
+		MockGetCalendarSubscription: func(ctx context.Context, studentUUID *string) (*domain.CalendarSubscription, error) {
+			return nil, nil
+		},
+		MockListCourses: func(ctx context.Context, sort domain.CourseSort) ([]*domain.Course, error) {
+			return []*domain.Course{}, nil
+		},
+		MockGetReview: func(ctx context.Context, reviewUUID *string) (*domain.Review, error) {
+			return nil, nil
+		},
+		MockGetCourseReviews: func(ctx context.Context, courseUUID *string, status domain.ReviewStatus) ([]*domain.Review, error) {
+			return []*domain.Review{}, nil
+		},
+		MockGetCategory: func(ctx context.Context, categoryUUID *string) (*domain.Category, error) {
+			return nil, nil
+		},
+		MockGetCategoryBySlug: func(ctx context.Context, slug string) (*domain.Category, error) {
+			return nil, nil
+		},
+		MockListCategories: func(ctx context.Context) ([]*domain.Category, error) {
+			return []*domain.Category{}, nil
+		},
+		MockGetInstructor: func(ctx context.Context, instructorUUID *string) (*domain.Instructor, error) {
+			return nil, nil
+		},
+		MockFindInstructorByName: func(ctx context.Context, name string) (*domain.Instructor, error) {
+			return nil, nil
+		},
+		MockListInstructors: func(ctx context.Context) ([]*domain.Instructor, error) {
+			return []*domain.Instructor{}, nil
+		},
+		MockGetCurrentCoursePrices: func(ctx context.Context, courseUUID *string, at time.Time) ([]*domain.CoursePrice, error) {
+			return []*domain.CoursePrice{}, nil
+		},
+		MockGetCoursePriceHistory: func(ctx context.Context, courseUUID *string, currency string) ([]*domain.CoursePrice, error) {
+			return []*domain.CoursePrice{}, nil
+		},
+		MockGetCoupon: func(ctx context.Context, couponUUID *string) (*domain.Coupon, error) {
+			return &domain.Coupon{}, nil
+		},
+		MockGetCouponByCode: func(ctx context.Context, code string) (*domain.Coupon, error) {
+			return &domain.Coupon{}, nil
+		},
+		MockListCoupons: func(ctx context.Context) ([]*domain.Coupon, error) {
+			return []*domain.Coupon{}, nil
+		},
+		MockCountCouponRedemptions: func(ctx context.Context, couponUUID, studentUUID *string) (int64, error) {
+			return 0, nil
+		},
+		MockGetCouponUsage: func(ctx context.Context) ([]*domain.CouponUsage, error) {
+			return []*domain.CouponUsage{}, nil
+		},
+		MockGetPlan: func(ctx context.Context, planUUID *string) (*domain.Plan, error) {
+			return &domain.Plan{}, nil
+		},
+		MockListPlans: func(ctx context.Context) ([]*domain.Plan, error) {
+			return []*domain.Plan{}, nil
+		},
+		MockGetSubscription: func(ctx context.Context, subscriptionUUID *string) (*domain.Subscription, error) {
+			return &domain.Subscription{}, nil
+		},
+		MockGetStudentSubscription: func(ctx context.Context, studentUUID *string) (*domain.Subscription, error) {
+			return &domain.Subscription{Status: domain.SubscriptionStatusActive, CurrentPeriodEnd: time.Now().AddDate(1, 0, 0)}, nil
+		},
+		MockGetSubscriptionCharges: func(ctx context.Context, subscriptionUUID *string) ([]*domain.SubscriptionCharge, error) {
+			return []*domain.SubscriptionCharge{}, nil
+		},
+		MockGetInvoice: func(ctx context.Context, invoiceUUID *string) (*domain.Invoice, error) {
+			return &domain.Invoice{AbstractBase: domain.AbstractBase{UUID: *invoiceUUID}, Number: domain.InvoiceNumber(2023, 1)}, nil
+		},
+		MockListStudentInvoices: func(ctx context.Context, studentUUID *string) ([]*domain.Invoice, error) {
+			return nil, nil
+		},
+		MockGetTaxRate: func(ctx context.Context, currency string) (*domain.TaxRate, error) {
+			return nil, nil
+		},
+		MockListTaxRates: func(ctx context.Context) ([]*domain.TaxRate, error) {
+			return nil, nil
+		},
+		MockGetSubscriptionCharge: func(ctx context.Context, chargeUUID *string) (*domain.SubscriptionCharge, error) {
+			return &domain.SubscriptionCharge{AbstractBase: domain.AbstractBase{UUID: *chargeUUID}, Amount: domain.Money{Amount: 9900, Currency: "USD"}, Status: domain.ChargeStatusSucceeded, Reference: "ch_test"}, nil
+		},
+		MockGetChargeRefunds: func(ctx context.Context, chargeUUID *string) ([]*domain.Refund, error) {
+			return nil, nil
+		},
+		MockGetLedgerBalances: func(ctx context.Context) ([]*domain.LedgerBalance, error) {
+			return nil, nil
+		},
+		MockGetWebhookEvent: func(ctx context.Context, providerEventID string) (*domain.WebhookEvent, error) {
+			return nil, nil
+		},
+		MockGetRefundByReference: func(ctx context.Context, reference string) (*domain.Refund, error) {
+			return nil, nil
+		},
+		MockGetSubscriptionChargeByReference: func(ctx context.Context, reference string) (*domain.SubscriptionCharge, error) {
+			return nil, nil
+		},
+		MockListLedgerEntries: func(ctx context.Context, query domain.LedgerQuery) ([]*domain.LedgerEntry, error) {
+			return nil, nil
+		},
+		MockGetRevenueReport: func(ctx context.Context, query domain.RevenueQuery) ([]*domain.RevenueReportRow, error) {
+			return nil, nil
+		},
+		MockListRevenueShares: func(ctx context.Context, instructorUUID *string) ([]*domain.RevenueShare, error) {
+			return nil, nil
+		},
+		MockListUnpaidSales: func(ctx context.Context, from, to time.Time) ([]*domain.PayoutLine, error) {
+			return nil, nil
+		},
+		MockGetPayoutStatement: func(ctx context.Context, statementUUID *string) (*domain.PayoutStatement, error) {
+			return nil, nil
+		},
+		MockListPayoutStatements: func(ctx context.Context, instructorUUID, month string) ([]*domain.PayoutStatement, error) {
+			return nil, nil
+		},
+		MockListStudents: func(ctx context.Context, query *domain.StudentQuery) ([]*domain.Student, error) {
+			return nil, nil
+		},
+		MockListEnrollments: func(ctx context.Context, query *domain.EnrollmentQuery) ([]*domain.StudentCourse, error) {
+			return nil, nil
+		},
+		MockGetImport: func(ctx context.Context, importUUID *string) (*domain.Import, error) {
+			return nil, nil
+		},
+		MockGetImportRows: func(ctx context.Context, importUUID *string, status domain.ImportRowStatus, limit int) ([]*domain.ImportRow, error) {
+			return nil, nil
+		},
+		MockPageCourses: func(ctx context.Context, query *domain.CourseQuery) ([]*domain.Course, error) {
+			return nil, nil
+		},
+		MockGetSubscriptionChargeByKey: func(ctx context.Context, key string) (*domain.SubscriptionCharge, error) {
+			return nil, nil
+		},
+		MockGetPendingSubscriptionCharge: func(ctx context.Context, subscriptionUUID *string) (*domain.SubscriptionCharge, error) {
+			return nil, nil
+		},
+		MockGetRevenueReconciliation: func(ctx context.Context, from, to *time.Time) ([]*domain.RevenueReconciliation, error) {
+			return nil, nil
+		},
+		MockListUnsentRefunds: func(ctx context.Context, createdBefore time.Time) ([]*domain.Refund, error) {
+			return nil, nil
+		},
+		MockListUninvoicedCharges: func(ctx context.Context, settledBefore time.Time) ([]*domain.SubscriptionCharge, error) {
+			return nil, nil
+		},
 	}
 }
 
@@ -102,3 +938,1022 @@ func (c *MockGetRepository) GetCourse(
 ) (*domain.Course, error) {
 	return c.MockGetCourse(ctx, title)
 }
+
+// GetCourseCurriculum mocks GetCourseCurriculum
+func (c *MockGetRepository) GetCourseCurriculum(
+	ctx context.Context,
+	courseUUID *string,
+) (*domain.Course, error) {
+	return c.MockGetCourseCurriculum(ctx, courseUUID)
+}
+
+// GetModule mocks GetModule
+func (c *MockGetRepository) GetModule(
+	ctx context.Context,
+	moduleUUID *string,
+) (*domain.Module, error) {
+	return c.MockGetModule(ctx, moduleUUID)
+}
+
+// GetLesson mocks GetLesson
+func (c *MockGetRepository) GetLesson(
+	ctx context.Context,
+	lessonUUID *string,
+) (*domain.Lesson, error) {
+	return c.MockGetLesson(ctx, lessonUUID)
+}
+
+// GetEnrollment mocks GetEnrollment
+func (c *MockGetRepository) GetEnrollment(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) (*domain.StudentCourse, error) {
+	return c.MockGetEnrollment(ctx, studentUUID, courseUUID)
+}
+
+// GetStudentEnrollments mocks GetStudentEnrollments
+func (c *MockGetRepository) GetStudentEnrollments(
+	ctx context.Context,
+	studentUUID *string,
+) ([]*domain.Enrollment, error) {
+	return c.MockGetStudentEnrollments(ctx, studentUUID)
+}
+
+// GetLessonProgress mocks GetLessonProgress
+func (c *MockGetRepository) GetLessonProgress(
+	ctx context.Context,
+	studentUUID *string,
+	lessonUUID *string,
+) (*domain.LessonProgress, error) {
+	return c.MockGetLessonProgress(ctx, studentUUID, lessonUUID)
+}
+
+// GetCourseCompletion mocks GetCourseCompletion
+func (c *MockGetRepository) GetCourseCompletion(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) (int64, int64, error) {
+	return c.MockGetCourseCompletion(ctx, studentUUID, courseUUID)
+}
+
+// GetQuiz mocks GetQuiz
+func (c *MockGetRepository) GetQuiz(
+	ctx context.Context,
+	quizUUID *string,
+) (*domain.Quiz, error) {
+	return c.MockGetQuiz(ctx, quizUUID)
+}
+
+// GetStudentByUUID mocks GetStudentByUUID
+func (c *MockGetRepository) GetStudentByUUID(
+	ctx context.Context,
+	studentUUID *string,
+) (*domain.Student, error) {
+	return c.MockGetStudentByUUID(ctx, studentUUID)
+}
+
+// GetCourseByUUID mocks GetCourseByUUID
+func (c *MockGetRepository) GetCourseByUUID(
+	ctx context.Context,
+	courseUUID *string,
+) (*domain.Course, error) {
+	return c.MockGetCourseByUUID(ctx, courseUUID)
+}
+
+// GetCertificate mocks GetCertificate
+func (c *MockGetRepository) GetCertificate(
+	ctx context.Context,
+	serial *string,
+) (*domain.Certificate, error) {
+	return c.MockGetCertificate(ctx, serial)
+}
+
+// GetStudentCertificate mocks GetStudentCertificate
+func (c *MockGetRepository) GetStudentCertificate(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) (*domain.Certificate, error) {
+	return c.MockGetStudentCertificate(ctx, studentUUID, courseUUID)
+}
+
+// GetCoursePrerequisites mocks GetCoursePrerequisites
+func (c *MockGetRepository) GetCoursePrerequisites(
+	ctx context.Context,
+	courseUUID *string,
+) ([]*domain.Course, error) {
+	return c.MockGetCoursePrerequisites(ctx, courseUUID)
+}
+
+// GetWaitlist mocks GetWaitlist
+func (c *MockGetRepository) GetWaitlist(
+	ctx context.Context,
+	courseUUID *string,
+) ([]*domain.WaitlistEntry, error) {
+	return c.MockGetWaitlist(ctx, courseUUID)
+}
+
+// GetWaitlistEntry mocks GetWaitlistEntry
+func (c *MockGetRepository) GetWaitlistEntry(
+	ctx context.Context,
+	courseUUID *string,
+	studentUUID *string,
+) (*domain.WaitlistEntry, error) {
+	return c.MockGetWaitlistEntry(ctx, courseUUID, studentUUID)
+}
+
+// GetCourseRun mocks GetCourseRun
+func (c *MockGetRepository) GetCourseRun(
+	ctx context.Context,
+	runUUID *string,
+) (*domain.CourseRun, error) {
+	return c.MockGetCourseRun(ctx, runUUID)
+}
+
+// GetUpcomingCourseRuns mocks GetUpcomingCourseRuns
+func (c *MockGetRepository) GetUpcomingCourseRuns(
+	ctx context.Context,
+	courseUUID *string,
+	after time.Time,
+) ([]*domain.CourseRun, error) {
+	return c.MockGetUpcomingCourseRuns(ctx, courseUUID, after)
+}
+
+// GetCalendarSubscription mocks GetCalendarSubscription
+func (c *MockGetRepository) GetCalendarSubscription(
+	ctx context.Context,
+	studentUUID *string,
+) (*domain.CalendarSubscription, error) {
+	return c.MockGetCalendarSubscription(ctx, studentUUID)
+}
+
+// ListCourses mocks ListCourses
+func (c *MockGetRepository) ListCourses(
+	ctx context.Context,
+	sort domain.CourseSort,
+) ([]*domain.Course, error) {
+	return c.MockListCourses(ctx, sort)
+}
+
+// GetReview mocks GetReview
+func (c *MockGetRepository) GetReview(
+	ctx context.Context,
+	reviewUUID *string,
+) (*domain.Review, error) {
+	return c.MockGetReview(ctx, reviewUUID)
+}
+
+// GetCourseReviews mocks GetCourseReviews
+func (c *MockGetRepository) GetCourseReviews(
+	ctx context.Context,
+	courseUUID *string,
+	status domain.ReviewStatus,
+) ([]*domain.Review, error) {
+	return c.MockGetCourseReviews(ctx, courseUUID, status)
+}
+
+// GetCategory mocks GetCategory
+func (c *MockGetRepository) GetCategory(
+	ctx context.Context,
+	categoryUUID *string,
+) (*domain.Category, error) {
+	return c.MockGetCategory(ctx, categoryUUID)
+}
+
+// GetCategoryBySlug mocks GetCategoryBySlug
+func (c *MockGetRepository) GetCategoryBySlug(
+	ctx context.Context,
+	slug string,
+) (*domain.Category, error) {
+	return c.MockGetCategoryBySlug(ctx, slug)
+}
+
+// ListCategories mocks ListCategories
+func (c *MockGetRepository) ListCategories(
+	ctx context.Context,
+) ([]*domain.Category, error) {
+	return c.MockListCategories(ctx)
+}
+
+// GetInstructor mocks GetInstructor
+func (c *MockGetRepository) GetInstructor(
+	ctx context.Context,
+	instructorUUID *string,
+) (*domain.Instructor, error) {
+	return c.MockGetInstructor(ctx, instructorUUID)
+}
+
+// FindInstructorByName mocks FindInstructorByName
+func (c *MockGetRepository) FindInstructorByName(
+	ctx context.Context,
+	name string,
+) (*domain.Instructor, error) {
+	return c.MockFindInstructorByName(ctx, name)
+}
+
+// ListInstructors mocks ListInstructors
+func (c *MockGetRepository) ListInstructors(
+	ctx context.Context,
+) ([]*domain.Instructor, error) {
+	return c.MockListInstructors(ctx)
+}
+
+// GetCurrentCoursePrices mocks GetCurrentCoursePrices
+func (c *MockGetRepository) GetCurrentCoursePrices(
+	ctx context.Context,
+	courseUUID *string,
+	at time.Time,
+) ([]*domain.CoursePrice, error) {
+	return c.MockGetCurrentCoursePrices(ctx, courseUUID, at)
+}
+
+// GetCoursePriceHistory mocks GetCoursePriceHistory
+func (c *MockGetRepository) GetCoursePriceHistory(
+	ctx context.Context,
+	courseUUID *string,
+	currency string,
+) ([]*domain.CoursePrice, error) {
+	return c.MockGetCoursePriceHistory(ctx, courseUUID, currency)
+}
+
+// GetCoupon mocks GetCoupon
+func (c *MockGetRepository) GetCoupon(
+	ctx context.Context,
+	couponUUID *string,
+) (*domain.Coupon, error) {
+	return c.MockGetCoupon(ctx, couponUUID)
+}
+
+// GetCouponByCode mocks GetCouponByCode
+func (c *MockGetRepository) GetCouponByCode(
+	ctx context.Context,
+	code string,
+) (*domain.Coupon, error) {
+	return c.MockGetCouponByCode(ctx, code)
+}
+
+// ListCoupons mocks ListCoupons
+func (c *MockGetRepository) ListCoupons(
+	ctx context.Context,
+) ([]*domain.Coupon, error) {
+	return c.MockListCoupons(ctx)
+}
+
+// CountCouponRedemptions mocks CountCouponRedemptions
+func (c *MockGetRepository) CountCouponRedemptions(
+	ctx context.Context,
+	couponUUID *string,
+	studentUUID *string,
+) (int64, error) {
+	return c.MockCountCouponRedemptions(ctx, couponUUID, studentUUID)
+}
+
+// GetCouponUsage mocks GetCouponUsage
+func (c *MockGetRepository) GetCouponUsage(
+	ctx context.Context,
+) ([]*domain.CouponUsage, error) {
+	return c.MockGetCouponUsage(ctx)
+}
+
+// GetPlan mocks GetPlan
+func (c *MockGetRepository) GetPlan(
+	ctx context.Context,
+	planUUID *string,
+) (*domain.Plan, error) {
+	return c.MockGetPlan(ctx, planUUID)
+}
+
+// ListPlans mocks ListPlans
+func (c *MockGetRepository) ListPlans(
+	ctx context.Context,
+) ([]*domain.Plan, error) {
+	return c.MockListPlans(ctx)
+}
+
+// GetSubscription mocks GetSubscription
+func (c *MockGetRepository) GetSubscription(
+	ctx context.Context,
+	subscriptionUUID *string,
+) (*domain.Subscription, error) {
+	return c.MockGetSubscription(ctx, subscriptionUUID)
+}
+
+// GetStudentSubscription mocks GetStudentSubscription
+func (c *MockGetRepository) GetStudentSubscription(
+	ctx context.Context,
+	studentUUID *string,
+) (*domain.Subscription, error) {
+	return c.MockGetStudentSubscription(ctx, studentUUID)
+}
+
+// GetSubscriptionCharges mocks GetSubscriptionCharges
+func (c *MockGetRepository) GetSubscriptionCharges(
+	ctx context.Context,
+	subscriptionUUID *string,
+) ([]*domain.SubscriptionCharge, error) {
+	return c.MockGetSubscriptionCharges(ctx, subscriptionUUID)
+}
+
+// GetInvoice mocks GetInvoice
+func (c *MockGetRepository) GetInvoice(
+	ctx context.Context,
+	invoiceUUID *string,
+) (*domain.Invoice, error) {
+	return c.MockGetInvoice(ctx, invoiceUUID)
+}
+
+// ListStudentInvoices mocks ListStudentInvoices
+func (c *MockGetRepository) ListStudentInvoices(
+	ctx context.Context,
+	studentUUID *string,
+) ([]*domain.Invoice, error) {
+	return c.MockListStudentInvoices(ctx, studentUUID)
+}
+
+// GetTaxRate mocks GetTaxRate
+func (c *MockGetRepository) GetTaxRate(
+	ctx context.Context,
+	currency string,
+) (*domain.TaxRate, error) {
+	return c.MockGetTaxRate(ctx, currency)
+}
+
+// ListTaxRates mocks ListTaxRates
+func (c *MockGetRepository) ListTaxRates(
+	ctx context.Context,
+) ([]*domain.TaxRate, error) {
+	return c.MockListTaxRates(ctx)
+}
+
+// GetSubscriptionCharge mocks GetSubscriptionCharge
+func (c *MockGetRepository) GetSubscriptionCharge(
+	ctx context.Context,
+	chargeUUID *string,
+) (*domain.SubscriptionCharge, error) {
+	return c.MockGetSubscriptionCharge(ctx, chargeUUID)
+}
+
+// GetChargeRefunds mocks GetChargeRefunds
+func (c *MockGetRepository) GetChargeRefunds(
+	ctx context.Context,
+	chargeUUID *string,
+) ([]*domain.Refund, error) {
+	return c.MockGetChargeRefunds(ctx, chargeUUID)
+}
+
+// GetLedgerBalances mocks GetLedgerBalances
+func (c *MockGetRepository) GetLedgerBalances(
+	ctx context.Context,
+) ([]*domain.LedgerBalance, error) {
+	return c.MockGetLedgerBalances(ctx)
+}
+
+// GetWebhookEvent mocks GetWebhookEvent
+func (c *MockGetRepository) GetWebhookEvent(
+	ctx context.Context,
+	providerEventID string,
+) (*domain.WebhookEvent, error) {
+	return c.MockGetWebhookEvent(ctx, providerEventID)
+}
+
+// GetRefundByReference mocks GetRefundByReference
+func (c *MockGetRepository) GetRefundByReference(
+	ctx context.Context,
+	reference string,
+) (*domain.Refund, error) {
+	return c.MockGetRefundByReference(ctx, reference)
+}
+
+// GetSubscriptionChargeByReference mocks GetSubscriptionChargeByReference
+func (c *MockGetRepository) GetSubscriptionChargeByReference(
+	ctx context.Context,
+	reference string,
+) (*domain.SubscriptionCharge, error) {
+	return c.MockGetSubscriptionChargeByReference(ctx, reference)
+}
+
+// ListLedgerEntries mocks ListLedgerEntries
+func (c *MockGetRepository) ListLedgerEntries(
+	ctx context.Context,
+	query domain.LedgerQuery,
+) ([]*domain.LedgerEntry, error) {
+	return c.MockListLedgerEntries(ctx, query)
+}
+
+// GetRevenueReport mocks GetRevenueReport
+func (c *MockGetRepository) GetRevenueReport(
+	ctx context.Context,
+	query domain.RevenueQuery,
+) ([]*domain.RevenueReportRow, error) {
+	return c.MockGetRevenueReport(ctx, query)
+}
+
+// ListRevenueShares mocks ListRevenueShares
+func (c *MockGetRepository) ListRevenueShares(
+	ctx context.Context,
+	instructorUUID *string,
+) ([]*domain.RevenueShare, error) {
+	return c.MockListRevenueShares(ctx, instructorUUID)
+}
+
+// ListUnpaidSales mocks ListUnpaidSales
+func (c *MockGetRepository) ListUnpaidSales(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]*domain.PayoutLine, error) {
+	return c.MockListUnpaidSales(ctx, from, to)
+}
+
+// GetPayoutStatement mocks GetPayoutStatement
+func (c *MockGetRepository) GetPayoutStatement(
+	ctx context.Context,
+	statementUUID *string,
+) (*domain.PayoutStatement, error) {
+	return c.MockGetPayoutStatement(ctx, statementUUID)
+}
+
+// ListPayoutStatements mocks ListPayoutStatements
+func (c *MockGetRepository) ListPayoutStatements(
+	ctx context.Context,
+	instructorUUID string,
+	month string,
+) ([]*domain.PayoutStatement, error) {
+	return c.MockListPayoutStatements(ctx, instructorUUID, month)
+}
+
+// ListStudents mocks ListStudents
+func (c *MockGetRepository) ListStudents(
+	ctx context.Context,
+	query *domain.StudentQuery,
+) ([]*domain.Student, error) {
+	return c.MockListStudents(ctx, query)
+}
+
+// ListEnrollments mocks ListEnrollments
+func (c *MockGetRepository) ListEnrollments(
+	ctx context.Context,
+	query *domain.EnrollmentQuery,
+) ([]*domain.StudentCourse, error) {
+	return c.MockListEnrollments(ctx, query)
+}
+
+// GetImport mocks GetImport
+func (c *MockGetRepository) GetImport(
+	ctx context.Context,
+	importUUID *string,
+) (*domain.Import, error) {
+	return c.MockGetImport(ctx, importUUID)
+}
+
+// GetImportRows mocks GetImportRows
+func (c *MockGetRepository) GetImportRows(
+	ctx context.Context,
+	importUUID *string,
+	status domain.ImportRowStatus,
+	limit int,
+) ([]*domain.ImportRow, error) {
+	return c.MockGetImportRows(ctx, importUUID, status, limit)
+}
+
+// PageCourses mocks PageCourses
+func (c *MockGetRepository) PageCourses(
+	ctx context.Context,
+	query *domain.CourseQuery,
+) ([]*domain.Course, error) {
+	return c.MockPageCourses(ctx, query)
+}
+
+// GetSubscriptionChargeByKey mocks GetSubscriptionChargeByKey
+func (c *MockGetRepository) GetSubscriptionChargeByKey(
+	ctx context.Context,
+	key string,
+) (*domain.SubscriptionCharge, error) {
+	return c.MockGetSubscriptionChargeByKey(ctx, key)
+}
+
+// GetPendingSubscriptionCharge mocks GetPendingSubscriptionCharge
+func (c *MockGetRepository) GetPendingSubscriptionCharge(
+	ctx context.Context,
+	subscriptionUUID *string,
+) (*domain.SubscriptionCharge, error) {
+	return c.MockGetPendingSubscriptionCharge(ctx, subscriptionUUID)
+}
+
+// GetRevenueReconciliation mocks GetRevenueReconciliation
+func (c *MockGetRepository) GetRevenueReconciliation(
+	ctx context.Context,
+	from *time.Time,
+	to *time.Time,
+) ([]*domain.RevenueReconciliation, error) {
+	return c.MockGetRevenueReconciliation(ctx, from, to)
+}
+
+// ListUnsentRefunds mocks ListUnsentRefunds
+func (c *MockGetRepository) ListUnsentRefunds(
+	ctx context.Context,
+	createdBefore time.Time,
+) ([]*domain.Refund, error) {
+	return c.MockListUnsentRefunds(ctx, createdBefore)
+}
+
+// ListUninvoicedCharges mocks ListUninvoicedCharges
+func (c *MockGetRepository) ListUninvoicedCharges(
+	ctx context.Context,
+	settledBefore time.Time,
+) ([]*domain.SubscriptionCharge, error) {
+	return c.MockListUninvoicedCharges(ctx, settledBefore)
+}
+
+// MockUpdateRepository mocks the database update repository
+type MockUpdateRepository struct {
+	MockUpdateModule func(
+		ctx context.Context,
+		module *domain.Module,
+	) (*domain.Module, error)
+	MockUpdateLesson func(
+		ctx context.Context,
+		lesson *domain.Lesson,
+	) (*domain.Lesson, error)
+	MockReorderModules func(
+		ctx context.Context,
+		courseUUID *string,
+		moduleUUIDs []string,
+	) error
+	MockReorderLessons func(
+		ctx context.Context,
+		moduleUUID *string,
+		lessonUUIDs []string,
+	) error
+	MockSaveLessonProgress func(
+		ctx context.Context,
+		progress *domain.LessonProgress,
+	) (*domain.LessonProgress, error)
+	MockCompleteEnrollment func(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+		completedAt time.Time,
+	) error
+	MockRevokeCertificate func(
+		ctx context.Context,
+		serial *string,
+		reason string,
+		revokedAt time.Time,
+	) error
+	MockSaveCalendarSubscription func(
+		ctx context.Context,
+		subscription *domain.CalendarSubscription,
+	) (*domain.CalendarSubscription, error)
+	MockSaveReview func(
+		ctx context.Context,
+		review *domain.Review,
+	) (*domain.Review, error)
+	MockModerateReview func(
+		ctx context.Context,
+		reviewUUID *string,
+		status domain.ReviewStatus,
+	) (*domain.Review, error)
+	MockUpdateCategory func(
+		ctx context.Context,
+		category *domain.Category,
+	) (*domain.Category, error)
+	MockUpdateInstructor func(
+		ctx context.Context,
+		instructor *domain.Instructor,
+	) (*domain.Instructor, error)
+	MockDeactivateCoupon func(
+		ctx context.Context,
+		couponUUID *string,
+	) error
+	MockUpdateSubscription func(
+		ctx context.Context,
+		subscription *domain.Subscription,
+	) (*domain.Subscription, error)
+	MockUpdateRefund func(
+		ctx context.Context,
+		refund *domain.Refund,
+	) (*domain.Refund, error)
+	MockMarkPayoutPaid func(
+		ctx context.Context,
+		statementUUID *string,
+		reference string,
+		paidAt time.Time,
+	) (*domain.PayoutStatement, error)
+	MockClaimImport func(
+		ctx context.Context,
+		now time.Time,
+		lease time.Duration,
+	) (*domain.Import, error)
+	MockSaveImportRows func(
+		ctx context.Context,
+		imp *domain.Import,
+		rows []*domain.ImportRow,
+		now time.Time,
+		lease time.Duration,
+	) (*domain.Import, error)
+	MockSettleSubscriptionCharge func(
+		ctx context.Context,
+		charge *domain.SubscriptionCharge,
+	) (*domain.SubscriptionCharge, error)
+	MockClaimDueSubscriptions func(
+		ctx context.Context,
+		now time.Time,
+		lease time.Duration,
+		limit int,
+	) ([]*domain.Subscription, error)
+}
+
+// NewMockUpdateRepository initializes a new MockUpdateRepository
+func NewMockUpdateRepository() *MockUpdateRepository {
+	return &MockUpdateRepository{
+		MockUpdateModule: func(ctx context.Context, module *domain.Module) (*domain.Module, error) {
+			return module, nil
+		},
+		MockUpdateLesson: func(ctx context.Context, lesson *domain.Lesson) (*domain.Lesson, error) {
+			return lesson, nil
+		},
+		MockReorderModules: func(ctx context.Context, courseUUID *string, moduleUUIDs []string) error {
+			return nil
+		},
+		MockReorderLessons: func(ctx context.Context, moduleUUID *string, lessonUUIDs []string) error {
+			return nil
+		},
+		MockSaveLessonProgress: func(ctx context.Context, progress *domain.LessonProgress) (*domain.LessonProgress, error) {
+			return progress, nil
+		},
+		MockCompleteEnrollment: func(ctx context.Context, studentUUID, courseUUID *string, completedAt time.Time) error {
+			return nil
+		},
+		MockRevokeCertificate: func(ctx context.Context, serial *string, reason string, revokedAt time.Time) error {
+			return nil
+		},
+		MockSaveCalendarSubscription: func(ctx context.Context, subscription *domain.CalendarSubscription) (*domain.CalendarSubscription, error) {
+			return subscription, nil
+		},
+		MockSaveReview: func(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+			return review, nil
+		},
+		MockModerateReview: func(ctx context.Context, reviewUUID *string, status domain.ReviewStatus) (*domain.Review, error) {
+			return &domain.Review{Status: status}, nil
+		},
+		MockUpdateCategory: func(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+			return category, nil
+		},
+		MockUpdateInstructor: func(ctx context.Context, instructor *domain.Instructor) (*domain.Instructor, error) {
+			return instructor, nil
+		},
+		MockDeactivateCoupon: func(ctx context.Context, couponUUID *string) error {
+			return nil
+		},
+		MockUpdateSubscription: func(ctx context.Context, subscription *domain.Subscription) (*domain.Subscription, error) {
+			return subscription, nil
+		},
+		MockUpdateRefund: func(ctx context.Context, refund *domain.Refund) (*domain.Refund, error) {
+			return refund, nil
+		},
+		MockMarkPayoutPaid: func(ctx context.Context, statementUUID *string, reference string, paidAt time.Time) (*domain.PayoutStatement, error) {
+			return nil, nil
+		},
+		MockClaimImport: func(ctx context.Context, now time.Time, lease time.Duration) (*domain.Import, error) {
+			return nil, nil
+		},
+		MockSaveImportRows: func(ctx context.Context, imp *domain.Import, rows []*domain.ImportRow, now time.Time, lease time.Duration) (*domain.Import, error) {
+			return imp, nil
+		},
+		MockSettleSubscriptionCharge: func(ctx context.Context, charge *domain.SubscriptionCharge) (*domain.SubscriptionCharge, error) {
+			return charge, nil
+		},
+		MockClaimDueSubscriptions: func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.Subscription, error) {
+			return []*domain.Subscription{}, nil
+		},
+	}
+}
+
+// UpdateModule mocks UpdateModule
+func (c *MockUpdateRepository) UpdateModule(
+	ctx context.Context,
+	module *domain.Module,
+) (*domain.Module, error) {
+	return c.MockUpdateModule(ctx, module)
+}
+
+// UpdateLesson mocks UpdateLesson
+func (c *MockUpdateRepository) UpdateLesson(
+	ctx context.Context,
+	lesson *domain.Lesson,
+) (*domain.Lesson, error) {
+	return c.MockUpdateLesson(ctx, lesson)
+}
+
+// ReorderModules mocks ReorderModules
+func (c *MockUpdateRepository) ReorderModules(
+	ctx context.Context,
+	courseUUID *string,
+	moduleUUIDs []string,
+) error {
+	return c.MockReorderModules(ctx, courseUUID, moduleUUIDs)
+}
+
+// ReorderLessons mocks ReorderLessons
+func (c *MockUpdateRepository) ReorderLessons(
+	ctx context.Context,
+	moduleUUID *string,
+	lessonUUIDs []string,
+) error {
+	return c.MockReorderLessons(ctx, moduleUUID, lessonUUIDs)
+}
+
+// SaveLessonProgress mocks SaveLessonProgress
+func (c *MockUpdateRepository) SaveLessonProgress(
+	ctx context.Context,
+	progress *domain.LessonProgress,
+) (*domain.LessonProgress, error) {
+	return c.MockSaveLessonProgress(ctx, progress)
+}
+
+// CompleteEnrollment mocks CompleteEnrollment
+func (c *MockUpdateRepository) CompleteEnrollment(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+	completedAt time.Time,
+) error {
+	return c.MockCompleteEnrollment(ctx, studentUUID, courseUUID, completedAt)
+}
+
+// RevokeCertificate mocks RevokeCertificate
+func (c *MockUpdateRepository) RevokeCertificate(
+	ctx context.Context,
+	serial *string,
+	reason string,
+	revokedAt time.Time,
+) error {
+	return c.MockRevokeCertificate(ctx, serial, reason, revokedAt)
+}
+
+// SaveCalendarSubscription mocks SaveCalendarSubscription
+func (c *MockUpdateRepository) SaveCalendarSubscription(
+	ctx context.Context,
+	subscription *domain.CalendarSubscription,
+) (*domain.CalendarSubscription, error) {
+	return c.MockSaveCalendarSubscription(ctx, subscription)
+}
+
+// SaveReview mocks SaveReview
+func (c *MockUpdateRepository) SaveReview(
+	ctx context.Context,
+	review *domain.Review,
+) (*domain.Review, error) {
+	return c.MockSaveReview(ctx, review)
+}
+
+// ModerateReview mocks ModerateReview
+func (c *MockUpdateRepository) ModerateReview(
+	ctx context.Context,
+	reviewUUID *string,
+	status domain.ReviewStatus,
+) (*domain.Review, error) {
+	return c.MockModerateReview(ctx, reviewUUID, status)
+}
+
+// UpdateCategory mocks UpdateCategory
+func (c *MockUpdateRepository) UpdateCategory(
+	ctx context.Context,
+	category *domain.Category,
+) (*domain.Category, error) {
+	return c.MockUpdateCategory(ctx, category)
+}
+
+// UpdateInstructor mocks UpdateInstructor
+func (c *MockUpdateRepository) UpdateInstructor(
+	ctx context.Context,
+	instructor *domain.Instructor,
+) (*domain.Instructor, error) {
+	return c.MockUpdateInstructor(ctx, instructor)
+}
+
+// DeactivateCoupon mocks DeactivateCoupon
+func (c *MockUpdateRepository) DeactivateCoupon(
+	ctx context.Context,
+	couponUUID *string,
+) error {
+	return c.MockDeactivateCoupon(ctx, couponUUID)
+}
+
+// UpdateSubscription mocks UpdateSubscription
+func (c *MockUpdateRepository) UpdateSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+) (*domain.Subscription, error) {
+	return c.MockUpdateSubscription(ctx, subscription)
+}
+
+// UpdateRefund mocks UpdateRefund
+func (c *MockUpdateRepository) UpdateRefund(
+	ctx context.Context,
+	refund *domain.Refund,
+) (*domain.Refund, error) {
+	return c.MockUpdateRefund(ctx, refund)
+}
+
+// MarkPayoutPaid mocks MarkPayoutPaid
+func (c *MockUpdateRepository) MarkPayoutPaid(
+	ctx context.Context,
+	statementUUID *string,
+	reference string,
+	paidAt time.Time,
+) (*domain.PayoutStatement, error) {
+	return c.MockMarkPayoutPaid(ctx, statementUUID, reference, paidAt)
+}
+
+// ClaimImport mocks ClaimImport
+func (c *MockUpdateRepository) ClaimImport(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+) (*domain.Import, error) {
+	return c.MockClaimImport(ctx, now, lease)
+}
+
+// SaveImportRows mocks SaveImportRows
+func (c *MockUpdateRepository) SaveImportRows(
+	ctx context.Context,
+	imp *domain.Import,
+	rows []*domain.ImportRow,
+	now time.Time,
+	lease time.Duration,
+) (*domain.Import, error) {
+	return c.MockSaveImportRows(ctx, imp, rows, now, lease)
+}
+
+// SettleSubscriptionCharge mocks SettleSubscriptionCharge
+func (c *MockUpdateRepository) SettleSubscriptionCharge(
+	ctx context.Context,
+	charge *domain.SubscriptionCharge,
+) (*domain.SubscriptionCharge, error) {
+	return c.MockSettleSubscriptionCharge(ctx, charge)
+}
+
+// ClaimDueSubscriptions mocks ClaimDueSubscriptions
+func (c *MockUpdateRepository) ClaimDueSubscriptions(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]*domain.Subscription, error) {
+	return c.MockClaimDueSubscriptions(ctx, now, lease, limit)
+}
+
+// MockDeleteRepository mocks the database delete repository
+type MockDeleteRepository struct {
+	MockDeleteModule func(
+		ctx context.Context,
+		moduleUUID *string,
+	) error
+	MockDeleteLesson func(
+		ctx context.Context,
+		lessonUUID *string,
+	) error
+	MockRemoveCoursePrerequisite func(
+		ctx context.Context,
+		courseUUID *string,
+		prerequisiteUUID *string,
+	) error
+	MockUnenrollStudent func(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) error
+	MockLeaveWaitlist func(
+		ctx context.Context,
+		courseUUID *string,
+		studentUUID *string,
+	) error
+	MockDeleteCategory func(
+		ctx context.Context,
+		categoryUUID *string,
+	) error
+	MockDeleteInstructor func(
+		ctx context.Context,
+		instructorUUID *string,
+	) error
+	MockDeleteStudent func(
+		ctx context.Context,
+		studentUUID *string,
+	) error
+	MockDeleteCourse func(
+		ctx context.Context,
+		courseUUID *string,
+	) error
+}
+
+// NewMockDeleteRepository initializes a new MockDeleteRepository
+func NewMockDeleteRepository() *MockDeleteRepository {
+	return &MockDeleteRepository{
+		MockDeleteModule: func(ctx context.Context, moduleUUID *string) error {
+			return nil
+		},
+		MockDeleteLesson: func(ctx context.Context, lessonUUID *string) error {
+			return nil
+		},
+		MockRemoveCoursePrerequisite: func(ctx context.Context, courseUUID, prerequisiteUUID *string) error {
+			return nil
+		},
+		MockUnenrollStudent: func(ctx context.Context, studentUUID, courseUUID *string) error {
+			return nil
+		},
+		MockLeaveWaitlist: func(ctx context.Context, courseUUID, studentUUID *string) error {
+			return nil
+		},
+		MockDeleteCategory: func(ctx context.Context, categoryUUID *string) error {
+			return nil
+		},
+		MockDeleteInstructor: func(ctx context.Context, instructorUUID *string) error {
+			return nil
+		},
+		MockDeleteStudent: func(ctx context.Context, studentUUID *string) error {
+			return nil
+		},
+		MockDeleteCourse: func(ctx context.Context, courseUUID *string) error {
+			return nil
+		},
+	}
+}
+
+// DeleteModule mocks DeleteModule
+func (c *MockDeleteRepository) DeleteModule(
+	ctx context.Context,
+	moduleUUID *string,
+) error {
+	return c.MockDeleteModule(ctx, moduleUUID)
+}
+
+// DeleteLesson mocks DeleteLesson
+func (c *MockDeleteRepository) DeleteLesson(
+	ctx context.Context,
+	lessonUUID *string,
+) error {
+	return c.MockDeleteLesson(ctx, lessonUUID)
+}
+
+// RemoveCoursePrerequisite mocks RemoveCoursePrerequisite
+func (c *MockDeleteRepository) RemoveCoursePrerequisite(
+	ctx context.Context,
+	courseUUID *string,
+	prerequisiteUUID *string,
+) error {
+	return c.MockRemoveCoursePrerequisite(ctx, courseUUID, prerequisiteUUID)
+}
+
+// UnenrollStudent mocks UnenrollStudent
+func (c *MockDeleteRepository) UnenrollStudent(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) error {
+	return c.MockUnenrollStudent(ctx, studentUUID, courseUUID)
+}
+
+// LeaveWaitlist mocks LeaveWaitlist
+func (c *MockDeleteRepository) LeaveWaitlist(
+	ctx context.Context,
+	courseUUID *string,
+	studentUUID *string,
+) error {
+	return c.MockLeaveWaitlist(ctx, courseUUID, studentUUID)
+}
+
+// DeleteCategory mocks DeleteCategory
+func (c *MockDeleteRepository) DeleteCategory(
+	ctx context.Context,
+	categoryUUID *string,
+) error {
+	return c.MockDeleteCategory(ctx, categoryUUID)
+}
+
+// DeleteInstructor mocks DeleteInstructor
+func (c *MockDeleteRepository) DeleteInstructor(
+	ctx context.Context,
+	instructorUUID *string,
+) error {
+	return c.MockDeleteInstructor(ctx, instructorUUID)
+}
+
+// DeleteStudent mocks DeleteStudent
+func (c *MockDeleteRepository) DeleteStudent(
+	ctx context.Context,
+	studentUUID *string,
+) error {
+	return c.MockDeleteStudent(ctx, studentUUID)
+}
+
+// DeleteCourse mocks DeleteCourse
+func (c *MockDeleteRepository) DeleteCourse(
+	ctx context.Context,
+	courseUUID *string,
+) error {
+	return c.MockDeleteCourse(ctx, courseUUID)
+}
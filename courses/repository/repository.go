@@ -2,10 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/MelvinKim/courses/domain"
 )
 
+// ErrStorage is wrapped by the errors of failed calls to the database, as
+// opposed to requests the repository refused
+var ErrStorage = errors.New("infrastructure")
+
 // CreateRepository defines create contract
 type CreateRepository interface {
 	CreateStudent(
@@ -20,7 +26,109 @@ type CreateRepository interface {
 		ctx context.Context,
 		email *string,
 		courseTitle *string,
+		runUUID *string,
+		redemption *domain.CouponRedemption,
 	) (*domain.Student, error)
+	CreateModule(
+		ctx context.Context,
+		module *domain.Module,
+	) (*domain.Module, error)
+	CreateLesson(
+		ctx context.Context,
+		lesson *domain.Lesson,
+	) (*domain.Lesson, error)
+	CreateQuiz(
+		ctx context.Context,
+		quiz *domain.Quiz,
+	) (*domain.Quiz, error)
+	CreateAttempt(
+		ctx context.Context,
+		attempt *domain.Attempt,
+		courseUUID string,
+		maxAttempts uint,
+	) (*domain.Attempt, error)
+	CreateCertificate(
+		ctx context.Context,
+		certificate *domain.Certificate,
+	) (*domain.Certificate, error)
+	AddCoursePrerequisite(
+		ctx context.Context,
+		prerequisite *domain.CoursePrerequisite,
+	) error
+	JoinWaitlist(
+		ctx context.Context,
+		entry *domain.WaitlistEntry,
+	) (*domain.WaitlistEntry, error)
+	CreateCourseRun(
+		ctx context.Context,
+		run *domain.CourseRun,
+	) (*domain.CourseRun, error)
+	CreateLiveSession(
+		ctx context.Context,
+		session *domain.LiveSession,
+	) (*domain.LiveSession, error)
+	CreateCategory(
+		ctx context.Context,
+		category *domain.Category,
+	) (*domain.Category, error)
+	CreateInstructor(
+		ctx context.Context,
+		instructor *domain.Instructor,
+	) (*domain.Instructor, error)
+	CreateCoursePrice(
+		ctx context.Context,
+		price *domain.CoursePrice,
+	) (*domain.CoursePrice, error)
+	CreateCoupon(
+		ctx context.Context,
+		coupon *domain.Coupon,
+	) (*domain.Coupon, error)
+	CreatePlan(
+		ctx context.Context,
+		plan *domain.Plan,
+	) (*domain.Plan, error)
+	CreateSubscription(
+		ctx context.Context,
+		subscription *domain.Subscription,
+		charge *domain.SubscriptionCharge,
+	) (*domain.Subscription, error)
+	CreateSubscriptionCharge(
+		ctx context.Context,
+		charge *domain.SubscriptionCharge,
+	) (*domain.SubscriptionCharge, error)
+	CreateInvoice(
+		ctx context.Context,
+		invoice *domain.Invoice,
+	) (*domain.Invoice, error)
+	SetTaxRate(
+		ctx context.Context,
+		rate *domain.TaxRate,
+	) (*domain.TaxRate, error)
+	CreateRefund(
+		ctx context.Context,
+		refund *domain.Refund,
+	) (*domain.Refund, error)
+	RecordWebhookEvent(
+		ctx context.Context,
+		event *domain.WebhookEvent,
+	) (*domain.WebhookEvent, error)
+	CreateRevenueShare(
+		ctx context.Context,
+		share *domain.RevenueShare,
+	) (*domain.RevenueShare, error)
+	CreatePayoutStatement(
+		ctx context.Context,
+		statement *domain.PayoutStatement,
+	) (*domain.PayoutStatement, error)
+	CreateImport(
+		ctx context.Context,
+		imp *domain.Import,
+		rows []*domain.ImportRow,
+	) (*domain.Import, error)
+	PromoteWaitlistEntry(
+		ctx context.Context,
+		entry *domain.WaitlistEntry,
+	) error
 }
 
 // GetRepository defines get contract
@@ -33,4 +141,422 @@ type GetRepository interface {
 		ctx context.Context,
 		title *string,
 	) (*domain.Course, error)
+	GetCourseCurriculum(
+		ctx context.Context,
+		courseUUID *string,
+	) (*domain.Course, error)
+	GetModule(
+		ctx context.Context,
+		moduleUUID *string,
+	) (*domain.Module, error)
+	GetLesson(
+		ctx context.Context,
+		lessonUUID *string,
+	) (*domain.Lesson, error)
+	GetEnrollment(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) (*domain.StudentCourse, error)
+	GetStudentEnrollments(
+		ctx context.Context,
+		studentUUID *string,
+	) ([]*domain.Enrollment, error)
+	GetLessonProgress(
+		ctx context.Context,
+		studentUUID *string,
+		lessonUUID *string,
+	) (*domain.LessonProgress, error)
+	GetCourseCompletion(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) (completed int64, total int64, err error)
+	GetQuiz(
+		ctx context.Context,
+		quizUUID *string,
+	) (*domain.Quiz, error)
+	GetStudentByUUID(
+		ctx context.Context,
+		studentUUID *string,
+	) (*domain.Student, error)
+	GetCourseByUUID(
+		ctx context.Context,
+		courseUUID *string,
+	) (*domain.Course, error)
+	GetCertificate(
+		ctx context.Context,
+		serial *string,
+	) (*domain.Certificate, error)
+	GetStudentCertificate(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) (*domain.Certificate, error)
+	GetCoursePrerequisites(
+		ctx context.Context,
+		courseUUID *string,
+	) ([]*domain.Course, error)
+	GetWaitlist(
+		ctx context.Context,
+		courseUUID *string,
+	) ([]*domain.WaitlistEntry, error)
+	GetWaitlistEntry(
+		ctx context.Context,
+		courseUUID *string,
+		studentUUID *string,
+	) (*domain.WaitlistEntry, error)
+	GetCourseRun(
+		ctx context.Context,
+		runUUID *string,
+	) (*domain.CourseRun, error)
+	GetUpcomingCourseRuns(
+		ctx context.Context,
+		courseUUID *string,
+		after time.Time,
+	) ([]*domain.CourseRun, error)
+	GetCalendarSubscription(
+		ctx context.Context,
+		studentUUID *string,
+	) (*domain.CalendarSubscription, error)
+	ListCourses(
+		ctx context.Context,
+		sort domain.CourseSort,
+	) ([]*domain.Course, error)
+	GetReview(
+		ctx context.Context,
+		reviewUUID *string,
+	) (*domain.Review, error)
+	GetCourseReviews(
+		ctx context.Context,
+		courseUUID *string,
+		status domain.ReviewStatus,
+	) ([]*domain.Review, error)
+	GetCategory(
+		ctx context.Context,
+		categoryUUID *string,
+	) (*domain.Category, error)
+	GetCategoryBySlug(
+		ctx context.Context,
+		slug string,
+	) (*domain.Category, error)
+	ListCategories(
+		ctx context.Context,
+	) ([]*domain.Category, error)
+	GetInstructor(
+		ctx context.Context,
+		instructorUUID *string,
+	) (*domain.Instructor, error)
+	FindInstructorByName(
+		ctx context.Context,
+		name string,
+	) (*domain.Instructor, error)
+	ListInstructors(
+		ctx context.Context,
+	) ([]*domain.Instructor, error)
+	GetCurrentCoursePrices(
+		ctx context.Context,
+		courseUUID *string,
+		at time.Time,
+	) ([]*domain.CoursePrice, error)
+	GetCoursePriceHistory(
+		ctx context.Context,
+		courseUUID *string,
+		currency string,
+	) ([]*domain.CoursePrice, error)
+	GetCoupon(
+		ctx context.Context,
+		couponUUID *string,
+	) (*domain.Coupon, error)
+	GetCouponByCode(
+		ctx context.Context,
+		code string,
+	) (*domain.Coupon, error)
+	ListCoupons(
+		ctx context.Context,
+	) ([]*domain.Coupon, error)
+	CountCouponRedemptions(
+		ctx context.Context,
+		couponUUID *string,
+		studentUUID *string,
+	) (int64, error)
+	GetCouponUsage(
+		ctx context.Context,
+	) ([]*domain.CouponUsage, error)
+	GetPlan(
+		ctx context.Context,
+		planUUID *string,
+	) (*domain.Plan, error)
+	ListPlans(
+		ctx context.Context,
+	) ([]*domain.Plan, error)
+	GetSubscription(
+		ctx context.Context,
+		subscriptionUUID *string,
+	) (*domain.Subscription, error)
+	GetStudentSubscription(
+		ctx context.Context,
+		studentUUID *string,
+	) (*domain.Subscription, error)
+	GetSubscriptionCharges(
+		ctx context.Context,
+		subscriptionUUID *string,
+	) ([]*domain.SubscriptionCharge, error)
+	GetInvoice(
+		ctx context.Context,
+		invoiceUUID *string,
+	) (*domain.Invoice, error)
+	ListStudentInvoices(
+		ctx context.Context,
+		studentUUID *string,
+	) ([]*domain.Invoice, error)
+	GetTaxRate(
+		ctx context.Context,
+		currency string,
+	) (*domain.TaxRate, error)
+	ListTaxRates(
+		ctx context.Context,
+	) ([]*domain.TaxRate, error)
+	GetSubscriptionCharge(
+		ctx context.Context,
+		chargeUUID *string,
+	) (*domain.SubscriptionCharge, error)
+	GetChargeRefunds(
+		ctx context.Context,
+		chargeUUID *string,
+	) ([]*domain.Refund, error)
+	GetLedgerBalances(
+		ctx context.Context,
+	) ([]*domain.LedgerBalance, error)
+	GetWebhookEvent(
+		ctx context.Context,
+		providerEventID string,
+	) (*domain.WebhookEvent, error)
+	GetRefundByReference(
+		ctx context.Context,
+		reference string,
+	) (*domain.Refund, error)
+	GetSubscriptionChargeByReference(
+		ctx context.Context,
+		reference string,
+	) (*domain.SubscriptionCharge, error)
+	ListLedgerEntries(
+		ctx context.Context,
+		query domain.LedgerQuery,
+	) ([]*domain.LedgerEntry, error)
+	GetRevenueReport(
+		ctx context.Context,
+		query domain.RevenueQuery,
+	) ([]*domain.RevenueReportRow, error)
+	ListRevenueShares(
+		ctx context.Context,
+		instructorUUID *string,
+	) ([]*domain.RevenueShare, error)
+	ListUnpaidSales(
+		ctx context.Context,
+		from time.Time,
+		to time.Time,
+	) ([]*domain.PayoutLine, error)
+	GetPayoutStatement(
+		ctx context.Context,
+		statementUUID *string,
+	) (*domain.PayoutStatement, error)
+	ListPayoutStatements(
+		ctx context.Context,
+		instructorUUID string,
+		month string,
+	) ([]*domain.PayoutStatement, error)
+	ListStudents(
+		ctx context.Context,
+		query *domain.StudentQuery,
+	) ([]*domain.Student, error)
+	ListEnrollments(
+		ctx context.Context,
+		query *domain.EnrollmentQuery,
+	) ([]*domain.StudentCourse, error)
+	GetImport(
+		ctx context.Context,
+		importUUID *string,
+	) (*domain.Import, error)
+	GetImportRows(
+		ctx context.Context,
+		importUUID *string,
+		status domain.ImportRowStatus,
+		limit int,
+	) ([]*domain.ImportRow, error)
+	PageCourses(
+		ctx context.Context,
+		query *domain.CourseQuery,
+	) ([]*domain.Course, error)
+	GetSubscriptionChargeByKey(
+		ctx context.Context,
+		key string,
+	) (*domain.SubscriptionCharge, error)
+	GetPendingSubscriptionCharge(
+		ctx context.Context,
+		subscriptionUUID *string,
+	) (*domain.SubscriptionCharge, error)
+	GetRevenueReconciliation(
+		ctx context.Context,
+		from *time.Time,
+		to *time.Time,
+	) ([]*domain.RevenueReconciliation, error)
+	ListUnsentRefunds(
+		ctx context.Context,
+		createdBefore time.Time,
+	) ([]*domain.Refund, error)
+	ListUninvoicedCharges(
+		ctx context.Context,
+		settledBefore time.Time,
+	) ([]*domain.SubscriptionCharge, error)
+}
+
+// UpdateRepository defines update contract
+type UpdateRepository interface {
+	UpdateModule(
+		ctx context.Context,
+		module *domain.Module,
+	) (*domain.Module, error)
+	UpdateLesson(
+		ctx context.Context,
+		lesson *domain.Lesson,
+	) (*domain.Lesson, error)
+	ReorderModules(
+		ctx context.Context,
+		courseUUID *string,
+		moduleUUIDs []string,
+	) error
+	ReorderLessons(
+		ctx context.Context,
+		moduleUUID *string,
+		lessonUUIDs []string,
+	) error
+	SaveLessonProgress(
+		ctx context.Context,
+		progress *domain.LessonProgress,
+	) (*domain.LessonProgress, error)
+	CompleteEnrollment(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+		completedAt time.Time,
+	) error
+	RevokeCertificate(
+		ctx context.Context,
+		serial *string,
+		reason string,
+		revokedAt time.Time,
+	) error
+	SaveCalendarSubscription(
+		ctx context.Context,
+		subscription *domain.CalendarSubscription,
+	) (*domain.CalendarSubscription, error)
+	SaveReview(
+		ctx context.Context,
+		review *domain.Review,
+	) (*domain.Review, error)
+	ModerateReview(
+		ctx context.Context,
+		reviewUUID *string,
+		status domain.ReviewStatus,
+	) (*domain.Review, error)
+	UpdateCategory(
+		ctx context.Context,
+		category *domain.Category,
+	) (*domain.Category, error)
+	UpdateInstructor(
+		ctx context.Context,
+		instructor *domain.Instructor,
+	) (*domain.Instructor, error)
+	DeactivateCoupon(
+		ctx context.Context,
+		couponUUID *string,
+	) error
+	UpdateSubscription(
+		ctx context.Context,
+		subscription *domain.Subscription,
+	) (*domain.Subscription, error)
+	UpdateRefund(
+		ctx context.Context,
+		refund *domain.Refund,
+	) (*domain.Refund, error)
+	MarkPayoutPaid(
+		ctx context.Context,
+		statementUUID *string,
+		reference string,
+		paidAt time.Time,
+	) (*domain.PayoutStatement, error)
+	ClaimImport(
+		ctx context.Context,
+		now time.Time,
+		lease time.Duration,
+	) (*domain.Import, error)
+	SaveImportRows(
+		ctx context.Context,
+		imp *domain.Import,
+		rows []*domain.ImportRow,
+		now time.Time,
+		lease time.Duration,
+	) (*domain.Import, error)
+	SettleSubscriptionCharge(
+		ctx context.Context,
+		charge *domain.SubscriptionCharge,
+	) (*domain.SubscriptionCharge, error)
+	ClaimDueSubscriptions(
+		ctx context.Context,
+		now time.Time,
+		lease time.Duration,
+		limit int,
+	) ([]*domain.Subscription, error)
+}
+
+// DeleteRepository defines delete contract
+type DeleteRepository interface {
+	DeleteModule(
+		ctx context.Context,
+		moduleUUID *string,
+	) error
+	DeleteLesson(
+		ctx context.Context,
+		lessonUUID *string,
+	) error
+	RemoveCoursePrerequisite(
+		ctx context.Context,
+		courseUUID *string,
+		prerequisiteUUID *string,
+	) error
+	UnenrollStudent(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) error
+	LeaveWaitlist(
+		ctx context.Context,
+		courseUUID *string,
+		studentUUID *string,
+	) error
+	DeleteCategory(
+		ctx context.Context,
+		categoryUUID *string,
+	) error
+	DeleteInstructor(
+		ctx context.Context,
+		instructorUUID *string,
+	) error
+	DeleteStudent(
+		ctx context.Context,
+		studentUUID *string,
+	) error
+	DeleteCourse(
+		ctx context.Context,
+		courseUUID *string,
+	) error
+}
+
+// SearchIndex defines the course search contract
+type SearchIndex interface {
+	SearchCourses(
+		ctx context.Context,
+		query *domain.CourseSearchQuery,
+	) (*domain.CourseSearchResults, error)
 }
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+const (
+	defaultAccessTokenTTL = 30 * 24 * time.Hour
+	maxAccessTokenTTL     = 365 * 24 * time.Hour
+)
+
+// accessTokenContext is signed ahead of a token's claims, so nothing else
+// signed with the same key can pass for an access token
+const accessTokenContext = "sudocode access token\x00"
+
+// signedClaims is what an access token's signature covers
+func signedClaims(claims []byte) []byte {
+	return append([]byte(accessTokenContext), claims...)
+}
+
+// IssueAccessToken hands out a signed access token for a role. Instructor
+// and student tokens are bound to the instructor's profile or the student
+func (u *Usecase) IssueAccessToken(
+	ctx context.Context,
+	role domain.Role,
+	subject string,
+	ttl time.Duration,
+) (*domain.AccessToken, error) {
+	if u.TokenSigner == nil {
+		return nil, fmt.Errorf("access tokens can not be issued without a signing key")
+	}
+	if !role.Valid() {
+		return nil, fmt.Errorf("invalid role %s", role)
+	}
+	if role == domain.RoleInstructor {
+		if subject == "" {
+			return nil, fmt.Errorf("instructor's UUID can not be empty")
+		}
+		instructor, err := u.Get.GetInstructor(ctx, &subject)
+		if err != nil {
+			return nil, err
+		}
+		if instructor == nil {
+			return nil, &domain.NotFoundError{Kind: "instructor", Key: subject}
+		}
+	}
+	if role == domain.RoleStudent {
+		if subject == "" {
+			return nil, fmt.Errorf("student's UUID can not be empty")
+		}
+		student, err := u.Get.GetStudentByUUID(ctx, &subject)
+		if err != nil {
+			return nil, err
+		}
+		if student == nil {
+			return nil, &domain.NotFoundError{Kind: "student", Key: subject}
+		}
+	}
+	switch {
+	case ttl < 0:
+		return nil, fmt.Errorf("token lifetime can not be negative")
+	case ttl == 0:
+		ttl = defaultAccessTokenTTL
+	case ttl > maxAccessTokenTTL:
+		return nil, fmt.Errorf("token lifetime can not be over %s", maxAccessTokenTTL)
+	}
+
+	principal := domain.Principal{
+		Role:      role,
+		Subject:   subject,
+		ExpiresAt: time.Now().Add(ttl).UTC().Truncate(time.Second),
+	}
+	claims, err := json.Marshal(principal)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode access token: %w", err)
+	}
+	return &domain.AccessToken{
+		Token:     base64.RawURLEncoding.EncodeToString(claims) + "." + u.TokenSigner.Sign(signedClaims(claims)),
+		Role:      principal.Role,
+		Subject:   principal.Subject,
+		ExpiresAt: principal.ExpiresAt,
+	}, nil
+}
+
+// Authenticate returns who an access token was issued to. The admin API
+// token is always an admin
+func (u *Usecase) Authenticate(
+	ctx context.Context,
+	token string,
+) (*domain.Principal, error) {
+	if token == "" {
+		return nil, domain.ErrUnauthenticated
+	}
+	if u.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(u.AdminToken)) == 1 {
+		return &domain.Principal{Role: domain.RoleAdmin}, nil
+	}
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || u.TokenSigner == nil {
+		return nil, domain.ErrUnauthenticated
+	}
+	claims, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || !u.TokenSigner.Verify(signedClaims(claims), signature) {
+		return nil, domain.ErrUnauthenticated
+	}
+	var principal domain.Principal
+	if err := json.Unmarshal(claims, &principal); err != nil || !principal.Role.Valid() {
+		return nil, domain.ErrUnauthenticated
+	}
+	if !time.Now().Before(principal.ExpiresAt) {
+		return nil, domain.ErrUnauthenticated
+	}
+	return &principal, nil
+}
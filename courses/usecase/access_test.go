@@ -0,0 +1,215 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_IssueAccessToken(t *testing.T) {
+	ctx := context.Background()
+	instructorUUID := gofakeit.UUID()
+	studentUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name    string
+		role    domain.Role
+		subject string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "Happy case - instructor token",
+			role:    domain.RoleInstructor,
+			subject: instructorUUID,
+		},
+		{
+			name: "Happy case - admin token",
+			role: domain.RoleAdmin,
+			ttl:  time.Hour,
+		},
+		{
+			name:    "Sad case - unknown instructor",
+			role:    domain.RoleInstructor,
+			subject: gofakeit.UUID(),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - instructor token without an instructor",
+			role:    domain.RoleInstructor,
+			wantErr: true,
+		},
+		{
+			name:    "Happy case - student token",
+			role:    domain.RoleStudent,
+			subject: studentUUID,
+		},
+		{
+			name:    "Sad case - unknown student",
+			role:    domain.RoleStudent,
+			subject: gofakeit.UUID(),
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - unknown role",
+			role:    "guest",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetInstructor = func(ctx context.Context, uuid *string) (*domain.Instructor, error) {
+				if *uuid != instructorUUID {
+					return nil, nil
+				}
+				return &domain.Instructor{AbstractBase: domain.AbstractBase{UUID: instructorUUID}, Name: "Grace Hopper"}, nil
+			}
+			get.MockGetStudentByUUID = func(ctx context.Context, uuid *string) (*domain.Student, error) {
+				if *uuid != studentUUID {
+					return nil, nil
+				}
+				return &domain.Student{AbstractBase: domain.AbstractBase{UUID: studentUUID}}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+			u.TokenSigner = testTokenSigner
+
+			token, err := u.IssueAccessToken(ctx, tt.role, tt.subject, tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.IssueAccessToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			principal, err := u.Authenticate(ctx, token.Token)
+			if err != nil {
+				t.Fatalf("expected the issued token to authenticate, got %v", err)
+			}
+			if principal.Role != tt.role || principal.Subject != tt.subject {
+				t.Errorf("expected a %s principal for %q, got %+v", tt.role, tt.subject, principal)
+			}
+		})
+	}
+}
+
+func TestUsecase_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	u := newMockTestUsecase()
+	u.AdminToken = "let-me-in"
+	u.TokenSigner = testTokenSigner
+
+	issued, err := u.IssueAccessToken(ctx, domain.RoleAdmin, "", time.Hour)
+	if err != nil {
+		t.Fatalf("can't issue a token: %v", err)
+	}
+	encoded, signature, _ := strings.Cut(issued.Token, ".")
+	other, err := u.IssueAccessToken(ctx, domain.RoleAdmin, "", 2*time.Hour)
+	if err != nil {
+		t.Fatalf("can't issue a token: %v", err)
+	}
+	_, otherSignature, _ := strings.Cut(other.Token, ".")
+	claims, _ := base64.RawURLEncoding.DecodeString(encoded)
+
+	tests := []struct {
+		name     string
+		token    string
+		wantRole domain.Role
+	}{
+		{
+			name:     "Happy case - issued token",
+			token:    issued.Token,
+			wantRole: domain.RoleAdmin,
+		},
+		{
+			name:     "Happy case - admin API token",
+			token:    "let-me-in",
+			wantRole: domain.RoleAdmin,
+		},
+		{
+			name:  "Sad case - no token",
+			token: "",
+		},
+		{
+			name:  "Sad case - wrong admin API token",
+			token: "let-me-out",
+		},
+		{
+			name:  "Sad case - signature of another token",
+			token: encoded + "." + otherSignature,
+		},
+		{
+			name:  "Sad case - claims signed with the certificate key",
+			token: encoded + "." + testSigner.Sign(claims),
+		},
+		{
+			name:  "Sad case - claims signed without the access token context",
+			token: encoded + "." + testTokenSigner.Sign(claims),
+		},
+		{
+			name:  "Sad case - tampered claims",
+			token: strings.ToUpper(encoded[:1]) + strings.ToLower(encoded[1:]) + "." + signature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := u.Authenticate(ctx, tt.token)
+			if tt.wantRole == "" {
+				if !errors.Is(err, domain.ErrUnauthenticated) {
+					t.Fatalf("Usecase.Authenticate() error = %v, want %v", err, domain.ErrUnauthenticated)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Usecase.Authenticate() error = %v", err)
+			}
+			if principal.Role != tt.wantRole {
+				t.Errorf("expected a %s principal, got %s", tt.wantRole, principal.Role)
+			}
+		})
+	}
+}
+
+func TestUsecase_Authenticate_WithoutTokenSigner(t *testing.T) {
+	ctx := context.Background()
+	u := newMockTestUsecase()
+	u.AdminToken = "let-me-in"
+
+	if _, err := u.IssueAccessToken(ctx, domain.RoleAdmin, "", time.Hour); err == nil {
+		t.Errorf("expected no tokens to be issued without a signing key")
+	}
+	if principal, err := u.Authenticate(ctx, "let-me-in"); err != nil || principal.Role != domain.RoleAdmin {
+		t.Errorf("expected the admin API token to still authenticate, got %v", err)
+	}
+}
+
+func TestPrincipal_CanActAsInstructor(t *testing.T) {
+	instructorUUID := gofakeit.UUID()
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		want      bool
+	}{
+		{name: "admin", principal: &domain.Principal{Role: domain.RoleAdmin}, want: true},
+		{name: "same instructor", principal: &domain.Principal{Role: domain.RoleInstructor, Subject: instructorUUID}, want: true},
+		{name: "other instructor", principal: &domain.Principal{Role: domain.RoleInstructor, Subject: gofakeit.UUID()}},
+		{name: "instructor without a subject", principal: &domain.Principal{Role: domain.RoleInstructor}},
+		{name: "student", principal: &domain.Principal{Role: domain.RoleStudent, Subject: instructorUUID}},
+		{name: "anonymous", principal: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.CanActAsInstructor(instructorUUID); got != tt.want {
+				t.Errorf("Principal.CanActAsInstructor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/calendar"
+)
+
+// IssueCalendarToken generates a new secret for a student's calendar feed,
+// revoking the previous one. Only the student or an admin can mint one
+func (u *Usecase) IssueCalendarToken(
+	ctx context.Context,
+	principal *domain.Principal,
+	studentUUID *string,
+) (*domain.CalendarSubscription, error) {
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	if !principal.CanActAsStudent(*studentUUID) {
+		return nil, domain.ErrForbidden
+	}
+	student, err := u.Get.GetStudentByUUID(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, &domain.NotFoundError{Kind: "student", Key: *studentUUID}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("can't generate calendar token: %w", err)
+	}
+	now := time.Now()
+	return u.Update.SaveCalendarSubscription(ctx, &domain.CalendarSubscription{
+		StudentUUID: *studentUUID,
+		Token:       base64.RawURLEncoding.EncodeToString(raw),
+		CreatedAt:   &now,
+	})
+}
+
+// GetStudentCalendar renders the iCalendar feed of a student's live sessions
+// and deadlines. The token must match the student's calendar token
+func (u *Usecase) GetStudentCalendar(
+	ctx context.Context,
+	studentUUID *string,
+	token string,
+) ([]byte, error) {
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	subscription, err := u.Get.GetCalendarSubscription(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil || subtle.ConstantTimeCompare([]byte(subscription.Token), []byte(token)) != 1 {
+		return nil, domain.ErrInvalidCalendarToken
+	}
+
+	student, err := u.Get.GetStudentByUUID(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, domain.ErrInvalidCalendarToken
+	}
+	enrollments, err := u.Get.GetStudentEnrollments(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(fmt.Sprintf("sudoCODE Academy - %s %s", student.FirstName, student.LastName))
+	return calendar.RenderICS(name, enrollments, time.Now()), nil
+}
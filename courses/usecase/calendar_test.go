@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_GetStudentCalendar(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	get := mock.NewMockGetRepository()
+	get.MockGetCalendarSubscription = func(ctx context.Context, uuid *string) (*domain.CalendarSubscription, error) {
+		return &domain.CalendarSubscription{StudentUUID: *uuid, Token: "secret"}, nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "Happy case - valid token",
+			token: "secret",
+		},
+		{
+			name:    "Sad case - wrong token",
+			token:   "guess",
+			wantErr: domain.ErrInvalidCalendarToken,
+		},
+		{
+			name:    "Sad case - missing token",
+			wantErr: domain.ErrInvalidCalendarToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := u.GetStudentCalendar(ctx, &studentUUID, tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Usecase.GetStudentCalendar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !strings.HasPrefix(string(feed), "BEGIN:VCALENDAR\r\n") {
+				t.Errorf("expected an iCalendar feed, got %q", feed)
+			}
+		})
+	}
+}
+
+func TestUsecase_IssueCalendarToken(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	u := newMockTestUsecase()
+	student := &domain.Principal{Role: domain.RoleStudent, Subject: studentUUID}
+
+	first, err := u.IssueCalendarToken(ctx, student, &studentUUID)
+	if err != nil {
+		t.Fatalf("Usecase.IssueCalendarToken() error = %v", err)
+	}
+	second, err := u.IssueCalendarToken(ctx, &domain.Principal{Role: domain.RoleAdmin}, &studentUUID)
+	if err != nil {
+		t.Fatalf("Usecase.IssueCalendarToken() error = %v", err)
+	}
+	if first.Token == "" || first.Token == second.Token {
+		t.Errorf("expected a fresh token on every call, got %q and %q", first.Token, second.Token)
+	}
+
+	for _, principal := range []*domain.Principal{
+		nil,
+		{Role: domain.RoleStudent, Subject: gofakeit.UUID()},
+		{Role: domain.RoleInstructor, Subject: studentUUID},
+	} {
+		if _, err := u.IssueCalendarToken(ctx, principal, &studentUUID); !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("expected %+v to be forbidden, got %v", principal, err)
+		}
+	}
+}
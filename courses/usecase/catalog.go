@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// CreateCategory creates a catalog category. The slug is derived from the
+// name when none is given
+func (u *Usecase) CreateCategory(
+	ctx context.Context,
+	category *domain.Category,
+) (*domain.Category, error) {
+	if err := u.validateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return u.Create.CreateCategory(ctx, category)
+}
+
+// GetCategory returns a category with its direct subcategories
+func (u *Usecase) GetCategory(
+	ctx context.Context,
+	categoryUUID *string,
+) (*domain.Category, error) {
+	if categoryUUID == nil || *categoryUUID == "" {
+		return nil, fmt.Errorf("category's UUID can not be empty")
+	}
+	return u.Get.GetCategory(ctx, categoryUUID)
+}
+
+// ListCategories returns the category tree: the top level categories with
+// their subcategories nested under them, by name
+func (u *Usecase) ListCategories(
+	ctx context.Context,
+) ([]*domain.Category, error) {
+	categories, err := u.Get.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byUUID := make(map[string]*domain.Category, len(categories))
+	for _, category := range categories {
+		category.Children = []*domain.Category{}
+		byUUID[category.UUID] = category
+	}
+	roots := []*domain.Category{}
+	for _, category := range categories {
+		var parent *domain.Category
+		if category.ParentUUID != nil {
+			parent = byUUID[*category.ParentUUID]
+		}
+		if parent == nil {
+			roots = append(roots, category)
+			continue
+		}
+		parent.Children = append(parent.Children, category)
+	}
+	return roots, nil
+}
+
+// UpdateCategory renames or moves a category. A category can't be moved
+// under one of its own subcategories
+func (u *Usecase) UpdateCategory(
+	ctx context.Context,
+	category *domain.Category,
+) (*domain.Category, error) {
+	if category.UUID == "" {
+		return nil, fmt.Errorf("category's UUID can not be empty")
+	}
+	existing, err := u.Get.GetCategory(ctx, &category.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, &domain.NotFoundError{Kind: "category", Key: category.UUID}
+	}
+	if err := u.validateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return u.Update.UpdateCategory(ctx, category)
+}
+
+// DeleteCategory deletes a category that no course or subcategory uses
+func (u *Usecase) DeleteCategory(
+	ctx context.Context,
+	categoryUUID *string,
+) error {
+	if categoryUUID == nil || *categoryUUID == "" {
+		return fmt.Errorf("category's UUID can not be empty")
+	}
+	return u.Delete.DeleteCategory(ctx, categoryUUID)
+}
+
+// validateCategory checks a category's name, slug and parent, filling in the
+// slug from the name when it is missing
+func (u *Usecase) validateCategory(
+	ctx context.Context,
+	category *domain.Category,
+) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("category's name can not be empty")
+	}
+	if category.Slug == "" {
+		category.Slug = category.Name
+	}
+	category.Slug = domain.Slugify(category.Slug)
+	if category.Slug == "" {
+		return fmt.Errorf("category's slug must contain letters or digits")
+	}
+	clash, err := u.Get.GetCategoryBySlug(ctx, category.Slug)
+	if err != nil {
+		return err
+	}
+	if clash != nil && clash.UUID != category.UUID {
+		return fmt.Errorf("category %s already uses the slug %s", clash.Name, category.Slug)
+	}
+
+	if category.ParentUUID == nil || *category.ParentUUID == "" {
+		category.ParentUUID = nil
+		return nil
+	}
+	// walk up from the new parent, meeting the category means a cycle
+	for ancestorUUID := category.ParentUUID; ancestorUUID != nil; {
+		if category.UUID != "" && *ancestorUUID == category.UUID {
+			return fmt.Errorf("category can not be nested under itself or one of its subcategories")
+		}
+		ancestor, err := u.Get.GetCategory(ctx, ancestorUUID)
+		if err != nil {
+			return err
+		}
+		if ancestor == nil {
+			return &domain.NotFoundError{Kind: "category", Key: *ancestorUUID}
+		}
+		ancestorUUID = ancestor.ParentUUID
+	}
+	return nil
+}
+
+// CreateInstructor creates an instructor profile
+func (u *Usecase) CreateInstructor(
+	ctx context.Context,
+	instructor *domain.Instructor,
+) (*domain.Instructor, error) {
+	instructor.Name = strings.TrimSpace(instructor.Name)
+	if instructor.Name == "" {
+		return nil, fmt.Errorf("instructor's name can not be empty")
+	}
+	return u.Create.CreateInstructor(ctx, instructor)
+}
+
+// GetInstructor returns an instructor's profile
+func (u *Usecase) GetInstructor(
+	ctx context.Context,
+	instructorUUID *string,
+) (*domain.Instructor, error) {
+	if instructorUUID == nil || *instructorUUID == "" {
+		return nil, fmt.Errorf("instructor's UUID can not be empty")
+	}
+	return u.Get.GetInstructor(ctx, instructorUUID)
+}
+
+// ListInstructors returns every instructor by name
+func (u *Usecase) ListInstructors(
+	ctx context.Context,
+) ([]*domain.Instructor, error) {
+	return u.Get.ListInstructors(ctx)
+}
+
+// UpdateInstructor updates an instructor's profile
+func (u *Usecase) UpdateInstructor(
+	ctx context.Context,
+	instructor *domain.Instructor,
+) (*domain.Instructor, error) {
+	if instructor.UUID == "" {
+		return nil, fmt.Errorf("instructor's UUID can not be empty")
+	}
+	instructor.Name = strings.TrimSpace(instructor.Name)
+	if instructor.Name == "" {
+		return nil, fmt.Errorf("instructor's name can not be empty")
+	}
+	return u.Update.UpdateInstructor(ctx, instructor)
+}
+
+// DeleteInstructor deletes an instructor who doesn't teach any course
+func (u *Usecase) DeleteInstructor(
+	ctx context.Context,
+	instructorUUID *string,
+) error {
+	if instructorUUID == nil || *instructorUUID == "" {
+		return fmt.Errorf("instructor's UUID can not be empty")
+	}
+	return u.Delete.DeleteInstructor(ctx, instructorUUID)
+}
+
+// linkCatalog points a new course at its category and instructor. Courses
+// given only names are linked to the category with the same slug and the
+// instructor with the same name, which are created when missing, so the
+// catalog doesn't fragment into spellings of the same thing
+func (u *Usecase) linkCatalog(
+	ctx context.Context,
+	course *domain.Course,
+) error {
+	category, err := u.courseCategory(ctx, course)
+	if err != nil {
+		return err
+	}
+	course.CategoryUUID, course.Category = &category.UUID, category.Name
+
+	instructor, err := u.courseInstructor(ctx, course)
+	if err != nil {
+		return err
+	}
+	course.InstructorUUID, course.Instructor = &instructor.UUID, instructor.Name
+	return nil
+}
+
+func (u *Usecase) courseCategory(
+	ctx context.Context,
+	course *domain.Course,
+) (*domain.Category, error) {
+	if course.CategoryUUID != nil && *course.CategoryUUID != "" {
+		category, err := u.Get.GetCategory(ctx, course.CategoryUUID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, &domain.NotFoundError{Kind: "category", Key: *course.CategoryUUID}
+		}
+		return category, nil
+	}
+	if strings.TrimSpace(course.Category) == "" {
+		return nil, fmt.Errorf("course's category can not be empty")
+	}
+	category, err := u.Get.GetCategoryBySlug(ctx, domain.Slugify(course.Category))
+	if err != nil || category != nil {
+		return category, err
+	}
+	return u.CreateCategory(ctx, &domain.Category{Name: course.Category})
+}
+
+func (u *Usecase) courseInstructor(
+	ctx context.Context,
+	course *domain.Course,
+) (*domain.Instructor, error) {
+	if course.InstructorUUID != nil && *course.InstructorUUID != "" {
+		instructor, err := u.Get.GetInstructor(ctx, course.InstructorUUID)
+		if err != nil {
+			return nil, err
+		}
+		if instructor == nil {
+			return nil, &domain.NotFoundError{Kind: "instructor", Key: *course.InstructorUUID}
+		}
+		return instructor, nil
+	}
+	if strings.TrimSpace(course.Instructor) == "" {
+		return nil, fmt.Errorf("course's instructor can not be empty")
+	}
+	instructor, err := u.Get.FindInstructorByName(ctx, course.Instructor)
+	if err != nil || instructor != nil {
+		return instructor, err
+	}
+	return u.CreateInstructor(ctx, &domain.Instructor{Name: course.Instructor})
+}
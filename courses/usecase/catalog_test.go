@@ -0,0 +1,199 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func newCategory(uuid, name string, parentUUID *string) *domain.Category {
+	return &domain.Category{
+		AbstractBase: domain.AbstractBase{UUID: uuid},
+		Name:         name,
+		Slug:         domain.Slugify(name),
+		ParentUUID:   parentUUID,
+	}
+}
+
+// newCategoryRepository serves categories from memory
+func newCategoryRepository(categories ...*domain.Category) *mock.MockGetRepository {
+	get := mock.NewMockGetRepository()
+	get.MockGetCategory = func(ctx context.Context, uuid *string) (*domain.Category, error) {
+		for _, category := range categories {
+			if category.UUID == *uuid {
+				return category, nil
+			}
+		}
+		return nil, nil
+	}
+	get.MockGetCategoryBySlug = func(ctx context.Context, slug string) (*domain.Category, error) {
+		for _, category := range categories {
+			if category.Slug == slug {
+				return category, nil
+			}
+		}
+		return nil, nil
+	}
+	get.MockListCategories = func(ctx context.Context) ([]*domain.Category, error) {
+		return categories, nil
+	}
+	return get
+}
+
+func TestUsecase_CreateCourse_LinksCatalog(t *testing.T) {
+	ctx := context.Background()
+	golang := newCategory(gofakeit.UUID(), "GoLang", nil)
+	unknown := gofakeit.UUID()
+
+	tests := []struct {
+		name            string
+		course          *domain.Course
+		wantCategory    string
+		wantNewCategory bool
+		wantErr         bool
+	}{
+		{
+			name:         "Happy case - category name differing in case",
+			course:       &domain.Course{Category: " golang ", Instructor: "Melvin Kim"},
+			wantCategory: "GoLang",
+		},
+		{
+			name:         "Happy case - category by UUID",
+			course:       &domain.Course{CategoryUUID: &golang.UUID, Instructor: "Melvin Kim"},
+			wantCategory: "GoLang",
+		},
+		{
+			name:            "Happy case - new category",
+			course:          &domain.Course{Category: "Rust", Instructor: "Melvin Kim"},
+			wantCategory:    "Rust",
+			wantNewCategory: true,
+		},
+		{
+			name:    "Sad case - unknown category UUID",
+			course:  &domain.Course{CategoryUUID: &unknown, Instructor: "Melvin Kim"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - no instructor",
+			course:  &domain.Course{Category: "GoLang"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create := mock.NewMockCreateRepository()
+			createdCategory := false
+			create.MockCreateCategory = func(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+				createdCategory = true
+				category.UUID = gofakeit.UUID()
+				return category, nil
+			}
+			create.MockCreateCourse = func(ctx context.Context, c *domain.Course) (*domain.Course, error) {
+				return c, nil
+			}
+			u := course.NewUsecase(create, newCategoryRepository(golang), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			tt.course.Title = gofakeit.Name()
+			tt.course.Description = gofakeit.Sentence(10)
+			tt.course.Price = 40
+			created, err := u.CreateCourse(ctx, tt.course)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.CreateCourse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if created.Category != tt.wantCategory || created.CategoryUUID == nil {
+				t.Errorf("expected course to be linked to category %s, got %q", tt.wantCategory, created.Category)
+			}
+			if createdCategory != tt.wantNewCategory {
+				t.Errorf("expected a new category to be created: %v, got %v", tt.wantNewCategory, createdCategory)
+			}
+			if created.InstructorUUID == nil {
+				t.Errorf("expected course to be linked to an instructor")
+			}
+		})
+	}
+}
+
+func TestUsecase_UpdateCategory(t *testing.T) {
+	ctx := context.Background()
+	root := newCategory(gofakeit.UUID(), "Programming", nil)
+	child := newCategory(gofakeit.UUID(), "Go", &root.UUID)
+	grandchild := newCategory(gofakeit.UUID(), "Concurrency", &child.UUID)
+	other := newCategory(gofakeit.UUID(), "Design", nil)
+	u := course.NewUsecase(mock.NewMockCreateRepository(), newCategoryRepository(root, child, grandchild, other), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	tests := []struct {
+		name       string
+		uuid       string
+		catName    string
+		parentUUID *string
+		wantErr    bool
+	}{
+		{
+			name:       "Happy case - move under another tree",
+			uuid:       child.UUID,
+			catName:    "Go",
+			parentUUID: &other.UUID,
+		},
+		{
+			name:    "Happy case - make top level",
+			uuid:    grandchild.UUID,
+			catName: "Concurrency",
+		},
+		{
+			name:       "Sad case - under its own subcategory",
+			uuid:       root.UUID,
+			catName:    "Programming",
+			parentUUID: &grandchild.UUID,
+			wantErr:    true,
+		},
+		{
+			name:       "Sad case - under itself",
+			uuid:       child.UUID,
+			catName:    "Go",
+			parentUUID: &child.UUID,
+			wantErr:    true,
+		},
+		{
+			name:    "Sad case - slug taken by another category",
+			uuid:    other.UUID,
+			catName: "programming",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category := &domain.Category{Name: tt.catName, ParentUUID: tt.parentUUID}
+			category.UUID = tt.uuid
+			_, err := u.UpdateCategory(ctx, category)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.UpdateCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsecase_ListCategories(t *testing.T) {
+	root := newCategory(gofakeit.UUID(), "Programming", nil)
+	child := newCategory(gofakeit.UUID(), "Go", &root.UUID)
+	grandchild := newCategory(gofakeit.UUID(), "Concurrency", &child.UUID)
+	u := course.NewUsecase(mock.NewMockCreateRepository(), newCategoryRepository(grandchild, child, root), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	tree, err := u.ListCategories(context.Background())
+	if err != nil {
+		t.Fatalf("Usecase.ListCategories() error = %v", err)
+	}
+	if len(tree) != 1 || tree[0].UUID != root.UUID {
+		t.Fatalf("expected a single top level category, got %v", tree)
+	}
+	if len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Errorf("expected subcategories to be nested under their parents")
+	}
+}
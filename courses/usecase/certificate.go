@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
+)
+
+// IssueCertificate signs and saves a certificate for a student who completed
+// a course. Issuing again returns the certificate the student already holds
+func (u *Usecase) IssueCertificate(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) (*domain.Certificate, error) {
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+
+	existing, err := u.Get.GetStudentCertificate(ctx, studentUUID, courseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	enrollment, err := u.Get.GetEnrollment(ctx, studentUUID, courseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil || enrollment.Status != domain.EnrollmentStatusCompleted {
+		return nil, fmt.Errorf("student %s has not completed course %s", *studentUUID, *courseUUID)
+	}
+	student, err := u.Get.GetStudentByUUID(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, &domain.NotFoundError{Kind: "student", Key: *studentUUID}
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, courseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, &domain.NotFoundError{Kind: "course", Key: *courseUUID}
+	}
+
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	serial, err := newCertificateSerial(issuedAt)
+	if err != nil {
+		return nil, err
+	}
+	certificate := &domain.Certificate{
+		Serial:      serial,
+		StudentUUID: student.UUID,
+		CourseUUID:  course.UUID,
+		StudentName: strings.TrimSpace(student.FirstName + " " + student.LastName),
+		CourseTitle: course.Title,
+		IssuedAt:    issuedAt,
+	}
+	certificate.Signature = u.Signer.Sign(certificate.SignedContent())
+	return u.Create.CreateCertificate(ctx, certificate)
+}
+
+// VerifyCertificate checks a certificate's signature and revocation status
+func (u *Usecase) VerifyCertificate(
+	ctx context.Context,
+	serial *string,
+) (*domain.CertificateVerification, error) {
+	certificate, err := u.getCertificate(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+	if certificate == nil {
+		return &domain.CertificateVerification{Valid: false}, nil
+	}
+
+	revoked := certificate.RevokedAt != nil
+	signed := u.Signer.Verify(certificate.SignedContent(), certificate.Signature)
+	return &domain.CertificateVerification{
+		Valid:       signed && !revoked,
+		Revoked:     revoked,
+		Certificate: certificate,
+	}, nil
+}
+
+// RevokeCertificate withdraws a certificate so that it no longer verifies
+func (u *Usecase) RevokeCertificate(
+	ctx context.Context,
+	serial *string,
+	reason string,
+) (*domain.Certificate, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("revocation reason can not be empty")
+	}
+	certificate, err := u.getCertificate(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+	if certificate == nil {
+		return nil, &domain.NotFoundError{Kind: "certificate", Key: *serial}
+	}
+	if certificate.RevokedAt != nil {
+		return certificate, nil
+	}
+
+	now := time.Now()
+	if err := u.Update.RevokeCertificate(ctx, serial, reason, now); err != nil {
+		return nil, err
+	}
+	certificate.RevokedAt = &now
+	certificate.RevocationReason = reason
+	return certificate, nil
+}
+
+// RenderCertificatePDF renders a certificate as a PDF document
+func (u *Usecase) RenderCertificatePDF(
+	ctx context.Context,
+	serial *string,
+	verifyURL string,
+) ([]byte, error) {
+	certificate, err := u.getCertificate(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+	if certificate == nil {
+		return nil, nil
+	}
+	return certificates.RenderPDF(certificate, verifyURL), nil
+}
+
+func (u *Usecase) getCertificate(
+	ctx context.Context,
+	serial *string,
+) (*domain.Certificate, error) {
+	if serial == nil || *serial == "" {
+		return nil, fmt.Errorf("certificate serial can not be empty")
+	}
+	return u.Get.GetCertificate(ctx, serial)
+}
+
+// newCertificateSerial returns a random, human friendly serial such as
+// SC-2023-7K3QJX2M4P
+func newCertificateSerial(issuedAt time.Time) (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("can't generate certificate serial: %w", err)
+	}
+	code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)[:10]
+	return fmt.Sprintf("SC-%d-%s", issuedAt.Year(), code), nil
+}
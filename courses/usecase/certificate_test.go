@@ -0,0 +1,154 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_IssueCertificate(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	courseUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name       string
+		status     domain.EnrollmentStatus
+		enrolled   bool
+		wantErr    bool
+		wantIssued bool
+	}{
+		{
+			name:       "Happy case - completed course",
+			status:     domain.EnrollmentStatusCompleted,
+			enrolled:   true,
+			wantIssued: true,
+		},
+		{
+			name:     "Sad case - course still in progress",
+			status:   domain.EnrollmentStatusActive,
+			enrolled: true,
+			wantErr:  true,
+		},
+		{
+			name:    "Sad case - student not enrolled",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create := mock.NewMockCreateRepository()
+			get := mock.NewMockGetRepository()
+			get.MockGetStudentCertificate = func(ctx context.Context, studentUUID, courseUUID *string) (*domain.Certificate, error) {
+				return nil, nil
+			}
+			get.MockGetEnrollment = func(ctx context.Context, studentUUID, courseUUID *string) (*domain.StudentCourse, error) {
+				if !tt.enrolled {
+					return nil, nil
+				}
+				return &domain.StudentCourse{StudentUUID: *studentUUID, CourseUUID: *courseUUID, Status: tt.status}, nil
+			}
+			get.MockGetStudentByUUID = func(ctx context.Context, studentUUID *string) (*domain.Student, error) {
+				student := &domain.Student{FirstName: "Ada", LastName: "Lovelace"}
+				student.UUID = *studentUUID
+				return student, nil
+			}
+			get.MockGetCourseByUUID = func(ctx context.Context, courseUUID *string) (*domain.Course, error) {
+				c := &domain.Course{Title: "Go 101"}
+				c.UUID = *courseUUID
+				return c, nil
+			}
+			issued := false
+			create.MockCreateCertificate = func(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error) {
+				issued = true
+				return certificate, nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			certificate, err := u.IssueCertificate(ctx, &studentUUID, &courseUUID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.IssueCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if issued != tt.wantIssued {
+				t.Errorf("expected certificate issued to be %v", tt.wantIssued)
+			}
+			if tt.wantErr {
+				return
+			}
+			if certificate.StudentName != "Ada Lovelace" || certificate.CourseTitle != "Go 101" {
+				t.Errorf("unexpected certificate names %q, %q", certificate.StudentName, certificate.CourseTitle)
+			}
+			if !testSigner.Verify(certificate.SignedContent(), certificate.Signature) {
+				t.Errorf("expected the certificate's signature to verify")
+			}
+		})
+	}
+}
+
+func TestUsecase_VerifyCertificate(t *testing.T) {
+	ctx := context.Background()
+	serial := "SC-2023-ABCDEFGHIJ"
+	revokedAt := time.Now()
+
+	signed := func(c *domain.Certificate) *domain.Certificate {
+		c.Serial = serial
+		c.StudentUUID = gofakeit.UUID()
+		c.CourseUUID = gofakeit.UUID()
+		c.IssuedAt = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+		c.Signature = testSigner.Sign(c.SignedContent())
+		return c
+	}
+	tampered := signed(&domain.Certificate{})
+	tampered.StudentUUID = gofakeit.UUID()
+
+	tests := []struct {
+		name        string
+		certificate *domain.Certificate
+		wantValid   bool
+		wantRevoked bool
+	}{
+		{
+			name:        "Happy case - genuine certificate",
+			certificate: signed(&domain.Certificate{}),
+			wantValid:   true,
+		},
+		{
+			name:        "Sad case - revoked certificate",
+			certificate: signed(&domain.Certificate{RevokedAt: &revokedAt, RevocationReason: "plagiarism"}),
+			wantRevoked: true,
+		},
+		{
+			name:        "Sad case - tampered certificate",
+			certificate: tampered,
+		},
+		{
+			name: "Sad case - unknown serial",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetCertificate = func(ctx context.Context, serial *string) (*domain.Certificate, error) {
+				return tt.certificate, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			verification, err := u.VerifyCertificate(ctx, &serial)
+			if err != nil {
+				t.Fatalf("Usecase.VerifyCertificate() error = %v", err)
+			}
+			if verification.Valid != tt.wantValid {
+				t.Errorf("expected valid to be %v", tt.wantValid)
+			}
+			if verification.Revoked != tt.wantRevoked {
+				t.Errorf("expected revoked to be %v", tt.wantRevoked)
+			}
+		})
+	}
+}
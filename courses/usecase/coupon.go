@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+)
+
+// CreateCoupon creates a discount code. Percentage coupons take 1-100% off,
+// fixed coupons take a positive amount off and the courses and categories a
+// coupon is limited to must exist
+func (u *Usecase) CreateCoupon(
+	ctx context.Context,
+	coupon *domain.Coupon,
+) (*domain.Coupon, error) {
+	coupon.Code = domain.NormalizeCouponCode(coupon.Code)
+	if coupon.Code == "" {
+		return nil, fmt.Errorf("coupon's code can not be empty")
+	}
+	clash, err := u.Get.GetCouponByCode(ctx, coupon.Code)
+	if err != nil {
+		return nil, err
+	}
+	if clash != nil && clash.UUID != "" {
+		return nil, fmt.Errorf("coupon %s already exists", coupon.Code)
+	}
+
+	switch coupon.DiscountType {
+	case domain.DiscountTypePercentage:
+		if coupon.PercentOff == 0 || coupon.PercentOff > 100 {
+			return nil, fmt.Errorf("coupon's percentage must be between 1 and 100")
+		}
+		coupon.AmountOff = domain.Money{}
+	case domain.DiscountTypeFixed:
+		if coupon.AmountOff.Amount <= 0 {
+			return nil, fmt.Errorf("coupon's amount must be positive")
+		}
+		currency, err := domain.NormalizeCurrency(coupon.AmountOff.Currency)
+		if err != nil {
+			return nil, err
+		}
+		coupon.AmountOff.Currency = currency
+		coupon.PercentOff = 0
+	default:
+		return nil, fmt.Errorf("invalid discount type %q", coupon.DiscountType)
+	}
+	if coupon.ValidFrom != nil && coupon.ValidUntil != nil && !coupon.ValidUntil.After(*coupon.ValidFrom) {
+		return nil, fmt.Errorf("coupon must expire after it starts")
+	}
+
+	for _, target := range coupon.Courses {
+		course, err := u.Get.GetCourseByUUID(ctx, &target.CourseUUID)
+		if err != nil {
+			return nil, err
+		}
+		if course == nil {
+			return nil, &domain.NotFoundError{Kind: "course", Key: target.CourseUUID}
+		}
+	}
+	for _, target := range coupon.Categories {
+		category, err := u.Get.GetCategory(ctx, &target.CategoryUUID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, &domain.NotFoundError{Kind: "category", Key: target.CategoryUUID}
+		}
+	}
+	coupon.Active = true
+	coupon.Redemptions = 0
+	return u.Create.CreateCoupon(ctx, coupon)
+}
+
+// GetCoupon returns a coupon, or nil when it does not exist
+func (u *Usecase) GetCoupon(
+	ctx context.Context,
+	couponUUID *string,
+) (*domain.Coupon, error) {
+	if couponUUID == nil || *couponUUID == "" {
+		return nil, fmt.Errorf("coupon's UUID can not be empty")
+	}
+	return u.Get.GetCoupon(ctx, couponUUID)
+}
+
+// ListCoupons returns every coupon
+func (u *Usecase) ListCoupons(
+	ctx context.Context,
+) ([]*domain.Coupon, error) {
+	return u.Get.ListCoupons(ctx)
+}
+
+// DeactivateCoupon stops a coupon from being used
+func (u *Usecase) DeactivateCoupon(
+	ctx context.Context,
+	couponUUID *string,
+) error {
+	if couponUUID == nil || *couponUUID == "" {
+		return fmt.Errorf("coupon's UUID can not be empty")
+	}
+	return u.Update.DeactivateCoupon(ctx, couponUUID)
+}
+
+// GetCouponUsage reports how every coupon has been used
+func (u *Usecase) GetCouponUsage(
+	ctx context.Context,
+) ([]*domain.CouponUsage, error) {
+	return u.Get.GetCouponUsage(ctx)
+}
+
+// QuoteCoupon prices a course with a coupon, in the given currency or the
+// base currency. When a student is given their own use of the coupon is
+// checked too. Quoting doesn't redeem the coupon, enrolling with it does
+func (u *Usecase) QuoteCoupon(
+	ctx context.Context,
+	code string,
+	courseUUID *string,
+	studentUUID string,
+	currency string,
+) (*domain.CouponQuote, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, courseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, &domain.NotFoundError{Kind: "course", Key: *courseUUID}
+	}
+	return u.quoteCoupon(ctx, code, course, studentUUID, currency)
+}
+
+func (u *Usecase) quoteCoupon(
+	ctx context.Context,
+	code string,
+	course *domain.Course,
+	studentUUID string,
+	currency string,
+) (*domain.CouponQuote, error) {
+	coupon, err := u.Get.GetCouponByCode(ctx, domain.NormalizeCouponCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if coupon == nil || coupon.UUID == "" {
+		return nil, domain.ErrCouponNotFound
+	}
+	if !coupon.IsValidAt(time.Now()) {
+		return nil, domain.ErrCouponInactive
+	}
+	categoryUUIDs, err := u.categoryLineage(ctx, course.CategoryUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !coupon.AppliesTo(course.UUID, categoryUUIDs) {
+		return nil, domain.ErrCouponNotApplied
+	}
+	if coupon.MaxRedemptions > 0 && coupon.Redemptions >= coupon.MaxRedemptions {
+		return nil, domain.ErrCouponExhausted
+	}
+	if studentUUID != "" && coupon.PerStudentLimit > 0 {
+		used, err := u.Get.CountCouponRedemptions(ctx, &coupon.UUID, &studentUUID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(coupon.PerStudentLimit) {
+			return nil, domain.ErrCouponLimitReached
+		}
+	}
+
+	if currency == "" {
+		currency = domain.BaseCurrency
+	}
+	if err := u.LocalizeCoursePrice(ctx, course, currency); err != nil {
+		return nil, err
+	}
+	price := *course.LocalPrice
+	discount, err := u.couponDiscount(ctx, coupon, price)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CouponQuote{
+		Code:          coupon.Code,
+		CouponUUID:    coupon.UUID,
+		CourseUUID:    course.UUID,
+		OriginalPrice: price,
+		Discount:      discount,
+		FinalPrice:    domain.Money{Amount: price.Amount - discount.Amount, Currency: price.Currency},
+	}, nil
+}
+
+// couponDiscount works out how much a coupon takes off a price. Fixed amounts
+// in another currency are converted at the current rate
+func (u *Usecase) couponDiscount(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	price domain.Money,
+) (domain.Money, error) {
+	discount := coupon.PercentageDiscount(price)
+	if coupon.DiscountType == domain.DiscountTypeFixed {
+		converted, err := fx.Convert(ctx, u.Rates, coupon.AmountOff, price.Currency)
+		if err != nil {
+			return domain.Money{}, err
+		}
+		discount = converted
+	}
+	if discount.Amount > price.Amount {
+		discount.Amount = price.Amount
+	}
+	return discount, nil
+}
+
+// categoryLineage returns a category's UUID followed by the UUIDs of its
+// ancestors
+func (u *Usecase) categoryLineage(
+	ctx context.Context,
+	categoryUUID *string,
+) ([]string, error) {
+	lineage := []string{}
+	seen := map[string]bool{}
+	for categoryUUID != nil && *categoryUUID != "" && !seen[*categoryUUID] {
+		seen[*categoryUUID] = true
+		lineage = append(lineage, *categoryUUID)
+		category, err := u.Get.GetCategory(ctx, categoryUUID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			break
+		}
+		categoryUUID = category.ParentUUID
+	}
+	return lineage, nil
+}
+
+// checkoutRedemption quotes the coupon a student is enrolling with and
+// returns the redemption to record with their enrollment, or nil without a
+// coupon
+func (u *Usecase) checkoutRedemption(
+	ctx context.Context,
+	email *string,
+	courseTitle *string,
+	couponCode string,
+) (*domain.CouponRedemption, error) {
+	if couponCode == "" {
+		return nil, nil
+	}
+	student, err := u.Get.GetStudent(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, &domain.NotFoundError{Kind: "student", Key: *email}
+	}
+	course, err := u.Get.GetCourse(ctx, courseTitle)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, &domain.NotFoundError{Kind: "course", Key: *courseTitle}
+	}
+
+	quote, err := u.quoteCoupon(ctx, couponCode, course, student.UUID, "")
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CouponRedemption{
+		CouponUUID:    quote.CouponUUID,
+		StudentUUID:   student.UUID,
+		CourseUUID:    course.UUID,
+		OriginalPrice: quote.OriginalPrice,
+		Discount:      quote.Discount,
+		FinalPrice:    quote.FinalPrice,
+	}, nil
+}
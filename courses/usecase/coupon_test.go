@@ -0,0 +1,344 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_CreateCoupon(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name    string
+		coupon  *domain.Coupon
+		taken   bool
+		wantErr bool
+	}{
+		{
+			name: "Happy case - percentage",
+			coupon: &domain.Coupon{
+				Code:         " launch20 ",
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   20,
+			},
+		},
+		{
+			name: "Happy case - fixed amount",
+			coupon: &domain.Coupon{
+				Code:         "TENOFF",
+				DiscountType: domain.DiscountTypeFixed,
+				AmountOff:    domain.Money{Amount: 1000, Currency: "usd"},
+			},
+		},
+		{
+			name: "Sad case - more than 100%",
+			coupon: &domain.Coupon{
+				Code:         "FREEPLUS",
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   120,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - fixed amount without a currency",
+			coupon: &domain.Coupon{
+				Code:         "TENOFF",
+				DiscountType: domain.DiscountTypeFixed,
+				AmountOff:    domain.Money{Amount: 1000},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - expires before it starts",
+			coupon: &domain.Coupon{
+				Code:         "BACKWARDS",
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   10,
+				ValidFrom:    &now,
+				ValidUntil:   &yesterday,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - code is taken",
+			coupon: &domain.Coupon{
+				Code:         "LAUNCH20",
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   20,
+			},
+			taken:   true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetCouponByCode = func(ctx context.Context, code string) (*domain.Coupon, error) {
+				if tt.taken {
+					return &domain.Coupon{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Code: code}, nil
+				}
+				return nil, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			coupon, err := u.CreateCoupon(ctx, tt.coupon)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.CreateCoupon() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if coupon.Code != domain.NormalizeCouponCode(coupon.Code) || coupon.Code == "" {
+				t.Errorf("expected the coupon code to be normalized, got %q", coupon.Code)
+			}
+			if coupon.DiscountType == domain.DiscountTypeFixed && coupon.AmountOff.Currency != "USD" {
+				t.Errorf("expected the currency to be normalized, got %q", coupon.AmountOff.Currency)
+			}
+			if !coupon.Active {
+				t.Errorf("expected a new coupon to be active")
+			}
+		})
+	}
+}
+
+func TestUsecase_QuoteCoupon(t *testing.T) {
+	ctx := context.Background()
+	rates := fx.NewStaticRates()
+	if err := rates.Set("USD", "KES", big.NewRat(130, 1)); err != nil {
+		t.Fatalf("can't set test rate: %v", err)
+	}
+	courseUUID := gofakeit.UUID()
+	parentUUID := gofakeit.UUID()
+	childUUID := gofakeit.UUID()
+	studentUUID := gofakeit.UUID()
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	active := func(coupon domain.Coupon) *domain.Coupon {
+		coupon.UUID = gofakeit.UUID()
+		coupon.Code = "PROMO"
+		coupon.Active = true
+		return &coupon
+	}
+
+	tests := []struct {
+		name      string
+		coupon    *domain.Coupon
+		currency  string
+		used      int64
+		want      *domain.CouponQuote
+		wantErrIs error
+	}{
+		{
+			name:   "Happy case - percentage",
+			coupon: active(domain.Coupon{DiscountType: domain.DiscountTypePercentage, PercentOff: 25}),
+			want: &domain.CouponQuote{
+				OriginalPrice: domain.Money{Amount: 4999, Currency: "USD"},
+				Discount:      domain.Money{Amount: 1250, Currency: "USD"},
+				FinalPrice:    domain.Money{Amount: 3749, Currency: "USD"},
+			},
+		},
+		{
+			name:     "Happy case - fixed amount converted",
+			coupon:   active(domain.Coupon{DiscountType: domain.DiscountTypeFixed, AmountOff: domain.Money{Amount: 1000, Currency: "USD"}}),
+			currency: "KES",
+			want: &domain.CouponQuote{
+				OriginalPrice: domain.Money{Amount: 649870, Currency: "KES"},
+				Discount:      domain.Money{Amount: 130000, Currency: "KES"},
+				FinalPrice:    domain.Money{Amount: 519870, Currency: "KES"},
+			},
+		},
+		{
+			name:   "Happy case - discount capped at the price",
+			coupon: active(domain.Coupon{DiscountType: domain.DiscountTypeFixed, AmountOff: domain.Money{Amount: 10000, Currency: "USD"}}),
+			want: &domain.CouponQuote{
+				OriginalPrice: domain.Money{Amount: 4999, Currency: "USD"},
+				Discount:      domain.Money{Amount: 4999, Currency: "USD"},
+				FinalPrice:    domain.Money{Amount: 0, Currency: "USD"},
+			},
+		},
+		{
+			name: "Happy case - parent category",
+			coupon: active(domain.Coupon{
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   100,
+				Categories:   []*domain.CouponCategory{{CategoryUUID: parentUUID}},
+			}),
+			want: &domain.CouponQuote{
+				OriginalPrice: domain.Money{Amount: 4999, Currency: "USD"},
+				Discount:      domain.Money{Amount: 4999, Currency: "USD"},
+				FinalPrice:    domain.Money{Amount: 0, Currency: "USD"},
+			},
+		},
+		{
+			name: "Sad case - another course",
+			coupon: active(domain.Coupon{
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   10,
+				Courses:      []*domain.CouponCourse{{CourseUUID: gofakeit.UUID()}},
+			}),
+			wantErrIs: domain.ErrCouponNotApplied,
+		},
+		{
+			name:      "Sad case - expired",
+			coupon:    active(domain.Coupon{DiscountType: domain.DiscountTypePercentage, PercentOff: 10, ValidUntil: &yesterday}),
+			wantErrIs: domain.ErrCouponInactive,
+		},
+		{
+			name:      "Sad case - fully redeemed",
+			coupon:    active(domain.Coupon{DiscountType: domain.DiscountTypePercentage, PercentOff: 10, MaxRedemptions: 5, Redemptions: 5}),
+			wantErrIs: domain.ErrCouponExhausted,
+		},
+		{
+			name:      "Sad case - student already used it",
+			coupon:    active(domain.Coupon{DiscountType: domain.DiscountTypePercentage, PercentOff: 10, PerStudentLimit: 1}),
+			used:      1,
+			wantErrIs: domain.ErrCouponLimitReached,
+		},
+		{
+			name:      "Sad case - unknown code",
+			wantErrIs: domain.ErrCouponNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetCourseByUUID = func(ctx context.Context, uuid *string) (*domain.Course, error) {
+				return &domain.Course{AbstractBase: domain.AbstractBase{UUID: *uuid}, CategoryUUID: &childUUID}, nil
+			}
+			get.MockGetCategory = func(ctx context.Context, categoryUUID *string) (*domain.Category, error) {
+				if *categoryUUID == childUUID {
+					return &domain.Category{AbstractBase: domain.AbstractBase{UUID: childUUID}, ParentUUID: &parentUUID}, nil
+				}
+				return &domain.Category{AbstractBase: domain.AbstractBase{UUID: *categoryUUID}}, nil
+			}
+			get.MockGetCurrentCoursePrices = func(ctx context.Context, courseUUID *string, at time.Time) ([]*domain.CoursePrice, error) {
+				return []*domain.CoursePrice{{Price: domain.Money{Amount: 4999, Currency: "USD"}}}, nil
+			}
+			get.MockGetCouponByCode = func(ctx context.Context, code string) (*domain.Coupon, error) {
+				return tt.coupon, nil
+			}
+			get.MockCountCouponRedemptions = func(ctx context.Context, couponUUID, studentUUID *string) (int64, error) {
+				return tt.used, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, rates, testPayments)
+
+			quote, err := u.QuoteCoupon(ctx, "promo", &courseUUID, studentUUID, tt.currency)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Usecase.QuoteCoupon() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Usecase.QuoteCoupon() error = %v", err)
+			}
+			if quote.OriginalPrice != tt.want.OriginalPrice || quote.Discount != tt.want.Discount || quote.FinalPrice != tt.want.FinalPrice {
+				t.Errorf("Usecase.QuoteCoupon() = %+v, want %+v", quote, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsecase_AssignCourseToStudent_Coupon(t *testing.T) {
+	ctx := context.Background()
+	email := gofakeit.Email()
+	title := "Go 101"
+	studentUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name           string
+		code           string
+		coupon         *domain.Coupon
+		wantRedemption bool
+		wantErrIs      error
+	}{
+		{
+			name: "Happy case - no coupon",
+		},
+		{
+			name: "Happy case - coupon redeemed with the enrollment",
+			code: "HALF",
+			coupon: &domain.Coupon{
+				AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID(), Active: true},
+				Code:         "HALF",
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   50,
+			},
+			wantRedemption: true,
+		},
+		{
+			name: "Sad case - deactivated coupon",
+			code: "HALF",
+			coupon: &domain.Coupon{
+				AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()},
+				Code:         "HALF",
+				DiscountType: domain.DiscountTypePercentage,
+				PercentOff:   50,
+			},
+			wantErrIs: domain.ErrCouponInactive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetStudent = func(ctx context.Context, email *string) (*domain.Student, error) {
+				return &domain.Student{AbstractBase: domain.AbstractBase{UUID: studentUUID}, Email: *email}, nil
+			}
+			get.MockGetCourse = func(ctx context.Context, title *string) (*domain.Course, error) {
+				return &domain.Course{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Title: *title, Price: 40}, nil
+			}
+			get.MockGetCurrentCoursePrices = func(ctx context.Context, courseUUID *string, at time.Time) ([]*domain.CoursePrice, error) {
+				return []*domain.CoursePrice{{Price: domain.Money{Amount: 4000, Currency: "USD"}}}, nil
+			}
+			get.MockGetCouponByCode = func(ctx context.Context, code string) (*domain.Coupon, error) {
+				return tt.coupon, nil
+			}
+			create := mock.NewMockCreateRepository()
+			var redeemed *domain.CouponRedemption
+			assigned := false
+			create.MockAssignCourseToStudent = func(ctx context.Context, email, courseTitle, runUUID *string, redemption *domain.CouponRedemption) (*domain.Student, error) {
+				assigned = true
+				redeemed = redemption
+				return &domain.Student{Email: *email}, nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.AssignCourseToStudent(ctx, &email, &title, true, tt.code)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Usecase.AssignCourseToStudent() error = %v, want %v", err, tt.wantErrIs)
+				}
+				if assigned {
+					t.Errorf("expected the student not to be enrolled with a rejected coupon")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Usecase.AssignCourseToStudent() error = %v", err)
+			}
+			if (redeemed != nil) != tt.wantRedemption {
+				t.Fatalf("expected a redemption %v, got %+v", tt.wantRedemption, redeemed)
+			}
+			if tt.wantRedemption {
+				if redeemed.CouponUUID != tt.coupon.UUID || redeemed.StudentUUID != studentUUID {
+					t.Errorf("expected the redemption to link the coupon and the student, got %+v", redeemed)
+				}
+				if redeemed.FinalPrice.Amount != 2000 || redeemed.Discount.Amount != 2000 {
+					t.Errorf("expected half of USD 40.00 off, got %+v", redeemed)
+				}
+			}
+		})
+	}
+}
@@ -2,11 +2,26 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"time"
 
+	"github.com/MelvinKim/courses/application/common/dto"
 	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
+	"github.com/MelvinKim/courses/infrastructure/events"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	"github.com/MelvinKim/courses/infrastructure/payments"
 	"github.com/MelvinKim/courses/repository"
+	"github.com/MelvinKim/platform/validation"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
 )
 
 type UsecaseContract interface {
@@ -22,6 +37,8 @@ type UsecaseContract interface {
 		ctx context.Context,
 		email *string,
 		courseTitle *string,
+		override bool,
+		couponCode string,
 	) (*domain.Student, error)
 	GetStudent(
 		ctx context.Context,
@@ -31,12 +48,467 @@ type UsecaseContract interface {
 		ctx context.Context,
 		title *string,
 	) (*domain.Course, error)
+	ListStudents(
+		ctx context.Context,
+		query *domain.StudentQuery,
+	) ([]*domain.Student, error)
+	ListEnrollments(
+		ctx context.Context,
+		query *domain.EnrollmentQuery,
+	) ([]*domain.StudentCourse, error)
+	DeleteStudent(
+		ctx context.Context,
+		studentUUID *string,
+	) error
+	DeleteCourse(
+		ctx context.Context,
+		courseUUID *string,
+	) error
+	GetCourseCurriculum(
+		ctx context.Context,
+		courseUUID *string,
+	) (*domain.Course, error)
+	CreateModule(
+		ctx context.Context,
+		module *domain.Module,
+	) (*domain.Module, error)
+	UpdateModule(
+		ctx context.Context,
+		module *domain.Module,
+	) (*domain.Module, error)
+	DeleteModule(
+		ctx context.Context,
+		moduleUUID *string,
+	) error
+	ReorderModules(
+		ctx context.Context,
+		courseUUID *string,
+		moduleUUIDs []string,
+	) error
+	CreateLesson(
+		ctx context.Context,
+		lesson *domain.Lesson,
+	) (*domain.Lesson, error)
+	UpdateLesson(
+		ctx context.Context,
+		lesson *domain.Lesson,
+	) (*domain.Lesson, error)
+	DeleteLesson(
+		ctx context.Context,
+		lessonUUID *string,
+	) error
+	ReorderLessons(
+		ctx context.Context,
+		moduleUUID *string,
+		lessonUUIDs []string,
+	) error
+	RecordLessonProgress(
+		ctx context.Context,
+		progress *domain.LessonProgress,
+	) (*domain.LessonProgress, error)
+	GetStudentEnrollments(
+		ctx context.Context,
+		studentUUID *string,
+	) ([]*domain.Enrollment, error)
+	CreateQuiz(
+		ctx context.Context,
+		quiz *domain.Quiz,
+	) (*domain.Quiz, error)
+	GetQuiz(
+		ctx context.Context,
+		quizUUID *string,
+	) (*domain.Quiz, error)
+	SubmitAttempt(
+		ctx context.Context,
+		attempt *domain.Attempt,
+	) (*domain.Attempt, error)
+	IssueCertificate(
+		ctx context.Context,
+		studentUUID *string,
+		courseUUID *string,
+	) (*domain.Certificate, error)
+	VerifyCertificate(
+		ctx context.Context,
+		serial *string,
+	) (*domain.CertificateVerification, error)
+	RevokeCertificate(
+		ctx context.Context,
+		serial *string,
+		reason string,
+	) (*domain.Certificate, error)
+	RenderCertificatePDF(
+		ctx context.Context,
+		serial *string,
+		verifyURL string,
+	) ([]byte, error)
+	AddCoursePrerequisite(
+		ctx context.Context,
+		courseUUID *string,
+		prerequisiteUUID *string,
+	) error
+	RemoveCoursePrerequisite(
+		ctx context.Context,
+		courseUUID *string,
+		prerequisiteUUID *string,
+	) error
+	GetPrerequisiteTree(
+		ctx context.Context,
+		courseUUID *string,
+	) (*domain.PrerequisiteTree, error)
+	UnenrollStudent(
+		ctx context.Context,
+		principal *domain.Principal,
+		studentUUID *string,
+		courseUUID *string,
+	) error
+	GetWaitlist(
+		ctx context.Context,
+		courseUUID *string,
+	) ([]*domain.WaitlistEntry, error)
+	GetWaitlistPosition(
+		ctx context.Context,
+		courseUUID *string,
+		studentUUID *string,
+	) (*domain.WaitlistEntry, error)
+	LeaveWaitlist(
+		ctx context.Context,
+		courseUUID *string,
+		studentUUID *string,
+	) error
+	CreateCourseRun(
+		ctx context.Context,
+		run *domain.CourseRun,
+	) (*domain.CourseRun, error)
+	AddLiveSession(
+		ctx context.Context,
+		session *domain.LiveSession,
+	) (*domain.LiveSession, error)
+	GetCourseRun(
+		ctx context.Context,
+		runUUID *string,
+	) (*domain.CourseRun, error)
+	GetUpcomingCourseRuns(
+		ctx context.Context,
+		courseUUID *string,
+	) ([]*domain.CourseRun, error)
+	EnrollInRun(
+		ctx context.Context,
+		email *string,
+		runUUID *string,
+		override bool,
+		couponCode string,
+	) (*domain.Student, error)
+	IssueCalendarToken(
+		ctx context.Context,
+		principal *domain.Principal,
+		studentUUID *string,
+	) (*domain.CalendarSubscription, error)
+	GetStudentCalendar(
+		ctx context.Context,
+		studentUUID *string,
+		token string,
+	) ([]byte, error)
+	ReviewCourse(
+		ctx context.Context,
+		principal *domain.Principal,
+		review *domain.Review,
+	) (*domain.Review, error)
+	GetCourseReviews(
+		ctx context.Context,
+		courseUUID *string,
+		status domain.ReviewStatus,
+	) ([]*domain.Review, error)
+	ModerateReview(
+		ctx context.Context,
+		reviewUUID *string,
+		status domain.ReviewStatus,
+	) (*domain.Review, error)
+	ListCourses(
+		ctx context.Context,
+		sort domain.CourseSort,
+	) ([]*domain.Course, error)
+	SearchCourses(
+		ctx context.Context,
+		query *domain.CourseSearchQuery,
+	) (*domain.CourseSearchResults, error)
+	CreateCategory(
+		ctx context.Context,
+		category *domain.Category,
+	) (*domain.Category, error)
+	GetCategory(
+		ctx context.Context,
+		categoryUUID *string,
+	) (*domain.Category, error)
+	ListCategories(
+		ctx context.Context,
+	) ([]*domain.Category, error)
+	UpdateCategory(
+		ctx context.Context,
+		category *domain.Category,
+	) (*domain.Category, error)
+	DeleteCategory(
+		ctx context.Context,
+		categoryUUID *string,
+	) error
+	CreateInstructor(
+		ctx context.Context,
+		instructor *domain.Instructor,
+	) (*domain.Instructor, error)
+	GetInstructor(
+		ctx context.Context,
+		instructorUUID *string,
+	) (*domain.Instructor, error)
+	ListInstructors(
+		ctx context.Context,
+	) ([]*domain.Instructor, error)
+	UpdateInstructor(
+		ctx context.Context,
+		instructor *domain.Instructor,
+	) (*domain.Instructor, error)
+	DeleteInstructor(
+		ctx context.Context,
+		instructorUUID *string,
+	) error
+	SetCoursePrice(
+		ctx context.Context,
+		courseUUID *string,
+		price domain.Money,
+		effectiveFrom *time.Time,
+	) (*domain.CoursePrice, error)
+	GetCoursePrices(
+		ctx context.Context,
+		courseUUID *string,
+	) ([]*domain.CoursePrice, error)
+	GetCoursePriceHistory(
+		ctx context.Context,
+		courseUUID *string,
+		currency string,
+	) ([]*domain.CoursePrice, error)
+	LocalizeCoursePrice(
+		ctx context.Context,
+		course *domain.Course,
+		currency string,
+	) error
+	CreateCoupon(
+		ctx context.Context,
+		coupon *domain.Coupon,
+	) (*domain.Coupon, error)
+	GetCoupon(
+		ctx context.Context,
+		couponUUID *string,
+	) (*domain.Coupon, error)
+	ListCoupons(
+		ctx context.Context,
+	) ([]*domain.Coupon, error)
+	DeactivateCoupon(
+		ctx context.Context,
+		couponUUID *string,
+	) error
+	GetCouponUsage(
+		ctx context.Context,
+	) ([]*domain.CouponUsage, error)
+	QuoteCoupon(
+		ctx context.Context,
+		code string,
+		courseUUID *string,
+		studentUUID string,
+		currency string,
+	) (*domain.CouponQuote, error)
+	CreatePlan(
+		ctx context.Context,
+		plan *domain.Plan,
+	) (*domain.Plan, error)
+	ListPlans(
+		ctx context.Context,
+	) ([]*domain.Plan, error)
+	Subscribe(
+		ctx context.Context,
+		principal *domain.Principal,
+		studentUUID *string,
+		planUUID *string,
+		idempotencyKey string,
+	) (*domain.Subscription, error)
+	GetStudentSubscription(
+		ctx context.Context,
+		studentUUID *string,
+	) (*domain.Subscription, error)
+	GetSubscriptionCharges(
+		ctx context.Context,
+		subscriptionUUID *string,
+	) ([]*domain.SubscriptionCharge, error)
+	CancelSubscription(
+		ctx context.Context,
+		principal *domain.Principal,
+		subscriptionUUID *string,
+	) (*domain.Subscription, error)
+	RenewSubscriptions(
+		ctx context.Context,
+		now time.Time,
+	) (int, error)
+	RetryRefunds(
+		ctx context.Context,
+		now time.Time,
+	) (int, error)
+	IssueMissingInvoices(
+		ctx context.Context,
+		now time.Time,
+	) (int, error)
+	SetTaxRate(
+		ctx context.Context,
+		rate *domain.TaxRate,
+	) (*domain.TaxRate, error)
+	ListTaxRates(
+		ctx context.Context,
+	) ([]*domain.TaxRate, error)
+	ListStudentInvoices(
+		ctx context.Context,
+		principal *domain.Principal,
+		studentUUID *string,
+	) ([]*domain.Invoice, error)
+	GetInvoice(
+		ctx context.Context,
+		principal *domain.Principal,
+		invoiceUUID *string,
+	) (*domain.Invoice, error)
+	RenderReceiptPDF(
+		ctx context.Context,
+		principal *domain.Principal,
+		invoiceUUID *string,
+	) ([]byte, error)
+	RenderReceiptHTML(
+		ctx context.Context,
+		principal *domain.Principal,
+		invoiceUUID *string,
+	) ([]byte, error)
+	RefundPayment(
+		ctx context.Context,
+		chargeUUID *string,
+		refund *domain.Refund,
+	) (*domain.Refund, error)
+	GetPaymentRefunds(
+		ctx context.Context,
+		chargeUUID *string,
+	) ([]*domain.Refund, error)
+	GetLedgerBalances(
+		ctx context.Context,
+	) ([]*domain.LedgerBalance, error)
+	ListLedgerEntries(
+		ctx context.Context,
+		query domain.LedgerQuery,
+	) ([]*domain.LedgerEntry, error)
+	GetRevenueReport(
+		ctx context.Context,
+		query domain.RevenueQuery,
+	) ([]*domain.RevenueReportRow, error)
+	GetRevenueReconciliation(
+		ctx context.Context,
+		query domain.RevenueQuery,
+	) ([]*domain.RevenueReconciliation, error)
+	IssueAccessToken(
+		ctx context.Context,
+		role domain.Role,
+		subject string,
+		ttl time.Duration,
+	) (*domain.AccessToken, error)
+	Authenticate(
+		ctx context.Context,
+		token string,
+	) (*domain.Principal, error)
+	SetRevenueShare(
+		ctx context.Context,
+		share *domain.RevenueShare,
+	) (*domain.RevenueShare, error)
+	ListRevenueShares(
+		ctx context.Context,
+		principal *domain.Principal,
+		instructorUUID *string,
+	) ([]*domain.RevenueShare, error)
+	GeneratePayoutStatements(
+		ctx context.Context,
+		month string,
+	) ([]*domain.PayoutStatement, error)
+	ListPayoutStatements(
+		ctx context.Context,
+		instructorUUID string,
+		month string,
+	) ([]*domain.PayoutStatement, error)
+	GetPayoutStatement(
+		ctx context.Context,
+		principal *domain.Principal,
+		statementUUID *string,
+	) (*domain.PayoutStatement, error)
+	RenderPayoutStatementHTML(
+		ctx context.Context,
+		principal *domain.Principal,
+		statementUUID *string,
+	) ([]byte, error)
+	RenderPayoutStatementCSV(
+		ctx context.Context,
+		principal *domain.Principal,
+		statementUUID *string,
+	) ([]byte, error)
+	MarkPayoutPaid(
+		ctx context.Context,
+		statementUUID *string,
+		reference string,
+	) (*domain.PayoutStatement, error)
+	GetInstructorEarnings(
+		ctx context.Context,
+		principal *domain.Principal,
+		instructorUUID *string,
+	) (*domain.InstructorEarnings, error)
+	HandlePaymentWebhook(
+		ctx context.Context,
+		signature string,
+		body []byte,
+	) (*domain.WebhookEvent, error)
+	CreateImport(
+		ctx context.Context,
+		imp *domain.Import,
+		file io.Reader,
+	) (*domain.Import, error)
+	GetImport(
+		ctx context.Context,
+		importUUID *string,
+	) (*domain.Import, error)
+	GetImportErrorReport(
+		ctx context.Context,
+		importUUID *string,
+	) ([]byte, error)
+	ProcessImports(
+		ctx context.Context,
+		now func() time.Time,
+	) (int, error)
+	Export(
+		ctx context.Context,
+		query *domain.ExportQuery,
+		w io.Writer,
+	) (int, error)
 }
 
 // Usecase represents the Courses's service business logic
 type Usecase struct {
-	Create repository.CreateRepository
-	Get    repository.GetRepository
+	Create   repository.CreateRepository
+	Get      repository.GetRepository
+	Update   repository.UpdateRepository
+	Delete   repository.DeleteRepository
+	Events   events.Publisher
+	Signer   certificates.Signer
+	Search   repository.SearchIndex
+	Rates    fx.RateProvider
+	Payments payments.Provider
+
+	// RefundPolicy is how refunds of dropped courses are handled
+	RefundPolicy domain.RefundPolicy
+	// Webhooks verifies payment provider webhooks, which are refused until
+	// it is set
+	Webhooks *payments.WebhookVerifier
+	// AdminToken is a static token that authenticates as an admin, to hand
+	// out the first access tokens. Empty disables it
+	AdminToken string
+	// TokenSigner signs access tokens, with a key of its own rather than the
+	// certificates'. Until it is set only AdminToken authenticates
+	TokenSigner certificates.Signer
 }
 
 // Checkpreconditions asserts all pre-conditions are met
@@ -47,66 +519,114 @@ func (u *Usecase) Checkpreconditions() {
 	if u.Get == nil {
 		log.Panicf("courses usecase has not initialized a get repository")
 	}
+	if u.Update == nil {
+		log.Panicf("courses usecase has not initialized an update repository")
+	}
+	if u.Delete == nil {
+		log.Panicf("courses usecase has not initialized a delete repository")
+	}
+	if u.Events == nil {
+		log.Panicf("courses usecase has not initialized an events publisher")
+	}
+	if u.Signer == nil {
+		log.Panicf("courses usecase has not initialized a certificate signer")
+	}
+	if u.Search == nil {
+		log.Panicf("courses usecase has not initialized a search index")
+	}
+	if u.Rates == nil {
+		log.Panicf("courses usecase has not initialized an exchange rate provider")
+	}
+	if u.Payments == nil {
+		log.Panicf("courses usecase has not initialized a payment provider")
+	}
 }
 
 // NewUsecase creates a new usecase instance
 func NewUsecase(
 	create repository.CreateRepository,
 	get repository.GetRepository,
+	update repository.UpdateRepository,
+	delete repository.DeleteRepository,
+	publisher events.Publisher,
+	signer certificates.Signer,
+	search repository.SearchIndex,
+	rates fx.RateProvider,
+	provider payments.Provider,
 ) *Usecase {
 	uc := &Usecase{
-		Create: create,
-		Get:    get,
+		Create:   create,
+		Get:      get,
+		Update:   update,
+		Delete:   delete,
+		Events:   publisher,
+		Signer:   signer,
+		Search:   search,
+		Rates:    rates,
+		Payments: provider,
+
+		RefundPolicy: domain.DefaultRefundPolicy(),
 	}
 	uc.Checkpreconditions()
 	return uc
 }
 
-// CreateStudent creates a new sudocode student
+// CreateStudent creates a new sudocode student. The student is validated
+// here as well as by the handlers so that the CLI can't skip the rules
 func (u *Usecase) CreateStudent(
 	ctx context.Context,
 	student *domain.Student,
 ) (*domain.Student, error) {
-	if student.Email == "" {
-		return nil, fmt.Errorf("email can not be empty")
-	}
-	if student.FirstName == "" {
-		return nil, fmt.Errorf("first name can not be empty")
-	}
-	if student.LastName == "" {
-		return nil, fmt.Errorf("last name can not be empty")
+	if err := validation.Validate(&dto.StudentCreationPayload{
+		FirstName: student.FirstName,
+		LastName:  student.LastName,
+		Email:     student.Email,
+	}); err != nil {
+		return nil, err
 	}
 	return u.Create.CreateStudent(ctx, student)
 }
 
-// CreateCourse creates a new sudocode course
+// CreateCourse creates a new sudocode course, listed at its launch price in
+// the base currency
 func (u *Usecase) CreateCourse(
 	ctx context.Context,
 	course *domain.Course,
 ) (*domain.Course, error) {
-	if course.Description == "" {
-		return nil, fmt.Errorf("course's description can not be empty")
+	payload := &dto.CourseCreationPayload{
+		Title:       course.Title,
+		Price:       course.Price,
+		Description: course.Description,
+		Instructor:  course.Instructor,
+		Category:    course.Category,
+		Capacity:    course.Capacity,
 	}
-	if course.Instructor == "" {
-		return nil, fmt.Errorf("course's instructor can not be empty")
+	if course.InstructorUUID != nil {
+		payload.InstructorUUID = *course.InstructorUUID
 	}
-	if course.Price == 0 {
-		return nil, fmt.Errorf("course's price can not be zero")
+	if course.CategoryUUID != nil {
+		payload.CategoryUUID = *course.CategoryUUID
 	}
-	if course.Title == "" {
-		return nil, fmt.Errorf("course's title can not be empty")
+	if err := validation.Validate(payload); err != nil {
+		return nil, err
 	}
-	if course.Category == "" {
-		return nil, fmt.Errorf("course's category can not be empty")
+	if err := u.linkCatalog(ctx, course); err != nil {
+		return nil, err
 	}
 	return u.Create.CreateCourse(ctx, course)
 }
 
-// AssignCourseToStudent assign a student a course
+// AssignCourseToStudent assign a student a course. The student needs a
+// subscription that grants access, and must have completed the course's
+// prerequisites unless an admin overrides the check.
+// Students are put on the waitlist of full courses. A coupon code, if given,
+// is redeemed when the student is enrolled
 func (u *Usecase) AssignCourseToStudent(
 	ctx context.Context,
 	email *string,
 	courseTitle *string,
+	override bool,
+	couponCode string,
 ) (*domain.Student, error) {
 	if *email == "" {
 		return nil, fmt.Errorf("student's email can not be empty")
@@ -114,7 +634,50 @@ func (u *Usecase) AssignCourseToStudent(
 	if *courseTitle == "" {
 		return nil, fmt.Errorf("course's title can not be empty")
 	}
-	return u.Create.AssignCourseToStudent(ctx, email, courseTitle)
+	return u.assign(ctx, email, courseTitle, nil, override, couponCode)
+}
+
+// assign enrolls a student into a run of a course, or the course's evergreen
+// run when no run is given. Students put on the waitlist don't use up their
+// coupon
+func (u *Usecase) assign(
+	ctx context.Context,
+	email *string,
+	courseTitle *string,
+	runUUID *string,
+	override bool,
+	couponCode string,
+) (*domain.Student, error) {
+	if err := u.checkEligibility(ctx, email, courseTitle, override); err != nil {
+		return nil, err
+	}
+	redemption, err := u.checkoutRedemption(ctx, email, courseTitle, couponCode)
+	if err != nil {
+		return nil, err
+	}
+	student, err := u.Create.AssignCourseToStudent(ctx, email, courseTitle, runUUID, redemption)
+	if errors.Is(err, domain.ErrCourseFull) {
+		return nil, u.joinWaitlist(ctx, email, courseTitle, runUUID)
+	}
+	return student, err
+}
+
+// checkEligibility checks that a student may take a course: they need a
+// subscription that grants access and, unless overridden, to have completed
+// the course's prerequisites
+func (u *Usecase) checkEligibility(
+	ctx context.Context,
+	email *string,
+	courseTitle *string,
+	override bool,
+) error {
+	if err := u.checkSubscription(ctx, email); err != nil {
+		return err
+	}
+	if override {
+		return nil
+	}
+	return u.checkPrerequisites(ctx, email, courseTitle)
 }
 
 // GetStudent gets student ny their email address
@@ -138,3 +701,410 @@ func (u *Usecase) GetCourse(
 	}
 	return u.Get.GetCourse(ctx, title)
 }
+
+// pageLimit checks a page's offset and returns its limit, a hundred when none
+// is given and never more than a thousand
+func pageLimit(limit, offset int) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("offset can not be negative")
+	}
+	switch {
+	case limit < 0:
+		return 0, fmt.Errorf("limit can not be negative")
+	case limit == 0:
+		return defaultPageLimit, nil
+	case limit > maxPageLimit:
+		return maxPageLimit, nil
+	}
+	return limit, nil
+}
+
+// ListStudents pages through students in the order they signed up, a
+// hundred at a time unless asked otherwise
+func (u *Usecase) ListStudents(
+	ctx context.Context,
+	query *domain.StudentQuery,
+) ([]*domain.Student, error) {
+	if query == nil {
+		query = &domain.StudentQuery{}
+	}
+	limit, err := pageLimit(query.Limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit = limit
+	return u.Get.ListStudents(ctx, query)
+}
+
+// ListEnrollments pages through every student's enrollments in the order they
+// enrolled, a hundred at a time unless asked otherwise
+func (u *Usecase) ListEnrollments(
+	ctx context.Context,
+	query *domain.EnrollmentQuery,
+) ([]*domain.StudentCourse, error) {
+	if query == nil {
+		query = &domain.EnrollmentQuery{}
+	}
+	limit, err := pageLimit(query.Limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit = limit
+	return u.Get.ListEnrollments(ctx, query)
+}
+
+// DeleteStudent deletes a student who is no longer enrolled in any course and
+// whose subscription has ended
+func (u *Usecase) DeleteStudent(
+	ctx context.Context,
+	studentUUID *string,
+) error {
+	if studentUUID == nil || *studentUUID == "" {
+		return fmt.Errorf("student's UUID can not be empty")
+	}
+	return u.Delete.DeleteStudent(ctx, studentUUID)
+}
+
+// DeleteCourse deletes a course no student is enrolled in
+func (u *Usecase) DeleteCourse(
+	ctx context.Context,
+	courseUUID *string,
+) error {
+	if courseUUID == nil || *courseUUID == "" {
+		return fmt.Errorf("course's UUID can not be empty")
+	}
+	return u.Delete.DeleteCourse(ctx, courseUUID)
+}
+
+// GetCourseCurriculum returns a course's modules and lessons in order
+func (u *Usecase) GetCourseCurriculum(
+	ctx context.Context,
+	courseUUID *string,
+) (*domain.Course, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	return u.Get.GetCourseCurriculum(ctx, courseUUID)
+}
+
+// CreateModule adds a module to a course
+func (u *Usecase) CreateModule(
+	ctx context.Context,
+	module *domain.Module,
+) (*domain.Module, error) {
+	if module.CourseUUID == "" {
+		return nil, fmt.Errorf("module's course can not be empty")
+	}
+	if module.Title == "" {
+		return nil, fmt.Errorf("module's title can not be empty")
+	}
+	if module.Position < 0 {
+		return nil, fmt.Errorf("module's position can not be negative")
+	}
+	return u.Create.CreateModule(ctx, module)
+}
+
+// UpdateModule renames an existing module
+func (u *Usecase) UpdateModule(
+	ctx context.Context,
+	module *domain.Module,
+) (*domain.Module, error) {
+	if module.Title == "" {
+		return nil, fmt.Errorf("module's title can not be empty")
+	}
+	existing, err := u.Get.GetModule(ctx, &module.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, &domain.NotFoundError{Kind: "module", Key: module.UUID}
+	}
+	existing.Title = module.Title
+	return u.Update.UpdateModule(ctx, existing)
+}
+
+// DeleteModule removes a module and its lessons
+func (u *Usecase) DeleteModule(
+	ctx context.Context,
+	moduleUUID *string,
+) error {
+	if moduleUUID == nil || *moduleUUID == "" {
+		return fmt.Errorf("module's UUID can not be empty")
+	}
+	return u.Delete.DeleteModule(ctx, moduleUUID)
+}
+
+// ReorderModules sets the order of all of a course's modules
+func (u *Usecase) ReorderModules(
+	ctx context.Context,
+	courseUUID *string,
+	moduleUUIDs []string,
+) error {
+	if courseUUID == nil || *courseUUID == "" {
+		return fmt.Errorf("course's UUID can not be empty")
+	}
+	if err := checkUniqueOrder(moduleUUIDs); err != nil {
+		return err
+	}
+	return u.Update.ReorderModules(ctx, courseUUID, moduleUUIDs)
+}
+
+// CreateLesson adds a lesson to a module
+func (u *Usecase) CreateLesson(
+	ctx context.Context,
+	lesson *domain.Lesson,
+) (*domain.Lesson, error) {
+	if lesson.ModuleUUID == "" {
+		return nil, fmt.Errorf("lesson's module can not be empty")
+	}
+	if lesson.Position < 0 {
+		return nil, fmt.Errorf("lesson's position can not be negative")
+	}
+	if err := validateLesson(lesson); err != nil {
+		return nil, err
+	}
+	return u.Create.CreateLesson(ctx, lesson)
+}
+
+// UpdateLesson replaces an existing lesson's content
+func (u *Usecase) UpdateLesson(
+	ctx context.Context,
+	lesson *domain.Lesson,
+) (*domain.Lesson, error) {
+	if err := validateLesson(lesson); err != nil {
+		return nil, err
+	}
+	existing, err := u.Get.GetLesson(ctx, &lesson.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, &domain.NotFoundError{Kind: "lesson", Key: lesson.UUID}
+	}
+	existing.Title = lesson.Title
+	existing.Type = lesson.Type
+	existing.DurationMinutes = lesson.DurationMinutes
+	existing.ContentURL = lesson.ContentURL
+	existing.Body = lesson.Body
+	return u.Update.UpdateLesson(ctx, existing)
+}
+
+// DeleteLesson removes a lesson
+func (u *Usecase) DeleteLesson(
+	ctx context.Context,
+	lessonUUID *string,
+) error {
+	if lessonUUID == nil || *lessonUUID == "" {
+		return fmt.Errorf("lesson's UUID can not be empty")
+	}
+	return u.Delete.DeleteLesson(ctx, lessonUUID)
+}
+
+// ReorderLessons sets the order of all of a module's lessons
+func (u *Usecase) ReorderLessons(
+	ctx context.Context,
+	moduleUUID *string,
+	lessonUUIDs []string,
+) error {
+	if moduleUUID == nil || *moduleUUID == "" {
+		return fmt.Errorf("module's UUID can not be empty")
+	}
+	if err := checkUniqueOrder(lessonUUIDs); err != nil {
+		return err
+	}
+	return u.Update.ReorderLessons(ctx, moduleUUID, lessonUUIDs)
+}
+
+// RecordLessonProgress saves how far an enrolled student has got through a
+// lesson. Progress never moves back from completed, and completing the last
+// lesson of a course completes the student's enrollment
+func (u *Usecase) RecordLessonProgress(
+	ctx context.Context,
+	progress *domain.LessonProgress,
+) (*domain.LessonProgress, error) {
+	if progress.StudentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	if progress.LessonUUID == "" {
+		return nil, fmt.Errorf("lesson's UUID can not be empty")
+	}
+	if !progress.Status.IsValid() {
+		return nil, fmt.Errorf("progress status %q is not supported", progress.Status)
+	}
+
+	enrollment, err := u.enrollmentForLesson(ctx, &progress.StudentUUID, &progress.LessonUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := u.Get.GetLessonProgress(ctx, &progress.StudentUUID, &progress.LessonUUID)
+	if err != nil {
+		return nil, err
+	}
+	record := mergeProgress(existing, progress, time.Now())
+	saved, err := u.Update.SaveLessonProgress(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if saved.Status == domain.ProgressStatusCompleted && enrollment.CompletedAt == nil {
+		if err := u.completeCourseIfDone(ctx, &progress.StudentUUID, &enrollment.CourseUUID); err != nil {
+			return nil, err
+		}
+	}
+	return saved, nil
+}
+
+// enrollmentForLesson returns the student's enrollment in the course that
+// the lesson belongs to, failing when the student is not enrolled
+func (u *Usecase) enrollmentForLesson(
+	ctx context.Context,
+	studentUUID *string,
+	lessonUUID *string,
+) (*domain.StudentCourse, error) {
+	lesson, err := u.Get.GetLesson(ctx, lessonUUID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil {
+		return nil, &domain.NotFoundError{Kind: "lesson", Key: *lessonUUID}
+	}
+	module, err := u.Get.GetModule(ctx, &lesson.ModuleUUID)
+	if err != nil {
+		return nil, err
+	}
+	if module == nil {
+		return nil, &domain.NotFoundError{Kind: "module", Key: lesson.ModuleUUID}
+	}
+	enrollment, err := u.Get.GetEnrollment(ctx, studentUUID, &module.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, fmt.Errorf("student %s is not enrolled in course %s", *studentUUID, module.CourseUUID)
+	}
+	return enrollment, nil
+}
+
+// mergeProgress applies an update on top of the stored progress, stamping
+// the start and completion times the first time they are reached
+func mergeProgress(
+	existing *domain.LessonProgress,
+	update *domain.LessonProgress,
+	now time.Time,
+) *domain.LessonProgress {
+	record := existing
+	if record == nil {
+		record = &domain.LessonProgress{
+			StudentUUID: update.StudentUUID,
+			LessonUUID:  update.LessonUUID,
+			Status:      domain.ProgressStatusNotStarted,
+		}
+	}
+	record.LastPositionSeconds = update.LastPositionSeconds
+	if record.Status == domain.ProgressStatusCompleted {
+		return record
+	}
+
+	record.Status = update.Status
+	if record.Status != domain.ProgressStatusNotStarted && record.StartedAt == nil {
+		record.StartedAt = &now
+	}
+	if record.Status == domain.ProgressStatusCompleted {
+		record.CompletedAt = &now
+	}
+	return record
+}
+
+// completeCourseIfDone completes the enrollment and raises a CourseCompleted
+// event once every lesson of the course is completed
+func (u *Usecase) completeCourseIfDone(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) error {
+	completed, total, err := u.Get.GetCourseCompletion(ctx, studentUUID, courseUUID)
+	if err != nil {
+		return err
+	}
+	if completionPercentage(completed, total) < 100 {
+		return nil
+	}
+
+	now := time.Now()
+	if err := u.Update.CompleteEnrollment(ctx, studentUUID, courseUUID, now); err != nil {
+		return err
+	}
+	err = u.Events.Publish(ctx, domain.CourseCompleted{
+		StudentUUID: *studentUUID,
+		CourseUUID:  *courseUUID,
+		CompletedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = u.IssueCertificate(ctx, studentUUID, courseUUID)
+	return err
+}
+
+// GetStudentEnrollments lists a student's courses with their completion
+func (u *Usecase) GetStudentEnrollments(
+	ctx context.Context,
+	studentUUID *string,
+) ([]*domain.Enrollment, error) {
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	enrollments, err := u.Get.GetStudentEnrollments(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	for _, enrollment := range enrollments {
+		completed, total, err := u.Get.GetCourseCompletion(ctx, studentUUID, &enrollment.Course.UUID)
+		if err != nil {
+			return nil, err
+		}
+		enrollment.CompletionPercentage = completionPercentage(completed, total)
+	}
+	return enrollments, nil
+}
+
+// completionPercentage rounds the share of completed lessons to two decimals
+func completionPercentage(completed, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(completed)/float64(total)*10000) / 100
+}
+
+// validateLesson checks that a lesson carries the content its type needs
+func validateLesson(lesson *domain.Lesson) error {
+	if lesson.Title == "" {
+		return fmt.Errorf("lesson's title can not be empty")
+	}
+	if !lesson.Type.IsValid() {
+		return fmt.Errorf("lesson's type %q is not supported", lesson.Type)
+	}
+	if lesson.Type == domain.LessonTypeVideo && lesson.ContentURL == "" {
+		return fmt.Errorf("video lessons must have a content URL")
+	}
+	if lesson.Type == domain.LessonTypeText && lesson.Body == "" && lesson.ContentURL == "" {
+		return fmt.Errorf("text lessons must have a body or a content URL")
+	}
+	return nil
+}
+
+// checkUniqueOrder ensures a new ordering lists every item exactly once
+func checkUniqueOrder(uuids []string) error {
+	if len(uuids) == 0 {
+		return fmt.Errorf("the new order can not be empty")
+	}
+	seen := make(map[string]bool, len(uuids))
+	for _, id := range uuids {
+		if seen[id] {
+			return fmt.Errorf("%s appears more than once in the new order", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
@@ -2,18 +2,27 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/MelvinKim/courses/domain"
 	"github.com/MelvinKim/courses/infrastructure/database"
+	"github.com/MelvinKim/courses/infrastructure/events"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+	"github.com/MelvinKim/courses/repository/mock"
 	course "github.com/MelvinKim/courses/usecase"
+	"github.com/MelvinKim/platform/validation"
 	"github.com/brianvoe/gofakeit/v6"
 )
 
 func newTestUsecase() *course.Usecase {
 	create := database.NewPostgresDB()
 	get := database.NewPostgresDB()
-	u := course.NewUsecase(create, get)
+	update := database.NewPostgresDB()
+	delete := database.NewPostgresDB()
+	u := course.NewUsecase(create, get, update, delete, events.NewLogPublisher(), testSigner, get, fx.NewStaticRates(), payments.NewLogProvider())
 	return u
 }
 
@@ -79,11 +88,30 @@ func TestUsecase_CreateStudent(t *testing.T) {
 	}
 }
 
+func TestUsecase_CreateStudent_Validation(t *testing.T) {
+	create := mock.NewMockCreateRepository()
+	reached := false
+	create.MockCreateStudent = func(ctx context.Context, student *domain.Student) (*domain.Student, error) {
+		reached = true
+		return student, nil
+	}
+	u := course.NewUsecase(create, mock.NewMockGetRepository(), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	_, err := u.CreateStudent(context.Background(), &domain.Student{FirstName: "Ada", LastName: "Lovelace", Email: "not an email"})
+	var violations validation.ValidationErrors
+	if !errors.As(err, &violations) || len(violations) != 1 || violations[0].Field != "email" {
+		t.Fatalf("expected the email to fail validation, got %v", err)
+	}
+	if reached {
+		t.Errorf("expected an invalid student not to be saved")
+	}
+}
+
 func TestUsecase_CreateCourse(t *testing.T) {
 	u := newTestUsecase()
 	ctx := context.Background()
 	course := &domain.Course{
-		Title:       gofakeit.LastName(),
+		Title:       gofakeit.Name(),
 		Price:       23,
 		Description: gofakeit.Address().City,
 		Instructor:  gofakeit.Name(),
@@ -157,8 +185,18 @@ func TestUsecase_AssignCourseToStudent(t *testing.T) {
 	if err != nil {
 		t.Errorf("error while creating test student, err: %v", err)
 	}
+	plan, err := u.CreatePlan(ctx, &domain.Plan{
+		Name:  gofakeit.UUID(),
+		Price: domain.Money{Amount: 9900, Currency: domain.BaseCurrency},
+	})
+	if err != nil {
+		t.Errorf("error while creating test plan, err: %v", err)
+	}
+	if _, err := u.Subscribe(ctx, testAdmin, &student.UUID, &plan.UUID, ""); err != nil {
+		t.Errorf("error while subscribing test student, err: %v", err)
+	}
 	course := &domain.Course{
-		Title:       gofakeit.LastName(),
+		Title:       gofakeit.Name(),
 		Price:       23,
 		Description: gofakeit.Address().City,
 		Instructor:  gofakeit.Name(),
@@ -202,7 +240,7 @@ func TestUsecase_AssignCourseToStudent(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			student, err := u.AssignCourseToStudent(tt.args.ctx, tt.args.email, tt.args.courseTitle)
+			student, err := u.AssignCourseToStudent(tt.args.ctx, tt.args.email, tt.args.courseTitle, false, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Usecase.AssignCourseToStudent() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -221,3 +259,71 @@ func TestUsecase_AssignCourseToStudent(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_ListStudents(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		query      *domain.StudentQuery
+		wantLimit  int
+		wantOffset int
+		wantErr    bool
+	}{
+		{
+			name:      "Happy case - default limit",
+			wantLimit: 100,
+		},
+		{
+			name:       "Happy case - limit is capped",
+			query:      &domain.StudentQuery{Limit: 5000, Offset: 2000},
+			wantLimit:  1000,
+			wantOffset: 2000,
+		},
+		{
+			name:    "Sad case - negative offset",
+			query:   &domain.StudentQuery{Offset: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			var queried *domain.StudentQuery
+			get.MockListStudents = func(ctx context.Context, query *domain.StudentQuery) ([]*domain.Student, error) {
+				queried = query
+				return []*domain.Student{}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.ListStudents(ctx, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.ListStudents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (queried.Limit != tt.wantLimit || queried.Offset != tt.wantOffset) {
+				t.Errorf("expected limit %d and offset %d, got %+v", tt.wantLimit, tt.wantOffset, queried)
+			}
+		})
+	}
+}
+
+func TestUsecase_DeleteStudent(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	empty := ""
+
+	remove := mock.NewMockDeleteRepository()
+	var deleted string
+	remove.MockDeleteStudent = func(ctx context.Context, uuid *string) error {
+		deleted = *uuid
+		return nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), mock.NewMockGetRepository(), mock.NewMockUpdateRepository(), remove, eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	if err := u.DeleteStudent(ctx, &empty); err == nil {
+		t.Errorf("expected an error deleting a student without a UUID")
+	}
+	if err := u.DeleteStudent(ctx, &studentUUID); err != nil || deleted != studentUUID {
+		t.Errorf("expected student %s to be deleted, got %q, error %v", studentUUID, deleted, err)
+	}
+}
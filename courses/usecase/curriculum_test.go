@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/certificates"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	paymentsmock "github.com/MelvinKim/courses/infrastructure/payments/mock"
+	"github.com/MelvinKim/courses/infrastructure/search"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+var (
+	mockCreate    = mock.NewMockCreateRepository()
+	mockGet       = mock.NewMockGetRepository()
+	mockUpdate    = mock.NewMockUpdateRepository()
+	mockDelete    = mock.NewMockDeleteRepository()
+	mockEvents    = eventsmock.NewMockPublisher()
+	testSigner, _ = certificates.NewEd25519Signer(make([]byte, 32))
+	// testTokenSigner signs access tokens with a key other than testSigner's
+	testTokenSigner, _ = certificates.NewEd25519Signer(bytes.Repeat([]byte{1}, 32))
+	testSearch         = search.NewMemoryIndex()
+	testRates          = fx.NewStaticRates()
+	testPayments       = paymentsmock.NewMockProvider()
+	testAdmin          = &domain.Principal{Role: domain.RoleAdmin}
+)
+
+// newMockTestUsecase initializes a Usecase backed by the mock repositories
+func newMockTestUsecase() *course.Usecase {
+	return course.NewUsecase(mockCreate, mockGet, mockUpdate, mockDelete, mockEvents, testSigner, testSearch, testRates, testPayments)
+}
+
+func TestUsecase_CreateLesson(t *testing.T) {
+	u := newMockTestUsecase()
+	ctx := context.Background()
+
+	type args struct {
+		ctx    context.Context
+		lesson *domain.Lesson
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Happy case - video lesson",
+			args: args{
+				ctx: ctx,
+				lesson: &domain.Lesson{
+					ModuleUUID: gofakeit.UUID(),
+					Title:      gofakeit.Sentence(3),
+					Type:       domain.LessonTypeVideo,
+					ContentURL: gofakeit.URL(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Happy case - text lesson",
+			args: args{
+				ctx: ctx,
+				lesson: &domain.Lesson{
+					ModuleUUID: gofakeit.UUID(),
+					Title:      gofakeit.Sentence(3),
+					Type:       domain.LessonTypeText,
+					Body:       gofakeit.Paragraph(1, 2, 10, " "),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Sad case - video lesson without content URL",
+			args: args{
+				ctx: ctx,
+				lesson: &domain.Lesson{
+					ModuleUUID: gofakeit.UUID(),
+					Title:      gofakeit.Sentence(3),
+					Type:       domain.LessonTypeVideo,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - unsupported type",
+			args: args{
+				ctx: ctx,
+				lesson: &domain.Lesson{
+					ModuleUUID: gofakeit.UUID(),
+					Title:      gofakeit.Sentence(3),
+					Type:       domain.LessonType("podcast"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - missing module",
+			args: args{
+				ctx: ctx,
+				lesson: &domain.Lesson{
+					Title: gofakeit.Sentence(3),
+					Type:  domain.LessonTypeQuiz,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.CreateLesson(tt.args.ctx, tt.args.lesson)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateLesson() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestUsecase_ReorderModules(t *testing.T) {
+	u := newMockTestUsecase()
+	ctx := context.Background()
+	courseUUID := gofakeit.UUID()
+	first, second := gofakeit.UUID(), gofakeit.UUID()
+
+	type args struct {
+		ctx         context.Context
+		courseUUID  *string
+		moduleUUIDs []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Happy case",
+			args: args{
+				ctx:         ctx,
+				courseUUID:  &courseUUID,
+				moduleUUIDs: []string{second, first},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Sad case - duplicate module",
+			args: args{
+				ctx:         ctx,
+				courseUUID:  &courseUUID,
+				moduleUUIDs: []string{first, first},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - empty order",
+			args: args{
+				ctx:        ctx,
+				courseUUID: &courseUUID,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := u.ReorderModules(tt.args.ctx, tt.args.courseUUID, tt.args.moduleUUIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.ReorderModules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/exports"
+)
+
+// exportPageSize is how many rows an export reads at a time, and so about
+// how many it holds in memory, Parquet row groups aside
+const exportPageSize = 1000
+
+// Export writes the rows of a table picked by the query to w in the query's
+// format, a page at a time, and returns how many rows it wrote. The query is
+// checked before anything is written, so an error with nothing written means
+// the query was refused
+func (u *Usecase) Export(
+	ctx context.Context,
+	query *domain.ExportQuery,
+	w io.Writer,
+) (int, error) {
+	table, ok := exports.Tables[query.Table]
+	if !ok {
+		return 0, fmt.Errorf("unknown table %q, export %s, %s or %s", query.Table,
+			domain.ExportTableStudents, domain.ExportTableCourses, domain.ExportTableEnrollments)
+	}
+	columns, err := table.Select(query.Columns)
+	if err != nil {
+		return 0, err
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return 0, fmt.Errorf("from must be before to")
+	}
+	writer, err := exports.NewWriter(query.Format, w, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for offset := 0; ; offset += exportPageSize {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		rows, err := u.exportPage(ctx, query, offset)
+		if err != nil {
+			return written, err
+		}
+		for _, row := range rows {
+			if err := writer.Write(row); err != nil {
+				return written, fmt.Errorf("can't write %s: %w", query.Table, err)
+			}
+		}
+		written += len(rows)
+		if len(rows) < exportPageSize {
+			break
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return written, fmt.Errorf("can't finish export: %w", err)
+	}
+	return written, nil
+}
+
+// exportPage reads a page of the query's table
+func (u *Usecase) exportPage(
+	ctx context.Context,
+	query *domain.ExportQuery,
+	offset int,
+) ([]interface{}, error) {
+	var rows []interface{}
+	switch query.Table {
+	case domain.ExportTableStudents:
+		students, err := u.Get.ListStudents(ctx, &domain.StudentQuery{
+			From: query.From, To: query.To, Limit: exportPageSize, Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, student := range students {
+			rows = append(rows, student)
+		}
+	case domain.ExportTableCourses:
+		courses, err := u.Get.PageCourses(ctx, &domain.CourseQuery{
+			From: query.From, To: query.To, Limit: exportPageSize, Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, course := range courses {
+			rows = append(rows, course)
+		}
+	case domain.ExportTableEnrollments:
+		enrollments, err := u.Get.ListEnrollments(ctx, &domain.EnrollmentQuery{
+			From: query.From, To: query.To, Limit: exportPageSize, Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, enrollment := range enrollments {
+			rows = append(rows, enrollment)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+)
+
+func TestUsecase_Export(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	students := make([]*domain.Student, 1500)
+	for i := range students {
+		students[i] = &domain.Student{AbstractBase: domain.AbstractBase{UUID: fmt.Sprint(i)}, Email: fmt.Sprintf("student%d@example.com", i)}
+	}
+
+	get := mock.NewMockGetRepository()
+	var queries []*domain.StudentQuery
+	get.MockListStudents = func(ctx context.Context, query *domain.StudentQuery) ([]*domain.Student, error) {
+		queries = append(queries, query)
+		end := query.Offset + query.Limit
+		if end > len(students) {
+			end = len(students)
+		}
+		return students[query.Offset:end], nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	var buf bytes.Buffer
+	written, err := u.Export(ctx, &domain.ExportQuery{
+		Table:   domain.ExportTableStudents,
+		Format:  domain.ExportFormatCSV,
+		Columns: []string{"email", "uuid"},
+		From:    &from,
+		To:      &to,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("Usecase.Export() error = %v", err)
+	}
+	if written != len(students) {
+		t.Errorf("expected %d rows written, got %d", len(students), written)
+	}
+	if len(queries) != 2 || queries[1].Offset != queries[0].Limit {
+		t.Fatalf("expected the students to be read in two pages, got %d", len(queries))
+	}
+	if queries[0].From != &from || queries[0].To != &to {
+		t.Errorf("expected the period to be passed on")
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV: %v", err)
+	}
+	if len(records) != len(students)+1 || records[0][0] != "email" || records[1500][1] != "1499" {
+		t.Errorf("expected a header and every student, got %d records", len(records))
+	}
+}
+
+func TestUsecase_Export_Refused(t *testing.T) {
+	ctx := context.Background()
+	from := time.Now()
+	to := from.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name  string
+		query *domain.ExportQuery
+	}{
+		{
+			name:  "Sad case - unknown table",
+			query: &domain.ExportQuery{Table: "payments", Format: domain.ExportFormatCSV},
+		},
+		{
+			name:  "Sad case - unknown column",
+			query: &domain.ExportQuery{Table: domain.ExportTableCourses, Format: domain.ExportFormatCSV, Columns: []string{"secret"}},
+		},
+		{
+			name:  "Sad case - unknown format",
+			query: &domain.ExportQuery{Table: domain.ExportTableEnrollments, Format: "xlsx"},
+		},
+		{
+			name:  "Sad case - period ends before it starts",
+			query: &domain.ExportQuery{Table: domain.ExportTableEnrollments, Format: domain.ExportFormatNDJSON, From: &from, To: &to},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			read := false
+			get.MockPageCourses = func(ctx context.Context, query *domain.CourseQuery) ([]*domain.Course, error) {
+				read = true
+				return nil, nil
+			}
+			get.MockListEnrollments = func(ctx context.Context, query *domain.EnrollmentQuery) ([]*domain.StudentCourse, error) {
+				read = true
+				return nil, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			var buf bytes.Buffer
+			if _, err := u.Export(ctx, tt.query, &buf); err == nil {
+				t.Fatalf("expected the export to be refused")
+			}
+			if read || buf.Len() > 0 {
+				t.Errorf("expected nothing to be read or written")
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/application/common/dto"
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/imports"
+	"github.com/MelvinKim/courses/repository"
+	"github.com/MelvinKim/platform/validation"
+)
+
+const (
+	// importBatchSize is how many rows of an import are processed between
+	// saves, and so how many are redone at most when processing is cut short
+	importBatchSize = 100
+	// importLease is how long a worker holds an import without saving a
+	// batch before another worker may take it over
+	importLease = 2 * time.Minute
+)
+
+// CreateImport stores an import file's rows for processing in the background.
+// Rows that are invalid on their own, without an email address or a name,
+// are failed straight away
+func (u *Usecase) CreateImport(
+	ctx context.Context,
+	imp *domain.Import,
+	file io.Reader,
+) (*domain.Import, error) {
+	if imp.Format != domain.ImportFormatCSV && imp.Format != domain.ImportFormatNDJSON {
+		return nil, fmt.Errorf("imports must be %s or %s, not %q", domain.ImportFormatCSV, domain.ImportFormatNDJSON, imp.Format)
+	}
+	if imp.PlanUUID != nil && *imp.PlanUUID != "" {
+		plan, err := u.Get.GetPlan(ctx, imp.PlanUUID)
+		if err != nil {
+			return nil, err
+		}
+		if plan == nil {
+			return nil, &domain.NotFoundError{Kind: "plan", Key: *imp.PlanUUID}
+		}
+	} else {
+		imp.PlanUUID = nil
+	}
+	rows, err := imports.Parse(imp.Format, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file has no rows")
+	}
+	for _, row := range rows {
+		row.Normalize()
+		if row.Status == domain.ImportRowFailed {
+			continue
+		}
+		row.Status = domain.ImportRowPending
+		if err := validateImportRow(row); err != nil {
+			row.Status, row.Message = domain.ImportRowFailed, err.Error()
+		}
+	}
+	imp.Status = domain.ImportStatusPending
+	imp.TotalRows = len(rows)
+	imp.ProcessedRows, imp.SucceededRows, imp.FailedRows = 0, 0, 0
+	return u.Create.CreateImport(ctx, imp, rows)
+}
+
+// validateImportRow checks what can be checked of a row without looking
+// anything up, by the same rules as a student created through the API
+func validateImportRow(row *domain.ImportRow) error {
+	return validation.Validate(&dto.StudentCreationPayload{
+		FirstName: row.FirstName,
+		LastName:  row.LastName,
+		Email:     row.Email,
+	})
+}
+
+// GetImport returns an import and how far it has got
+func (u *Usecase) GetImport(
+	ctx context.Context,
+	importUUID *string,
+) (*domain.Import, error) {
+	if importUUID == nil || *importUUID == "" {
+		return nil, fmt.Errorf("import's UUID can not be empty")
+	}
+	return u.Get.GetImport(ctx, importUUID)
+}
+
+// GetImportErrorReport renders an import's failed rows as CSV. It returns nil
+// when the import doesn't exist
+func (u *Usecase) GetImportErrorReport(
+	ctx context.Context,
+	importUUID *string,
+) ([]byte, error) {
+	imp, err := u.GetImport(ctx, importUUID)
+	if err != nil || imp == nil {
+		return nil, err
+	}
+	rows, err := u.Get.GetImportRows(ctx, importUUID, domain.ImportRowFailed, 0)
+	if err != nil {
+		return nil, err
+	}
+	return imports.RenderErrorReport(rows)
+}
+
+// ProcessImports works through unfinished imports a batch of rows at a time
+// until none is left or the context is done, and returns how many rows it
+// processed. Imports interrupted earlier carry on with the rows left
+func (u *Usecase) ProcessImports(
+	ctx context.Context,
+	now func() time.Time,
+) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		imp, err := u.Update.ClaimImport(ctx, now(), importLease)
+		if err != nil || imp == nil {
+			return processed, err
+		}
+		n, err := u.processImport(ctx, imp, now)
+		processed += n
+		if err != nil {
+			return processed, fmt.Errorf("import %s: %w", imp.UUID, err)
+		}
+	}
+	return processed, nil
+}
+
+func (u *Usecase) processImport(
+	ctx context.Context,
+	imp *domain.Import,
+	now func() time.Time,
+) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		rows, err := u.Get.GetImportRows(ctx, &imp.UUID, domain.ImportRowPending, importBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		for _, row := range rows {
+			if err := u.importRow(ctx, imp, row); err != nil {
+				// the batch isn't saved, so its rows are processed again
+				// once the lease runs out
+				return processed, err
+			}
+		}
+		imp, err = u.Update.SaveImportRows(ctx, imp, rows, now(), importLease)
+		if err != nil {
+			return processed, err
+		}
+		processed += len(rows)
+		if imp.Status == domain.ImportStatusCompleted {
+			break
+		}
+	}
+	return processed, nil
+}
+
+// importRow creates a row's student and enrolls them in the row's courses,
+// subscribing them to the import's plan when they have no subscription.
+// Students and enrollments that already exist are left alone, so a row can
+// be processed again safely. Courses that are full put the student on the
+// waitlist. The row fails at the first course that can't be assigned, and on
+// a dry run it only checks that the courses exist. Database errors aren't
+// the row's fault, so they are returned for the row to be retried rather than
+// failing it
+func (u *Usecase) importRow(
+	ctx context.Context,
+	imp *domain.Import,
+	row *domain.ImportRow,
+) error {
+	notes, err := u.applyImportRow(ctx, imp, row)
+	if errors.Is(err, repository.ErrStorage) || ctx.Err() != nil {
+		return err
+	}
+	if err != nil {
+		row.Status, row.Message = domain.ImportRowFailed, err.Error()
+		return nil
+	}
+	row.Status, row.Message = domain.ImportRowSucceeded, strings.Join(notes, "; ")
+	return nil
+}
+
+func (u *Usecase) applyImportRow(
+	ctx context.Context,
+	imp *domain.Import,
+	row *domain.ImportRow,
+) ([]string, error) {
+	notes := []string{}
+	student, err := u.Get.GetStudent(ctx, &row.Email)
+	if err != nil {
+		return nil, err
+	}
+	courses := make([]*domain.Course, 0, len(row.Courses))
+	for _, title := range row.Courses {
+		title := title
+		course, err := u.Get.GetCourse(ctx, &title)
+		if err != nil {
+			return nil, err
+		}
+		if course == nil {
+			return nil, fmt.Errorf("course %q does not exist", title)
+		}
+		courses = append(courses, course)
+	}
+
+	if imp.DryRun {
+		if student == nil {
+			notes = append(notes, "student would be created")
+		}
+		if len(courses) > 0 {
+			notes = append(notes, fmt.Sprintf("would be enrolled in %d courses", len(courses)))
+		}
+		return notes, nil
+	}
+
+	if student == nil {
+		student, err = u.CreateStudent(ctx, &domain.Student{
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+			Email:     row.Email,
+		})
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, "student created")
+	}
+	if imp.PlanUUID != nil && len(courses) > 0 {
+		subscription, err := u.Get.GetStudentSubscription(ctx, &student.UUID)
+		if err != nil {
+			return nil, err
+		}
+		if subscription == nil || subscription.Status == domain.SubscriptionStatusIncomplete {
+			// keyed by the row, so a row retried after an interruption picks
+			// its signup back up rather than charging the student again
+			key := fmt.Sprintf("import:%s:%s", imp.UUID, row.UUID)
+			if _, err := u.subscribe(ctx, &student.UUID, imp.PlanUUID, key); err != nil {
+				return nil, fmt.Errorf("can't subscribe student: %w", err)
+			}
+			notes = append(notes, "subscribed")
+		}
+	}
+	for _, course := range courses {
+		enrollment, err := u.Get.GetEnrollment(ctx, &student.UUID, &course.UUID)
+		if err != nil {
+			return nil, err
+		}
+		if enrollment != nil {
+			continue
+		}
+		_, err = u.AssignCourseToStudent(ctx, &student.Email, &course.Title, imp.Override, "")
+		var waitlisted *domain.WaitlistedError
+		if errors.As(err, &waitlisted) {
+			notes = append(notes, fmt.Sprintf("waitlisted for %s", course.Title))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("course %q: %w", course.Title, err)
+		}
+		notes = append(notes, fmt.Sprintf("enrolled in %s", course.Title))
+	}
+	return notes, nil
+}
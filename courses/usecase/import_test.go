@@ -0,0 +1,270 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_CreateImport(t *testing.T) {
+	ctx := context.Background()
+	planUUID := gofakeit.UUID()
+	missingPlan := gofakeit.UUID()
+	file := "email,first_name,last_name,courses\n" +
+		"jane@example.com,Jane,Doe,Go 101\n" +
+		"not-an-email,Tom,Kariuki,\n" +
+		"amina@example.com,,Hassan,Go 101; ;Docker\n"
+
+	tests := []struct {
+		name       string
+		imp        *domain.Import
+		file       string
+		wantStatus []domain.ImportRowStatus
+		wantErr    bool
+	}{
+		{
+			name:       "Happy case - invalid rows fail straight away",
+			imp:        &domain.Import{Format: domain.ImportFormatCSV, PlanUUID: &planUUID},
+			file:       file,
+			wantStatus: []domain.ImportRowStatus{domain.ImportRowPending, domain.ImportRowFailed, domain.ImportRowFailed},
+		},
+		{
+			name:    "Sad case - unknown format",
+			imp:     &domain.Import{Format: "xlsx"},
+			file:    file,
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - plan does not exist",
+			imp:     &domain.Import{Format: domain.ImportFormatCSV, PlanUUID: &missingPlan},
+			file:    file,
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - no rows",
+			imp:     &domain.Import{Format: domain.ImportFormatCSV},
+			file:    "email,first_name,last_name\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetPlan = func(ctx context.Context, uuid *string) (*domain.Plan, error) {
+				if *uuid != planUUID {
+					return nil, nil
+				}
+				return &domain.Plan{AbstractBase: domain.AbstractBase{UUID: planUUID}}, nil
+			}
+			create := mock.NewMockCreateRepository()
+			var stored []*domain.ImportRow
+			create.MockCreateImport = func(ctx context.Context, imp *domain.Import, rows []*domain.ImportRow) (*domain.Import, error) {
+				stored = rows
+				return imp, nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			imp, err := u.CreateImport(ctx, tt.imp, strings.NewReader(tt.file))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.CreateImport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if stored != nil {
+					t.Errorf("expected nothing to be stored")
+				}
+				return
+			}
+			if imp.Status != domain.ImportStatusPending || imp.TotalRows != len(tt.wantStatus) {
+				t.Errorf("expected a pending import of %d rows, got %s with %d", len(tt.wantStatus), imp.Status, imp.TotalRows)
+			}
+			for i, row := range stored {
+				if row.Status != tt.wantStatus[i] {
+					t.Errorf("expected line %d to be %s, got %s (%s)", row.Line, tt.wantStatus[i], row.Status, row.Message)
+				}
+			}
+			if got := stored[2].Courses; len(got) != 2 || got[1] != "Docker" {
+				t.Errorf("expected blank course titles to be dropped, got %q", got)
+			}
+		})
+	}
+}
+
+func TestUsecase_ProcessImports(t *testing.T) {
+	ctx := context.Background()
+	planUUID := gofakeit.UUID()
+	existing := &domain.Student{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Email: "tom@example.com"}
+
+	tests := []struct {
+		name         string
+		dryRun       bool
+		wantStatus   []domain.ImportRowStatus
+		wantStudents int
+		wantAssigned int
+	}{
+		{
+			name:         "Happy case - students created, subscribed and enrolled",
+			wantStatus:   []domain.ImportRowStatus{domain.ImportRowSucceeded, domain.ImportRowSucceeded, domain.ImportRowFailed},
+			wantStudents: 1,
+			wantAssigned: 3,
+		},
+		{
+			name:       "Happy case - dry run creates nothing",
+			dryRun:     true,
+			wantStatus: []domain.ImportRowStatus{domain.ImportRowSucceeded, domain.ImportRowSucceeded, domain.ImportRowFailed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := []*domain.ImportRow{
+				{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Line: 2, Email: "jane@example.com", FirstName: "Jane", LastName: "Doe", Courses: domain.StringList{"Go 101", "Docker"}, Status: domain.ImportRowPending},
+				{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Line: 3, Email: existing.Email, FirstName: "Tom", LastName: "Kariuki", Courses: domain.StringList{"Go 101"}, Status: domain.ImportRowPending},
+				{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Line: 4, Email: "amina@example.com", FirstName: "Amina", LastName: "Hassan", Courses: domain.StringList{"Cobol"}, Status: domain.ImportRowPending},
+			}
+			students := map[string]*domain.Student{existing.Email: existing}
+			subscriptions := map[string]*domain.Subscription{}
+
+			get := mock.NewMockGetRepository()
+			get.MockGetStudent = func(ctx context.Context, email *string) (*domain.Student, error) {
+				return students[*email], nil
+			}
+			get.MockGetStudentByUUID = func(ctx context.Context, uuid *string) (*domain.Student, error) {
+				for _, student := range students {
+					if student.UUID == *uuid {
+						return student, nil
+					}
+				}
+				return nil, nil
+			}
+			get.MockGetCourse = func(ctx context.Context, title *string) (*domain.Course, error) {
+				if *title == "Cobol" {
+					return nil, nil
+				}
+				return &domain.Course{AbstractBase: domain.AbstractBase{UUID: *title}, Title: *title}, nil
+			}
+			get.MockGetEnrollment = func(ctx context.Context, studentUUID, courseUUID *string) (*domain.StudentCourse, error) {
+				return nil, nil
+			}
+			get.MockGetPlan = func(ctx context.Context, uuid *string) (*domain.Plan, error) {
+				return &domain.Plan{AbstractBase: domain.AbstractBase{UUID: *uuid}, Name: "Demo", TrialDays: 30}, nil
+			}
+			get.MockGetStudentSubscription = func(ctx context.Context, studentUUID *string) (*domain.Subscription, error) {
+				return subscriptions[*studentUUID], nil
+			}
+			var keys []string
+			get.MockGetSubscriptionChargeByKey = func(ctx context.Context, key string) (*domain.SubscriptionCharge, error) {
+				keys = append(keys, key)
+				return nil, nil
+			}
+			pending := rows
+			get.MockGetImportRows = func(ctx context.Context, importUUID *string, status domain.ImportRowStatus, limit int) ([]*domain.ImportRow, error) {
+				batch := pending
+				pending = nil
+				return batch, nil
+			}
+
+			create := mock.NewMockCreateRepository()
+			create.MockCreateStudent = func(ctx context.Context, student *domain.Student) (*domain.Student, error) {
+				student.UUID = gofakeit.UUID()
+				students[student.Email] = student
+				return student, nil
+			}
+			create.MockCreateSubscription = func(ctx context.Context, subscription *domain.Subscription, charge *domain.SubscriptionCharge) (*domain.Subscription, error) {
+				subscription.UUID = gofakeit.UUID()
+				subscriptions[subscription.StudentUUID] = subscription
+				return subscription, nil
+			}
+			assigned := 0
+			create.MockAssignCourseToStudent = func(ctx context.Context, email, courseTitle, runUUID *string, redemption *domain.CouponRedemption) (*domain.Student, error) {
+				assigned++
+				return students[*email], nil
+			}
+
+			update := mock.NewMockUpdateRepository()
+			imp := &domain.Import{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, DryRun: tt.dryRun, Override: true, PlanUUID: &planUUID}
+			claimed := false
+			update.MockClaimImport = func(ctx context.Context, now time.Time, lease time.Duration) (*domain.Import, error) {
+				if claimed {
+					return nil, nil
+				}
+				claimed = true
+				imp.Status = domain.ImportStatusProcessing
+				return imp, nil
+			}
+			update.MockSaveImportRows = func(ctx context.Context, imp *domain.Import, saved []*domain.ImportRow, now time.Time, lease time.Duration) (*domain.Import, error) {
+				if len(pending) == 0 {
+					imp.Status = domain.ImportStatusCompleted
+				}
+				return imp, nil
+			}
+			u := course.NewUsecase(create, get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			processed, err := u.ProcessImports(ctx, time.Now)
+			if err != nil {
+				t.Fatalf("Usecase.ProcessImports() error = %v", err)
+			}
+			if processed != len(rows) || imp.Status != domain.ImportStatusCompleted {
+				t.Errorf("expected %d rows processed and the import completed, got %d and %s", len(rows), processed, imp.Status)
+			}
+			for i, row := range rows {
+				if row.Status != tt.wantStatus[i] {
+					t.Errorf("expected line %d to be %s, got %s (%s)", row.Line, tt.wantStatus[i], row.Status, row.Message)
+				}
+			}
+			if len(students)-1 != tt.wantStudents {
+				t.Errorf("expected %d students created, got %d", tt.wantStudents, len(students)-1)
+			}
+			if assigned != tt.wantAssigned {
+				t.Errorf("expected %d enrollments, got %d", tt.wantAssigned, assigned)
+			}
+			if !tt.dryRun && subscriptions[students["jane@example.com"].UUID] == nil {
+				t.Errorf("expected the new student to be subscribed to the import's plan")
+			}
+			if !tt.dryRun {
+				want := "signup:" + students["jane@example.com"].UUID + ":import:" + imp.UUID + ":" + rows[0].UUID
+				if len(keys) == 0 || keys[0] != want {
+					t.Errorf("expected the signup to be keyed by the import and its row, got %q", keys)
+				}
+			}
+		})
+	}
+}
+
+func TestUsecase_ProcessImports_StorageError(t *testing.T) {
+	ctx := context.Background()
+	row := &domain.ImportRow{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Line: 2, Email: "jane@example.com", FirstName: "Jane", LastName: "Doe", Courses: domain.StringList{"Go 101"}, Status: domain.ImportRowPending}
+
+	get := mock.NewMockGetRepository()
+	get.MockGetStudent = func(ctx context.Context, email *string) (*domain.Student, error) {
+		return nil, fmt.Errorf("%w: can't get student: connection refused", repository.ErrStorage)
+	}
+	get.MockGetImportRows = func(ctx context.Context, importUUID *string, status domain.ImportRowStatus, limit int) ([]*domain.ImportRow, error) {
+		return []*domain.ImportRow{row}, nil
+	}
+	update := mock.NewMockUpdateRepository()
+	imp := &domain.Import{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Status: domain.ImportStatusProcessing}
+	update.MockClaimImport = func(ctx context.Context, now time.Time, lease time.Duration) (*domain.Import, error) {
+		return imp, nil
+	}
+	saved := false
+	update.MockSaveImportRows = func(ctx context.Context, imp *domain.Import, rows []*domain.ImportRow, now time.Time, lease time.Duration) (*domain.Import, error) {
+		saved = true
+		return imp, nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	if _, err := u.ProcessImports(ctx, time.Now); err == nil {
+		t.Fatalf("expected the storage error to be returned")
+	}
+	if saved || row.Status != domain.ImportRowPending {
+		t.Errorf("expected the row to be left pending for a retry, got %s (%s)", row.Status, row.Message)
+	}
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/invoices"
+)
+
+// invoiceRetryDelay is how long a successful charge goes without an invoice
+// before IssueMissingInvoices issues it
+const invoiceRetryDelay = 5 * time.Minute
+
+// SetTaxRate sets the tax included in prices charged in a currency
+func (u *Usecase) SetTaxRate(
+	ctx context.Context,
+	rate *domain.TaxRate,
+) (*domain.TaxRate, error) {
+	currency, err := domain.NormalizeCurrency(rate.Currency)
+	if err != nil {
+		return nil, err
+	}
+	rate.Currency = currency
+	rate.Name = strings.TrimSpace(rate.Name)
+	if rate.Name == "" {
+		return nil, fmt.Errorf("tax's name can not be empty")
+	}
+	if rate.BasisPoints > 10000 {
+		return nil, fmt.Errorf("tax rate can not be over 100%%")
+	}
+	return u.Create.SetTaxRate(ctx, rate)
+}
+
+// ListTaxRates returns the tax rates of every taxed currency
+func (u *Usecase) ListTaxRates(
+	ctx context.Context,
+) ([]*domain.TaxRate, error) {
+	return u.Get.ListTaxRates(ctx)
+}
+
+// ListStudentInvoices returns a student's invoices, newest first. Students
+// can only list their own
+func (u *Usecase) ListStudentInvoices(
+	ctx context.Context,
+	principal *domain.Principal,
+	studentUUID *string,
+) ([]*domain.Invoice, error) {
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	if !principal.CanActAsStudent(*studentUUID) {
+		return nil, domain.ErrForbidden
+	}
+	return u.Get.ListStudentInvoices(ctx, studentUUID)
+}
+
+// GetInvoice returns an invoice, or nil when it does not exist. Students can
+// only see their own
+func (u *Usecase) GetInvoice(
+	ctx context.Context,
+	principal *domain.Principal,
+	invoiceUUID *string,
+) (*domain.Invoice, error) {
+	if invoiceUUID == nil || *invoiceUUID == "" {
+		return nil, fmt.Errorf("invoice's UUID can not be empty")
+	}
+	invoice, err := u.Get.GetInvoice(ctx, invoiceUUID)
+	if err != nil || invoice == nil {
+		return nil, err
+	}
+	if !principal.CanActAsStudent(invoice.StudentUUID) {
+		// don't tell someone else's invoice apart from a missing one
+		return nil, nil
+	}
+	return invoice, nil
+}
+
+// RenderReceiptPDF renders an invoice's receipt as a PDF, or returns nil
+// when the invoice does not exist
+func (u *Usecase) RenderReceiptPDF(
+	ctx context.Context,
+	principal *domain.Principal,
+	invoiceUUID *string,
+) ([]byte, error) {
+	invoice, err := u.GetInvoice(ctx, principal, invoiceUUID)
+	if err != nil || invoice == nil {
+		return nil, err
+	}
+	return invoices.RenderPDF(invoice), nil
+}
+
+// RenderReceiptHTML renders an invoice's receipt as an HTML page, or returns
+// nil when the invoice does not exist
+func (u *Usecase) RenderReceiptHTML(
+	ctx context.Context,
+	principal *domain.Principal,
+	invoiceUUID *string,
+) ([]byte, error) {
+	invoice, err := u.GetInvoice(ctx, principal, invoiceUUID)
+	if err != nil || invoice == nil {
+		return nil, err
+	}
+	return invoices.RenderHTML(invoice)
+}
+
+// issueInvoice invoices a successful charge of a single item. Prices include
+// the tax of their currency, which is broken out of the line into a tax line
+func (u *Usecase) issueInvoice(
+	ctx context.Context,
+	studentUUID string,
+	chargeUUID string,
+	reference string,
+	description string,
+	amount domain.Money,
+	issuedAt time.Time,
+) (*domain.Invoice, error) {
+	student, err := u.Get.GetStudentByUUID(ctx, &studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, &domain.NotFoundError{Kind: "student", Key: studentUUID}
+	}
+	rate, err := u.Get.GetTaxRate(ctx, amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	tax := domain.Money{Currency: amount.Currency}
+	var taxLines []*domain.InvoiceTaxLine
+	if rate != nil && rate.BasisPoints > 0 {
+		tax = rate.IncludedIn(amount)
+		taxLines = append(taxLines, &domain.InvoiceTaxLine{
+			Label:       rate.Label(),
+			BasisPoints: rate.BasisPoints,
+			Amount:      tax,
+		})
+	}
+	net := domain.Money{Amount: amount.Amount - tax.Amount, Currency: amount.Currency}
+	issuedAt = issuedAt.UTC()
+	return u.Create.CreateInvoice(ctx, &domain.Invoice{
+		Year:        issuedAt.Year(),
+		StudentUUID: studentUUID,
+		BilledTo:    strings.TrimSpace(student.FirstName + " " + student.LastName),
+		BilledEmail: student.Email,
+		ChargeUUID:  chargeUUID,
+		Reference:   reference,
+		IssuedAt:    issuedAt,
+		Subtotal:    net,
+		Tax:         tax,
+		Total:       amount,
+		Lines: []*domain.InvoiceLine{{
+			Position:    1,
+			Description: description,
+			Quantity:    1,
+			UnitPrice:   net,
+			Amount:      net,
+		}},
+		TaxLines: taxLines,
+	})
+}
+
+// IssueMissingInvoices issues the invoices of successful subscription charges
+// that couldn't be invoiced when they were settled. It is run periodically by
+// the renewal scheduler and returns how many invoices it issued. A charge
+// only ever has one invoice, so one invoiced in the meantime isn't invoiced
+// twice
+func (u *Usecase) IssueMissingInvoices(
+	ctx context.Context,
+	now time.Time,
+) (int, error) {
+	// leave charges that are being invoiced right now alone
+	charges, err := u.Get.ListUninvoicedCharges(ctx, now.Add(-invoiceRetryDelay))
+	if err != nil {
+		return 0, err
+	}
+	issued := 0
+	var firstErr error
+	for _, charge := range charges {
+		if err := u.invoiceCharge(ctx, charge); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("charge %s: %w", charge.UUID, err)
+			}
+			continue
+		}
+		issued++
+	}
+	if firstErr != nil {
+		return issued, fmt.Errorf("%d of %d uninvoiced charges failed, first error: %w", len(charges)-issued, len(charges), firstErr)
+	}
+	return issued, nil
+}
+
+// invoiceCharge issues the invoice of a successful charge of the
+// subscription it was made for
+func (u *Usecase) invoiceCharge(
+	ctx context.Context,
+	charge *domain.SubscriptionCharge,
+) error {
+	subscription, err := u.Get.GetSubscription(ctx, &charge.SubscriptionUUID)
+	if err != nil {
+		return err
+	}
+	if subscription == nil {
+		return &domain.NotFoundError{Kind: "subscription", Key: charge.SubscriptionUUID}
+	}
+	plan, err := u.subscriptionPlan(ctx, subscription)
+	if err != nil {
+		return err
+	}
+	_, err = u.issueChargeInvoice(ctx, subscription, plan, charge)
+	return err
+}
+
+// issueChargeInvoice issues the invoice of a successful subscription charge
+func (u *Usecase) issueChargeInvoice(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+	charge *domain.SubscriptionCharge,
+) (*domain.Invoice, error) {
+	description := fmt.Sprintf(
+		"%s subscription, %s to %s",
+		plan.Name,
+		charge.PeriodStart.UTC().Format("2 Jan 2006"),
+		charge.PeriodEnd.UTC().Format("2 Jan 2006"),
+	)
+	return u.issueInvoice(
+		ctx,
+		subscription.StudentUUID,
+		charge.UUID,
+		charge.Reference,
+		description,
+		charge.Amount,
+		time.Now(),
+	)
+}
+
+// invoiceSubscriptionCharge issues the invoice of a successful subscription
+// charge. The payment has already gone through, so a failure is logged
+// rather than failing the subscription, and IssueMissingInvoices issues the
+// invoice later
+func (u *Usecase) invoiceSubscriptionCharge(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+	charge *domain.SubscriptionCharge,
+) *domain.Invoice {
+	invoice, err := u.issueChargeInvoice(ctx, subscription, plan, charge)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"subscription": subscription.UUID,
+			"reference":    charge.Reference,
+		}).Errorf("can't invoice subscription charge: %v", err)
+		return nil
+	}
+	return invoice
+}
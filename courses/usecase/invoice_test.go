@@ -0,0 +1,324 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_SetTaxRate(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		rate    *domain.TaxRate
+		wantErr bool
+	}{
+		{
+			name: "Happy case",
+			rate: &domain.TaxRate{Currency: "kes", Name: " VAT ", BasisPoints: 1600},
+		},
+		{
+			name: "Happy case - untaxed currency",
+			rate: &domain.TaxRate{Currency: "USD", Name: "Sales tax"},
+		},
+		{
+			name:    "Sad case - unsupported currency",
+			rate:    &domain.TaxRate{Currency: "XYZ", Name: "VAT", BasisPoints: 1600},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - no name",
+			rate:    &domain.TaxRate{Currency: "KES", BasisPoints: 1600},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - over 100%",
+			rate:    &domain.TaxRate{Currency: "KES", Name: "VAT", BasisPoints: 10001},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := course.NewUsecase(mock.NewMockCreateRepository(), mock.NewMockGetRepository(), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+			rate, err := u.SetTaxRate(ctx, tt.rate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.SetTaxRate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if rate.Currency != strings.ToUpper(rate.Currency) || rate.Name != strings.TrimSpace(rate.Name) {
+				t.Errorf("expected the rate to be normalized, got %s %q", rate.Currency, rate.Name)
+			}
+		})
+	}
+}
+
+func TestUsecase_Subscribe_Invoice(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	planUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name        string
+		taxRate     *domain.TaxRate
+		trialDays   uint
+		wantInvoice bool
+		wantTax     int64
+	}{
+		{
+			name:        "Happy case - taxed",
+			taxRate:     &domain.TaxRate{Currency: "KES", Name: "VAT", BasisPoints: 1600},
+			wantInvoice: true,
+			wantTax:     1366,
+		},
+		{
+			name:        "Happy case - untaxed",
+			wantInvoice: true,
+		},
+		{
+			name:      "Happy case - trial isn't invoiced",
+			trialDays: 14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetStudentByUUID = func(ctx context.Context, uuid *string) (*domain.Student, error) {
+				return &domain.Student{AbstractBase: domain.AbstractBase{UUID: *uuid}, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"}, nil
+			}
+			get.MockGetPlan = func(ctx context.Context, uuid *string) (*domain.Plan, error) {
+				return &domain.Plan{
+					AbstractBase:   domain.AbstractBase{UUID: *uuid},
+					Name:           "Annual",
+					Price:          domain.Money{Amount: 9900, Currency: "KES"},
+					IntervalMonths: 12,
+					TrialDays:      tt.trialDays,
+				}, nil
+			}
+			get.MockGetStudentSubscription = func(ctx context.Context, uuid *string) (*domain.Subscription, error) {
+				return nil, nil
+			}
+			get.MockGetTaxRate = func(ctx context.Context, currency string) (*domain.TaxRate, error) {
+				return tt.taxRate, nil
+			}
+			create := mock.NewMockCreateRepository()
+			chargeUUID := gofakeit.UUID()
+			create.MockCreateSubscription = func(ctx context.Context, subscription *domain.Subscription, charge *domain.SubscriptionCharge) (*domain.Subscription, error) {
+				if charge != nil {
+					charge.UUID = chargeUUID
+				}
+				return subscription, nil
+			}
+			var invoice *domain.Invoice
+			create.MockCreateInvoice = func(ctx context.Context, created *domain.Invoice) (*domain.Invoice, error) {
+				created.Number = domain.InvoiceNumber(created.Year, 1)
+				invoice = created
+				return created, nil
+			}
+			publisher := eventsmock.NewMockPublisher()
+			var started *domain.SubscriptionStarted
+			publisher.MockPublish = func(ctx context.Context, event domain.Event) error {
+				if e, ok := event.(domain.SubscriptionStarted); ok {
+					started = &e
+				}
+				return nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), publisher, testSigner, testSearch, testRates, testPayments)
+
+			if _, err := u.Subscribe(ctx, testAdmin, &studentUUID, &planUUID, ""); err != nil {
+				t.Fatalf("Usecase.Subscribe() error = %v", err)
+			}
+			if started == nil {
+				t.Fatalf("expected a subscription started event")
+			}
+			if (invoice != nil) != tt.wantInvoice || (started.Invoice != nil) != tt.wantInvoice {
+				t.Fatalf("expected an invoice %v, got %v", tt.wantInvoice, invoice)
+			}
+			if !tt.wantInvoice {
+				return
+			}
+			if invoice.ChargeUUID != chargeUUID || invoice.StudentUUID != studentUUID {
+				t.Errorf("expected the invoice to be for the student's charge")
+			}
+			if invoice.BilledTo != "Ada Lovelace" || invoice.Year != time.Now().UTC().Year() {
+				t.Errorf("expected the invoice to be billed to the student this year, got %q %d", invoice.BilledTo, invoice.Year)
+			}
+			if invoice.Total.Amount != 9900 || invoice.Tax.Amount != tt.wantTax || invoice.Subtotal.Amount != 9900-tt.wantTax {
+				t.Errorf("expected %d of tax included in 9900, got %+v", tt.wantTax, invoice)
+			}
+			if len(invoice.Lines) != 1 || invoice.Lines[0].Amount != invoice.Subtotal {
+				t.Errorf("expected a single line for the subscription, got %v", invoice.Lines)
+			}
+			if (len(invoice.TaxLines) == 1) != (tt.taxRate != nil) {
+				t.Errorf("expected a tax line only when the currency is taxed, got %v", invoice.TaxLines)
+			}
+		})
+	}
+}
+
+func TestUsecase_RenewSubscriptions_Invoice(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	periodEnd := now.Add(-time.Hour)
+
+	for _, declined := range []bool{false, true} {
+		get := mock.NewMockGetRepository()
+		update := mock.NewMockUpdateRepository()
+		update.MockClaimDueSubscriptions = func(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]*domain.Subscription, error) {
+			return []*domain.Subscription{{
+				AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+				StudentUUID:      gofakeit.UUID(),
+				Plan:             &domain.Plan{Name: "Annual", Price: domain.Money{Amount: 9900, Currency: "USD"}, IntervalMonths: 12, GraceDays: 7},
+				Status:           domain.SubscriptionStatusActive,
+				CurrentPeriodEnd: periodEnd,
+				RenewsAt:         &periodEnd,
+			}}, nil
+		}
+		create := mock.NewMockCreateRepository()
+		invoiced := 0
+		create.MockCreateInvoice = func(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+			invoiced++
+			return invoice, nil
+		}
+		u := course.NewUsecase(create, get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, decliningProvider(declined))
+
+		if _, err := u.RenewSubscriptions(ctx, now); err != nil {
+			t.Fatalf("Usecase.RenewSubscriptions() error = %v", err)
+		}
+		if want := map[bool]int{false: 1, true: 0}[declined]; invoiced != want {
+			t.Errorf("declined %v: expected %d invoices, got %d", declined, want, invoiced)
+		}
+	}
+}
+
+func TestUsecase_IssueMissingInvoices(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	studentUUID := gofakeit.UUID()
+	subscriptionUUID := gofakeit.UUID()
+	invoicedUUID := gofakeit.UUID()
+	orphanUUID := gofakeit.UUID()
+
+	get := mock.NewMockGetRepository()
+	var settledBefore time.Time
+	get.MockListUninvoicedCharges = func(ctx context.Context, before time.Time) ([]*domain.SubscriptionCharge, error) {
+		settledBefore = before
+		return []*domain.SubscriptionCharge{
+			{
+				AbstractBase:     domain.AbstractBase{UUID: invoicedUUID},
+				SubscriptionUUID: subscriptionUUID,
+				Amount:           domain.Money{Amount: 9900, Currency: "USD"},
+				Status:           domain.ChargeStatusSucceeded,
+				Reference:        "ch_123",
+			},
+			{
+				AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+				SubscriptionUUID: orphanUUID,
+				Status:           domain.ChargeStatusSucceeded,
+			},
+		}, nil
+	}
+	get.MockGetSubscription = func(ctx context.Context, uuid *string) (*domain.Subscription, error) {
+		if *uuid == orphanUUID {
+			return nil, nil
+		}
+		return &domain.Subscription{
+			AbstractBase: domain.AbstractBase{UUID: *uuid},
+			StudentUUID:  studentUUID,
+			Plan:         &domain.Plan{Name: "Monthly"},
+		}, nil
+	}
+	get.MockGetStudentByUUID = func(ctx context.Context, uuid *string) (*domain.Student, error) {
+		return &domain.Student{AbstractBase: domain.AbstractBase{UUID: *uuid}, FirstName: "Ada", Email: gofakeit.Email()}, nil
+	}
+	create := mock.NewMockCreateRepository()
+	var invoiced []string
+	create.MockCreateInvoice = func(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+		invoiced = append(invoiced, invoice.ChargeUUID)
+		return invoice, nil
+	}
+	u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	issued, err := u.IssueMissingInvoices(ctx, now)
+	if err == nil {
+		t.Errorf("expected the charge of a missing subscription to be reported")
+	}
+	if issued != 1 || len(invoiced) != 1 || invoiced[0] != invoicedUUID {
+		t.Errorf("expected the other charge to be invoiced, got %d: %v", issued, invoiced)
+	}
+	if !settledBefore.Before(now) {
+		t.Errorf("expected charges that are being invoiced right now to be left alone")
+	}
+}
+
+func TestUsecase_GetInvoice(t *testing.T) {
+	ctx := context.Background()
+	invoiceUUID := gofakeit.UUID()
+	ownerUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		wantFound bool
+	}{
+		{
+			name:      "Happy case - the student's own invoice",
+			principal: &domain.Principal{Role: domain.RoleStudent, Subject: ownerUUID},
+			wantFound: true,
+		},
+		{
+			name:      "Happy case - admin",
+			principal: testAdmin,
+			wantFound: true,
+		},
+		{
+			name:      "Sad case - another student's invoice",
+			principal: &domain.Principal{Role: domain.RoleStudent, Subject: gofakeit.UUID()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetInvoice = func(ctx context.Context, uuid *string) (*domain.Invoice, error) {
+				return &domain.Invoice{AbstractBase: domain.AbstractBase{UUID: *uuid}, StudentUUID: ownerUUID}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			invoice, err := u.GetInvoice(ctx, tt.principal, &invoiceUUID)
+			if err != nil {
+				t.Fatalf("Usecase.GetInvoice() error = %v", err)
+			}
+			if (invoice != nil) != tt.wantFound {
+				t.Errorf("Usecase.GetInvoice() = %v, want found %v", invoice, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestTaxRate_IncludedIn(t *testing.T) {
+	tests := []struct {
+		basisPoints uint
+		amount      int64
+		want        int64
+	}{
+		{basisPoints: 1600, amount: 11600, want: 1600},
+		{basisPoints: 1600, amount: 9900, want: 1366},
+		{basisPoints: 2000, amount: 100, want: 17},
+		{basisPoints: 0, amount: 9900, want: 0},
+	}
+	for _, tt := range tests {
+		rate := &domain.TaxRate{Name: "VAT", BasisPoints: tt.basisPoints}
+		got := rate.IncludedIn(domain.Money{Amount: tt.amount, Currency: "KES"})
+		if got.Amount != tt.want || got.Currency != "KES" {
+			t.Errorf("%s of KES %d = %v, want %d", rate.Label(), tt.amount, got, tt.want)
+		}
+	}
+}
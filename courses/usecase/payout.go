@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/payouts"
+)
+
+// SetRevenueShare records a revenue share agreement with an instructor, for
+// one of their courses or all of them. It applies to sales from when it takes
+// effect, now unless it says otherwise
+func (u *Usecase) SetRevenueShare(
+	ctx context.Context,
+	share *domain.RevenueShare,
+) (*domain.RevenueShare, error) {
+	if share.InstructorUUID == "" {
+		return nil, fmt.Errorf("instructor's UUID can not be empty")
+	}
+	if share.BasisPoints > 10000 {
+		return nil, fmt.Errorf("revenue share can not be over 100%%")
+	}
+	instructor, err := u.Get.GetInstructor(ctx, &share.InstructorUUID)
+	if err != nil {
+		return nil, err
+	}
+	if instructor == nil {
+		return nil, &domain.NotFoundError{Kind: "instructor", Key: share.InstructorUUID}
+	}
+	if share.CourseUUID != nil && *share.CourseUUID == "" {
+		share.CourseUUID = nil
+	}
+	if share.CourseUUID != nil {
+		course, err := u.Get.GetCourseByUUID(ctx, share.CourseUUID)
+		if err != nil {
+			return nil, err
+		}
+		if course == nil {
+			return nil, &domain.NotFoundError{Kind: "course", Key: *share.CourseUUID}
+		}
+		if course.InstructorUUID == nil || *course.InstructorUUID != share.InstructorUUID {
+			return nil, fmt.Errorf("course %s is not taught by instructor %s", course.Title, instructor.Name)
+		}
+	}
+	if share.EffectiveFrom.IsZero() {
+		share.EffectiveFrom = time.Now()
+	}
+	return u.Create.CreateRevenueShare(ctx, share)
+}
+
+// ListRevenueShares returns an instructor's revenue share agreements, latest
+// first
+func (u *Usecase) ListRevenueShares(
+	ctx context.Context,
+	principal *domain.Principal,
+	instructorUUID *string,
+) ([]*domain.RevenueShare, error) {
+	if instructorUUID == nil || *instructorUUID == "" {
+		return nil, fmt.Errorf("instructor's UUID can not be empty")
+	}
+	if !principal.CanActAsInstructor(*instructorUUID) {
+		return nil, domain.ErrForbidden
+	}
+	return u.Get.ListRevenueShares(ctx, instructorUUID)
+}
+
+// GeneratePayoutStatements puts the sales of a month that aren't on a
+// statement yet on a statement per instructor and currency, with each
+// instructor's cut under the agreement in force when the course was sold.
+// Sales no agreement covers earn nothing. Running it again for the same month
+// only picks up sales recorded since
+func (u *Usecase) GeneratePayoutStatements(
+	ctx context.Context,
+	month string,
+) ([]*domain.PayoutStatement, error) {
+	from, err := time.Parse("2006-01", strings.TrimSpace(month))
+	if err != nil {
+		return nil, fmt.Errorf("month must look like 2006-01")
+	}
+	to := from.AddDate(0, 1, 0)
+	if to.After(time.Now()) {
+		return nil, fmt.Errorf("%s isn't over yet", month)
+	}
+	month = domain.RevenueMonth(from)
+
+	sales, err := u.Get.ListUnpaidSales(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	shares := map[string][]*domain.RevenueShare{}
+	statements := map[string]*domain.PayoutStatement{}
+	var keys []string
+	for _, sale := range sales {
+		if _, ok := shares[sale.InstructorUUID]; !ok {
+			agreements, err := u.Get.ListRevenueShares(ctx, &sale.InstructorUUID)
+			if err != nil {
+				return nil, err
+			}
+			shares[sale.InstructorUUID] = agreements
+		}
+		if share := domain.ApplicableShare(shares[sale.InstructorUUID], sale.CourseUUID, sale.SoldAt); share != nil {
+			sale.BasisPoints = share.BasisPoints
+			sale.Amount = share.Of(sale.Net)
+		} else {
+			sale.Amount = domain.Money{Currency: sale.Net.Currency}
+		}
+
+		key := sale.InstructorUUID + "/" + sale.Net.Currency
+		statement, ok := statements[key]
+		if !ok {
+			statement = &domain.PayoutStatement{
+				InstructorUUID: sale.InstructorUUID,
+				Month:          month,
+				Sales:          domain.Money{Currency: sale.Net.Currency},
+				Earnings:       domain.Money{Currency: sale.Net.Currency},
+				Status:         domain.PayoutStatusPending,
+			}
+			statements[key] = statement
+			keys = append(keys, key)
+		}
+		statement.Sales.Amount += sale.Net.Amount
+		statement.Earnings.Amount += sale.Amount.Amount
+		statement.Lines = append(statement.Lines, sale)
+	}
+
+	sort.Strings(keys)
+	created := make([]*domain.PayoutStatement, 0, len(keys))
+	for _, key := range keys {
+		statement := statements[key]
+		instructor, err := u.Get.GetInstructor(ctx, &statement.InstructorUUID)
+		if err != nil {
+			return nil, err
+		}
+		if instructor != nil {
+			statement.InstructorName = instructor.Name
+		}
+		statement, err = u.Create.CreatePayoutStatement(ctx, statement)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, statement)
+	}
+	return created, nil
+}
+
+// ListPayoutStatements returns payout statements, optionally only an
+// instructor's or a month's
+func (u *Usecase) ListPayoutStatements(
+	ctx context.Context,
+	instructorUUID string,
+	month string,
+) ([]*domain.PayoutStatement, error) {
+	return u.Get.ListPayoutStatements(ctx, instructorUUID, month)
+}
+
+// GetPayoutStatement returns a payout statement with its lines, or nil when it
+// does not exist. Instructors can only see their own
+func (u *Usecase) GetPayoutStatement(
+	ctx context.Context,
+	principal *domain.Principal,
+	statementUUID *string,
+) (*domain.PayoutStatement, error) {
+	if statementUUID == nil || *statementUUID == "" {
+		return nil, fmt.Errorf("payout statement's UUID can not be empty")
+	}
+	statement, err := u.Get.GetPayoutStatement(ctx, statementUUID)
+	if err != nil || statement == nil {
+		return nil, err
+	}
+	if !principal.CanActAsInstructor(statement.InstructorUUID) {
+		// don't tell someone else's statement apart from a missing one
+		return nil, nil
+	}
+	return statement, nil
+}
+
+// RenderPayoutStatementHTML renders a payout statement as an HTML page, or
+// returns nil when it does not exist
+func (u *Usecase) RenderPayoutStatementHTML(
+	ctx context.Context,
+	principal *domain.Principal,
+	statementUUID *string,
+) ([]byte, error) {
+	statement, err := u.GetPayoutStatement(ctx, principal, statementUUID)
+	if err != nil || statement == nil {
+		return nil, err
+	}
+	return payouts.RenderHTML(statement)
+}
+
+// RenderPayoutStatementCSV renders the lines of a payout statement as CSV, or
+// returns nil when it does not exist
+func (u *Usecase) RenderPayoutStatementCSV(
+	ctx context.Context,
+	principal *domain.Principal,
+	statementUUID *string,
+) ([]byte, error) {
+	statement, err := u.GetPayoutStatement(ctx, principal, statementUUID)
+	if err != nil || statement == nil {
+		return nil, err
+	}
+	return payouts.RenderCSV(statement)
+}
+
+// MarkPayoutPaid records that a payout statement was paid to the instructor,
+// or returns nil when it does not exist
+func (u *Usecase) MarkPayoutPaid(
+	ctx context.Context,
+	statementUUID *string,
+	reference string,
+) (*domain.PayoutStatement, error) {
+	if statementUUID == nil || *statementUUID == "" {
+		return nil, fmt.Errorf("payout statement's UUID can not be empty")
+	}
+	return u.Update.MarkPayoutPaid(ctx, statementUUID, strings.TrimSpace(reference), time.Now())
+}
+
+// GetInstructorEarnings returns an instructor's payout statements and how much
+// they have earned and been paid in each currency. Instructors can only see
+// their own
+func (u *Usecase) GetInstructorEarnings(
+	ctx context.Context,
+	principal *domain.Principal,
+	instructorUUID *string,
+) (*domain.InstructorEarnings, error) {
+	if instructorUUID == nil || *instructorUUID == "" {
+		return nil, fmt.Errorf("instructor's UUID can not be empty")
+	}
+	if !principal.CanActAsInstructor(*instructorUUID) {
+		return nil, domain.ErrForbidden
+	}
+	statements, err := u.Get.ListPayoutStatements(ctx, *instructorUUID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	earnings := &domain.InstructorEarnings{
+		InstructorUUID: *instructorUUID,
+		Totals:         []*domain.EarningsTotal{},
+		Statements:     statements,
+	}
+	byCurrency := map[string]*domain.EarningsTotal{}
+	for _, statement := range statements {
+		currency := statement.Earnings.Currency
+		total, ok := byCurrency[currency]
+		if !ok {
+			total = &domain.EarningsTotal{
+				Currency:    currency,
+				Earned:      domain.Money{Currency: currency},
+				Paid:        domain.Money{Currency: currency},
+				Outstanding: domain.Money{Currency: currency},
+			}
+			byCurrency[currency] = total
+			earnings.Totals = append(earnings.Totals, total)
+		}
+		total.Earned.Amount += statement.Earnings.Amount
+		if statement.Status == domain.PayoutStatusPaid {
+			total.Paid.Amount += statement.Earnings.Amount
+		} else {
+			total.Outstanding.Amount += statement.Earnings.Amount
+		}
+	}
+	sort.Slice(earnings.Totals, func(i, j int) bool {
+		return earnings.Totals[i].Currency < earnings.Totals[j].Currency
+	})
+	return earnings, nil
+}
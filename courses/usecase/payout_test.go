@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_GeneratePayoutStatements(t *testing.T) {
+	ctx := context.Background()
+	grace, ada := gofakeit.UUID(), gofakeit.UUID()
+	goCourse, cobolCourse := gofakeit.UUID(), gofakeit.UUID()
+	may := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	usd := func(amount int64) domain.Money { return domain.Money{Amount: amount, Currency: "USD"} }
+
+	tests := []struct {
+		name           string
+		month          string
+		wantStatements int
+		wantEarnings   map[string]int64
+		wantErr        bool
+	}{
+		{
+			name:           "Happy case - a statement per instructor",
+			month:          "2023-05",
+			wantStatements: 2,
+			// grace: 60% of 49.00 before the course deal, 75% of 36.75 after
+			// it; ada has no agreement
+			wantEarnings: map[string]int64{grace: 2940 + 2756, ada: 0},
+		},
+		{
+			name:    "Sad case - not a month",
+			month:   "May 2023",
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - month isn't over",
+			month:   time.Now().Format("2006-01"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			var from, to time.Time
+			get.MockListUnpaidSales = func(ctx context.Context, start time.Time, end time.Time) ([]*domain.PayoutLine, error) {
+				from, to = start, end
+				return []*domain.PayoutLine{
+					{SaleUUID: gofakeit.UUID(), InstructorUUID: grace, CourseUUID: goCourse, SoldAt: may.AddDate(0, 0, 2), Net: usd(4900)},
+					{SaleUUID: gofakeit.UUID(), InstructorUUID: ada, CourseUUID: cobolCourse, SoldAt: may.AddDate(0, 0, 5), Net: usd(2900)},
+					{SaleUUID: gofakeit.UUID(), InstructorUUID: grace, CourseUUID: goCourse, SoldAt: may.AddDate(0, 0, 20), Net: usd(3675)},
+				}, nil
+			}
+			get.MockListRevenueShares = func(ctx context.Context, instructorUUID *string) ([]*domain.RevenueShare, error) {
+				if *instructorUUID != grace {
+					return nil, nil
+				}
+				return []*domain.RevenueShare{
+					{InstructorUUID: grace, CourseUUID: &goCourse, BasisPoints: 7500, EffectiveFrom: may.AddDate(0, 0, 10)},
+					{InstructorUUID: grace, BasisPoints: 6000, EffectiveFrom: may.AddDate(-1, 0, 0)},
+				}, nil
+			}
+			create := mock.NewMockCreateRepository()
+			var created []*domain.PayoutStatement
+			create.MockCreatePayoutStatement = func(ctx context.Context, statement *domain.PayoutStatement) (*domain.PayoutStatement, error) {
+				created = append(created, statement)
+				return statement, nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			statements, err := u.GeneratePayoutStatements(ctx, tt.month)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.GeneratePayoutStatements() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !from.Equal(may) || !to.Equal(may.AddDate(0, 1, 0)) {
+				t.Errorf("expected the sales of May 2023, got %s to %s", from, to)
+			}
+			if len(statements) != tt.wantStatements || len(created) != tt.wantStatements {
+				t.Fatalf("expected %d statements, got %d", tt.wantStatements, len(statements))
+			}
+			for _, statement := range statements {
+				if statement.Month != "2023-05" || statement.Status != domain.PayoutStatusPending {
+					t.Errorf("expected a pending statement for 2023-05, got %s %s", statement.Month, statement.Status)
+				}
+				if statement.Earnings.Amount != tt.wantEarnings[statement.InstructorUUID] {
+					t.Errorf("expected %s to earn %d, got %d", statement.InstructorUUID, tt.wantEarnings[statement.InstructorUUID], statement.Earnings.Amount)
+				}
+				var sales int64
+				for _, line := range statement.Lines {
+					sales += line.Net.Amount
+				}
+				if sales != statement.Sales.Amount {
+					t.Errorf("expected the statement's sales to add up, got %d and %d", sales, statement.Sales.Amount)
+				}
+			}
+		})
+	}
+}
+
+func TestUsecase_GetInstructorEarnings(t *testing.T) {
+	ctx := context.Background()
+	instructorUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		wantErrIs error
+	}{
+		{
+			name:      "Happy case - instructor sees their own earnings",
+			principal: &domain.Principal{Role: domain.RoleInstructor, Subject: instructorUUID},
+		},
+		{
+			name:      "Happy case - admin sees anyone's earnings",
+			principal: &domain.Principal{Role: domain.RoleAdmin},
+		},
+		{
+			name:      "Sad case - instructor can't see someone else's earnings",
+			principal: &domain.Principal{Role: domain.RoleInstructor, Subject: gofakeit.UUID()},
+			wantErrIs: domain.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockListPayoutStatements = func(ctx context.Context, instructor string, month string) ([]*domain.PayoutStatement, error) {
+				return []*domain.PayoutStatement{
+					{InstructorUUID: instructor, Month: "2023-06", Earnings: domain.Money{Amount: 1500, Currency: "USD"}, Status: domain.PayoutStatusPending},
+					{InstructorUUID: instructor, Month: "2023-05", Earnings: domain.Money{Amount: 5696, Currency: "USD"}, Status: domain.PayoutStatusPaid},
+					{InstructorUUID: instructor, Month: "2023-05", Earnings: domain.Money{Amount: 120000, Currency: "KES"}, Status: domain.PayoutStatusPending},
+				}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			earnings, err := u.GetInstructorEarnings(ctx, tt.principal, &instructorUUID)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Usecase.GetInstructorEarnings() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Usecase.GetInstructorEarnings() error = %v", err)
+			}
+			if len(earnings.Totals) != 2 || earnings.Totals[0].Currency != "KES" {
+				t.Fatalf("expected totals in KES and USD, got %+v", earnings.Totals)
+			}
+			usd := earnings.Totals[1]
+			if usd.Earned.Amount != 7196 || usd.Paid.Amount != 5696 || usd.Outstanding.Amount != 1500 {
+				t.Errorf("expected USD 71.96 earned of which 56.96 paid, got %+v", usd)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetPayoutStatement(t *testing.T) {
+	ctx := context.Background()
+	statementUUID := gofakeit.UUID()
+	owner := gofakeit.UUID()
+	get := mock.NewMockGetRepository()
+	get.MockGetPayoutStatement = func(ctx context.Context, uuid *string) (*domain.PayoutStatement, error) {
+		return &domain.PayoutStatement{AbstractBase: domain.AbstractBase{UUID: *uuid}, InstructorUUID: owner, Month: "2023-05"}, nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	statement, err := u.GetPayoutStatement(ctx, &domain.Principal{Role: domain.RoleInstructor, Subject: owner}, &statementUUID)
+	if err != nil || statement == nil {
+		t.Fatalf("expected the instructor to see their statement, got %v, %v", statement, err)
+	}
+	statement, err = u.GetPayoutStatement(ctx, &domain.Principal{Role: domain.RoleInstructor, Subject: gofakeit.UUID()}, &statementUUID)
+	if err != nil || statement != nil {
+		t.Errorf("expected someone else's statement to look missing, got %v, %v", statement, err)
+	}
+	page, err := u.RenderPayoutStatementCSV(ctx, &domain.Principal{Role: domain.RoleAdmin}, &statementUUID)
+	if err != nil || len(page) == 0 {
+		t.Errorf("expected an admin to download the statement, got %v", err)
+	}
+}
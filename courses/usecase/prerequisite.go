@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// AddCoursePrerequisite makes a course require another course. Prerequisites
+// that would make a course (indirectly) require itself are rejected
+func (u *Usecase) AddCoursePrerequisite(
+	ctx context.Context,
+	courseUUID *string,
+	prerequisiteUUID *string,
+) error {
+	if courseUUID == nil || *courseUUID == "" {
+		return fmt.Errorf("course's UUID can not be empty")
+	}
+	if prerequisiteUUID == nil || *prerequisiteUUID == "" {
+		return fmt.Errorf("prerequisite's UUID can not be empty")
+	}
+	if *courseUUID == *prerequisiteUUID {
+		return fmt.Errorf("a course can not be its own prerequisite")
+	}
+	for _, uuid := range []*string{courseUUID, prerequisiteUUID} {
+		course, err := u.Get.GetCourseByUUID(ctx, uuid)
+		if err != nil {
+			return err
+		}
+		if course == nil {
+			return &domain.NotFoundError{Kind: "course", Key: *uuid}
+		}
+	}
+
+	err := u.Create.AddCoursePrerequisite(ctx, &domain.CoursePrerequisite{
+		CourseUUID:       *courseUUID,
+		PrerequisiteUUID: *prerequisiteUUID,
+	})
+	if errors.Is(err, domain.ErrPrerequisiteCycle) {
+		return fmt.Errorf("course %s already requires course %s: %w", *prerequisiteUUID, *courseUUID, err)
+	}
+	return err
+}
+
+// RemoveCoursePrerequisite removes a prerequisite from a course
+func (u *Usecase) RemoveCoursePrerequisite(
+	ctx context.Context,
+	courseUUID *string,
+	prerequisiteUUID *string,
+) error {
+	if courseUUID == nil || *courseUUID == "" {
+		return fmt.Errorf("course's UUID can not be empty")
+	}
+	if prerequisiteUUID == nil || *prerequisiteUUID == "" {
+		return fmt.Errorf("prerequisite's UUID can not be empty")
+	}
+	return u.Delete.RemoveCoursePrerequisite(ctx, courseUUID, prerequisiteUUID)
+}
+
+// GetPrerequisiteTree returns a course with all of its prerequisites, direct
+// and indirect
+func (u *Usecase) GetPrerequisiteTree(
+	ctx context.Context,
+	courseUUID *string,
+) (*domain.PrerequisiteTree, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, courseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, nil
+	}
+	return u.prerequisiteTree(ctx, course, map[string]bool{})
+}
+
+func (u *Usecase) prerequisiteTree(
+	ctx context.Context,
+	course *domain.Course,
+	path map[string]bool,
+) (*domain.PrerequisiteTree, error) {
+	tree := &domain.PrerequisiteTree{Course: course, Prerequisites: []*domain.PrerequisiteTree{}}
+	prerequisites, err := u.Get.GetCoursePrerequisites(ctx, &course.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	path[course.UUID] = true
+	defer delete(path, course.UUID)
+	for _, prerequisite := range prerequisites {
+		// cycles are rejected on write, this only guards against bad data
+		if path[prerequisite.UUID] {
+			continue
+		}
+		subtree, err := u.prerequisiteTree(ctx, prerequisite, path)
+		if err != nil {
+			return nil, err
+		}
+		tree.Prerequisites = append(tree.Prerequisites, subtree)
+	}
+	return tree, nil
+}
+
+// checkPrerequisites returns a MissingPrerequisitesError when the student has
+// not completed every direct prerequisite of the course
+func (u *Usecase) checkPrerequisites(
+	ctx context.Context,
+	email *string,
+	courseTitle *string,
+) error {
+	course, err := u.Get.GetCourse(ctx, courseTitle)
+	if err != nil {
+		return err
+	}
+	if course == nil {
+		return &domain.NotFoundError{Kind: "course", Key: *courseTitle}
+	}
+	prerequisites, err := u.Get.GetCoursePrerequisites(ctx, &course.UUID)
+	if err != nil {
+		return err
+	}
+	if len(prerequisites) == 0 {
+		return nil
+	}
+	student, err := u.Get.GetStudent(ctx, email)
+	if err != nil {
+		return err
+	}
+	if student == nil {
+		return &domain.NotFoundError{Kind: "student", Key: *email}
+	}
+
+	missing := []*domain.Course{}
+	for _, prerequisite := range prerequisites {
+		enrollment, err := u.Get.GetEnrollment(ctx, &student.UUID, &prerequisite.UUID)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil || enrollment.Status != domain.EnrollmentStatusCompleted {
+			missing = append(missing, prerequisite)
+		}
+	}
+	if len(missing) > 0 {
+		return &domain.MissingPrerequisitesError{CourseTitle: course.Title, Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+)
+
+// newPrerequisiteGraphRepository serves courses and prerequisite edges from
+// memory. Courses are titled after their UUIDs
+func newPrerequisiteGraphRepository(graph map[string][]string) *mock.MockGetRepository {
+	get := mock.NewMockGetRepository()
+	newCourse := func(uuid string) *domain.Course {
+		c := &domain.Course{Title: uuid}
+		c.UUID = uuid
+		return c
+	}
+	get.MockGetCourseByUUID = func(ctx context.Context, courseUUID *string) (*domain.Course, error) {
+		return newCourse(*courseUUID), nil
+	}
+	get.MockGetCourse = func(ctx context.Context, title *string) (*domain.Course, error) {
+		return newCourse(*title), nil
+	}
+	get.MockGetCoursePrerequisites = func(ctx context.Context, courseUUID *string) ([]*domain.Course, error) {
+		var courses []*domain.Course
+		for _, uuid := range graph[*courseUUID] {
+			courses = append(courses, newCourse(uuid))
+		}
+		return courses, nil
+	}
+	return get
+}
+
+func TestUsecase_AddCoursePrerequisite(t *testing.T) {
+	ctx := context.Background()
+	graph := map[string][]string{
+		"go":          {"basics"},
+		"concurrency": {"go"},
+	}
+
+	tests := []struct {
+		name         string
+		course       string
+		prerequisite string
+		cycle        bool
+		wantAdded    bool
+		wantErr      error
+	}{
+		{
+			name:         "Happy case - new prerequisite",
+			course:       "concurrency",
+			prerequisite: "basics",
+			wantAdded:    true,
+		},
+		{
+			name:         "Sad case - cycle",
+			course:       "basics",
+			prerequisite: "concurrency",
+			cycle:        true,
+			wantAdded:    true,
+			wantErr:      domain.ErrPrerequisiteCycle,
+		},
+		{
+			name:         "Sad case - self reference",
+			course:       "go",
+			prerequisite: "go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create := mock.NewMockCreateRepository()
+			added := false
+			create.MockAddCoursePrerequisite = func(ctx context.Context, prerequisite *domain.CoursePrerequisite) error {
+				added = true
+				if tt.cycle {
+					// the repository checks for cycles as it adds the prerequisite
+					return domain.ErrPrerequisiteCycle
+				}
+				return nil
+			}
+			u := course.NewUsecase(create, newPrerequisiteGraphRepository(graph), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			err := u.AddCoursePrerequisite(ctx, &tt.course, &tt.prerequisite)
+			if (err != nil) != (tt.wantErr != nil || !tt.wantAdded) {
+				t.Fatalf("Usecase.AddCoursePrerequisite() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Usecase.AddCoursePrerequisite() error = %v, want %v", err, tt.wantErr)
+			}
+			if added != tt.wantAdded {
+				t.Errorf("expected the prerequisite to be added %v", tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetPrerequisiteTree(t *testing.T) {
+	graph := map[string][]string{
+		"go":          {"basics"},
+		"concurrency": {"go"},
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), newPrerequisiteGraphRepository(graph), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+	courseUUID := "concurrency"
+
+	tree, err := u.GetPrerequisiteTree(context.Background(), &courseUUID)
+	if err != nil {
+		t.Fatalf("Usecase.GetPrerequisiteTree() error = %v", err)
+	}
+	if len(tree.Prerequisites) != 1 || tree.Prerequisites[0].Course.UUID != "go" {
+		t.Fatalf("expected go to be the direct prerequisite, got %+v", tree.Prerequisites)
+	}
+	if len(tree.Prerequisites[0].Prerequisites) != 1 || tree.Prerequisites[0].Prerequisites[0].Course.UUID != "basics" {
+		t.Errorf("expected basics to be the indirect prerequisite")
+	}
+}
+
+func TestUsecase_AssignCourseToStudent_Prerequisites(t *testing.T) {
+	ctx := context.Background()
+	email := "student@example.com"
+	graph := map[string][]string{
+		"concurrency": {"go", "basics"},
+	}
+
+	tests := []struct {
+		name        string
+		courseTitle string
+		completed   map[string]bool
+		override    bool
+		wantMissing []string
+	}{
+		{
+			name:        "Happy case - prerequisites completed",
+			courseTitle: "concurrency",
+			completed:   map[string]bool{"go": true, "basics": true},
+		},
+		{
+			name:        "Happy case - admin override",
+			courseTitle: "concurrency",
+			override:    true,
+		},
+		{
+			name:        "Happy case - no prerequisites",
+			courseTitle: "basics",
+		},
+		{
+			name:        "Sad case - missing prerequisites",
+			courseTitle: "concurrency",
+			completed:   map[string]bool{"basics": true},
+			wantMissing: []string{"go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := newPrerequisiteGraphRepository(graph)
+			get.MockGetEnrollment = func(ctx context.Context, studentUUID, courseUUID *string) (*domain.StudentCourse, error) {
+				if !tt.completed[*courseUUID] {
+					return nil, nil
+				}
+				return &domain.StudentCourse{Status: domain.EnrollmentStatusCompleted}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.AssignCourseToStudent(ctx, &email, &tt.courseTitle, tt.override, "")
+			if tt.wantMissing == nil {
+				if err != nil {
+					t.Errorf("Usecase.AssignCourseToStudent() error = %v", err)
+				}
+				return
+			}
+			var missing *domain.MissingPrerequisitesError
+			if !errors.As(err, &missing) {
+				t.Fatalf("expected a MissingPrerequisitesError, got %v", err)
+			}
+			if len(missing.Missing) != len(tt.wantMissing) || missing.Missing[0].UUID != tt.wantMissing[0] {
+				t.Errorf("expected missing prerequisites %v, got %v", tt.wantMissing, missing.Missing)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+)
+
+// SetCoursePrice adds a price to a course's price list, effective
+// immediately or from a later date. Past prices are kept as history, so
+// prices can be scheduled but not backdated
+func (u *Usecase) SetCoursePrice(
+	ctx context.Context,
+	courseUUID *string,
+	price domain.Money,
+	effectiveFrom *time.Time,
+) (*domain.CoursePrice, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	currency, err := domain.NormalizeCurrency(price.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if price.Amount <= 0 {
+		return nil, fmt.Errorf("course's price must be positive")
+	}
+	now := time.Now()
+	if effectiveFrom == nil {
+		effectiveFrom = &now
+	}
+	if effectiveFrom.Before(now.Add(-time.Minute)) {
+		return nil, fmt.Errorf("price changes can not be backdated")
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, courseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, &domain.NotFoundError{Kind: "course", Key: *courseUUID}
+	}
+
+	return u.Create.CreateCoursePrice(ctx, &domain.CoursePrice{
+		CourseUUID:    *courseUUID,
+		Price:         domain.Money{Amount: price.Amount, Currency: currency},
+		EffectiveFrom: effectiveFrom.UTC(),
+	})
+}
+
+// GetCoursePrices returns a course's current price in each currency it is
+// listed in
+func (u *Usecase) GetCoursePrices(
+	ctx context.Context,
+	courseUUID *string,
+) ([]*domain.CoursePrice, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	return u.Get.GetCurrentCoursePrices(ctx, courseUUID, time.Now())
+}
+
+// GetCoursePriceHistory returns every price of a course, past and scheduled,
+// optionally in a single currency
+func (u *Usecase) GetCoursePriceHistory(
+	ctx context.Context,
+	courseUUID *string,
+	currency string,
+) ([]*domain.CoursePrice, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	if currency != "" {
+		normalized, err := domain.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = normalized
+	}
+	return u.Get.GetCoursePriceHistory(ctx, courseUUID, currency)
+}
+
+// LocalizeCoursePrice sets the course's price in the requested currency. The
+// course's own price in that currency is used when it is listed in it,
+// otherwise its base currency price is converted at the current rate. A
+// course that isn't listed in any currency returns ErrCourseNotPriced
+func (u *Usecase) LocalizeCoursePrice(
+	ctx context.Context,
+	course *domain.Course,
+	currency string,
+) error {
+	currency, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return err
+	}
+	prices, err := u.Get.GetCurrentCoursePrices(ctx, &course.UUID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var source *domain.Money
+	for _, price := range prices {
+		if price.Price.Currency == currency {
+			course.LocalPrice = &domain.Money{Amount: price.Price.Amount, Currency: currency}
+			return nil
+		}
+		if price.Price.Currency == domain.BaseCurrency || source == nil {
+			source = &domain.Money{Amount: price.Price.Amount, Currency: price.Price.Currency}
+		}
+	}
+	if source == nil {
+		return fmt.Errorf("%w: %s", domain.ErrCourseNotPriced, course.UUID)
+	}
+
+	local, err := fx.Convert(ctx, u.Rates, *source, currency)
+	if err != nil {
+		return err
+	}
+	course.LocalPrice = &local
+	return nil
+}
@@ -0,0 +1,162 @@
+package usecase_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/fx"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_SetCoursePrice(t *testing.T) {
+	ctx := context.Background()
+	courseUUID := gofakeit.UUID()
+	past := time.Now().Add(-24 * time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		courseUUID    string
+		price         domain.Money
+		effectiveFrom *time.Time
+		wantErr       bool
+	}{
+		{
+			name:       "Happy case - effective now",
+			courseUUID: courseUUID,
+			price:      domain.Money{Amount: 4999, Currency: "kes"},
+		},
+		{
+			name:          "Happy case - scheduled",
+			courseUUID:    courseUUID,
+			price:         domain.Money{Amount: 4599, Currency: "EUR"},
+			effectiveFrom: &future,
+		},
+		{
+			name:          "Sad case - backdated",
+			courseUUID:    courseUUID,
+			price:         domain.Money{Amount: 4599, Currency: "EUR"},
+			effectiveFrom: &past,
+			wantErr:       true,
+		},
+		{
+			name:       "Sad case - unsupported currency",
+			courseUUID: courseUUID,
+			price:      domain.Money{Amount: 4599, Currency: "XYZ"},
+			wantErr:    true,
+		},
+		{
+			name:       "Sad case - free",
+			courseUUID: courseUUID,
+			price:      domain.Money{Currency: "USD"},
+			wantErr:    true,
+		},
+		{
+			name:       "Sad case - unknown course",
+			courseUUID: gofakeit.UUID(),
+			price:      domain.Money{Amount: 4599, Currency: "USD"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetCourseByUUID = func(ctx context.Context, uuid *string) (*domain.Course, error) {
+				if *uuid != courseUUID {
+					return nil, nil
+				}
+				return &domain.Course{AbstractBase: domain.AbstractBase{UUID: courseUUID}}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			price, err := u.SetCoursePrice(ctx, &tt.courseUUID, tt.price, tt.effectiveFrom)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.SetCoursePrice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if price.Price.Currency != strings.ToUpper(tt.price.Currency) {
+				t.Errorf("expected the currency code to be normalized, got %s", price.Price.Currency)
+			}
+			if price.EffectiveFrom.IsZero() {
+				t.Errorf("expected the price to have an effective date")
+			}
+		})
+	}
+}
+
+func TestUsecase_LocalizeCoursePrice(t *testing.T) {
+	ctx := context.Background()
+	rates := fx.NewStaticRates()
+	if err := rates.Set("USD", "KES", big.NewRat(130, 1)); err != nil {
+		t.Fatalf("can't set test rate: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		prices   []*domain.CoursePrice
+		currency string
+		want     domain.Money
+		wantErr  bool
+	}{
+		{
+			name: "Happy case - listed in the currency",
+			prices: []*domain.CoursePrice{
+				{Price: domain.Money{Amount: 2000, Currency: "USD"}},
+				{Price: domain.Money{Amount: 199900, Currency: "KES"}},
+			},
+			currency: "KES",
+			want:     domain.Money{Amount: 199900, Currency: "KES"},
+		},
+		{
+			name: "Happy case - converted from the base currency",
+			prices: []*domain.CoursePrice{
+				{Price: domain.Money{Amount: 2000, Currency: "USD"}},
+			},
+			currency: "kes",
+			want:     domain.Money{Amount: 260000, Currency: "KES"},
+		},
+		{
+			name:     "Sad case - course without a price list",
+			currency: "KES",
+			wantErr:  true,
+		},
+		{
+			name: "Sad case - no exchange rate",
+			prices: []*domain.CoursePrice{
+				{Price: domain.Money{Amount: 2000, Currency: "USD"}},
+			},
+			currency: "EUR",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetCurrentCoursePrices = func(ctx context.Context, courseUUID *string, at time.Time) ([]*domain.CoursePrice, error) {
+				return tt.prices, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, rates, testPayments)
+
+			c := &domain.Course{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, Price: 23}
+			err := u.LocalizeCoursePrice(ctx, c, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.LocalizeCoursePrice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if c.LocalPrice == nil || *c.LocalPrice != tt.want {
+				t.Errorf("Usecase.LocalizeCoursePrice() = %v, want %v", c.LocalPrice, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_RecordLessonProgress(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	lessonUUID := gofakeit.UUID()
+	courseUUID := gofakeit.UUID()
+
+	type args struct {
+		ctx      context.Context
+		progress *domain.LessonProgress
+	}
+	tests := []struct {
+		name           string
+		args           args
+		enrolled       bool
+		completed      int64
+		total          int64
+		wantErr        bool
+		wantStatus     domain.ProgressStatus
+		wantCompletion bool
+	}{
+		{
+			name: "Happy case - lesson started",
+			args: args{
+				ctx: ctx,
+				progress: &domain.LessonProgress{
+					StudentUUID:         studentUUID,
+					LessonUUID:          lessonUUID,
+					Status:              domain.ProgressStatusInProgress,
+					LastPositionSeconds: 42,
+				},
+			},
+			enrolled:   true,
+			wantStatus: domain.ProgressStatusInProgress,
+		},
+		{
+			name: "Happy case - last lesson completes the course",
+			args: args{
+				ctx: ctx,
+				progress: &domain.LessonProgress{
+					StudentUUID: studentUUID,
+					LessonUUID:  lessonUUID,
+					Status:      domain.ProgressStatusCompleted,
+				},
+			},
+			enrolled:       true,
+			completed:      3,
+			total:          3,
+			wantStatus:     domain.ProgressStatusCompleted,
+			wantCompletion: true,
+		},
+		{
+			name: "Happy case - course not yet complete",
+			args: args{
+				ctx: ctx,
+				progress: &domain.LessonProgress{
+					StudentUUID: studentUUID,
+					LessonUUID:  lessonUUID,
+					Status:      domain.ProgressStatusCompleted,
+				},
+			},
+			enrolled:   true,
+			completed:  2,
+			total:      3,
+			wantStatus: domain.ProgressStatusCompleted,
+		},
+		{
+			name: "Sad case - student not enrolled",
+			args: args{
+				ctx: ctx,
+				progress: &domain.LessonProgress{
+					StudentUUID: studentUUID,
+					LessonUUID:  lessonUUID,
+					Status:      domain.ProgressStatusInProgress,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - unknown status",
+			args: args{
+				ctx: ctx,
+				progress: &domain.LessonProgress{
+					StudentUUID: studentUUID,
+					LessonUUID:  lessonUUID,
+					Status:      domain.ProgressStatus("skipped"),
+				},
+			},
+			enrolled: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			update := mock.NewMockUpdateRepository()
+			publisher := eventsmock.NewMockPublisher()
+			get.MockGetModule = func(ctx context.Context, moduleUUID *string) (*domain.Module, error) {
+				return &domain.Module{CourseUUID: courseUUID}, nil
+			}
+			enrollmentCompleted := false
+			get.MockGetEnrollment = func(ctx context.Context, studentUUID, courseUUID *string) (*domain.StudentCourse, error) {
+				if !tt.enrolled {
+					return nil, nil
+				}
+				status := domain.EnrollmentStatusActive
+				if enrollmentCompleted {
+					status = domain.EnrollmentStatusCompleted
+				}
+				return &domain.StudentCourse{StudentUUID: *studentUUID, CourseUUID: *courseUUID, Status: status}, nil
+			}
+			get.MockGetCourseCompletion = func(ctx context.Context, studentUUID, courseUUID *string) (int64, int64, error) {
+				return tt.completed, tt.total, nil
+			}
+			update.MockCompleteEnrollment = func(ctx context.Context, studentUUID, courseUUID *string, completedAt time.Time) error {
+				enrollmentCompleted = true
+				return nil
+			}
+			var published []domain.Event
+			publisher.MockPublish = func(ctx context.Context, event domain.Event) error {
+				published = append(published, event)
+				return nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, update, mock.NewMockDeleteRepository(), publisher, testSigner, testSearch, testRates, testPayments)
+
+			progress, err := u.RecordLessonProgress(tt.args.ctx, tt.args.progress)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.RecordLessonProgress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if progress.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, progress.Status)
+			}
+			if progress.StartedAt == nil {
+				t.Errorf("expected progress to have a started at timestamp")
+			}
+			if tt.wantStatus == domain.ProgressStatusCompleted && progress.CompletedAt == nil {
+				t.Errorf("expected progress to have a completed at timestamp")
+			}
+			if enrollmentCompleted != tt.wantCompletion {
+				t.Errorf("expected enrollment completion to be %v", tt.wantCompletion)
+			}
+			if tt.wantCompletion && (len(published) != 1 || published[0].EventName() != "course.completed") {
+				t.Errorf("expected a course.completed event, got %v", published)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetStudentEnrollments(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	get := mock.NewMockGetRepository()
+	get.MockGetStudentEnrollments = func(ctx context.Context, studentUUID *string) ([]*domain.Enrollment, error) {
+		return []*domain.Enrollment{{Course: &domain.Course{Title: gofakeit.LastName()}}}, nil
+	}
+	get.MockGetCourseCompletion = func(ctx context.Context, studentUUID, courseUUID *string) (int64, int64, error) {
+		return 1, 3, nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	enrollments, err := u.GetStudentEnrollments(ctx, &studentUUID)
+	if err != nil {
+		t.Fatalf("Usecase.GetStudentEnrollments() error = %v", err)
+	}
+	if len(enrollments) != 1 {
+		t.Fatalf("expected 1 enrollment, got %d", len(enrollments))
+	}
+	if enrollments[0].CompletionPercentage != 33.33 {
+		t.Errorf("expected 33.33%% completion, got %v", enrollments[0].CompletionPercentage)
+	}
+}
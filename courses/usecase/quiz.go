@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// CreateQuiz attaches a quiz to a quiz lesson
+func (u *Usecase) CreateQuiz(
+	ctx context.Context,
+	quiz *domain.Quiz,
+) (*domain.Quiz, error) {
+	if quiz.Title == "" {
+		return nil, fmt.Errorf("quiz's title can not be empty")
+	}
+	if quiz.PassThreshold <= 0 || quiz.PassThreshold > 100 {
+		return nil, fmt.Errorf("quiz's pass threshold must be between 0 and 100")
+	}
+	if len(quiz.Questions) == 0 {
+		return nil, fmt.Errorf("quiz must have at least one question")
+	}
+	for i, question := range quiz.Questions {
+		if err := validateQuestion(question); err != nil {
+			return nil, fmt.Errorf("question %d: %w", i+1, err)
+		}
+		question.Position = i + 1
+		if question.Points == 0 {
+			question.Points = 1
+		}
+	}
+
+	lesson, err := u.Get.GetLesson(ctx, &quiz.LessonUUID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil {
+		return nil, &domain.NotFoundError{Kind: "lesson", Key: quiz.LessonUUID}
+	}
+	if lesson.Type != domain.LessonTypeQuiz {
+		return nil, fmt.Errorf("quizzes can only be attached to quiz lessons")
+	}
+	return u.Create.CreateQuiz(ctx, quiz)
+}
+
+// GetQuiz returns a quiz and its questions, without the correct answers
+func (u *Usecase) GetQuiz(
+	ctx context.Context,
+	quizUUID *string,
+) (*domain.Quiz, error) {
+	if quizUUID == nil || *quizUUID == "" {
+		return nil, fmt.Errorf("quiz's UUID can not be empty")
+	}
+	return u.Get.GetQuiz(ctx, quizUUID)
+}
+
+// SubmitAttempt grades a student's answers to a quiz. ErrNoAttemptsLeft is
+// returned when the student has used all their attempts. A passing attempt
+// completes the quiz's lesson in the student's progress
+func (u *Usecase) SubmitAttempt(
+	ctx context.Context,
+	attempt *domain.Attempt,
+) (*domain.Attempt, error) {
+	if attempt.StudentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	quiz, err := u.GetQuiz(ctx, &attempt.QuizUUID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz == nil {
+		return nil, &domain.NotFoundError{Kind: "quiz", Key: attempt.QuizUUID}
+	}
+	enrollment, err := u.enrollmentForLesson(ctx, &attempt.StudentUUID, &quiz.LessonUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt.Score = gradeQuiz(quiz, attempt.Answers)
+	attempt.Passed = attempt.Score >= quiz.PassThreshold
+	attempt.SubmittedAt = time.Now()
+	// the attempts left are counted as the attempt is saved
+	saved, err := u.Create.CreateAttempt(ctx, attempt, enrollment.CourseUUID, quiz.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	if saved.Passed {
+		_, err := u.RecordLessonProgress(ctx, &domain.LessonProgress{
+			StudentUUID: saved.StudentUUID,
+			LessonUUID:  quiz.LessonUUID,
+			Status:      domain.ProgressStatusCompleted,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return saved, nil
+}
+
+// validateQuestion checks that a question can be auto-graded
+func validateQuestion(question *domain.Question) error {
+	if !question.Type.IsValid() {
+		return fmt.Errorf("question type %q is not supported", question.Type)
+	}
+	if question.Prompt == "" {
+		return fmt.Errorf("question's prompt can not be empty")
+	}
+	if len(question.CorrectAnswers) == 0 {
+		return fmt.Errorf("question must have a correct answer")
+	}
+	if question.Type != domain.QuestionTypeSingleChoice && question.Type != domain.QuestionTypeMultipleChoice {
+		return nil
+	}
+
+	if len(question.Options) < 2 {
+		return fmt.Errorf("choice questions must have at least two options")
+	}
+	if question.Type == domain.QuestionTypeSingleChoice && len(question.CorrectAnswers) != 1 {
+		return fmt.Errorf("single choice questions must have exactly one correct answer")
+	}
+	options := make(map[string]bool, len(question.Options))
+	for _, option := range question.Options {
+		options[option] = true
+	}
+	for _, answer := range question.CorrectAnswers {
+		if !options[answer] {
+			return fmt.Errorf("correct answer %q is not one of the options", answer)
+		}
+	}
+	return nil
+}
+
+// gradeQuiz returns the percentage of the quiz's points earned by the answers
+func gradeQuiz(quiz *domain.Quiz, answers domain.Answers) float64 {
+	var earned, total uint
+	for _, question := range quiz.Questions {
+		total += question.Points
+		if isCorrect(question, answers[question.UUID]) {
+			earned += question.Points
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(earned)/float64(total)*10000) / 100
+}
+
+// isCorrect grades the answers given to a single question
+func isCorrect(question *domain.Question, given []string) bool {
+	switch question.Type {
+	case domain.QuestionTypeSingleChoice:
+		return len(given) == 1 && given[0] == question.CorrectAnswers[0]
+	case domain.QuestionTypeMultipleChoice:
+		return sameSet(given, question.CorrectAnswers)
+	case domain.QuestionTypeShortText:
+		if len(given) != 1 {
+			return false
+		}
+		for _, accepted := range question.CorrectAnswers {
+			if strings.TrimSpace(given[0]) == strings.TrimSpace(accepted) {
+				return true
+			}
+		}
+	case domain.QuestionTypeCodeOutput:
+		if len(given) != 1 {
+			return false
+		}
+		for _, accepted := range question.CorrectAnswers {
+			if normalizeOutput(given[0]) == normalizeOutput(accepted) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameSet reports whether both lists hold the same distinct values
+func sameSet(a, b []string) bool {
+	dedupe := func(values []string) []string {
+		seen := make(map[string]bool, len(values))
+		out := make([]string, 0, len(values))
+		for _, v := range values {
+			if !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	x, y := dedupe(a), dedupe(b)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeOutput ignores line ending styles and trailing whitespace so that
+// program output compares the same across platforms
+func normalizeOutput(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,228 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func newTestQuiz() *domain.Quiz {
+	quiz := &domain.Quiz{
+		LessonUUID:    gofakeit.UUID(),
+		Title:         "Go basics",
+		PassThreshold: 75,
+		MaxAttempts:   2,
+		Questions: []*domain.Question{
+			{
+				Type:           domain.QuestionTypeSingleChoice,
+				Prompt:         "Which keyword starts a goroutine?",
+				Options:        domain.StringList{"go", "async", "spawn"},
+				CorrectAnswers: domain.StringList{"go"},
+				Points:         1,
+			},
+			{
+				Type:           domain.QuestionTypeMultipleChoice,
+				Prompt:         "Which of these are reference types?",
+				Options:        domain.StringList{"map", "slice", "int", "array"},
+				CorrectAnswers: domain.StringList{"map", "slice"},
+				Points:         1,
+			},
+			{
+				Type:           domain.QuestionTypeShortText,
+				Prompt:         "What is the zero value of a pointer?",
+				CorrectAnswers: domain.StringList{"nil"},
+				Points:         1,
+			},
+			{
+				Type:           domain.QuestionTypeCodeOutput,
+				Prompt:         "What does fmt.Println(1, 2) print?",
+				CorrectAnswers: domain.StringList{"1 2\n"},
+				Points:         1,
+			},
+		},
+	}
+	quiz.UUID = gofakeit.UUID()
+	for _, question := range quiz.Questions {
+		question.UUID = gofakeit.UUID()
+	}
+	return quiz
+}
+
+func TestUsecase_SubmitAttempt(t *testing.T) {
+	ctx := context.Background()
+	quiz := newTestQuiz()
+	studentUUID := gofakeit.UUID()
+	q := quiz.Questions
+
+	type args struct {
+		ctx     context.Context
+		answers domain.Answers
+	}
+	tests := []struct {
+		name              string
+		args              args
+		noAttemptsLeft    bool
+		wantErr           bool
+		wantScore         float64
+		wantPassed        bool
+		wantLessonUpdated bool
+	}{
+		{
+			name: "Happy case - all correct",
+			args: args{
+				ctx: ctx,
+				answers: domain.Answers{
+					q[0].UUID: {"go"},
+					q[1].UUID: {"slice", "map"},
+					q[2].UUID: {" nil "},
+					q[3].UUID: {"1 2  \r\n"},
+				},
+			},
+			wantScore:         100,
+			wantPassed:        true,
+			wantLessonUpdated: true,
+		},
+		{
+			name: "Happy case - below the pass threshold",
+			args: args{
+				ctx: ctx,
+				answers: domain.Answers{
+					q[0].UUID: {"go"},
+					q[1].UUID: {"map"},
+					q[2].UUID: {"null"},
+					q[3].UUID: {"1 2"},
+				},
+			},
+			wantScore:  50,
+			wantPassed: false,
+		},
+		{
+			name: "Sad case - attempts exhausted",
+			args: args{
+				ctx:     ctx,
+				answers: domain.Answers{},
+			},
+			noAttemptsLeft: true,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			update := mock.NewMockUpdateRepository()
+			get.MockGetQuiz = func(ctx context.Context, quizUUID *string) (*domain.Quiz, error) {
+				return quiz, nil
+			}
+			get.MockGetLesson = func(ctx context.Context, lessonUUID *string) (*domain.Lesson, error) {
+				return &domain.Lesson{Type: domain.LessonTypeQuiz}, nil
+			}
+			courseUUID := gofakeit.UUID()
+			get.MockGetEnrollment = func(ctx context.Context, studentUUID, _ *string) (*domain.StudentCourse, error) {
+				return &domain.StudentCourse{StudentUUID: *studentUUID, CourseUUID: courseUUID}, nil
+			}
+			create := mock.NewMockCreateRepository()
+			create.MockCreateAttempt = func(ctx context.Context, attempt *domain.Attempt, enrolledIn string, maxAttempts uint) (*domain.Attempt, error) {
+				if enrolledIn != courseUUID || maxAttempts != quiz.MaxAttempts {
+					t.Errorf("expected the attempt to be limited to %d in course %s, got %d in %s", quiz.MaxAttempts, courseUUID, maxAttempts, enrolledIn)
+				}
+				if tt.noAttemptsLeft {
+					return nil, domain.ErrNoAttemptsLeft
+				}
+				return attempt, nil
+			}
+			lessonUpdated := false
+			update.MockSaveLessonProgress = func(ctx context.Context, progress *domain.LessonProgress) (*domain.LessonProgress, error) {
+				lessonUpdated = progress.LessonUUID == quiz.LessonUUID && progress.Status == domain.ProgressStatusCompleted
+				return progress, nil
+			}
+			u := course.NewUsecase(create, get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			attempt, err := u.SubmitAttempt(tt.args.ctx, &domain.Attempt{
+				QuizUUID:    quiz.UUID,
+				StudentUUID: studentUUID,
+				Answers:     tt.args.answers,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.SubmitAttempt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if tt.noAttemptsLeft && !errors.Is(err, domain.ErrNoAttemptsLeft) {
+					t.Errorf("expected ErrNoAttemptsLeft, got %v", err)
+				}
+				if lessonUpdated {
+					t.Errorf("expected a refused attempt not to update progress")
+				}
+				return
+			}
+			if attempt.Score != tt.wantScore {
+				t.Errorf("expected score %v, got %v", tt.wantScore, attempt.Score)
+			}
+			if attempt.Passed != tt.wantPassed {
+				t.Errorf("expected passed to be %v", tt.wantPassed)
+			}
+			if lessonUpdated != tt.wantLessonUpdated {
+				t.Errorf("expected lesson progress update to be %v", tt.wantLessonUpdated)
+			}
+		})
+	}
+}
+
+func TestUsecase_CreateQuiz(t *testing.T) {
+	u := newMockTestUsecase()
+	ctx := context.Background()
+	invalidChoice := newTestQuiz()
+	invalidChoice.Questions[0].CorrectAnswers = domain.StringList{"goroutine"}
+
+	type args struct {
+		ctx  context.Context
+		quiz *domain.Quiz
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Sad case - correct answer is not an option",
+			args: args{
+				ctx:  ctx,
+				quiz: invalidChoice,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - no questions",
+			args: args{
+				ctx: ctx,
+				quiz: &domain.Quiz{
+					LessonUUID:    gofakeit.UUID(),
+					Title:         "Empty",
+					PassThreshold: 50,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - not a quiz lesson",
+			args: args{
+				ctx:  ctx,
+				quiz: newTestQuiz(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.CreateQuiz(tt.args.ctx, tt.args.quiz)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateQuiz() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
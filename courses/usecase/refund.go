@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+)
+
+// refundRetryDelay is how old a refund the payment provider never
+// acknowledged must be before it is sent again
+const refundRetryDelay = 5 * time.Minute
+
+// RefundPayment refunds all or part of a payment, the whole of what is left
+// of it when no amount is given. Subscription charges are the only payments
+// there are, so dropping a course refunds part of the charge for the period
+// the student enrolled in. Refunds for dropping a course are only given within
+// the cooling-off period, and unenroll the student when the refund policy
+// says so. It returns nil when the payment does not exist
+func (u *Usecase) RefundPayment(
+	ctx context.Context,
+	chargeUUID *string,
+	refund *domain.Refund,
+) (*domain.Refund, error) {
+	if chargeUUID == nil || *chargeUUID == "" {
+		return nil, fmt.Errorf("payment's UUID can not be empty")
+	}
+	refund.Reason = strings.TrimSpace(refund.Reason)
+	if refund.Reason == "" {
+		return nil, fmt.Errorf("refund's reason can not be empty")
+	}
+	if refund.Amount.Amount < 0 {
+		return nil, fmt.Errorf("refund's amount can not be negative")
+	}
+	charge, err := u.Get.GetSubscriptionCharge(ctx, chargeUUID)
+	if err != nil {
+		return nil, err
+	}
+	if charge == nil {
+		return nil, nil
+	}
+	if charge.Status != domain.ChargeStatusSucceeded {
+		return nil, fmt.Errorf("payment %s did not go through, there is nothing to refund", *chargeUUID)
+	}
+	if refund.Amount.Currency == "" {
+		refund.Amount.Currency = charge.Amount.Currency
+	}
+	if !strings.EqualFold(refund.Amount.Currency, charge.Amount.Currency) {
+		return nil, fmt.Errorf("payment %s was made in %s", *chargeUUID, charge.Amount.Currency)
+	}
+	refund.Amount.Currency = charge.Amount.Currency
+	if refund.Amount.Amount == 0 {
+		refunded, err := u.refundedAmount(ctx, chargeUUID)
+		if err != nil {
+			return nil, err
+		}
+		refund.Amount.Amount = charge.Amount.Amount - refunded
+		if refund.Amount.Amount <= 0 {
+			return nil, domain.ErrRefundExceedsCharge
+		}
+	}
+
+	subscription, err := u.Get.GetSubscription(ctx, &charge.SubscriptionUUID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, &domain.NotFoundError{Kind: "subscription", Key: charge.SubscriptionUUID}
+	}
+	refund.StudentUUID = subscription.StudentUUID
+	if refund.CourseUUID != "" {
+		if err := u.checkCoolingOff(ctx, refund.StudentUUID, refund.CourseUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	refund.ChargeUUID = charge.UUID
+	refund.Status = domain.RefundStatusPending
+	created, err := u.Create.CreateRefund(ctx, refund)
+	if err != nil {
+		return nil, err
+	}
+	sent, err := u.sendRefund(ctx, charge, created)
+	switch {
+	case errors.Is(err, payments.ErrDeclined):
+		return nil, fmt.Errorf("can't refund payment %s: %w", *chargeUUID, err)
+	case err != nil:
+		// the provider may have taken the refund before failing, so it stays
+		// pending and RetryRefunds sends it again
+		log.WithField("refund", created.UUID).Warnf("refund will be sent again: %v", err)
+		return created, nil
+	}
+	return sent, nil
+}
+
+// RetryRefunds sends the pending refunds the payment provider never
+// acknowledged again. It is run periodically by the renewal scheduler and
+// returns how many refunds it sent. Refunds keep their idempotency key, so a
+// refund the provider did make isn't made twice
+func (u *Usecase) RetryRefunds(
+	ctx context.Context,
+	now time.Time,
+) (int, error) {
+	// leave refunds that are being sent right now alone
+	refunds, err := u.Get.ListUnsentRefunds(ctx, now.Add(-refundRetryDelay))
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	var firstErr error
+	for _, refund := range refunds {
+		charge, err := u.Get.GetSubscriptionCharge(ctx, &refund.ChargeUUID)
+		if err == nil && charge == nil {
+			err = &domain.NotFoundError{Kind: "payment", Key: refund.ChargeUUID}
+		}
+		if err == nil {
+			_, err = u.sendRefund(ctx, charge, refund)
+		}
+		if err != nil && !errors.Is(err, payments.ErrDeclined) {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("refund %s: %w", refund.UUID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	if firstErr != nil {
+		return sent, fmt.Errorf("%d of %d unsent refunds failed, first error: %w", len(refunds)-sent, len(refunds), firstErr)
+	}
+	return sent, nil
+}
+
+// GetPaymentRefunds returns the refunds of a payment, oldest first
+func (u *Usecase) GetPaymentRefunds(
+	ctx context.Context,
+	chargeUUID *string,
+) ([]*domain.Refund, error) {
+	if chargeUUID == nil || *chargeUUID == "" {
+		return nil, fmt.Errorf("payment's UUID can not be empty")
+	}
+	return u.Get.GetChargeRefunds(ctx, chargeUUID)
+}
+
+// GetLedgerBalances returns the balance of every ledger account
+func (u *Usecase) GetLedgerBalances(
+	ctx context.Context,
+) ([]*domain.LedgerBalance, error) {
+	return u.Get.GetLedgerBalances(ctx)
+}
+
+// sendRefund asks the payment provider for a pending refund, keyed by the
+// refund's UUID. A refund the provider declines fails, and no longer counts
+// against the charge so it can be asked for again. Any other error leaves it
+// pending
+func (u *Usecase) sendRefund(
+	ctx context.Context,
+	charge *domain.SubscriptionCharge,
+	refund *domain.Refund,
+) (*domain.Refund, error) {
+	reference, status, err := u.Payments.Refund(ctx, charge.Reference, refund.Amount, refund.Reason, "refund:"+refund.UUID)
+	if errors.Is(err, payments.ErrDeclined) {
+		refund.Status = domain.RefundStatusFailed
+		refund.FailureReason = err.Error()
+		if _, saveErr := u.Update.UpdateRefund(ctx, refund); saveErr != nil {
+			log.WithField("refund", refund.UUID).Errorf("can't record declined refund: %v", saveErr)
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	refund.Reference = reference
+	return u.settleRefund(ctx, refund, status, "")
+}
+
+// settleRefund records where a refund is at with the payment provider. Once
+// it has gone through the student is unenrolled from the dropped course when
+// the refund policy says so
+func (u *Usecase) settleRefund(
+	ctx context.Context,
+	refund *domain.Refund,
+	status domain.RefundStatus,
+	failureReason string,
+) (*domain.Refund, error) {
+	refund.Status = status
+	refund.FailureReason = failureReason
+	if status == domain.RefundStatusSucceeded && refund.CourseUUID != "" && u.RefundPolicy.Unenroll && !refund.Unenrolled {
+		// the money has already gone back, so the refund is recorded even
+		// when the student can't be unenrolled
+		if err := u.unenrollStudent(ctx, &refund.StudentUUID, &refund.CourseUUID); err != nil {
+			log.WithFields(log.Fields{
+				"refund":  refund.UUID,
+				"student": refund.StudentUUID,
+				"course":  refund.CourseUUID,
+			}).Errorf("can't unenroll student after refund: %v", err)
+		} else {
+			refund.Unenrolled = true
+		}
+	}
+	return u.Update.UpdateRefund(ctx, refund)
+}
+
+// refundedAmount adds up the refunds of a charge that haven't failed
+func (u *Usecase) refundedAmount(
+	ctx context.Context,
+	chargeUUID *string,
+) (int64, error) {
+	refunds, err := u.Get.GetChargeRefunds(ctx, chargeUUID)
+	if err != nil {
+		return 0, err
+	}
+	var refunded int64
+	for _, refund := range refunds {
+		if refund.Status != domain.RefundStatusFailed {
+			refunded += refund.Amount.Amount
+		}
+	}
+	return refunded, nil
+}
+
+// checkCoolingOff checks that a student enrolled in a course recently enough
+// to drop it for a refund
+func (u *Usecase) checkCoolingOff(
+	ctx context.Context,
+	studentUUID string,
+	courseUUID string,
+) error {
+	enrollment, err := u.Get.GetEnrollment(ctx, &studentUUID, &courseUUID)
+	if err != nil {
+		return err
+	}
+	if enrollment == nil {
+		return fmt.Errorf("student %s is not enrolled in course %s", studentUUID, courseUUID)
+	}
+	coolingOff := time.Duration(u.RefundPolicy.CoolingOffDays) * 24 * time.Hour
+	if enrollment.EnrolledAt == nil || time.Since(*enrollment.EnrolledAt) > coolingOff {
+		return fmt.Errorf("%w: students can drop a course for a refund within %d days of enrolling", domain.ErrCoolingOffOver, u.RefundPolicy.CoolingOffDays)
+	}
+	return nil
+}
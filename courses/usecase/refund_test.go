@@ -0,0 +1,364 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+	paymentsmock "github.com/MelvinKim/courses/infrastructure/payments/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_RefundPayment(t *testing.T) {
+	ctx := context.Background()
+	chargeUUID := gofakeit.UUID()
+	studentUUID := gofakeit.UUID()
+	courseUUID := gofakeit.UUID()
+	recently := time.Now().AddDate(0, 0, -3)
+	longAgo := time.Now().AddDate(0, -2, 0)
+
+	tests := []struct {
+		name           string
+		refund         *domain.Refund
+		chargeStatus   domain.ChargeStatus
+		missingCharge  bool
+		enrolledAt     *time.Time
+		unenroll       bool
+		providerStatus domain.RefundStatus
+		providerErr    error
+		createErr      error
+		wantAmount     int64
+		wantStatus     domain.RefundStatus
+		wantUnenrolled bool
+		wantUnsent     bool
+		wantErrIs      error
+		wantErr        bool
+	}{
+		{
+			name:       "Happy case - full refund of what is left",
+			refund:     &domain.Refund{Reason: "duplicate payment"},
+			wantAmount: 9900 - 2000,
+			wantStatus: domain.RefundStatusSucceeded,
+		},
+		{
+			name:       "Happy case - partial refund",
+			refund:     &domain.Refund{Amount: domain.Money{Amount: 500, Currency: "usd"}, Reason: "goodwill"},
+			wantAmount: 500,
+			wantStatus: domain.RefundStatusSucceeded,
+		},
+		{
+			name:           "Happy case - dropped course is unenrolled",
+			refund:         &domain.Refund{Amount: domain.Money{Amount: 500}, Reason: "dropped course", CourseUUID: courseUUID},
+			enrolledAt:     &recently,
+			unenroll:       true,
+			wantAmount:     500,
+			wantStatus:     domain.RefundStatusSucceeded,
+			wantUnenrolled: true,
+		},
+		{
+			name:       "Happy case - dropped course is kept when not configured",
+			refund:     &domain.Refund{Amount: domain.Money{Amount: 500}, Reason: "dropped course", CourseUUID: courseUUID},
+			enrolledAt: &recently,
+			wantAmount: 500,
+			wantStatus: domain.RefundStatusSucceeded,
+		},
+		{
+			name:           "Happy case - provider settles later",
+			refund:         &domain.Refund{Amount: domain.Money{Amount: 500}, Reason: "dropped course", CourseUUID: courseUUID},
+			enrolledAt:     &recently,
+			unenroll:       true,
+			providerStatus: domain.RefundStatusPending,
+			wantAmount:     500,
+			wantStatus:     domain.RefundStatusPending,
+		},
+		{
+			name:       "Sad case - cooling-off period over",
+			refund:     &domain.Refund{Reason: "dropped course", CourseUUID: courseUUID},
+			enrolledAt: &longAgo,
+			wantErrIs:  domain.ErrCoolingOffOver,
+		},
+		{
+			name:      "Sad case - more than the charge",
+			refund:    &domain.Refund{Amount: domain.Money{Amount: 9000}, Reason: "goodwill"},
+			createErr: domain.ErrRefundExceedsCharge,
+			wantErrIs: domain.ErrRefundExceedsCharge,
+		},
+		{
+			name:    "Sad case - another currency",
+			refund:  &domain.Refund{Amount: domain.Money{Amount: 500, Currency: "EUR"}, Reason: "goodwill"},
+			wantErr: true,
+		},
+		{
+			name:         "Sad case - failed charge",
+			refund:       &domain.Refund{Reason: "goodwill"},
+			chargeStatus: domain.ChargeStatusFailed,
+			wantErr:      true,
+		},
+		{
+			name:    "Sad case - no reason",
+			refund:  &domain.Refund{Amount: domain.Money{Amount: 500}, Reason: " "},
+			wantErr: true,
+		},
+		{
+			name:        "Sad case - provider refuses",
+			refund:      &domain.Refund{Amount: domain.Money{Amount: 500}, Reason: "goodwill"},
+			providerErr: fmt.Errorf("%w: charge is disputed", payments.ErrDeclined),
+			wantErrIs:   payments.ErrDeclined,
+			wantStatus:  domain.RefundStatusFailed,
+		},
+		{
+			name:        "Sad case - provider unreachable",
+			refund:      &domain.Refund{Amount: domain.Money{Amount: 500}, Reason: "goodwill"},
+			providerErr: errors.New("connection reset by peer"),
+			wantUnsent:  true,
+		},
+		{
+			name:          "Sad case - unknown payment",
+			refund:        &domain.Refund{Reason: "goodwill"},
+			missingCharge: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetSubscriptionCharge = func(ctx context.Context, uuid *string) (*domain.SubscriptionCharge, error) {
+				if tt.missingCharge {
+					return nil, nil
+				}
+				status := tt.chargeStatus
+				if status == "" {
+					status = domain.ChargeStatusSucceeded
+				}
+				return &domain.SubscriptionCharge{
+					AbstractBase:     domain.AbstractBase{UUID: *uuid},
+					SubscriptionUUID: gofakeit.UUID(),
+					Amount:           domain.Money{Amount: 9900, Currency: "USD"},
+					Status:           status,
+					Reference:        "ch_123",
+				}, nil
+			}
+			get.MockGetChargeRefunds = func(ctx context.Context, uuid *string) ([]*domain.Refund, error) {
+				return []*domain.Refund{
+					{Amount: domain.Money{Amount: 2000, Currency: "USD"}, Status: domain.RefundStatusSucceeded},
+					{Amount: domain.Money{Amount: 1000, Currency: "USD"}, Status: domain.RefundStatusFailed},
+				}, nil
+			}
+			get.MockGetSubscription = func(ctx context.Context, uuid *string) (*domain.Subscription, error) {
+				return &domain.Subscription{AbstractBase: domain.AbstractBase{UUID: *uuid}, StudentUUID: studentUUID}, nil
+			}
+			get.MockGetEnrollment = func(ctx context.Context, student, course *string) (*domain.StudentCourse, error) {
+				return &domain.StudentCourse{StudentUUID: *student, CourseUUID: *course, EnrolledAt: tt.enrolledAt}, nil
+			}
+			create := mock.NewMockCreateRepository()
+			create.MockCreateRefund = func(ctx context.Context, refund *domain.Refund) (*domain.Refund, error) {
+				if tt.createErr != nil {
+					return nil, tt.createErr
+				}
+				refund.UUID = gofakeit.UUID()
+				return refund, nil
+			}
+			update := mock.NewMockUpdateRepository()
+			var saved *domain.Refund
+			update.MockUpdateRefund = func(ctx context.Context, refund *domain.Refund) (*domain.Refund, error) {
+				saved = refund
+				return refund, nil
+			}
+			remove := mock.NewMockDeleteRepository()
+			unenrolled := false
+			remove.MockUnenrollStudent = func(ctx context.Context, student, course *string) error {
+				unenrolled = *student == studentUUID && *course == courseUUID
+				return nil
+			}
+			provider := paymentsmock.NewMockProvider()
+			var idempotencyKey string
+			provider.MockRefund = func(ctx context.Context, chargeReference string, amount domain.Money, reason string, key string) (string, domain.RefundStatus, error) {
+				idempotencyKey = key
+				if tt.providerErr != nil {
+					return "", "", tt.providerErr
+				}
+				if tt.providerStatus != "" {
+					return "re_123", tt.providerStatus, nil
+				}
+				return "re_123", domain.RefundStatusSucceeded, nil
+			}
+			u := course.NewUsecase(create, get, update, remove, eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, provider)
+			u.RefundPolicy.Unenroll = tt.unenroll
+
+			refund, err := u.RefundPayment(ctx, &chargeUUID, tt.refund)
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("Usecase.RefundPayment() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if (err != nil) != (tt.wantErr || tt.wantErrIs != nil) {
+				t.Fatalf("Usecase.RefundPayment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantUnsent {
+				if refund == nil || refund.Status != domain.RefundStatusPending || saved != nil {
+					t.Errorf("expected the refund to stay pending to be sent again, got %+v", refund)
+				}
+				return
+			}
+			if tt.wantStatus == domain.RefundStatusFailed {
+				if saved == nil || saved.Status != domain.RefundStatusFailed || saved.FailureReason == "" {
+					t.Errorf("expected the refund to be recorded as failed, got %+v", saved)
+				}
+				return
+			}
+			if err != nil {
+				return
+			}
+			if tt.missingCharge {
+				if refund != nil {
+					t.Errorf("expected no refund for an unknown payment")
+				}
+				return
+			}
+			if refund.Amount.Amount != tt.wantAmount || refund.Amount.Currency != "USD" {
+				t.Errorf("expected a refund of USD %d, got %v", tt.wantAmount, refund.Amount)
+			}
+			if refund.Status != tt.wantStatus || saved != refund {
+				t.Errorf("expected a saved %s refund, got %s", tt.wantStatus, refund.Status)
+			}
+			if refund.StudentUUID != studentUUID || refund.ChargeUUID != chargeUUID || refund.Reference != "re_123" {
+				t.Errorf("expected the refund to be linked to the student's payment, got %+v", refund)
+			}
+			if idempotencyKey != "refund:"+refund.UUID {
+				t.Errorf("expected the refund to be keyed by its UUID, got %q", idempotencyKey)
+			}
+			if unenrolled != tt.wantUnenrolled || refund.Unenrolled != tt.wantUnenrolled {
+				t.Errorf("expected unenrolled %v, got %v", tt.wantUnenrolled, unenrolled)
+			}
+		})
+	}
+}
+
+func TestUsecase_RetryRefunds(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		providerErr error
+		wantStatus  domain.RefundStatus
+		wantSent    int
+		wantErr     bool
+	}{
+		{
+			name:       "Happy case - refund goes through",
+			wantStatus: domain.RefundStatusSucceeded,
+			wantSent:   1,
+		},
+		{
+			name:        "Happy case - provider declines",
+			providerErr: payments.ErrDeclined,
+			wantStatus:  domain.RefundStatusFailed,
+			wantSent:    1,
+		},
+		{
+			name:        "Sad case - provider still unreachable",
+			providerErr: errors.New("connection reset by peer"),
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refundUUID := gofakeit.UUID()
+			get := mock.NewMockGetRepository()
+			var createdBefore time.Time
+			get.MockListUnsentRefunds = func(ctx context.Context, before time.Time) ([]*domain.Refund, error) {
+				createdBefore = before
+				return []*domain.Refund{{
+					AbstractBase: domain.AbstractBase{UUID: refundUUID},
+					ChargeUUID:   gofakeit.UUID(),
+					Amount:       domain.Money{Amount: 500, Currency: "USD"},
+					Reason:       "goodwill",
+					Status:       domain.RefundStatusPending,
+				}}, nil
+			}
+			get.MockGetSubscriptionCharge = func(ctx context.Context, uuid *string) (*domain.SubscriptionCharge, error) {
+				return &domain.SubscriptionCharge{AbstractBase: domain.AbstractBase{UUID: *uuid}, Reference: "ch_123"}, nil
+			}
+			update := mock.NewMockUpdateRepository()
+			var saved *domain.Refund
+			update.MockUpdateRefund = func(ctx context.Context, refund *domain.Refund) (*domain.Refund, error) {
+				saved = refund
+				return refund, nil
+			}
+			provider := paymentsmock.NewMockProvider()
+			var idempotencyKey string
+			provider.MockRefund = func(ctx context.Context, chargeReference string, amount domain.Money, reason string, key string) (string, domain.RefundStatus, error) {
+				idempotencyKey = key
+				if tt.providerErr != nil {
+					return "", "", tt.providerErr
+				}
+				return "re_123", domain.RefundStatusSucceeded, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, provider)
+
+			sent, err := u.RetryRefunds(ctx, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.RetryRefunds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("Usecase.RetryRefunds() = %d, want %d", sent, tt.wantSent)
+			}
+			if !createdBefore.Before(now) {
+				t.Errorf("expected refunds that are being sent right now to be left alone")
+			}
+			if idempotencyKey != "refund:"+refundUUID {
+				t.Errorf("expected the refund to be sent again under its own key, got %q", idempotencyKey)
+			}
+			if tt.wantStatus == "" {
+				if saved != nil {
+					t.Errorf("expected the refund to stay pending, got %+v", saved)
+				}
+				return
+			}
+			if saved == nil || saved.Status != tt.wantStatus {
+				t.Errorf("expected a saved %s refund, got %+v", tt.wantStatus, saved)
+			}
+		})
+	}
+}
+
+func TestJournals_Balance(t *testing.T) {
+	now := time.Now()
+	charge := &domain.SubscriptionCharge{
+		AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()},
+		Amount:       domain.Money{Amount: 9900, Currency: "USD"},
+		Reference:    "ch_123",
+	}
+	refund := &domain.Refund{
+		AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()},
+		ChargeUUID:   charge.UUID,
+		Amount:       domain.Money{Amount: 2500, Currency: "USD"},
+		Reason:       "dropped course",
+	}
+
+	entries := append(charge.Journal(now), refund.Journal(now)...)
+	if !domain.Balanced(entries) {
+		t.Fatalf("expected the journals to balance")
+	}
+	balances := map[string]int64{}
+	for _, entry := range entries {
+		balances[entry.Account] += entry.Amount.Amount
+		if entry.ChargeUUID != charge.UUID {
+			t.Errorf("expected every entry to reference the charge")
+		}
+	}
+	if balances[domain.LedgerAccountCash] != 7400 {
+		t.Errorf("expected USD 74.00 left in cash, got %d", balances[domain.LedgerAccountCash])
+	}
+	if balances[domain.LedgerAccountSubscriptionRevenue]+balances[domain.LedgerAccountRefunds] != -7400 {
+		t.Errorf("expected net revenue to match cash, got %v", balances)
+	}
+	if domain.Balanced(charge.Journal(now)[:1]) {
+		t.Errorf("expected a one sided journal not to balance")
+	}
+}
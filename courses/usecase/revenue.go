@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+const (
+	defaultLedgerLimit = 100
+	maxLedgerLimit     = 1000
+)
+
+// ListLedgerEntries returns the ledger entries matching a query, newest first
+func (u *Usecase) ListLedgerEntries(
+	ctx context.Context,
+	query domain.LedgerQuery,
+) ([]*domain.LedgerEntry, error) {
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("offset can not be negative")
+	}
+	switch {
+	case query.Limit < 0:
+		return nil, fmt.Errorf("limit can not be negative")
+	case query.Limit == 0:
+		query.Limit = defaultLedgerLimit
+	case query.Limit > maxLedgerLimit:
+		query.Limit = maxLedgerLimit
+	}
+	return u.Get.ListLedgerEntries(ctx, query)
+}
+
+// GetRevenueReport returns course revenue per month and course, category or
+// instructor, after reversed sales. Revenue is grouped by course unless asked
+// otherwise
+func (u *Usecase) GetRevenueReport(
+	ctx context.Context,
+	query domain.RevenueQuery,
+) ([]*domain.RevenueReportRow, error) {
+	if query.GroupBy == "" {
+		query.GroupBy = domain.RevenueGroupCourse
+	}
+	if !query.GroupBy.Valid() {
+		return nil, fmt.Errorf("can't group revenue by %s, use course, category or instructor", query.GroupBy)
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	return u.Get.GetRevenueReport(ctx, query)
+}
+
+// GetRevenueReconciliation checks the ledger's cash against the charges and
+// refunds the payment provider reported, per month. Revenue is reconciled as
+// a whole, so the query's grouping is ignored
+func (u *Usecase) GetRevenueReconciliation(
+	ctx context.Context,
+	query domain.RevenueQuery,
+) ([]*domain.RevenueReconciliation, error) {
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	return u.Get.GetRevenueReconciliation(ctx, query.From, query.To)
+}
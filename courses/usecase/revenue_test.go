@@ -0,0 +1,227 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_ListLedgerEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	earlier := now.AddDate(0, -1, 0)
+
+	tests := []struct {
+		name      string
+		query     domain.LedgerQuery
+		wantLimit int
+		wantErr   bool
+	}{
+		{
+			name:      "Happy case - default limit",
+			query:     domain.LedgerQuery{Account: domain.LedgerAccountCourseRevenue},
+			wantLimit: 100,
+		},
+		{
+			name:      "Happy case - limit is capped",
+			query:     domain.LedgerQuery{Limit: 5000, From: &earlier, To: &now},
+			wantLimit: 1000,
+		},
+		{
+			name:    "Sad case - negative limit",
+			query:   domain.LedgerQuery{Limit: -1},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - period ends before it starts",
+			query:   domain.LedgerQuery{From: &now, To: &earlier},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			var queried domain.LedgerQuery
+			get.MockListLedgerEntries = func(ctx context.Context, query domain.LedgerQuery) ([]*domain.LedgerEntry, error) {
+				queried = query
+				return []*domain.LedgerEntry{}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.ListLedgerEntries(ctx, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.ListLedgerEntries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && queried.Limit != tt.wantLimit {
+				t.Errorf("expected a limit of %d, got %d", tt.wantLimit, queried.Limit)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetRevenueReport(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		groupBy     domain.RevenueGroup
+		wantGroupBy domain.RevenueGroup
+		wantErr     bool
+	}{
+		{
+			name:        "Happy case - grouped by course by default",
+			wantGroupBy: domain.RevenueGroupCourse,
+		},
+		{
+			name:        "Happy case - grouped by instructor",
+			groupBy:     domain.RevenueGroupInstructor,
+			wantGroupBy: domain.RevenueGroupInstructor,
+		},
+		{
+			name:    "Sad case - unknown grouping",
+			groupBy: "student",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			var queried domain.RevenueQuery
+			get.MockGetRevenueReport = func(ctx context.Context, query domain.RevenueQuery) ([]*domain.RevenueReportRow, error) {
+				queried = query
+				return []*domain.RevenueReportRow{}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.GetRevenueReport(ctx, domain.RevenueQuery{GroupBy: tt.groupBy})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.GetRevenueReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && queried.GroupBy != tt.wantGroupBy {
+				t.Errorf("expected revenue grouped by %s, got %s", tt.wantGroupBy, queried.GroupBy)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetRevenueReconciliation(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name    string
+		from    *time.Time
+		to      *time.Time
+		wantErr bool
+	}{
+		{
+			name: "Happy case - every month",
+		},
+		{
+			name: "Happy case - one month",
+			from: &from,
+			to:   &to,
+		},
+		{
+			name:    "Sad case - period ends before it starts",
+			from:    &to,
+			to:      &from,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			queried := false
+			get.MockGetRevenueReconciliation = func(ctx context.Context, from, to *time.Time) ([]*domain.RevenueReconciliation, error) {
+				queried = true
+				if from != tt.from || to != tt.to {
+					t.Errorf("expected the period to be passed on")
+				}
+				return []*domain.RevenueReconciliation{}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.GetRevenueReconciliation(ctx, domain.RevenueQuery{From: tt.from, To: tt.to})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.GetRevenueReconciliation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if queried == tt.wantErr {
+				t.Errorf("expected only valid periods to be reconciled")
+			}
+		})
+	}
+}
+
+func TestCourseSale_Journal(t *testing.T) {
+	sale := &domain.CourseSale{
+		AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()},
+		CourseUUID:   gofakeit.UUID(),
+		ListPrice:    domain.Money{Amount: 4900, Currency: "USD"},
+		Discount:     domain.Money{Amount: 1225, Currency: "USD"},
+		SoldAt:       time.Now(),
+	}
+
+	entries := sale.Journal()
+	if !domain.Balanced(entries) {
+		t.Fatalf("expected the sale's journal to balance")
+	}
+	balances := map[string]int64{}
+	for _, entry := range entries {
+		balances[entry.Account] += entry.Amount.Amount
+		if entry.SaleUUID != sale.UUID || entry.CourseUUID != sale.CourseUUID {
+			t.Errorf("expected every entry to reference the sale and its course")
+		}
+	}
+	if balances[domain.LedgerAccountCourseRevenue] != -4900 {
+		t.Errorf("expected the course to earn its list price, got %d", balances[domain.LedgerAccountCourseRevenue])
+	}
+	if balances[domain.LedgerAccountCouponDiscounts] != 1225 {
+		t.Errorf("expected the coupon discount to be recorded, got %d", balances[domain.LedgerAccountCouponDiscounts])
+	}
+	if sale.Net().String() != "USD 36.75" {
+		t.Errorf("expected a net of USD 36.75, got %s", sale.Net())
+	}
+
+	sale.Discount = domain.Money{Currency: "USD"}
+	if len(sale.Journal()) != 2 {
+		t.Errorf("expected no discount entry without a coupon")
+	}
+}
+
+func TestCourseSale_ReversalJournal(t *testing.T) {
+	sale := &domain.CourseSale{
+		AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()},
+		CourseUUID:   gofakeit.UUID(),
+		ListPrice:    domain.Money{Amount: 4900, Currency: "USD"},
+		Discount:     domain.Money{Amount: 1225, Currency: "USD"},
+		SoldAt:       time.Now().AddDate(0, -1, 0),
+	}
+	reversedAt := time.Now()
+
+	entries := append(sale.Journal(), sale.ReversalJournal(reversedAt)...)
+	if !domain.Balanced(entries) {
+		t.Fatalf("expected the sale's reversal to balance")
+	}
+	balances := map[string]int64{}
+	for _, entry := range entries {
+		balances[entry.Account] += entry.Amount.Amount
+	}
+	for account, balance := range balances {
+		if balance != 0 {
+			t.Errorf("expected the reversal to take back %s, %d left", account, balance)
+		}
+	}
+	for _, entry := range sale.ReversalJournal(reversedAt) {
+		if entry.SaleUUID != sale.UUID || !entry.PostedAt.Equal(reversedAt) {
+			t.Errorf("expected the reversal to reference the sale and be posted when it was reversed")
+		}
+	}
+}
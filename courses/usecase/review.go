@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+const (
+	minRating = 1
+	maxRating = 5
+)
+
+// ReviewCourse saves a student's review of a course they are enrolled in. A
+// student who already reviewed the course edits their review instead, and
+// every new or edited review waits for moderation before it is shown. Only
+// the student or an admin can write it
+func (u *Usecase) ReviewCourse(
+	ctx context.Context,
+	principal *domain.Principal,
+	review *domain.Review,
+) (*domain.Review, error) {
+	if review.CourseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	if review.StudentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	if !principal.CanActAsStudent(review.StudentUUID) {
+		return nil, domain.ErrForbidden
+	}
+	if review.Rating < minRating || review.Rating > maxRating {
+		return nil, fmt.Errorf("rating must be between %d and %d", minRating, maxRating)
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, &review.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, &domain.NotFoundError{Kind: "course", Key: review.CourseUUID}
+	}
+	enrollment, err := u.Get.GetEnrollment(ctx, &review.StudentUUID, &review.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, domain.ErrReviewerNotEnrolled
+	}
+
+	review.Status = domain.ReviewStatusPending
+	return u.Update.SaveReview(ctx, review)
+}
+
+// GetCourseReviews returns a course's reviews with the given moderation
+// status. Approved reviews are returned when no status is given
+func (u *Usecase) GetCourseReviews(
+	ctx context.Context,
+	courseUUID *string,
+	status domain.ReviewStatus,
+) ([]*domain.Review, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	if status == "" {
+		status = domain.ReviewStatusApproved
+	}
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid review status %s", status)
+	}
+	return u.Get.GetCourseReviews(ctx, courseUUID, status)
+}
+
+// ModerateReview approves or rejects a review. Only approved reviews count
+// towards the course's rating
+func (u *Usecase) ModerateReview(
+	ctx context.Context,
+	reviewUUID *string,
+	status domain.ReviewStatus,
+) (*domain.Review, error) {
+	if reviewUUID == nil || *reviewUUID == "" {
+		return nil, fmt.Errorf("review's UUID can not be empty")
+	}
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid review status %s", status)
+	}
+	review, err := u.Get.GetReview(ctx, reviewUUID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, nil
+	}
+	return u.Update.ModerateReview(ctx, reviewUUID, status)
+}
+
+// ListCourses returns every course in the given order, by title by default
+func (u *Usecase) ListCourses(
+	ctx context.Context,
+	sort domain.CourseSort,
+) ([]*domain.Course, error) {
+	if sort == "" {
+		sort = domain.CourseSortTitle
+	}
+	if !sort.IsValid() {
+		return nil, fmt.Errorf("invalid sort %s", sort)
+	}
+	return u.Get.ListCourses(ctx, sort)
+}
@@ -0,0 +1,191 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_ReviewCourse(t *testing.T) {
+	ctx := context.Background()
+	enrolledUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name        string
+		studentUUID string
+		principal   *domain.Principal
+		rating      int
+		wantErr     bool
+		wantErrIs   error
+	}{
+		{
+			name:        "Happy case - enrolled student",
+			studentUUID: enrolledUUID,
+			rating:      4,
+		},
+		{
+			name:        "Sad case - student is not enrolled",
+			studentUUID: gofakeit.UUID(),
+			rating:      5,
+			wantErr:     true,
+			wantErrIs:   domain.ErrReviewerNotEnrolled,
+		},
+		{
+			name:        "Sad case - reviewing as another student",
+			studentUUID: enrolledUUID,
+			principal:   &domain.Principal{Role: domain.RoleStudent, Subject: gofakeit.UUID()},
+			rating:      4,
+			wantErr:     true,
+			wantErrIs:   domain.ErrForbidden,
+		},
+		{
+			name:        "Sad case - rating out of range",
+			studentUUID: enrolledUUID,
+			rating:      6,
+			wantErr:     true,
+		},
+		{
+			name:        "Sad case - no rating",
+			studentUUID: enrolledUUID,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetEnrollment = func(ctx context.Context, studentUUID, courseUUID *string) (*domain.StudentCourse, error) {
+				if *studentUUID != enrolledUUID {
+					return nil, nil
+				}
+				return &domain.StudentCourse{StudentUUID: *studentUUID, CourseUUID: *courseUUID}, nil
+			}
+			update := mock.NewMockUpdateRepository()
+			saved := false
+			update.MockSaveReview = func(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+				saved = true
+				return review, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			principal := tt.principal
+			if principal == nil {
+				principal = &domain.Principal{Role: domain.RoleStudent, Subject: tt.studentUUID}
+			}
+			review, err := u.ReviewCourse(ctx, principal, &domain.Review{
+				CourseUUID:  gofakeit.UUID(),
+				StudentUUID: tt.studentUUID,
+				Rating:      tt.rating,
+				Text:        gofakeit.Sentence(12),
+				// students can't approve their own reviews
+				Status: domain.ReviewStatusApproved,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.ReviewCourse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Usecase.ReviewCourse() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if tt.wantErr == saved {
+				t.Errorf("expected the review to be saved only when it is valid")
+			}
+			if !tt.wantErr && review.Status != domain.ReviewStatusPending {
+				t.Errorf("expected a new review to wait for moderation, got %s", review.Status)
+			}
+		})
+	}
+}
+
+func TestUsecase_ModerateReview(t *testing.T) {
+	ctx := context.Background()
+	reviewUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name       string
+		reviewUUID string
+		status     domain.ReviewStatus
+		wantNil    bool
+		wantErr    bool
+	}{
+		{
+			name:       "Happy case - approve",
+			reviewUUID: reviewUUID,
+			status:     domain.ReviewStatusApproved,
+		},
+		{
+			name:       "Happy case - unknown review",
+			reviewUUID: gofakeit.UUID(),
+			status:     domain.ReviewStatusRejected,
+			wantNil:    true,
+		},
+		{
+			name:       "Sad case - invalid status",
+			reviewUUID: reviewUUID,
+			status:     "hidden",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetReview = func(ctx context.Context, uuid *string) (*domain.Review, error) {
+				if *uuid != reviewUUID {
+					return nil, nil
+				}
+				return &domain.Review{Status: domain.ReviewStatusPending}, nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			review, err := u.ModerateReview(ctx, &tt.reviewUUID, tt.status)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.ModerateReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (review == nil) != tt.wantNil {
+				t.Fatalf("Usecase.ModerateReview() = %v, wantNil %v", review, tt.wantNil)
+			}
+			if review != nil && review.Status != tt.status {
+				t.Errorf("expected review to be %s, got %s", tt.status, review.Status)
+			}
+		})
+	}
+}
+
+func TestUsecase_ListCourses(t *testing.T) {
+	ctx := context.Background()
+	u := newMockTestUsecase()
+
+	tests := []struct {
+		name    string
+		sort    domain.CourseSort
+		wantErr bool
+	}{
+		{
+			name: "Happy case - default order",
+		},
+		{
+			name: "Happy case - by rating",
+			sort: domain.CourseSortRating,
+		},
+		{
+			name:    "Sad case - unknown sort key",
+			sort:    "popularity",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.ListCourses(ctx, tt.sort)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.ListCourses() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// CreateCourseRun schedules a new run of a course
+func (u *Usecase) CreateCourseRun(
+	ctx context.Context,
+	run *domain.CourseRun,
+) (*domain.CourseRun, error) {
+	if run.Name == "" {
+		return nil, fmt.Errorf("run's name can not be empty")
+	}
+	if run.StartDate == nil || run.EndDate == nil {
+		return nil, fmt.Errorf("run's start and end dates can not be empty")
+	}
+	if !run.EndDate.After(*run.StartDate) {
+		return nil, fmt.Errorf("run's end date must be after its start date")
+	}
+	if run.Timezone == "" {
+		run.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(run.Timezone); err != nil {
+		return nil, fmt.Errorf("run's timezone %q is not a valid IANA timezone", run.Timezone)
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, &run.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, &domain.NotFoundError{Kind: "course", Key: run.CourseUUID}
+	}
+
+	run.Evergreen = false
+	return u.Create.CreateCourseRun(ctx, run)
+}
+
+// AddLiveSession schedules a live session within a course run's dates
+func (u *Usecase) AddLiveSession(
+	ctx context.Context,
+	session *domain.LiveSession,
+) (*domain.LiveSession, error) {
+	if session.Title == "" {
+		return nil, fmt.Errorf("session's title can not be empty")
+	}
+	if session.DurationMinutes == 0 {
+		return nil, fmt.Errorf("session's duration can not be zero")
+	}
+	run, err := u.GetCourseRun(ctx, &session.RunUUID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, &domain.NotFoundError{Kind: "course run", Key: session.RunUUID}
+	}
+	if run.Evergreen {
+		return nil, fmt.Errorf("self-paced runs can not have live sessions")
+	}
+	if session.StartsAt.Before(*run.StartDate) || session.StartsAt.After(*run.EndDate) {
+		return nil, fmt.Errorf("session must start between the run's start and end dates")
+	}
+
+	session.StartsAt = session.StartsAt.UTC()
+	return u.Create.CreateLiveSession(ctx, session)
+}
+
+// GetCourseRun returns a course run with its live sessions
+func (u *Usecase) GetCourseRun(
+	ctx context.Context,
+	runUUID *string,
+) (*domain.CourseRun, error) {
+	if runUUID == nil || *runUUID == "" {
+		return nil, fmt.Errorf("run's UUID can not be empty")
+	}
+	return u.Get.GetCourseRun(ctx, runUUID)
+}
+
+// GetUpcomingCourseRuns lists the scheduled runs of a course that have not ended
+func (u *Usecase) GetUpcomingCourseRuns(
+	ctx context.Context,
+	courseUUID *string,
+) ([]*domain.CourseRun, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	return u.Get.GetUpcomingCourseRuns(ctx, courseUUID, time.Now())
+}
+
+// EnrollInRun assigns a student a course through one of its runs. Runs that
+// have already ended can't be joined
+func (u *Usecase) EnrollInRun(
+	ctx context.Context,
+	email *string,
+	runUUID *string,
+	override bool,
+	couponCode string,
+) (*domain.Student, error) {
+	if email == nil || *email == "" {
+		return nil, fmt.Errorf("student's email can not be empty")
+	}
+	run, err := u.GetCourseRun(ctx, runUUID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, &domain.NotFoundError{Kind: "course run", Key: *runUUID}
+	}
+	if run.HasEnded(time.Now()) {
+		return nil, fmt.Errorf("course run %s has already ended", run.Name)
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, &run.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, &domain.NotFoundError{Kind: "course", Key: run.CourseUUID}
+	}
+	return u.assign(ctx, email, &course.Title, runUUID, override, couponCode)
+}
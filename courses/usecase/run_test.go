@@ -0,0 +1,159 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_CreateCourseRun(t *testing.T) {
+	u := newMockTestUsecase()
+	ctx := context.Background()
+	start := time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 2, 0)
+
+	tests := []struct {
+		name    string
+		run     *domain.CourseRun
+		wantErr bool
+	}{
+		{
+			name: "Happy case - scheduled run",
+			run: &domain.CourseRun{
+				CourseUUID: gofakeit.UUID(),
+				Name:       "September cohort",
+				StartDate:  &start,
+				EndDate:    &end,
+				Timezone:   "Africa/Nairobi",
+			},
+		},
+		{
+			name: "Sad case - ends before it starts",
+			run: &domain.CourseRun{
+				CourseUUID: gofakeit.UUID(),
+				Name:       "Backwards cohort",
+				StartDate:  &end,
+				EndDate:    &start,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad case - unknown timezone",
+			run: &domain.CourseRun{
+				CourseUUID: gofakeit.UUID(),
+				Name:       "Lost cohort",
+				StartDate:  &start,
+				EndDate:    &end,
+				Timezone:   "Mars/Olympus_Mons",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.CreateCourseRun(ctx, tt.run)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateCourseRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsecase_AddLiveSession(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 2, 0)
+	get := mock.NewMockGetRepository()
+	get.MockGetCourseRun = func(ctx context.Context, runUUID *string) (*domain.CourseRun, error) {
+		return &domain.CourseRun{StartDate: &start, EndDate: &end, Timezone: "Africa/Nairobi"}, nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+	nairobi, _ := time.LoadLocation("Africa/Nairobi")
+
+	tests := []struct {
+		name     string
+		startsAt time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "Happy case - during the run",
+			startsAt: time.Date(2023, 9, 6, 18, 0, 0, 0, nairobi),
+		},
+		{
+			name:     "Sad case - after the run",
+			startsAt: end.AddDate(0, 0, 1),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := u.AddLiveSession(ctx, &domain.LiveSession{
+				RunUUID:         gofakeit.UUID(),
+				Title:           "Office hours",
+				StartsAt:        tt.startsAt,
+				DurationMinutes: 60,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.AddLiveSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (session.StartsAt.Location() != time.UTC || !session.StartsAt.Equal(tt.startsAt)) {
+				t.Errorf("expected the session to be stored as the same instant in UTC, got %v", session.StartsAt)
+			}
+		})
+	}
+}
+
+func TestUsecase_EnrollInRun(t *testing.T) {
+	ctx := context.Background()
+	email := gofakeit.Email()
+	runUUID := gofakeit.UUID()
+	now := time.Now()
+	past := now.AddDate(0, -1, 0)
+
+	tests := []struct {
+		name    string
+		endDate *time.Time
+		wantErr bool
+	}{
+		{
+			name: "Happy case - open run",
+		},
+		{
+			name:    "Sad case - run has ended",
+			endDate: &past,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetCourseRun = func(ctx context.Context, uuid *string) (*domain.CourseRun, error) {
+				return &domain.CourseRun{CourseUUID: gofakeit.UUID(), Name: "cohort", EndDate: tt.endDate}, nil
+			}
+			get.MockGetCourseByUUID = func(ctx context.Context, courseUUID *string) (*domain.Course, error) {
+				return &domain.Course{Title: "Go 101"}, nil
+			}
+			create := mock.NewMockCreateRepository()
+			var assignedRun string
+			create.MockAssignCourseToStudent = func(ctx context.Context, email, courseTitle, runUUID *string, redemption *domain.CouponRedemption) (*domain.Student, error) {
+				assignedRun = *runUUID
+				return &domain.Student{Email: *email}, nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.EnrollInRun(ctx, &email, &runUUID, false, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.EnrollInRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && assignedRun != runUUID {
+				t.Errorf("expected the student to be enrolled into run %s, got %q", runUUID, assignedRun)
+			}
+		})
+	}
+}
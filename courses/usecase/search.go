@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchLength    = 200
+)
+
+// SearchCourses finds the courses best matching a free text query, together
+// with facet counts by category, instructor and price band
+func (u *Usecase) SearchCourses(
+	ctx context.Context,
+	query *domain.CourseSearchQuery,
+) (*domain.CourseSearchResults, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return nil, fmt.Errorf("search query can not be empty")
+	}
+	if len(query.Text) > maxSearchLength {
+		return nil, fmt.Errorf("search query can not be longer than %d characters", maxSearchLength)
+	}
+	if query.PriceBand != "" {
+		if _, ok := domain.LookupPriceBand(query.PriceBand); !ok {
+			return nil, fmt.Errorf("invalid price band %s", query.PriceBand)
+		}
+	}
+	switch {
+	case query.Limit < 0:
+		return nil, fmt.Errorf("limit can not be negative")
+	case query.Limit == 0:
+		query.Limit = defaultSearchLimit
+	case query.Limit > maxSearchLimit:
+		query.Limit = maxSearchLimit
+	}
+	return u.Search.SearchCourses(ctx, query)
+}
@@ -0,0 +1,61 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/search"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+)
+
+func TestUsecase_SearchCourses(t *testing.T) {
+	ctx := context.Background()
+	index := search.NewMemoryIndex(&domain.Course{
+		AbstractBase: domain.AbstractBase{UUID: "go"},
+		Title:        "Golang for backend engineers",
+		Category:     "backend",
+	})
+	u := course.NewUsecase(mock.NewMockCreateRepository(), mock.NewMockGetRepository(), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, index, testRates, testPayments)
+
+	tests := []struct {
+		name     string
+		query    *domain.CourseSearchQuery
+		wantHits int
+		wantErr  bool
+	}{
+		{
+			name:     "Happy case - matching query",
+			query:    &domain.CourseSearchQuery{Text: "  golang "},
+			wantHits: 1,
+		},
+		{
+			name:    "Sad case - blank query",
+			query:   &domain.CourseSearchQuery{Text: "   "},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - unknown price band",
+			query:   &domain.CourseSearchQuery{Text: "golang", PriceBand: "cheap"},
+			wantErr: true,
+		},
+		{
+			name:    "Sad case - negative limit",
+			query:   &domain.CourseSearchQuery{Text: "golang", Limit: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := u.SearchCourses(ctx, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.SearchCourses() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(results.Hits) != tt.wantHits {
+				t.Errorf("expected %d hits, got %d", tt.wantHits, len(results.Hits))
+			}
+		})
+	}
+}
@@ -0,0 +1,557 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+)
+
+const (
+	// renewalBatchSize is how many due subscriptions are claimed at a time
+	renewalBatchSize = 100
+	// renewalLease is how long a claimed subscription is left to the
+	// scheduler that claimed it, long enough to charge a whole batch
+	renewalLease = 15 * time.Minute
+)
+
+// CreatePlan creates a subscription plan. Plans renew yearly and give a week
+// of grace to failed renewals unless they say otherwise
+func (u *Usecase) CreatePlan(
+	ctx context.Context,
+	plan *domain.Plan,
+) (*domain.Plan, error) {
+	plan.Name = strings.TrimSpace(plan.Name)
+	if plan.Name == "" {
+		return nil, fmt.Errorf("plan's name can not be empty")
+	}
+	if plan.Price.Amount <= 0 {
+		return nil, fmt.Errorf("plan's price must be positive")
+	}
+	currency, err := domain.NormalizeCurrency(plan.Price.Currency)
+	if err != nil {
+		return nil, err
+	}
+	plan.Price.Currency = currency
+	if plan.IntervalMonths == 0 {
+		plan.IntervalMonths = 12
+	}
+	if plan.GraceDays == 0 {
+		plan.GraceDays = 7
+	}
+	return u.Create.CreatePlan(ctx, plan)
+}
+
+// ListPlans returns every subscription plan
+func (u *Usecase) ListPlans(
+	ctx context.Context,
+) ([]*domain.Plan, error) {
+	return u.Get.ListPlans(ctx)
+}
+
+// Subscribe subscribes a student to a plan. Plans with a trial start with
+// it and are charged when it ends, other plans are charged straight away:
+// the subscription and its pending charge are saved first and the subscription
+// starts once the charge goes through. Retrying with the same idempotency key
+// picks an interrupted signup back up rather than charging again. Only the
+// student or an admin can subscribe them
+func (u *Usecase) Subscribe(
+	ctx context.Context,
+	principal *domain.Principal,
+	studentUUID *string,
+	planUUID *string,
+	idempotencyKey string,
+) (*domain.Subscription, error) {
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	if !principal.CanActAsStudent(*studentUUID) {
+		return nil, domain.ErrForbidden
+	}
+	return u.subscribe(ctx, studentUUID, planUUID, idempotencyKey)
+}
+
+// subscribe subscribes a student to a plan on behalf of the service itself,
+// e.g. for a student imported with a plan
+func (u *Usecase) subscribe(
+	ctx context.Context,
+	studentUUID *string,
+	planUUID *string,
+	idempotencyKey string,
+) (*domain.Subscription, error) {
+	if planUUID == nil || *planUUID == "" {
+		return nil, fmt.Errorf("plan's UUID can not be empty")
+	}
+	if idempotencyKey != "" {
+		// keys are the client's, so they only need to be unique per student
+		idempotencyKey = fmt.Sprintf("signup:%s:%s", *studentUUID, idempotencyKey)
+		charge, err := u.Get.GetSubscriptionChargeByKey(ctx, idempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if charge != nil {
+			return u.resumeSignup(ctx, charge)
+		}
+	}
+	student, err := u.Get.GetStudentByUUID(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, &domain.NotFoundError{Kind: "student", Key: *studentUUID}
+	}
+	plan, err := u.Get.GetPlan(ctx, planUUID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || plan.UUID == "" {
+		return nil, &domain.NotFoundError{Kind: "plan", Key: *planUUID}
+	}
+	now := time.Now()
+	current, err := u.Get.GetStudentSubscription(ctx, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.HasAccess(now) {
+		return nil, fmt.Errorf("student %s already has a %s subscription", *studentUUID, current.Status)
+	}
+
+	subscription := &domain.Subscription{
+		StudentUUID:        *studentUUID,
+		PlanUUID:           plan.UUID,
+		Status:             domain.SubscriptionStatusTrial,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now.AddDate(0, 0, int(plan.TrialDays)),
+	}
+	var charge *domain.SubscriptionCharge
+	if plan.TrialDays == 0 {
+		subscription.Status = domain.SubscriptionStatusIncomplete
+		subscription.CurrentPeriodEnd = now.AddDate(0, int(plan.IntervalMonths), 0)
+		charge = pendingCharge(subscription, plan)
+		charge.IdempotencyKey = idempotencyKey
+	}
+	renewsAt := subscription.CurrentPeriodEnd
+	subscription.RenewsAt = &renewsAt
+
+	created, err := u.Create.CreateSubscription(ctx, subscription, charge)
+	if err != nil {
+		return nil, err
+	}
+	return u.startSubscription(ctx, created, plan, charge)
+}
+
+// resumeSignup picks up the signup a charge was made for where it was left
+func (u *Usecase) resumeSignup(
+	ctx context.Context,
+	charge *domain.SubscriptionCharge,
+) (*domain.Subscription, error) {
+	subscription, err := u.Get.GetSubscription(ctx, &charge.SubscriptionUUID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, &domain.NotFoundError{Kind: "subscription", Key: charge.SubscriptionUUID}
+	}
+	plan, err := u.subscriptionPlan(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+	return u.startSubscription(ctx, subscription, plan, charge)
+}
+
+// subscriptionPlan returns the plan of a subscription
+func (u *Usecase) subscriptionPlan(
+	ctx context.Context,
+	subscription *domain.Subscription,
+) (*domain.Plan, error) {
+	if subscription.Plan != nil {
+		return subscription.Plan, nil
+	}
+	plan, err := u.Get.GetPlan(ctx, &subscription.PlanUUID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, &domain.NotFoundError{Kind: "plan", Key: subscription.PlanUUID}
+	}
+	return plan, nil
+}
+
+// startSubscription charges for a new subscription if it still has to be and
+// starts it once the charge went through. A declined signup charge ends the
+// subscription before it started
+func (u *Usecase) startSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+	charge *domain.SubscriptionCharge,
+) (*domain.Subscription, error) {
+	var invoice *domain.Invoice
+	if charge != nil {
+		var err error
+		if charge.Status == domain.ChargeStatusPending {
+			charge, invoice, err = u.chargeSubscription(ctx, subscription, plan, charge)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if charge.Status != domain.ChargeStatusSucceeded {
+			if err := u.endUnpaidSignup(ctx, subscription); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("can't charge for the subscription: %s", charge.FailureReason)
+		}
+		if subscription.Status != domain.SubscriptionStatusIncomplete {
+			// an earlier attempt already saw the signup through
+			subscription.Plan = plan
+			return subscription, nil
+		}
+	}
+	return u.activateSubscription(ctx, subscription, plan, invoice)
+}
+
+// activateSubscription starts a subscription whose signup is paid for, or
+// that starts with a trial, and welcomes the student with the invoice of
+// their first payment
+func (u *Usecase) activateSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+	invoice *domain.Invoice,
+) (*domain.Subscription, error) {
+	if subscription.Status == domain.SubscriptionStatusIncomplete {
+		subscription.Status = domain.SubscriptionStatusActive
+		var err error
+		if subscription, err = u.Update.UpdateSubscription(ctx, subscription); err != nil {
+			return nil, err
+		}
+	}
+	subscription.Plan = plan
+
+	err := u.Events.Publish(ctx, domain.SubscriptionStarted{
+		StudentUUID:      subscription.StudentUUID,
+		SubscriptionUUID: subscription.UUID,
+		PlanName:         plan.Name,
+		Invoice:          invoice,
+	})
+	if err != nil {
+		log.WithField("subscription", subscription.UUID).Errorf("can't publish subscription started: %v", err)
+	}
+	return subscription, nil
+}
+
+// endUnpaidSignup ends a subscription whose signup charge failed before it
+// started
+func (u *Usecase) endUnpaidSignup(
+	ctx context.Context,
+	subscription *domain.Subscription,
+) error {
+	if subscription.Status != domain.SubscriptionStatusIncomplete {
+		return nil
+	}
+	subscription.Status = domain.SubscriptionStatusExpired
+	subscription.RenewsAt = nil
+	_, err := u.Update.UpdateSubscription(ctx, subscription)
+	return err
+}
+
+// GetStudentSubscription returns a student's latest subscription, or nil when
+// they never subscribed
+func (u *Usecase) GetStudentSubscription(
+	ctx context.Context,
+	studentUUID *string,
+) (*domain.Subscription, error) {
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	return u.Get.GetStudentSubscription(ctx, studentUUID)
+}
+
+// GetSubscriptionCharges returns every attempt to charge for a subscription
+func (u *Usecase) GetSubscriptionCharges(
+	ctx context.Context,
+	subscriptionUUID *string,
+) ([]*domain.SubscriptionCharge, error) {
+	if subscriptionUUID == nil || *subscriptionUUID == "" {
+		return nil, fmt.Errorf("subscription's UUID can not be empty")
+	}
+	return u.Get.GetSubscriptionCharges(ctx, subscriptionUUID)
+}
+
+// CancelSubscription stops a subscription from renewing. The student keeps
+// access until the end of the period they paid for, or of their trial. Only
+// the student or an admin can cancel it
+func (u *Usecase) CancelSubscription(
+	ctx context.Context,
+	principal *domain.Principal,
+	subscriptionUUID *string,
+) (*domain.Subscription, error) {
+	if subscriptionUUID == nil || *subscriptionUUID == "" {
+		return nil, fmt.Errorf("subscription's UUID can not be empty")
+	}
+	subscription, err := u.Get.GetSubscription(ctx, subscriptionUUID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil || subscription.UUID == "" {
+		return nil, nil
+	}
+	if !principal.CanActAsStudent(subscription.StudentUUID) {
+		// don't tell someone else's subscription apart from a missing one
+		return nil, nil
+	}
+	switch subscription.Status {
+	case domain.SubscriptionStatusCanceled, domain.SubscriptionStatusExpired:
+		return nil, fmt.Errorf("subscription is already %s", subscription.Status)
+	case domain.SubscriptionStatusPastDue:
+		// the unpaid period was never granted, access ends now
+		subscription.CurrentPeriodEnd = time.Now()
+	}
+	now := time.Now()
+	subscription.Status = domain.SubscriptionStatusCanceled
+	subscription.CanceledAt = &now
+	subscription.RenewsAt = nil
+	subscription.NextRetryAt = nil
+	subscription.GraceUntil = nil
+	return u.Update.UpdateSubscription(ctx, subscription)
+}
+
+// RenewSubscriptions renews the subscriptions that are due, retries failed
+// renewals and expires subscriptions that ran out of time. It is run
+// periodically by the renewal scheduler and returns how many subscriptions
+// it acted on. Subscriptions are claimed a batch at a time, so schedulers
+// running side by side never renew the same one. A subscription that can't
+// be processed doesn't hold up the others and is retried once its lease runs
+// out
+func (u *Usecase) RenewSubscriptions(
+	ctx context.Context,
+	now time.Time,
+) (int, error) {
+	processed, failed := 0, 0
+	var firstErr error
+	for {
+		due, err := u.Update.ClaimDueSubscriptions(ctx, now, renewalLease, renewalBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		for _, subscription := range due {
+			if err := u.renewSubscription(ctx, subscription, now); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("subscription %s: %w", subscription.UUID, err)
+				}
+				failed++
+				continue
+			}
+			processed++
+		}
+		if len(due) < renewalBatchSize {
+			break
+		}
+	}
+	if firstErr != nil {
+		return processed, fmt.Errorf("%d of %d due subscriptions failed, first error: %w", failed, processed+failed, firstErr)
+	}
+	return processed, nil
+}
+
+func (u *Usecase) renewSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	now time.Time,
+) error {
+	switch {
+	case subscription.Status == domain.SubscriptionStatusCanceled:
+		return u.expireSubscription(ctx, subscription, false)
+	case subscription.Status == domain.SubscriptionStatusPastDue &&
+		subscription.GraceUntil != nil && !now.Before(*subscription.GraceUntil):
+		return u.expireSubscription(ctx, subscription, true)
+	}
+
+	plan, err := u.subscriptionPlan(ctx, subscription)
+	if err != nil {
+		return err
+	}
+	// a renewal interrupted after it was sent to the provider is retried
+	// under the same charge, so it can't be paid for twice
+	charge, err := u.Get.GetPendingSubscriptionCharge(ctx, &subscription.UUID)
+	if err != nil {
+		return err
+	}
+	if charge == nil {
+		// the new period follows on from the last one, even when it is paid
+		// late
+		renewal := *subscription
+		renewal.CurrentPeriodStart = subscription.CurrentPeriodEnd
+		renewal.CurrentPeriodEnd = subscription.CurrentPeriodEnd.AddDate(0, int(plan.IntervalMonths), 0)
+		charge, err = u.Create.CreateSubscriptionCharge(ctx, pendingCharge(&renewal, plan))
+		if err != nil {
+			return err
+		}
+	}
+	charge, _, err = u.chargeSubscription(ctx, subscription, plan, charge)
+	if err != nil {
+		return err
+	}
+
+	if charge.Status == domain.ChargeStatusSucceeded {
+		return u.grantChargedPeriod(ctx, subscription, charge)
+	}
+	return u.startDunning(ctx, subscription, plan, now)
+}
+
+// grantChargedPeriod renews a subscription for the period a charge paid for
+func (u *Usecase) grantChargedPeriod(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	charge *domain.SubscriptionCharge,
+) error {
+	renewsAt := charge.PeriodEnd
+	subscription.Status = domain.SubscriptionStatusActive
+	subscription.CurrentPeriodStart = charge.PeriodStart
+	subscription.CurrentPeriodEnd = charge.PeriodEnd
+	subscription.RenewsAt = &renewsAt
+	subscription.RenewalAttempts = 0
+	subscription.NextRetryAt = nil
+	subscription.GraceUntil = nil
+	_, err := u.Update.UpdateSubscription(ctx, subscription)
+	return err
+}
+
+// startDunning makes a subscription whose period wasn't paid for past due:
+// the charge is retried on the dunning schedule and the student keeps access
+// until the plan's grace period after the paid period runs out
+func (u *Usecase) startDunning(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+	now time.Time,
+) error {
+	subscription.RenewalAttempts++
+	if subscription.GraceUntil == nil {
+		graceUntil := subscription.CurrentPeriodEnd.AddDate(0, 0, int(plan.GraceDays))
+		subscription.GraceUntil = &graceUntil
+	}
+	subscription.Status = domain.SubscriptionStatusPastDue
+	subscription.NextRetryAt = nil
+	if attempt := int(subscription.RenewalAttempts); attempt <= len(domain.DunningRetryDelays) {
+		nextRetryAt := now.Add(domain.DunningRetryDelays[attempt-1])
+		if nextRetryAt.Before(*subscription.GraceUntil) {
+			subscription.NextRetryAt = &nextRetryAt
+		}
+	}
+	if _, err := u.Update.UpdateSubscription(ctx, subscription); err != nil {
+		return err
+	}
+	return u.Events.Publish(ctx, domain.SubscriptionPaymentFailed{
+		StudentUUID:      subscription.StudentUUID,
+		SubscriptionUUID: subscription.UUID,
+		NextRetryAt:      subscription.NextRetryAt,
+	})
+}
+
+// expireSubscription ends a subscription whose paid or grace period is over
+func (u *Usecase) expireSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	unpaid bool,
+) error {
+	subscription.Status = domain.SubscriptionStatusExpired
+	subscription.RenewsAt = nil
+	subscription.NextRetryAt = nil
+	if _, err := u.Update.UpdateSubscription(ctx, subscription); err != nil {
+		return err
+	}
+	if !unpaid {
+		return nil
+	}
+	return u.Events.Publish(ctx, domain.SubscriptionPaymentFailed{
+		StudentUUID:      subscription.StudentUUID,
+		SubscriptionUUID: subscription.UUID,
+		Expired:          true,
+	})
+}
+
+// pendingCharge is the charge for the period of the subscription, recorded
+// before the provider is asked for it
+func pendingCharge(
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+) *domain.SubscriptionCharge {
+	return &domain.SubscriptionCharge{
+		SubscriptionUUID: subscription.UUID,
+		Amount:           plan.Price,
+		Status:           domain.ChargeStatusPending,
+		PeriodStart:      subscription.CurrentPeriodStart,
+		PeriodEnd:        subscription.CurrentPeriodEnd,
+	}
+}
+
+// chargeSubscription asks the provider for a pending charge and saves how it
+// went, invoicing it when it went through. The provider is keyed by the
+// charge's UUID, so charging it again after an interruption is only ever paid
+// for once. Declined payments come back as failed charges, other errors are
+// returned and leave the charge pending to be retried
+func (u *Usecase) chargeSubscription(
+	ctx context.Context,
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+	charge *domain.SubscriptionCharge,
+) (*domain.SubscriptionCharge, *domain.Invoice, error) {
+	description := fmt.Sprintf("%s subscription", plan.Name)
+	reference, err := u.Payments.Charge(ctx, subscription.StudentUUID, charge.Amount, description, "charge:"+charge.UUID)
+	switch {
+	case errors.Is(err, payments.ErrDeclined):
+		charge.Status = domain.ChargeStatusFailed
+		charge.FailureReason = err.Error()
+	case err != nil:
+		return nil, nil, err
+	default:
+		charge.Status = domain.ChargeStatusSucceeded
+		charge.Reference = reference
+	}
+	settled, err := u.Update.SettleSubscriptionCharge(ctx, charge)
+	if errors.Is(err, domain.ErrChargeSettled) {
+		// the provider's webhook settled it first and did what follows
+		settled, err := u.Get.GetSubscriptionCharge(ctx, &charge.UUID)
+		if err == nil && settled == nil {
+			err = &domain.NotFoundError{Kind: "subscription charge", Key: charge.UUID}
+		}
+		return settled, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if settled.Status != domain.ChargeStatusSucceeded {
+		return settled, nil, nil
+	}
+	return settled, u.invoiceSubscriptionCharge(ctx, subscription, plan, settled), nil
+}
+
+// checkSubscription checks that a student's subscription lets them take
+// courses
+func (u *Usecase) checkSubscription(
+	ctx context.Context,
+	email *string,
+) error {
+	student, err := u.Get.GetStudent(ctx, email)
+	if err != nil {
+		return err
+	}
+	if student == nil {
+		return &domain.NotFoundError{Kind: "student", Key: *email}
+	}
+	subscription, err := u.Get.GetStudentSubscription(ctx, &student.UUID)
+	if err != nil {
+		return err
+	}
+	if subscription == nil || !subscription.HasAccess(time.Now()) {
+		return domain.ErrSubscriptionRequired
+	}
+	return nil
+}
@@ -0,0 +1,589 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+	paymentsmock "github.com/MelvinKim/courses/infrastructure/payments/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func decliningProvider(declined bool) *paymentsmock.MockProvider {
+	provider := paymentsmock.NewMockProvider()
+	provider.MockCharge = func(ctx context.Context, studentUUID string, amount domain.Money, description string, idempotencyKey string) (string, error) {
+		if declined {
+			return "", fmt.Errorf("%w: insufficient funds", payments.ErrDeclined)
+		}
+		return "ch_" + idempotencyKey, nil
+	}
+	return provider
+}
+
+func TestUsecase_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	planUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name       string
+		trialDays  uint
+		declined   bool
+		current    *domain.Subscription
+		wantStatus domain.SubscriptionStatus
+		wantCharge bool
+		wantErr    bool
+	}{
+		{
+			name:       "Happy case - charged at signup",
+			wantStatus: domain.SubscriptionStatusActive,
+			wantCharge: true,
+		},
+		{
+			name:       "Happy case - trial is not charged",
+			trialDays:  14,
+			wantStatus: domain.SubscriptionStatusTrial,
+		},
+		{
+			name: "Happy case - resubscribing after expiry",
+			current: &domain.Subscription{
+				Status:           domain.SubscriptionStatusExpired,
+				CurrentPeriodEnd: time.Now().AddDate(0, -1, 0),
+			},
+			wantStatus: domain.SubscriptionStatusActive,
+			wantCharge: true,
+		},
+		{
+			name:       "Sad case - payment declined",
+			declined:   true,
+			wantStatus: domain.SubscriptionStatusExpired,
+			wantCharge: true,
+			wantErr:    true,
+		},
+		{
+			name: "Sad case - already subscribed",
+			current: &domain.Subscription{
+				Status:           domain.SubscriptionStatusActive,
+				CurrentPeriodEnd: time.Now().AddDate(0, 6, 0),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetStudentByUUID = func(ctx context.Context, uuid *string) (*domain.Student, error) {
+				return &domain.Student{AbstractBase: domain.AbstractBase{UUID: *uuid}}, nil
+			}
+			get.MockGetPlan = func(ctx context.Context, uuid *string) (*domain.Plan, error) {
+				return &domain.Plan{
+					AbstractBase:   domain.AbstractBase{UUID: *uuid},
+					Name:           "Annual",
+					Price:          domain.Money{Amount: 9900, Currency: "USD"},
+					IntervalMonths: 12,
+					TrialDays:      tt.trialDays,
+					GraceDays:      7,
+				}, nil
+			}
+			get.MockGetStudentSubscription = func(ctx context.Context, uuid *string) (*domain.Subscription, error) {
+				return tt.current, nil
+			}
+			create := mock.NewMockCreateRepository()
+			var pending *domain.SubscriptionCharge
+			create.MockCreateSubscription = func(ctx context.Context, subscription *domain.Subscription, charge *domain.SubscriptionCharge) (*domain.Subscription, error) {
+				if subscription.HasAccess(time.Now()) && subscription.Status != domain.SubscriptionStatusTrial {
+					t.Errorf("expected a paid subscription to be saved without access until it is charged")
+				}
+				subscription.UUID = gofakeit.UUID()
+				if charge != nil {
+					charge.UUID = gofakeit.UUID()
+					charge.SubscriptionUUID = subscription.UUID
+					pending = charge
+				}
+				return subscription, nil
+			}
+			update := mock.NewMockUpdateRepository()
+			var settled *domain.SubscriptionCharge
+			update.MockSettleSubscriptionCharge = func(ctx context.Context, charge *domain.SubscriptionCharge) (*domain.SubscriptionCharge, error) {
+				settled = charge
+				return charge, nil
+			}
+			var saved *domain.Subscription
+			update.MockUpdateSubscription = func(ctx context.Context, subscription *domain.Subscription) (*domain.Subscription, error) {
+				saved = subscription
+				return subscription, nil
+			}
+			provider := decliningProvider(tt.declined)
+			var key string
+			charge := provider.MockCharge
+			provider.MockCharge = func(ctx context.Context, studentUUID string, amount domain.Money, description string, idempotencyKey string) (string, error) {
+				if pending == nil || pending.Status != domain.ChargeStatusPending {
+					t.Errorf("expected a pending charge to be saved before the provider is asked for it")
+				}
+				key = idempotencyKey
+				return charge(ctx, studentUUID, amount, description, idempotencyKey)
+			}
+			u := course.NewUsecase(create, get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, provider)
+
+			subscription, err := u.Subscribe(ctx, testAdmin, &studentUUID, &planUUID, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.Subscribe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (settled != nil) != tt.wantCharge {
+				t.Fatalf("expected a charge %v, got %v", tt.wantCharge, settled)
+			}
+			if tt.wantCharge && key != "charge:"+pending.UUID {
+				t.Errorf("expected the provider to be keyed by the pending charge, got %q", key)
+			}
+			if tt.wantErr {
+				if tt.wantStatus != "" && (saved == nil || saved.Status != tt.wantStatus) {
+					t.Errorf("expected the subscription to be saved %s", tt.wantStatus)
+				}
+				return
+			}
+			if subscription.Status != tt.wantStatus {
+				t.Errorf("expected a %s subscription, got %s", tt.wantStatus, subscription.Status)
+			}
+			if !subscription.HasAccess(time.Now()) {
+				t.Errorf("expected a new subscription to grant access")
+			}
+			if subscription.RenewsAt == nil || !subscription.RenewsAt.Equal(subscription.CurrentPeriodEnd) {
+				t.Errorf("expected the subscription to renew at the end of its period")
+			}
+			if tt.wantCharge && (settled.SubscriptionUUID != subscription.UUID || settled.Status != domain.ChargeStatusSucceeded) {
+				t.Errorf("expected the signup charge to be settled against the subscription")
+			}
+		})
+	}
+}
+
+func TestUsecase_Subscribe_Retried(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	planUUID := gofakeit.UUID()
+	plan := &domain.Plan{
+		AbstractBase:   domain.AbstractBase{UUID: planUUID},
+		Name:           "Annual",
+		Price:          domain.Money{Amount: 9900, Currency: "USD"},
+		IntervalMonths: 12,
+	}
+
+	tests := []struct {
+		name         string
+		status       domain.SubscriptionStatus
+		chargeStatus domain.ChargeStatus
+		wantCharged  bool
+		wantErr      bool
+	}{
+		{
+			name:         "Happy case - interrupted before the provider answered",
+			status:       domain.SubscriptionStatusIncomplete,
+			chargeStatus: domain.ChargeStatusPending,
+			wantCharged:  true,
+		},
+		{
+			name:         "Happy case - interrupted after the charge was settled",
+			status:       domain.SubscriptionStatusIncomplete,
+			chargeStatus: domain.ChargeStatusSucceeded,
+		},
+		{
+			name:         "Happy case - already started",
+			status:       domain.SubscriptionStatusActive,
+			chargeStatus: domain.ChargeStatusSucceeded,
+		},
+		{
+			name:         "Sad case - declined",
+			status:       domain.SubscriptionStatusExpired,
+			chargeStatus: domain.ChargeStatusFailed,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := &domain.Subscription{
+				AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+				StudentUUID:      studentUUID,
+				PlanUUID:         planUUID,
+				Plan:             plan,
+				Status:           tt.status,
+				CurrentPeriodEnd: time.Now().AddDate(1, 0, 0),
+			}
+			charge := &domain.SubscriptionCharge{
+				AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+				SubscriptionUUID: subscription.UUID,
+				Amount:           plan.Price,
+				Status:           tt.chargeStatus,
+			}
+			get := mock.NewMockGetRepository()
+			get.MockGetSubscriptionChargeByKey = func(ctx context.Context, key string) (*domain.SubscriptionCharge, error) {
+				if key != "signup:"+studentUUID+":retry-me" {
+					t.Errorf("expected the client's key to be scoped to the student, got %q", key)
+				}
+				return charge, nil
+			}
+			get.MockGetSubscription = func(ctx context.Context, uuid *string) (*domain.Subscription, error) {
+				return subscription, nil
+			}
+			create := mock.NewMockCreateRepository()
+			create.MockCreateSubscription = func(ctx context.Context, subscription *domain.Subscription, charge *domain.SubscriptionCharge) (*domain.Subscription, error) {
+				t.Errorf("expected no second subscription")
+				return subscription, nil
+			}
+			provider := paymentsmock.NewMockProvider()
+			charged := false
+			provider.MockCharge = func(ctx context.Context, studentUUID string, amount domain.Money, description string, idempotencyKey string) (string, error) {
+				charged = true
+				if idempotencyKey != "charge:"+charge.UUID {
+					t.Errorf("expected the retry to reuse the charge's key, got %q", idempotencyKey)
+				}
+				return "ch_1", nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, provider)
+
+			got, err := u.Subscribe(ctx, testAdmin, &studentUUID, &planUUID, "retry-me")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Usecase.Subscribe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if charged != tt.wantCharged {
+				t.Errorf("expected the provider to be asked %v, got %v", tt.wantCharged, charged)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.UUID != subscription.UUID || got.Status != domain.SubscriptionStatusActive {
+				t.Errorf("expected the same subscription to be active, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestUsecase_RenewSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	periodEnd := now.Add(-time.Hour)
+	graceUntil := periodEnd.AddDate(0, 0, 7)
+	plan := &domain.Plan{
+		AbstractBase:   domain.AbstractBase{UUID: gofakeit.UUID()},
+		Name:           "Annual",
+		Price:          domain.Money{Amount: 9900, Currency: "USD"},
+		IntervalMonths: 12,
+		GraceDays:      7,
+	}
+	due := func(status domain.SubscriptionStatus, attempts uint, graceUntil *time.Time) *domain.Subscription {
+		return &domain.Subscription{
+			AbstractBase:       domain.AbstractBase{UUID: gofakeit.UUID()},
+			StudentUUID:        gofakeit.UUID(),
+			PlanUUID:           plan.UUID,
+			Plan:               plan,
+			Status:             status,
+			CurrentPeriodStart: periodEnd.AddDate(-1, 0, 0),
+			CurrentPeriodEnd:   periodEnd,
+			RenewsAt:           &periodEnd,
+			RenewalAttempts:    attempts,
+			GraceUntil:         graceUntil,
+		}
+	}
+	lapsed := now.Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		subscription *domain.Subscription
+		declined     bool
+		wantStatus   domain.SubscriptionStatus
+		wantRetry    bool
+		wantEvent    bool
+		wantAccess   bool
+	}{
+		{
+			name:         "Happy case - renewed",
+			subscription: due(domain.SubscriptionStatusActive, 0, nil),
+			wantStatus:   domain.SubscriptionStatusActive,
+			wantAccess:   true,
+		},
+		{
+			name:         "Happy case - trial converted",
+			subscription: due(domain.SubscriptionStatusTrial, 0, nil),
+			wantStatus:   domain.SubscriptionStatusActive,
+			wantAccess:   true,
+		},
+		{
+			name:         "Happy case - retry succeeds",
+			subscription: due(domain.SubscriptionStatusPastDue, 1, &graceUntil),
+			wantStatus:   domain.SubscriptionStatusActive,
+			wantAccess:   true,
+		},
+		{
+			name:         "Sad case - declined renewal enters grace period",
+			subscription: due(domain.SubscriptionStatusActive, 0, nil),
+			declined:     true,
+			wantStatus:   domain.SubscriptionStatusPastDue,
+			wantRetry:    true,
+			wantEvent:    true,
+			wantAccess:   true,
+		},
+		{
+			name:         "Sad case - out of retries waits for the grace period",
+			subscription: due(domain.SubscriptionStatusPastDue, uint(len(domain.DunningRetryDelays)), &graceUntil),
+			declined:     true,
+			wantStatus:   domain.SubscriptionStatusPastDue,
+			wantEvent:    true,
+			wantAccess:   true,
+		},
+		{
+			name:         "Sad case - grace period over",
+			subscription: due(domain.SubscriptionStatusPastDue, 2, &lapsed),
+			wantStatus:   domain.SubscriptionStatusExpired,
+			wantEvent:    true,
+		},
+		{
+			name:         "Happy case - canceled subscription ends",
+			subscription: due(domain.SubscriptionStatusCanceled, 0, nil),
+			wantStatus:   domain.SubscriptionStatusExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			update := mock.NewMockUpdateRepository()
+			update.MockClaimDueSubscriptions = func(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]*domain.Subscription, error) {
+				return []*domain.Subscription{tt.subscription}, nil
+			}
+			create := mock.NewMockCreateRepository()
+			create.MockCreateSubscriptionCharge = func(ctx context.Context, charge *domain.SubscriptionCharge) (*domain.SubscriptionCharge, error) {
+				if charge.Status != domain.ChargeStatusPending {
+					t.Errorf("expected the renewal to be recorded as pending first, got %s", charge.Status)
+				}
+				charge.UUID = gofakeit.UUID()
+				return charge, nil
+			}
+			var saved *domain.Subscription
+			update.MockUpdateSubscription = func(ctx context.Context, subscription *domain.Subscription) (*domain.Subscription, error) {
+				saved = subscription
+				return subscription, nil
+			}
+			publisher := eventsmock.NewMockPublisher()
+			published := false
+			publisher.MockPublish = func(ctx context.Context, event domain.Event) error {
+				_, published = event.(domain.SubscriptionPaymentFailed)
+				return nil
+			}
+			u := course.NewUsecase(create, get, update, mock.NewMockDeleteRepository(), publisher, testSigner, testSearch, testRates, decliningProvider(tt.declined))
+
+			processed, err := u.RenewSubscriptions(ctx, now)
+			if err != nil {
+				t.Fatalf("Usecase.RenewSubscriptions() error = %v", err)
+			}
+			if processed != 1 || saved == nil {
+				t.Fatalf("expected the due subscription to be processed and saved")
+			}
+			if saved.Status != tt.wantStatus {
+				t.Errorf("expected a %s subscription, got %s", tt.wantStatus, saved.Status)
+			}
+			if (saved.NextRetryAt != nil) != tt.wantRetry {
+				t.Errorf("expected a retry %v, got %v", tt.wantRetry, saved.NextRetryAt)
+			}
+			if published != tt.wantEvent {
+				t.Errorf("expected a payment failed event %v, got %v", tt.wantEvent, published)
+			}
+			if saved.HasAccess(now) != tt.wantAccess {
+				t.Errorf("expected access %v after processing", tt.wantAccess)
+			}
+			if saved.Status == domain.SubscriptionStatusActive && !saved.CurrentPeriodStart.Equal(periodEnd) {
+				t.Errorf("expected the new period to follow on from the last one")
+			}
+		})
+	}
+}
+
+func TestUsecase_RenewSubscriptions_ProviderDown(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	periodEnd := now.Add(-time.Hour)
+
+	get := mock.NewMockGetRepository()
+	update := mock.NewMockUpdateRepository()
+	update.MockClaimDueSubscriptions = func(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]*domain.Subscription, error) {
+		return []*domain.Subscription{{
+			AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+			Plan:             &domain.Plan{Price: domain.Money{Amount: 9900, Currency: "USD"}, IntervalMonths: 12},
+			Status:           domain.SubscriptionStatusActive,
+			CurrentPeriodEnd: periodEnd,
+			RenewsAt:         &periodEnd,
+		}}, nil
+	}
+	update.MockUpdateSubscription = func(ctx context.Context, subscription *domain.Subscription) (*domain.Subscription, error) {
+		t.Errorf("expected the subscription to be left for the next run")
+		return subscription, nil
+	}
+	provider := paymentsmock.NewMockProvider()
+	unreachable := errors.New("connection refused")
+	provider.MockCharge = func(ctx context.Context, studentUUID string, amount domain.Money, description string, idempotencyKey string) (string, error) {
+		return "", unreachable
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, provider)
+
+	processed, err := u.RenewSubscriptions(ctx, now)
+	if !errors.Is(err, unreachable) {
+		t.Errorf("Usecase.RenewSubscriptions() error = %v, want %v", err, unreachable)
+	}
+	if processed != 0 {
+		t.Errorf("expected no subscription to be processed, got %d", processed)
+	}
+}
+
+func TestUsecase_RenewSubscriptions_Batches(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	periodEnd := now.Add(-time.Hour)
+
+	update := mock.NewMockUpdateRepository()
+	claims := 0
+	update.MockClaimDueSubscriptions = func(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]*domain.Subscription, error) {
+		claims++
+		if lease <= 0 || limit <= 0 {
+			t.Fatalf("expected subscriptions to be leased in batches, got lease %v limit %d", lease, limit)
+		}
+		if claims > 1 {
+			return nil, nil
+		}
+		// a full batch means there may be more to claim
+		due := make([]*domain.Subscription, limit)
+		for i := range due {
+			due[i] = &domain.Subscription{
+				AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+				Status:           domain.SubscriptionStatusCanceled,
+				CurrentPeriodEnd: periodEnd,
+			}
+		}
+		return due, nil
+	}
+	u := course.NewUsecase(mock.NewMockCreateRepository(), mock.NewMockGetRepository(), update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	processed, err := u.RenewSubscriptions(ctx, now)
+	if err != nil {
+		t.Fatalf("Usecase.RenewSubscriptions() error = %v", err)
+	}
+	if claims != 2 || processed == 0 {
+		t.Errorf("expected claiming to go on after a full batch, got %d claims and %d processed", claims, processed)
+	}
+}
+
+func TestUsecase_RenewSubscriptions_PendingCharge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	periodEnd := now.Add(-time.Hour)
+	subscription := &domain.Subscription{
+		AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+		Plan:             &domain.Plan{Price: domain.Money{Amount: 9900, Currency: "USD"}, IntervalMonths: 12},
+		Status:           domain.SubscriptionStatusActive,
+		CurrentPeriodEnd: periodEnd,
+		RenewsAt:         &periodEnd,
+	}
+	pending := &domain.SubscriptionCharge{
+		AbstractBase:     domain.AbstractBase{UUID: gofakeit.UUID()},
+		SubscriptionUUID: subscription.UUID,
+		Amount:           subscription.Plan.Price,
+		Status:           domain.ChargeStatusPending,
+		PeriodStart:      periodEnd,
+		PeriodEnd:        periodEnd.AddDate(1, 0, 0),
+	}
+
+	get := mock.NewMockGetRepository()
+	update := mock.NewMockUpdateRepository()
+	update.MockClaimDueSubscriptions = func(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]*domain.Subscription, error) {
+		return []*domain.Subscription{subscription}, nil
+	}
+	get.MockGetPendingSubscriptionCharge = func(ctx context.Context, subscriptionUUID *string) (*domain.SubscriptionCharge, error) {
+		return pending, nil
+	}
+	create := mock.NewMockCreateRepository()
+	create.MockCreateSubscriptionCharge = func(ctx context.Context, charge *domain.SubscriptionCharge) (*domain.SubscriptionCharge, error) {
+		t.Errorf("expected the pending charge to be retried rather than a new one made")
+		return charge, nil
+	}
+	provider := paymentsmock.NewMockProvider()
+	provider.MockCharge = func(ctx context.Context, studentUUID string, amount domain.Money, description string, idempotencyKey string) (string, error) {
+		if idempotencyKey != "charge:"+pending.UUID {
+			t.Errorf("expected the pending charge's key, got %q", idempotencyKey)
+		}
+		return "ch_1", nil
+	}
+	u := course.NewUsecase(create, get, update, mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, provider)
+
+	if _, err := u.RenewSubscriptions(ctx, now); err != nil {
+		t.Fatalf("Usecase.RenewSubscriptions() error = %v", err)
+	}
+	if !subscription.CurrentPeriodEnd.Equal(pending.PeriodEnd) || subscription.Status != domain.SubscriptionStatusActive {
+		t.Errorf("expected the period the pending charge was for to be granted")
+	}
+}
+
+func TestUsecase_AssignCourseToStudent_Subscription(t *testing.T) {
+	ctx := context.Background()
+	email := gofakeit.Email()
+	title := "Go 101"
+	graceUntil := time.Now().AddDate(0, 0, 3)
+
+	tests := []struct {
+		name         string
+		subscription *domain.Subscription
+		wantErrIs    error
+	}{
+		{
+			name: "Happy case - past due within grace",
+			subscription: &domain.Subscription{
+				Status:           domain.SubscriptionStatusPastDue,
+				CurrentPeriodEnd: time.Now().AddDate(0, 0, -1),
+				GraceUntil:       &graceUntil,
+			},
+		},
+		{
+			name:      "Sad case - never subscribed",
+			wantErrIs: domain.ErrSubscriptionRequired,
+		},
+		{
+			name: "Sad case - expired",
+			subscription: &domain.Subscription{
+				Status:           domain.SubscriptionStatusExpired,
+				CurrentPeriodEnd: time.Now().AddDate(0, 0, -10),
+			},
+			wantErrIs: domain.ErrSubscriptionRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := mock.NewMockGetRepository()
+			get.MockGetStudentSubscription = func(ctx context.Context, studentUUID *string) (*domain.Subscription, error) {
+				return tt.subscription, nil
+			}
+			create := mock.NewMockCreateRepository()
+			assigned := false
+			create.MockAssignCourseToStudent = func(ctx context.Context, email, courseTitle, runUUID *string, redemption *domain.CouponRedemption) (*domain.Student, error) {
+				assigned = true
+				return &domain.Student{Email: *email}, nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+			_, err := u.AssignCourseToStudent(ctx, &email, &title, false, "")
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Usecase.AssignCourseToStudent() error = %v, want %v", err, tt.wantErrIs)
+				}
+				if assigned {
+					t.Errorf("expected the student not to be enrolled")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Usecase.AssignCourseToStudent() error = %v", err)
+			}
+		})
+	}
+}
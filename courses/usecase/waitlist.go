@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// UnenrollStudent removes a student from a course. The seat goes to the first
+// student on the course's waitlist who may take the course, who is told about
+// it through a WaitlistPromoted event. Only the student or an admin can
+// unenroll them
+func (u *Usecase) UnenrollStudent(
+	ctx context.Context,
+	principal *domain.Principal,
+	studentUUID *string,
+	courseUUID *string,
+) error {
+	if studentUUID == nil || *studentUUID == "" {
+		return fmt.Errorf("student's UUID can not be empty")
+	}
+	if courseUUID == nil || *courseUUID == "" {
+		return fmt.Errorf("course's UUID can not be empty")
+	}
+	if !principal.CanActAsStudent(*studentUUID) {
+		return domain.ErrForbidden
+	}
+	return u.unenrollStudent(ctx, studentUUID, courseUUID)
+}
+
+// unenrollStudent removes a student from a course on behalf of the service
+// itself, e.g. when their payment for it is refunded
+func (u *Usecase) unenrollStudent(
+	ctx context.Context,
+	studentUUID *string,
+	courseUUID *string,
+) error {
+	if err := u.Delete.UnenrollStudent(ctx, studentUUID, courseUUID); err != nil {
+		return err
+	}
+	return u.promoteFromWaitlist(ctx, courseUUID)
+}
+
+// promoteFromWaitlist gives a freed seat of a course to the first student on
+// its waitlist who may take the course, checked the same way as enrolling
+// them directly. Students without a subscription or the prerequisites keep
+// their place and are passed over
+func (u *Usecase) promoteFromWaitlist(
+	ctx context.Context,
+	courseUUID *string,
+) error {
+	entries, err := u.Get.GetWaitlist(ctx, courseUUID)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	course, err := u.Get.GetCourseByUUID(ctx, courseUUID)
+	if err != nil {
+		return err
+	}
+	if course == nil {
+		return &domain.NotFoundError{Kind: "course", Key: *courseUUID}
+	}
+	for _, entry := range entries {
+		student, err := u.Get.GetStudentByUUID(ctx, &entry.StudentUUID)
+		if err != nil {
+			return err
+		}
+		if student == nil {
+			continue
+		}
+		err = u.checkEligibility(ctx, &student.Email, &course.Title, false)
+		var missing *domain.MissingPrerequisitesError
+		if errors.Is(err, domain.ErrSubscriptionRequired) || errors.As(err, &missing) {
+			log.WithFields(log.Fields{"course": course.UUID, "student": student.UUID}).
+				Infof("passed over on the waitlist: %v", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = u.Create.PromoteWaitlistEntry(ctx, entry)
+		var gone *domain.NotFoundError
+		switch {
+		case errors.Is(err, domain.ErrCourseFull):
+			// someone else took the seat
+			return nil
+		case errors.As(err, &gone):
+			continue
+		case err != nil:
+			return err
+		}
+		return u.Events.Publish(ctx, domain.WaitlistPromoted{
+			StudentUUID: entry.StudentUUID,
+			CourseUUID:  entry.CourseUUID,
+			PromotedAt:  time.Now(),
+		})
+	}
+	return nil
+}
+
+// GetWaitlist returns a course's waitlist in the order students will be served
+func (u *Usecase) GetWaitlist(
+	ctx context.Context,
+	courseUUID *string,
+) ([]*domain.WaitlistEntry, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	return u.Get.GetWaitlist(ctx, courseUUID)
+}
+
+// GetWaitlistPosition returns a student's place on a course's waitlist
+func (u *Usecase) GetWaitlistPosition(
+	ctx context.Context,
+	courseUUID *string,
+	studentUUID *string,
+) (*domain.WaitlistEntry, error) {
+	if courseUUID == nil || *courseUUID == "" {
+		return nil, fmt.Errorf("course's UUID can not be empty")
+	}
+	if studentUUID == nil || *studentUUID == "" {
+		return nil, fmt.Errorf("student's UUID can not be empty")
+	}
+	return u.Get.GetWaitlistEntry(ctx, courseUUID, studentUUID)
+}
+
+// LeaveWaitlist gives up a student's place on a course's waitlist
+func (u *Usecase) LeaveWaitlist(
+	ctx context.Context,
+	courseUUID *string,
+	studentUUID *string,
+) error {
+	if courseUUID == nil || *courseUUID == "" {
+		return fmt.Errorf("course's UUID can not be empty")
+	}
+	if studentUUID == nil || *studentUUID == "" {
+		return fmt.Errorf("student's UUID can not be empty")
+	}
+	return u.Delete.LeaveWaitlist(ctx, courseUUID, studentUUID)
+}
+
+// joinWaitlist queues a student for a full course and returns a
+// WaitlistedError carrying their place in the queue. The student is promoted
+// into the run they asked for
+func (u *Usecase) joinWaitlist(
+	ctx context.Context,
+	email *string,
+	courseTitle *string,
+	runUUID *string,
+) error {
+	student, err := u.Get.GetStudent(ctx, email)
+	if err != nil {
+		return err
+	}
+	if student == nil {
+		return &domain.NotFoundError{Kind: "student", Key: *email}
+	}
+	course, err := u.Get.GetCourse(ctx, courseTitle)
+	if err != nil {
+		return err
+	}
+	if course == nil {
+		return &domain.NotFoundError{Kind: "course", Key: *courseTitle}
+	}
+
+	entry := &domain.WaitlistEntry{
+		CourseUUID:  course.UUID,
+		StudentUUID: student.UUID,
+	}
+	if runUUID != nil {
+		entry.RunUUID = *runUUID
+	}
+	entry, err = u.Create.JoinWaitlist(ctx, entry)
+	if err != nil {
+		return err
+	}
+	return &domain.WaitlistedError{CourseTitle: course.Title, Entry: entry}
+}
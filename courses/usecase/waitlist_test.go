@@ -0,0 +1,160 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_AssignCourseToStudent_Waitlist(t *testing.T) {
+	ctx := context.Background()
+	email := gofakeit.Email()
+	courseTitle := gofakeit.LastName()
+
+	create := mock.NewMockCreateRepository()
+	create.MockAssignCourseToStudent = func(ctx context.Context, email, courseTitle, runUUID *string, redemption *domain.CouponRedemption) (*domain.Student, error) {
+		return nil, domain.ErrCourseFull
+	}
+	var joined *domain.WaitlistEntry
+	create.MockJoinWaitlist = func(ctx context.Context, entry *domain.WaitlistEntry) (*domain.WaitlistEntry, error) {
+		joined = entry
+		entry.Position = 3
+		return entry, nil
+	}
+	u := course.NewUsecase(create, mock.NewMockGetRepository(), mock.NewMockUpdateRepository(), mock.NewMockDeleteRepository(), eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+
+	student, err := u.AssignCourseToStudent(ctx, &email, &courseTitle, false, "")
+	if student != nil {
+		t.Errorf("expected no student to be assigned a full course")
+	}
+	var waitlisted *domain.WaitlistedError
+	if !errors.As(err, &waitlisted) {
+		t.Fatalf("expected a WaitlistedError, got %v", err)
+	}
+	if !errors.Is(err, domain.ErrCourseFull) {
+		t.Errorf("expected the error to match ErrCourseFull")
+	}
+	if joined == nil || waitlisted.Entry.Position != 3 {
+		t.Errorf("expected the student to join the waitlist at position 3, got %+v", waitlisted.Entry)
+	}
+}
+
+func TestUsecase_UnenrollStudent(t *testing.T) {
+	ctx := context.Background()
+	studentUUID := gofakeit.UUID()
+	courseUUID := "concurrency"
+	waitlist := func(students ...string) []*domain.WaitlistEntry {
+		var entries []*domain.WaitlistEntry
+		for _, student := range students {
+			entries = append(entries, &domain.WaitlistEntry{AbstractBase: domain.AbstractBase{UUID: gofakeit.UUID()}, StudentUUID: student, CourseUUID: courseUUID})
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name         string
+		waitlist     []*domain.WaitlistEntry
+		full         bool
+		wantPromoted string
+	}{
+		{
+			name:         "Happy case - next student promoted",
+			waitlist:     waitlist("ready"),
+			wantPromoted: "ready",
+		},
+		{
+			name:         "Happy case - students who may not take the course are passed over",
+			waitlist:     waitlist("unsubscribed", "unprepared", "ready", "also ready"),
+			wantPromoted: "ready",
+		},
+		{
+			name:     "Happy case - nobody on the waitlist may take the course",
+			waitlist: waitlist("unsubscribed", "unprepared"),
+		},
+		{
+			name:     "Happy case - seat taken in the meantime",
+			waitlist: waitlist("ready"),
+			full:     true,
+		},
+		{
+			name: "Happy case - empty waitlist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// students are emailed at their UUIDs
+			get := newPrerequisiteGraphRepository(map[string][]string{courseUUID: {"go"}})
+			get.MockGetWaitlist = func(ctx context.Context, courseUUID *string) ([]*domain.WaitlistEntry, error) {
+				return tt.waitlist, nil
+			}
+			get.MockGetStudent = func(ctx context.Context, email *string) (*domain.Student, error) {
+				return &domain.Student{AbstractBase: domain.AbstractBase{UUID: *email}, Email: *email}, nil
+			}
+			get.MockGetStudentByUUID = func(ctx context.Context, uuid *string) (*domain.Student, error) {
+				return &domain.Student{AbstractBase: domain.AbstractBase{UUID: *uuid}, Email: *uuid}, nil
+			}
+			get.MockGetStudentSubscription = func(ctx context.Context, studentUUID *string) (*domain.Subscription, error) {
+				if *studentUUID == "unsubscribed" {
+					return nil, nil
+				}
+				return &domain.Subscription{Status: domain.SubscriptionStatusActive, CurrentPeriodEnd: time.Now().AddDate(1, 0, 0)}, nil
+			}
+			get.MockGetEnrollment = func(ctx context.Context, studentUUID, courseUUID *string) (*domain.StudentCourse, error) {
+				if *studentUUID == "unprepared" {
+					return nil, nil
+				}
+				return &domain.StudentCourse{Status: domain.EnrollmentStatusCompleted}, nil
+			}
+			create := mock.NewMockCreateRepository()
+			var tried []string
+			create.MockPromoteWaitlistEntry = func(ctx context.Context, entry *domain.WaitlistEntry) error {
+				tried = append(tried, entry.StudentUUID)
+				if tt.full {
+					return domain.ErrCourseFull
+				}
+				return nil
+			}
+			del := mock.NewMockDeleteRepository()
+			unenrolled := false
+			del.MockUnenrollStudent = func(ctx context.Context, student, course *string) error {
+				unenrolled = *student == studentUUID && *course == courseUUID
+				return nil
+			}
+			publisher := eventsmock.NewMockPublisher()
+			var published []domain.Event
+			publisher.MockPublish = func(ctx context.Context, event domain.Event) error {
+				published = append(published, event)
+				return nil
+			}
+			u := course.NewUsecase(create, get, mock.NewMockUpdateRepository(), del, publisher, testSigner, testSearch, testRates, testPayments)
+
+			if err := u.UnenrollStudent(ctx, testAdmin, &studentUUID, &courseUUID); err != nil {
+				t.Fatalf("Usecase.UnenrollStudent() error = %v", err)
+			}
+			if !unenrolled {
+				t.Errorf("expected the student to be unenrolled")
+			}
+			for _, student := range tried {
+				if student == "unsubscribed" || student == "unprepared" {
+					t.Errorf("expected %s to be passed over", student)
+				}
+			}
+			if len(tried) > 1 {
+				t.Errorf("expected one seat to be given away, got %v", tried)
+			}
+			if (tt.wantPromoted != "") != (len(published) == 1) {
+				t.Fatalf("expected a promotion event for %q, got %v", tt.wantPromoted, published)
+			}
+			if tt.wantPromoted != "" && published[0].(domain.WaitlistPromoted).StudentUUID != tt.wantPromoted {
+				t.Errorf("expected %s to be promoted, got %+v", tt.wantPromoted, published[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/MelvinKim/courses/domain"
+)
+
+// HandlePaymentWebhook verifies a webhook from the payment provider and acts
+// on its event. Events are recorded once handled, so redelivered events are
+// only acted on once: the recorded event is returned for them
+func (u *Usecase) HandlePaymentWebhook(
+	ctx context.Context,
+	signature string,
+	body []byte,
+) (*domain.WebhookEvent, error) {
+	if u.Webhooks == nil {
+		return nil, fmt.Errorf("payment webhooks are not configured")
+	}
+	now := time.Now()
+	event, err := u.Webhooks.Verify(signature, body, now)
+	if err != nil {
+		return nil, err
+	}
+	handled, err := u.Get.GetWebhookEvent(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	if handled != nil {
+		return handled, nil
+	}
+
+	outcome, err := u.applyPaymentEvent(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	return u.Create.RecordWebhookEvent(ctx, &domain.WebhookEvent{
+		ProviderEventID: event.ID,
+		Type:            event.Type,
+		Reference:       event.Reference,
+		Outcome:         outcome,
+		ReceivedAt:      now,
+	})
+}
+
+// applyPaymentEvent moves the charge or refund an event is about to the
+// state the provider reports and describes what was done. Acting on an event
+// twice has no further effect, so concurrent deliveries are safe
+func (u *Usecase) applyPaymentEvent(
+	ctx context.Context,
+	event *domain.PaymentEvent,
+) (string, error) {
+	switch event.Type {
+	case domain.PaymentEventRefundSucceeded, domain.PaymentEventRefundFailed:
+		refund, err := u.Get.GetRefundByReference(ctx, event.Reference)
+		if err != nil {
+			return "", err
+		}
+		if refund == nil {
+			return "unknown refund", nil
+		}
+		if refund.Status != domain.RefundStatusPending {
+			return fmt.Sprintf("refund already %s", refund.Status), nil
+		}
+		status := domain.RefundStatusSucceeded
+		if event.Type == domain.PaymentEventRefundFailed {
+			status = domain.RefundStatusFailed
+		}
+		if _, err := u.settleRefund(ctx, refund, status, event.FailureReason); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("refund %s", status), nil
+
+	case domain.PaymentEventChargeSucceeded, domain.PaymentEventChargeFailed:
+		return u.applyChargeEvent(ctx, event)
+	}
+	return "ignored", nil
+}
+
+// applyChargeEvent settles the charge an event is about and does what the
+// outcome calls for. Charges the provider never answered are still pending
+// and start or renew their subscription once they succeed. A charge that
+// fails, even after it went through, ends an unpaid signup or makes the
+// subscription past due and starts dunning for the period it was for
+func (u *Usecase) applyChargeEvent(
+	ctx context.Context,
+	event *domain.PaymentEvent,
+) (string, error) {
+	charge, err := u.Get.GetSubscriptionChargeByReference(ctx, event.Reference)
+	if err != nil {
+		return "", err
+	}
+	if chargeUUID := strings.TrimPrefix(event.IdempotencyKey, "charge:"); charge == nil && chargeUUID != event.IdempotencyKey {
+		if charge, err = u.Get.GetSubscriptionCharge(ctx, &chargeUUID); err != nil {
+			return "", err
+		}
+	}
+	if charge == nil {
+		return "unknown charge", nil
+	}
+	status := domain.ChargeStatusSucceeded
+	if event.Type == domain.PaymentEventChargeFailed {
+		status = domain.ChargeStatusFailed
+	}
+	if !charge.Status.CanBecome(status) {
+		if charge.Status == domain.ChargeStatusFailed && status == domain.ChargeStatusSucceeded {
+			// the student paid for a period they were refused, which
+			// finance has to refund or grant by hand
+			log.WithFields(log.Fields{
+				"charge":    charge.UUID,
+				"reference": event.Reference,
+				"event":     event.ID,
+			}).Error("payment provider reports a failed charge as succeeded")
+			return "charge succeeded after it failed, needs review", nil
+		}
+		return fmt.Sprintf("charge already %s", charge.Status), nil
+	}
+
+	previous := charge.Status
+	charge.Status = status
+	if status == domain.ChargeStatusSucceeded {
+		charge.Reference = event.Reference
+	} else {
+		charge.FailureReason = event.FailureReason
+	}
+	settled, err := u.Update.SettleSubscriptionCharge(ctx, charge)
+	if errors.Is(err, domain.ErrChargeSettled) {
+		return "charge already settled", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	subscription, err := u.Get.GetSubscription(ctx, &settled.SubscriptionUUID)
+	if err != nil {
+		return "", err
+	}
+	if subscription == nil {
+		return "", &domain.NotFoundError{Kind: "subscription", Key: settled.SubscriptionUUID}
+	}
+	plan, err := u.subscriptionPlan(ctx, subscription)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case status == domain.ChargeStatusSucceeded:
+		invoice := u.invoiceSubscriptionCharge(ctx, subscription, plan, settled)
+		if subscription.Status == domain.SubscriptionStatusIncomplete {
+			_, err = u.activateSubscription(ctx, subscription, plan, invoice)
+			return "charge succeeded, subscription started", err
+		}
+		return "charge succeeded, subscription renewed", u.grantChargedPeriod(ctx, subscription, settled)
+	case subscription.Status == domain.SubscriptionStatusIncomplete:
+		return "charge failed, signup ended", u.endUnpaidSignup(ctx, subscription)
+	case previous == domain.ChargeStatusSucceeded && !settled.PeriodEnd.Equal(subscription.CurrentPeriodEnd):
+		// the subscription has moved on since, so there's no period to take
+		// back
+		log.WithFields(log.Fields{
+			"charge":       settled.UUID,
+			"subscription": subscription.UUID,
+			"event":        event.ID,
+		}).Errorf("payment provider reports a charge for an earlier period as failed: %s", event.FailureReason)
+		return "charge failed after it succeeded, needs review", nil
+	}
+	if previous == domain.ChargeStatusSucceeded {
+		// the period the charge paid for wasn't paid for after all
+		subscription.CurrentPeriodStart = settled.PeriodStart
+		subscription.CurrentPeriodEnd = settled.PeriodStart
+	}
+	switch subscription.Status {
+	case domain.SubscriptionStatusCanceled, domain.SubscriptionStatusExpired:
+		// nothing is retried for a subscription that won't renew
+		_, err := u.Update.UpdateSubscription(ctx, subscription)
+		return "charge failed, access ended", err
+	}
+	return "charge failed, subscription past due", u.startDunning(ctx, subscription, plan, time.Now())
+}
@@ -0,0 +1,405 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MelvinKim/courses/domain"
+	eventsmock "github.com/MelvinKim/courses/infrastructure/events/mock"
+	"github.com/MelvinKim/courses/infrastructure/payments"
+	"github.com/MelvinKim/courses/repository/mock"
+	course "github.com/MelvinKim/courses/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestUsecase_HandlePaymentWebhook(t *testing.T) {
+	ctx := context.Background()
+	verifier := payments.NewWebhookVerifier("whsec_test", payments.DefaultWebhookTolerance)
+	studentUUID := gofakeit.UUID()
+	courseUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name           string
+		eventType      string
+		refund         *domain.Refund
+		handled        bool
+		unconfigured   bool
+		forged         bool
+		wantStatus     domain.RefundStatus
+		wantOutcome    string
+		wantUnenrolled bool
+		wantErrIs      error
+		wantErr        bool
+	}{
+		{
+			name:           "Happy case - pending refund goes through",
+			eventType:      domain.PaymentEventRefundSucceeded,
+			refund:         &domain.Refund{Status: domain.RefundStatusPending, CourseUUID: courseUUID},
+			wantStatus:     domain.RefundStatusSucceeded,
+			wantOutcome:    "refund succeeded",
+			wantUnenrolled: true,
+		},
+		{
+			name:        "Happy case - pending refund fails",
+			eventType:   domain.PaymentEventRefundFailed,
+			refund:      &domain.Refund{Status: domain.RefundStatusPending, CourseUUID: courseUUID},
+			wantStatus:  domain.RefundStatusFailed,
+			wantOutcome: "refund failed",
+		},
+		{
+			name:        "Happy case - settled refund is left alone",
+			eventType:   domain.PaymentEventRefundFailed,
+			refund:      &domain.Refund{Status: domain.RefundStatusSucceeded},
+			wantOutcome: "refund already succeeded",
+		},
+		{
+			name:        "Happy case - unknown refund",
+			eventType:   domain.PaymentEventRefundSucceeded,
+			wantOutcome: "unknown refund",
+		},
+		{
+			name:        "Happy case - redelivered event",
+			eventType:   domain.PaymentEventRefundSucceeded,
+			refund:      &domain.Refund{Status: domain.RefundStatusPending},
+			handled:     true,
+			wantOutcome: "refund succeeded",
+		},
+		{
+			name:        "Happy case - unsupported event",
+			eventType:   "payout.created",
+			wantOutcome: "ignored",
+		},
+		{
+			name:      "Sad case - forged",
+			eventType: domain.PaymentEventRefundSucceeded,
+			forged:    true,
+			wantErrIs: payments.ErrInvalidSignature,
+		},
+		{
+			name:         "Sad case - webhooks not configured",
+			eventType:    domain.PaymentEventRefundSucceeded,
+			unconfigured: true,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(domain.PaymentEvent{ID: "evt_" + gofakeit.UUID(), Type: tt.eventType, Reference: "re_123"})
+			signature := verifier.Sign(body, time.Now())
+			if tt.forged {
+				signature = payments.NewWebhookVerifier("whsec_forged", time.Minute).Sign(body, time.Now())
+			}
+
+			get := mock.NewMockGetRepository()
+			get.MockGetWebhookEvent = func(ctx context.Context, providerEventID string) (*domain.WebhookEvent, error) {
+				if tt.handled {
+					return &domain.WebhookEvent{ProviderEventID: providerEventID, Outcome: "refund succeeded"}, nil
+				}
+				return nil, nil
+			}
+			get.MockGetRefundByReference = func(ctx context.Context, reference string) (*domain.Refund, error) {
+				if tt.refund != nil {
+					tt.refund.StudentUUID = studentUUID
+					tt.refund.Reference = reference
+				}
+				return tt.refund, nil
+			}
+			update := mock.NewMockUpdateRepository()
+			var saved *domain.Refund
+			update.MockUpdateRefund = func(ctx context.Context, refund *domain.Refund) (*domain.Refund, error) {
+				saved = refund
+				return refund, nil
+			}
+			remove := mock.NewMockDeleteRepository()
+			unenrolled := false
+			remove.MockUnenrollStudent = func(ctx context.Context, student, course *string) error {
+				unenrolled = true
+				return nil
+			}
+			create := mock.NewMockCreateRepository()
+			recorded := false
+			create.MockRecordWebhookEvent = func(ctx context.Context, event *domain.WebhookEvent) (*domain.WebhookEvent, error) {
+				recorded = true
+				return event, nil
+			}
+			u := course.NewUsecase(create, get, update, remove, eventsmock.NewMockPublisher(), testSigner, testSearch, testRates, testPayments)
+			u.RefundPolicy.Unenroll = true
+			if !tt.unconfigured {
+				u.Webhooks = verifier
+			}
+
+			event, err := u.HandlePaymentWebhook(ctx, signature, body)
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("Usecase.HandlePaymentWebhook() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if (err != nil) != (tt.wantErr || tt.wantErrIs != nil) {
+				t.Fatalf("Usecase.HandlePaymentWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if saved != nil || recorded {
+					t.Errorf("expected a refused webhook to change nothing")
+				}
+				return
+			}
+			if event.Outcome != tt.wantOutcome {
+				t.Errorf("expected outcome %q, got %q", tt.wantOutcome, event.Outcome)
+			}
+			if recorded == tt.handled {
+				t.Errorf("expected only new events to be recorded")
+			}
+			if tt.wantStatus == "" {
+				if saved != nil {
+					t.Errorf("expected the refund to be left alone, got %s", saved.Status)
+				}
+				return
+			}
+			if saved == nil || saved.Status != tt.wantStatus {
+				t.Fatalf("expected the refund to be %s, got %+v", tt.wantStatus, saved)
+			}
+			if unenrolled != tt.wantUnenrolled || saved.Unenrolled != tt.wantUnenrolled {
+				t.Errorf("expected unenrolled %v, got %v", tt.wantUnenrolled, unenrolled)
+			}
+		})
+	}
+}
+
+func TestUsecase_HandlePaymentWebhook_Charges(t *testing.T) {
+	ctx := context.Background()
+	verifier := payments.NewWebhookVerifier("whsec_test", payments.DefaultWebhookTolerance)
+	now := time.Now()
+	periodStart := now.AddDate(0, 0, -3)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+	chargeUUID := gofakeit.UUID()
+
+	tests := []struct {
+		name             string
+		eventType        string
+		byKey            bool
+		chargeStatus     domain.ChargeStatus
+		subStatus        domain.SubscriptionStatus
+		subPeriodEnd     time.Time
+		settledMeanwhile bool
+		wantOutcome      string
+		wantCharge       domain.ChargeStatus
+		wantSubscription domain.SubscriptionStatus
+		wantPeriodEnd    time.Time
+		wantPaymentEvent bool
+	}{
+		{
+			name:             "Happy case - signup charge goes through",
+			eventType:        domain.PaymentEventChargeSucceeded,
+			byKey:            true,
+			chargeStatus:     domain.ChargeStatusPending,
+			subStatus:        domain.SubscriptionStatusIncomplete,
+			subPeriodEnd:     periodEnd,
+			wantOutcome:      "charge succeeded, subscription started",
+			wantCharge:       domain.ChargeStatusSucceeded,
+			wantSubscription: domain.SubscriptionStatusActive,
+			wantPeriodEnd:    periodEnd,
+		},
+		{
+			name:             "Happy case - renewal charge goes through",
+			eventType:        domain.PaymentEventChargeSucceeded,
+			chargeStatus:     domain.ChargeStatusPending,
+			subStatus:        domain.SubscriptionStatusActive,
+			subPeriodEnd:     periodStart,
+			wantOutcome:      "charge succeeded, subscription renewed",
+			wantCharge:       domain.ChargeStatusSucceeded,
+			wantSubscription: domain.SubscriptionStatusActive,
+			wantPeriodEnd:    periodEnd,
+		},
+		{
+			name:             "Happy case - signup charge is declined",
+			eventType:        domain.PaymentEventChargeFailed,
+			chargeStatus:     domain.ChargeStatusPending,
+			subStatus:        domain.SubscriptionStatusIncomplete,
+			subPeriodEnd:     periodEnd,
+			wantOutcome:      "charge failed, signup ended",
+			wantCharge:       domain.ChargeStatusFailed,
+			wantSubscription: domain.SubscriptionStatusExpired,
+			wantPeriodEnd:    periodEnd,
+		},
+		{
+			name:             "Happy case - renewal charge is declined",
+			eventType:        domain.PaymentEventChargeFailed,
+			chargeStatus:     domain.ChargeStatusPending,
+			subStatus:        domain.SubscriptionStatusActive,
+			subPeriodEnd:     periodStart,
+			wantOutcome:      "charge failed, subscription past due",
+			wantCharge:       domain.ChargeStatusFailed,
+			wantSubscription: domain.SubscriptionStatusPastDue,
+			wantPeriodEnd:    periodStart,
+			wantPaymentEvent: true,
+		},
+		{
+			name:             "Happy case - charge fails after it went through",
+			eventType:        domain.PaymentEventChargeFailed,
+			chargeStatus:     domain.ChargeStatusSucceeded,
+			subStatus:        domain.SubscriptionStatusActive,
+			subPeriodEnd:     periodEnd,
+			wantOutcome:      "charge failed, subscription past due",
+			wantCharge:       domain.ChargeStatusFailed,
+			wantSubscription: domain.SubscriptionStatusPastDue,
+			wantPeriodEnd:    periodStart,
+			wantPaymentEvent: true,
+		},
+		{
+			name:             "Happy case - charge of a canceled subscription fails after it went through",
+			eventType:        domain.PaymentEventChargeFailed,
+			chargeStatus:     domain.ChargeStatusSucceeded,
+			subStatus:        domain.SubscriptionStatusCanceled,
+			subPeriodEnd:     periodEnd,
+			wantOutcome:      "charge failed, access ended",
+			wantCharge:       domain.ChargeStatusFailed,
+			wantSubscription: domain.SubscriptionStatusCanceled,
+			wantPeriodEnd:    periodStart,
+		},
+		{
+			name:         "Happy case - charge for an earlier period fails after it went through",
+			eventType:    domain.PaymentEventChargeFailed,
+			chargeStatus: domain.ChargeStatusSucceeded,
+			subStatus:    domain.SubscriptionStatusActive,
+			subPeriodEnd: periodEnd.AddDate(0, 1, 0),
+			wantOutcome:  "charge failed after it succeeded, needs review",
+			wantCharge:   domain.ChargeStatusFailed,
+		},
+		{
+			name:         "Happy case - failed charge reported as succeeded",
+			eventType:    domain.PaymentEventChargeSucceeded,
+			chargeStatus: domain.ChargeStatusFailed,
+			wantOutcome:  "charge succeeded after it failed, needs review",
+		},
+		{
+			name:         "Happy case - succeeded charge is left alone",
+			eventType:    domain.PaymentEventChargeSucceeded,
+			chargeStatus: domain.ChargeStatusSucceeded,
+			wantOutcome:  "charge already succeeded",
+		},
+		{
+			name:             "Happy case - charge settled meanwhile",
+			eventType:        domain.PaymentEventChargeSucceeded,
+			chargeStatus:     domain.ChargeStatusPending,
+			settledMeanwhile: true,
+			wantOutcome:      "charge already settled",
+		},
+		{
+			name:        "Happy case - unknown charge",
+			eventType:   domain.PaymentEventChargeSucceeded,
+			wantOutcome: "unknown charge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := domain.PaymentEvent{ID: "evt_" + gofakeit.UUID(), Type: tt.eventType, Reference: "ch_123"}
+			if tt.byKey {
+				event.IdempotencyKey = "charge:" + chargeUUID
+			}
+			body, _ := json.Marshal(event)
+			signature := verifier.Sign(body, now)
+
+			subscription := &domain.Subscription{
+				AbstractBase:       domain.AbstractBase{UUID: gofakeit.UUID()},
+				StudentUUID:        gofakeit.UUID(),
+				Status:             tt.subStatus,
+				CurrentPeriodStart: tt.subPeriodEnd.AddDate(0, -1, 0),
+				CurrentPeriodEnd:   tt.subPeriodEnd,
+			}
+			var charge *domain.SubscriptionCharge
+			if tt.chargeStatus != "" {
+				charge = &domain.SubscriptionCharge{
+					AbstractBase:     domain.AbstractBase{UUID: chargeUUID},
+					SubscriptionUUID: subscription.UUID,
+					Amount:           domain.Money{Amount: 9900, Currency: "USD"},
+					Status:           tt.chargeStatus,
+					PeriodStart:      periodStart,
+					PeriodEnd:        periodEnd,
+				}
+			}
+			get := mock.NewMockGetRepository()
+			get.MockGetWebhookEvent = func(ctx context.Context, providerEventID string) (*domain.WebhookEvent, error) {
+				return nil, nil
+			}
+			get.MockGetSubscriptionChargeByReference = func(ctx context.Context, reference string) (*domain.SubscriptionCharge, error) {
+				if tt.byKey {
+					return nil, nil
+				}
+				return charge, nil
+			}
+			get.MockGetSubscriptionCharge = func(ctx context.Context, uuid *string) (*domain.SubscriptionCharge, error) {
+				if !tt.byKey || *uuid != chargeUUID {
+					t.Errorf("expected the charge to be looked up by its idempotency key, got %s", *uuid)
+				}
+				return charge, nil
+			}
+			get.MockGetSubscription = func(ctx context.Context, uuid *string) (*domain.Subscription, error) {
+				return subscription, nil
+			}
+			get.MockGetPlan = func(ctx context.Context, planUUID *string) (*domain.Plan, error) {
+				return &domain.Plan{Name: "Monthly", Price: domain.Money{Amount: 9900, Currency: "USD"}, IntervalMonths: 1, GraceDays: 7}, nil
+			}
+			update := mock.NewMockUpdateRepository()
+			var settled *domain.SubscriptionCharge
+			update.MockSettleSubscriptionCharge = func(ctx context.Context, c *domain.SubscriptionCharge) (*domain.SubscriptionCharge, error) {
+				if tt.settledMeanwhile {
+					return nil, domain.ErrChargeSettled
+				}
+				settled = c
+				return c, nil
+			}
+			var saved *domain.Subscription
+			update.MockUpdateSubscription = func(ctx context.Context, s *domain.Subscription) (*domain.Subscription, error) {
+				copied := *s
+				saved = &copied
+				return s, nil
+			}
+			publisher := eventsmock.NewMockPublisher()
+			paymentFailed := false
+			publisher.MockPublish = func(ctx context.Context, event domain.Event) error {
+				if _, ok := event.(domain.SubscriptionPaymentFailed); ok {
+					paymentFailed = true
+				}
+				return nil
+			}
+			u := course.NewUsecase(mock.NewMockCreateRepository(), get, update, mock.NewMockDeleteRepository(), publisher, testSigner, testSearch, testRates, testPayments)
+			u.Webhooks = verifier
+
+			handled, err := u.HandlePaymentWebhook(ctx, signature, body)
+			if err != nil {
+				t.Fatalf("Usecase.HandlePaymentWebhook() error = %v", err)
+			}
+			if handled.Outcome != tt.wantOutcome {
+				t.Errorf("expected outcome %q, got %q", tt.wantOutcome, handled.Outcome)
+			}
+			if tt.wantCharge == "" {
+				if settled != nil || saved != nil {
+					t.Errorf("expected the charge and subscription to be left alone")
+				}
+				return
+			}
+			if settled == nil || settled.Status != tt.wantCharge {
+				t.Fatalf("expected the charge to be %s, got %+v", tt.wantCharge, settled)
+			}
+			if tt.wantSubscription == "" {
+				if saved != nil {
+					t.Errorf("expected the subscription to be left alone, got %s", saved.Status)
+				}
+				return
+			}
+			if saved == nil || saved.Status != tt.wantSubscription {
+				t.Fatalf("expected the subscription to be %s, got %+v", tt.wantSubscription, saved)
+			}
+			if !saved.CurrentPeriodEnd.Equal(tt.wantPeriodEnd) {
+				t.Errorf("expected the paid period to end %v, got %v", tt.wantPeriodEnd, saved.CurrentPeriodEnd)
+			}
+			if paymentFailed != tt.wantPaymentEvent {
+				t.Errorf("expected payment failed published %v, got %v", tt.wantPaymentEvent, paymentFailed)
+			}
+			if tt.wantPaymentEvent && saved.NextRetryAt == nil {
+				t.Errorf("expected the charge to be retried")
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Package interceptors holds the gRPC middleware shared by sudoCODE services
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"expvar"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the metadata key used to track a request across services
+const RequestIDHeader = "x-request-id"
+
+type requestIDKey struct{}
+
+var metricsMu sync.Mutex
+
+// metricsMap returns the published expvar map with the name, creating it on
+// first use so interceptors can be set up more than once
+func metricsMap(name string) *expvar.Map {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if m, ok := expvar.Get(name).(*expvar.Map); ok {
+		return m
+	}
+	return expvar.NewMap(name)
+}
+
+// RequestIDFromContext returns the request ID attached by RequestIDInterceptor
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDInterceptor reuses the caller's request ID or generates a new one,
+// stores it in the context and echoes it back in the response headers
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(RequestIDHeader); len(ids) > 0 {
+				id = ids[0]
+			}
+		}
+		if id == "" {
+			id = uuid.New().String()
+		}
+		if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id)); err != nil {
+			log.Warnf("can't set the request ID response header: %v", err)
+		}
+		return handler(context.WithValue(ctx, requestIDKey{}, id), req)
+	}
+}
+
+// AuthInterceptor requires callers to present the shared service token as a
+// bearer token. Health checks are always allowed. With an empty token every
+// other call is refused, servers that mean to go without auth must leave the
+// interceptor out
+func AuthInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, "/"+grpc_health_v1.Health_ServiceDesc.ServiceName+"/") {
+			return handler(ctx, req)
+		}
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, "no authorization token is configured")
+		}
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+		}
+		presented := strings.TrimPrefix(values[0], "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthFromEnv returns the interceptor authenticating callers with the token in
+// the GRPC_AUTH_TOKEN env variable. Without a token it refuses to set up,
+// unless GRPC_INSECURE=true explicitly lets every caller in, for local
+// development
+func AuthFromEnv() (grpc.UnaryServerInterceptor, error) {
+	if token := os.Getenv("GRPC_AUTH_TOKEN"); token != "" {
+		return AuthInterceptor(token), nil
+	}
+	insecure, _ := strconv.ParseBool(os.Getenv("GRPC_INSECURE"))
+	if !insecure {
+		return nil, fmt.Errorf("GRPC_AUTH_TOKEN is not set, set it or GRPC_INSECURE=true to serve gRPC without auth")
+	}
+	log.Warn("GRPC_INSECURE is set, gRPC calls will not be authenticated")
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(ctx, req)
+	}, nil
+}
+
+// LoggingInterceptor logs every call together with its outcome and duration
+func LoggingInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		entry := log.WithFields(log.Fields{
+			"method":     info.FullMethod,
+			"code":       status.Code(err).String(),
+			"duration":   time.Since(start).String(),
+			"request_id": RequestIDFromContext(ctx),
+		})
+		if err != nil {
+			entry.WithField("error", err).Error("gRPC call failed")
+		} else {
+			entry.Info("gRPC call handled")
+		}
+		return resp, err
+	}
+}
+
+// MetricsInterceptor counts calls per method and status code and accumulates
+// their latency. The counters are published through expvar as
+// <service>_grpc_requests_total and <service>_grpc_latency_ms_total
+func MetricsInterceptor(service string) grpc.UnaryServerInterceptor {
+	requests := metricsMap(service + "_grpc_requests_total")
+	latencyMs := metricsMap(service + "_grpc_latency_ms_total")
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		requests.Add(info.FullMethod+" "+status.Code(err).String(), 1)
+		latencyMs.Add(info.FullMethod, time.Since(start).Milliseconds())
+		return resp, err
+	}
+}
@@ -0,0 +1,83 @@
+package interceptors_test
+
+import (
+	"context"
+	"expvar"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/MelvinKim/platform/interceptors"
+)
+
+func ok(ctx context.Context, req interface{}) (interface{}, error) {
+	return "ok", nil
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/courses.Courses/GetCourse"}
+	tests := []struct {
+		name     string
+		token    string
+		metadata metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "Happy case - valid token", token: "secret", metadata: metadata.Pairs("authorization", "Bearer secret"), wantCode: codes.OK},
+		{name: "Sad case - no token configured", metadata: metadata.Pairs("authorization", "Bearer "), wantCode: codes.Unauthenticated},
+		{name: "Sad case - wrong token", token: "secret", metadata: metadata.Pairs("authorization", "Bearer guess"), wantCode: codes.Unauthenticated},
+		{name: "Sad case - no token", token: "secret", metadata: metadata.MD{}, wantCode: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.metadata)
+			_, err := interceptors.AuthInterceptor(tt.token)(ctx, nil, info, ok)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("AuthInterceptor() code = %s, want %s", status.Code(err), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthFromEnv(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/courses.Courses/GetCourse"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+
+	t.Setenv("GRPC_AUTH_TOKEN", "")
+	t.Setenv("GRPC_INSECURE", "")
+	if _, err := interceptors.AuthFromEnv(); err == nil {
+		t.Errorf("expected to refuse serving without a token")
+	}
+
+	t.Setenv("GRPC_INSECURE", "true")
+	auth, err := interceptors.AuthFromEnv()
+	if err != nil {
+		t.Fatalf("AuthFromEnv() error = %v", err)
+	}
+	if _, err := auth(ctx, nil, info, ok); err != nil {
+		t.Errorf("expected calls to be let in when insecure, got %v", err)
+	}
+
+	t.Setenv("GRPC_AUTH_TOKEN", "secret")
+	if auth, err = interceptors.AuthFromEnv(); err != nil {
+		t.Fatalf("AuthFromEnv() error = %v", err)
+	}
+	if _, err := auth(ctx, nil, info, ok); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected the token to be required once set, got %v", err)
+	}
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/courses.Courses/GetCourse"}
+	// setting the interceptor up twice must not register the counters twice
+	interceptors.MetricsInterceptor("test")
+	if _, err := interceptors.MetricsInterceptor("test")(context.Background(), nil, info, ok); err != nil {
+		t.Fatalf("MetricsInterceptor() error = %v", err)
+	}
+	requests, _ := expvar.Get("test_grpc_requests_total").(*expvar.Map)
+	if requests == nil || requests.Get(info.FullMethod+" OK").String() != "1" {
+		t.Errorf("expected the call to be counted, got %v", requests)
+	}
+}
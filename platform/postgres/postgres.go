@@ -0,0 +1,101 @@
+// Package postgres connects sudoCODE services to their postgres databases
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Config is where a service's database is and how to connect to it
+type Config struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+	TimeZone string
+}
+
+// ConfigFromEnv reads the connection settings from DB_HOST, DB_PORT, DB_USER,
+// DB_PASSWORD and DB_NAME, keeping the defaults for the ones that are not set.
+// Outside of prod, i.e. when ENVIRONMENT isn't "prod", TEST_DB_NAME names the
+// database when it is set
+func ConfigFromEnv(defaults Config) Config {
+	config := defaults
+	for env, value := range map[string]*string{
+		"DB_HOST":     &config.Host,
+		"DB_PORT":     &config.Port,
+		"DB_USER":     &config.User,
+		"DB_PASSWORD": &config.Password,
+		"DB_NAME":     &config.Name,
+	} {
+		if v := os.Getenv(env); v != "" {
+			*value = v
+		}
+	}
+	if os.Getenv("ENVIRONMENT") != "prod" {
+		if name := os.Getenv("TEST_DB_NAME"); name != "" {
+			config.Name = name
+		}
+	}
+	if config.SSLMode == "" {
+		config.SSLMode = "disable"
+	}
+	return config
+}
+
+// DSN formats the config as a postgres connection string
+func (c Config) DSN() string {
+	settings := []string{}
+	for _, setting := range []struct{ key, value string }{
+		{"host", c.Host},
+		{"user", c.User},
+		{"password", c.Password},
+		{"dbname", c.Name},
+		{"port", c.Port},
+		{"sslmode", c.SSLMode},
+		{"TimeZone", c.TimeZone},
+	} {
+		if setting.value != "" {
+			settings = append(settings, fmt.Sprintf("%s=%s", setting.key, setting.value))
+		}
+	}
+	return strings.Join(settings, " ")
+}
+
+// Connect opens a gorm connection to a service's database and runs its
+// migrations. The service can't run without it, so failing to connect is fatal
+func Connect(service string, config Config, migrate func(db *gorm.DB)) *gorm.DB {
+	db, err := Open(service, config, migrate)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
+
+// Open is Connect for callers that handle failing to connect themselves
+func Open(service string, config Config, migrate func(db *gorm.DB)) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(config.DSN()), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("can't open postgres db connection for the %s service: %w", service, err)
+	}
+	log.Info("Database connected successfully.")
+	if migrate != nil {
+		migrate(db)
+		log.Info("Database migrations ran successfully.")
+	}
+	return db, nil
+}
+
+// Checkpreconditions assert all conditions required to run the service are met
+func Checkpreconditions(db *gorm.DB) {
+	if db == nil {
+		log.Fatalf("postgres database ORM has not been initialized.")
+	}
+}
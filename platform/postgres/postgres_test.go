@@ -0,0 +1,42 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/MelvinKim/platform/postgres"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	defaults := postgres.Config{Host: "postgresql-service", Port: "5432", User: "postgres", Password: "postgres", Name: "sudocode"}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantDSN string
+	}{
+		{
+			name:    "Happy case - defaults",
+			wantDSN: "host=postgresql-service user=postgres password=postgres dbname=sudocode port=5432 sslmode=disable",
+		},
+		{
+			name:    "Happy case - env overrides defaults",
+			env:     map[string]string{"DB_HOST": "localhost", "DB_NAME": "academy", "ENVIRONMENT": "prod", "TEST_DB_NAME": "academy_test"},
+			wantDSN: "host=localhost user=postgres password=postgres dbname=academy port=5432 sslmode=disable",
+		},
+		{
+			name:    "Happy case - test database outside of prod",
+			env:     map[string]string{"DB_NAME": "academy", "ENVIRONMENT": "test", "TEST_DB_NAME": "academy_test"},
+			wantDSN: "host=postgresql-service user=postgres password=postgres dbname=academy_test port=5432 sslmode=disable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "ENVIRONMENT", "TEST_DB_NAME"} {
+				t.Setenv(env, tt.env[env])
+			}
+			if got := postgres.ConfigFromEnv(defaults).DSN(); got != tt.wantDSN {
+				t.Errorf("ConfigFromEnv().DSN() = %q, want %q", got, tt.wantDSN)
+			}
+		})
+	}
+}
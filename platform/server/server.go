@@ -0,0 +1,112 @@
+// Package server bootstraps the HTTP servers of sudoCODE services
+package server
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gorilla/handlers"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultTimeout is how long a request can take to be read or answered
+const DefaultTimeout = 120 * time.Second
+
+// AllowedHeaders are the request headers browsers may send cross origin
+var AllowedHeaders = []string{
+	"Authorization", "Accept", "Accept-Charset", "Accept-Language",
+	"Accept-Encoding", "Origin", "Host", "User-Agent", "Content-Length",
+	"Content-Type", " X-Authorization", " Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers",
+}
+
+// Middleware wraps a handler with behaviour shared by every route
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps a handler in middlewares. The first middleware is the
+// outermost, so it sees requests first and responses last
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// Compress gzips responses for clients that accept it
+func Compress() Middleware {
+	return func(h http.Handler) http.Handler {
+		return handlers.CompressHandlerLevel(h, gzip.BestCompression)
+	}
+}
+
+// CORS lets browsers call the given methods cross origin with credentials
+func CORS(methods ...string) Middleware {
+	return handlers.CORS(
+		handlers.AllowedHeaders(AllowedHeaders),
+		handlers.AllowCredentials(),
+		handlers.AllowedMethods(methods),
+	)
+}
+
+// AccessLog writes every request to out in the combined log format
+func AccessLog(out io.Writer) Middleware {
+	return func(h http.Handler) http.Handler {
+		return handlers.CombinedLoggingHandler(out, h)
+	}
+}
+
+// ContentTypes refuses request bodies that aren't one of the content types
+func ContentTypes(contentTypes ...string) Middleware {
+	return func(h http.Handler) http.Handler {
+		return handlers.ContentTypeHandler(h, contentTypes...)
+	}
+}
+
+// Options configure a service's HTTP server
+type Options struct {
+	Port int
+	// Methods are the methods allowed cross origin, GET, POST and OPTIONS
+	// when empty
+	Methods []string
+	// ContentTypes are the request body content types accepted, JSON and
+	// forms when empty
+	ContentTypes []string
+	// Timeout bounds reading requests and writing responses, DefaultTimeout
+	// when zero
+	Timeout time.Duration
+	// Middlewares run inside the standard ones, closest to the router
+	Middlewares []Middleware
+}
+
+// New sets up a server for a service's router with the standard middleware
+// chain: content type checks, access logs, CORS and compression
+func New(router http.Handler, options Options) *http.Server {
+	if len(options.Methods) == 0 {
+		options.Methods = []string{http.MethodOptions, http.MethodGet, http.MethodPost}
+	}
+	if len(options.ContentTypes) == 0 {
+		options.ContentTypes = []string{"application/json", "application/x-www-form-urlencoded"}
+	}
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	middlewares := append([]Middleware{
+		ContentTypes(options.ContentTypes...),
+		AccessLog(os.Stdout),
+		CORS(options.Methods...),
+		Compress(),
+	}, options.Middlewares...)
+	addr := fmt.Sprintf(":%d", options.Port)
+	srv := &http.Server{
+		Handler:      Chain(router, middlewares...),
+		Addr:         addr,
+		WriteTimeout: options.Timeout,
+		ReadTimeout:  options.Timeout,
+	}
+	log.Infof("Server running at port %v", addr)
+	return srv
+}
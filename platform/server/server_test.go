@@ -0,0 +1,60 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MelvinKim/platform/server"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	mark := func(name string) server.Middleware {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				h.ServeHTTP(w, r)
+			})
+		}
+	}
+	h := server.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), mark("outer"), mark("inner"))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Join(order, ",") != "outer,inner,handler" {
+		t.Errorf("expected middlewares to run outermost first, got %v", order)
+	}
+}
+
+func TestNew(t *testing.T) {
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := server.New(router, server.Options{Port: 9000})
+	if srv.Addr != ":9000" || srv.ReadTimeout != server.DefaultTimeout {
+		t.Errorf("expected the defaults to be applied, got %s and %s", srv.Addr, srv.ReadTimeout)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		wantStatus  int
+	}{
+		{name: "Happy case - JSON body", contentType: "application/json", wantStatus: http.StatusNoContent},
+		{name: "Sad case - unsupported body", contentType: "text/plain", wantStatus: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+			r.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package validation checks request payloads against their `validate` struct
+// tags and reports every violation by the fields' JSON names
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+// newValidator sets up a validator that reports fields by their JSON names
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// FieldError describes a single rule violation on a payload field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every rule violation found on a payload
+type ValidationErrors []FieldError
+
+// Error implements the error interface
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, e := range v {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", e.Field, e.Message))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks a payload against its `validate` struct tags and returns
+// all the violations found as ValidationErrors
+func Validate(payload interface{}) error {
+	err := validate.Struct(payload)
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	violations := make(ValidationErrors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		violations = append(violations, FieldError{
+			Field:   fe.Field(),
+			Code:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	return violations
+}
+
+// message renders a human readable description of a failed rule
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s can not be empty", fe.Field())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fe.Field())
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters long", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at most %s characters long", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), fe.Param())
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", fe.Field())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", fe.Field(), fe.Param())
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "uuid":
+		return fmt.Sprintf("%s must be a valid UUID", fe.Field())
+	default:
+		return fmt.Sprintf("%s failed the %s rule", fe.Field(), fe.Tag())
+	}
+}
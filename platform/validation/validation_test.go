@@ -0,0 +1,58 @@
+package validation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MelvinKim/platform/validation"
+)
+
+type payload struct {
+	Name     string `json:"name" validate:"required,max=5"`
+	Email    string `json:"email" validate:"omitempty,email"`
+	Kind     string `json:"kind" validate:"omitempty,oneof=a b"`
+	Internal string `json:"-" validate:"max=1"`
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    *payload
+		wantFields []string
+		wantCodes  []string
+	}{
+		{
+			name:    "Happy case",
+			payload: &payload{Name: "Ada", Email: "ada@example.com", Kind: "a"},
+		},
+		{
+			name:       "Sad case - every violation is reported by JSON name",
+			payload:    &payload{Name: "Ada Lovelace", Email: "ada", Kind: "c"},
+			wantFields: []string{"name", "email", "kind"},
+			wantCodes:  []string{"max", "email", "oneof"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validation.Validate(tt.payload)
+			if (err != nil) != (len(tt.wantFields) > 0) {
+				t.Fatalf("Validate() error = %v, want violations on %v", err, tt.wantFields)
+			}
+			if err == nil {
+				return
+			}
+			var violations validation.ValidationErrors
+			if !errors.As(err, &violations) {
+				t.Fatalf("expected validation errors, got %T", err)
+			}
+			if len(violations) != len(tt.wantFields) {
+				t.Fatalf("expected %d violations, got %d: %v", len(tt.wantFields), len(violations), violations)
+			}
+			for i, field := range tt.wantFields {
+				if violations[i].Field != field || violations[i].Code != tt.wantCodes[i] || violations[i].Message == "" {
+					t.Errorf("expected a %s violation on %s, got %+v", tt.wantCodes[i], field, violations[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package web holds the request and response helpers of sudoCODE REST APIs
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/MelvinKim/platform/validation"
+)
+
+// JSON writes data as the JSON response body with the status code
+func JSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+// DecodePayload strictly unmarshals the request body into the payload and
+// runs the payload's validation rules
+func DecodePayload(r *http.Request, payload interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return fmt.Errorf("error unmarshalling request body to struct: %w", err)
+	}
+	return validation.Validate(payload)
+}
+
+// PayloadError writes the error raised while decoding a payload, listing
+// every field violation when the payload failed validation
+func PayloadError(w http.ResponseWriter, err error) {
+	var violations validation.ValidationErrors
+	if errors.As(err, &violations) {
+		JSON(w, map[string]validation.ValidationErrors{"errors": violations}, http.StatusBadRequest)
+		return
+	}
+	JSON(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+}
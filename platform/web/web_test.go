@@ -0,0 +1,57 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MelvinKim/platform/web"
+)
+
+type payload struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestDecodePayload(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantErrors int
+		wantError  bool
+	}{
+		{name: "Happy case", body: `{"name": "Ada"}`},
+		{name: "Sad case - failed validation", body: `{"name": ""}`, wantErrors: 1},
+		{name: "Sad case - unknown field", body: `{"name": "Ada", "age": 36}`, wantError: true},
+		{name: "Sad case - not JSON", body: `name=Ada`, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			err := web.DecodePayload(r, &payload{})
+			if (err != nil) != (tt.wantErrors > 0 || tt.wantError) {
+				t.Fatalf("DecodePayload() error = %v", err)
+			}
+			if err == nil {
+				return
+			}
+
+			w := httptest.NewRecorder()
+			web.PayloadError(w, err)
+			if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != "application/json" {
+				t.Fatalf("expected a JSON bad request, got %d %s", w.Code, w.Header().Get("Content-Type"))
+			}
+			var body struct {
+				Error  string            `json:"error"`
+				Errors []json.RawMessage `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("expected a JSON body, got %v", err)
+			}
+			if len(body.Errors) != tt.wantErrors || (tt.wantError && body.Error == "") {
+				t.Errorf("expected %d field errors, got %+v", tt.wantErrors, body)
+			}
+		})
+	}
+}
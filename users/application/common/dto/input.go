@@ -2,9 +2,9 @@ package dto
 
 // StudentCreationPayload
 type StudentCreationPayload struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
+	FirstName string `json:"first_name" validate:"required,max=255"`
+	LastName  string `json:"last_name" validate:"required,max=255"`
+	Email     string `json:"email" validate:"required,email,max=255"`
 }
 
 // GetStudentPayload
@@ -0,0 +1,69 @@
+package dto_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MelvinKim/platform/validation"
+	"github.com/MelvinKim/users/application/common/dto"
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func TestValidate(t *testing.T) {
+	validStudent := &dto.StudentCreationPayload{
+		FirstName: gofakeit.FirstName(),
+		LastName:  gofakeit.LastName(),
+		Email:     gofakeit.Email(),
+	}
+	invalidStudent := &dto.StudentCreationPayload{
+		FirstName: "",
+		LastName:  "",
+		Email:     "not-an-email",
+	}
+
+	type args struct {
+		payload interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantFields []string
+	}{
+		{
+			name: "Happy case",
+			args: args{
+				payload: validStudent,
+			},
+		},
+		{
+			name: "Sad case - invalid fields",
+			args: args{
+				payload: invalidStudent,
+			},
+			wantFields: []string{"first_name", "last_name", "email"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validation.Validate(tt.args.payload)
+			if (err != nil) != (len(tt.wantFields) > 0) {
+				t.Fatalf("Validate() error = %v, want violations on %v", err, tt.wantFields)
+			}
+			if err == nil {
+				return
+			}
+			var violations validation.ValidationErrors
+			if !errors.As(err, &violations) {
+				t.Fatalf("expected validation errors, got %T", err)
+			}
+			if len(violations) != len(tt.wantFields) {
+				t.Fatalf("expected %d violations, got %d: %v", len(tt.wantFields), len(violations), violations)
+			}
+			for i, field := range tt.wantFields {
+				if violations[i].Field != field {
+					t.Errorf("expected violation on %s, got %s", field, violations[i].Field)
+				}
+			}
+		})
+	}
+}
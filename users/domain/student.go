@@ -1,26 +1,9 @@
 package domain
 
-import (
-	"time"
+import "github.com/MelvinKim/platform/entity"
 
-	"github.com/google/uuid"
-	"gorm.io/gorm"
-)
-
-// AbstractBase is an abstract struct that can be embedded in other structs
-type AbstractBase struct {
-	UUID      string `gorm:"primaryKey"`
-	Active    bool   `gorm:"default:true"`
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
-}
-
-// BeforeCreate ensures a UUID and createdAt data is inserted
-func (ab *AbstractBase) BeforeCreate(tx *gorm.DB) (err error) {
-	ab.UUID = uuid.New().String()
-	return
-}
+// AbstractBase is the UUID, active flag and timestamps every entity has
+type AbstractBase = entity.AbstractBase
 
 // Student ...
 type Student struct {
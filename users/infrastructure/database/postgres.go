@@ -3,11 +3,10 @@ package database
 import (
 	"context"
 	"fmt"
-	"os"
 
+	"github.com/MelvinKim/platform/postgres"
 	"github.com/MelvinKim/users/domain"
 	log "github.com/sirupsen/logrus"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -18,9 +17,7 @@ type PostgresDB struct {
 
 // Checkpreconditions assert all conditions required to run the service are met
 func (p *PostgresDB) Checkpreconditions() {
-	if p.DB == nil {
-		log.Fatalf("postgres database ORM has not been initialized.")
-	}
+	postgres.Checkpreconditions(p.DB)
 }
 
 // NewPostgresDB initializes a new postgres DB instance
@@ -46,35 +43,8 @@ func Migrate(db *gorm.DB) {
 
 // Init initializes a new gorm instance by connecting to a postgres DB instance
 func Init() *gorm.DB {
-	var dsn string
-	if os.Getenv("ENVIRONMENT") == "prod" {
-		dsn = fmt.Sprintf(
-			"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Africa/Nairobi",
-			os.Getenv("DB_HOST"),
-			os.Getenv("DB_USER"),
-			os.Getenv("DB_PASSWORD"),
-			os.Getenv("DB_NAME"),
-			os.Getenv("DB_PORT"),
-		)
-	} else {
-		dsn = fmt.Sprintf(
-			"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Africa/Nairobi",
-			os.Getenv("DB_HOST"),
-			os.Getenv("DB_USER"),
-			os.Getenv("DB_PASSWORD"),
-			os.Getenv("TEST_DB_NAME"),
-			os.Getenv("DB_PORT"),
-		)
-	}
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatalf("can't open postgres db connection for the users service: %v", err)
-	}
-	log.Info("Database connected successfully.")
-	Migrate(db)
-	log.Info("Database migrations ran successfully.")
-	return db
+	config := postgres.ConfigFromEnv(postgres.Config{TimeZone: "Africa/Nairobi"})
+	return postgres.Connect("users", config, Migrate)
 }
 
 // CreateStudent creates a new student in sudoCODE academy
@@ -1,35 +1,25 @@
 package presentation
 
 import (
-	"compress/gzip"
 	"context"
 	"fmt"
 	"net/http"
-	"os"
-	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/MelvinKim/platform/interceptors"
+	"github.com/MelvinKim/platform/server"
 	"github.com/MelvinKim/users/infrastructure/database"
 	"github.com/MelvinKim/users/presentation/interactor"
 	"github.com/MelvinKim/users/presentation/rest"
+	"github.com/MelvinKim/users/presentation/rpc"
 	"github.com/MelvinKim/users/usecase"
-	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
-const (
-	serverTimeoutSeconds = 120
-)
-
-var allowedHeaders = []string{
-	"Authorization", "Accept", "Accept-Charset", "Accept-Language",
-	"Accept-Encoding", "Origin", "Host", "User-Agent", "Content-Length",
-	"Content-Type", " X-Authorization", " Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers",
-}
-
-// Router sets up the gorilla Mux router
-func Router(ctx context.Context) (*mux.Router, error) {
+// newInteractor wires the users usecase to its postgres repositories
+func newInteractor() (*interactor.Interactor, error) {
 	create := database.NewPostgresDB()
 	get := database.NewPostgresDB()
 	users := usecase.NewUsecase(create, get)
@@ -40,6 +30,15 @@ func Router(ctx context.Context) (*mux.Router, error) {
 	if err != nil {
 		return nil, fmt.Errorf("can't instantiate a new service: %w", err)
 	}
+	return i, nil
+}
+
+// Router sets up the gorilla Mux router
+func Router(ctx context.Context) (*mux.Router, error) {
+	i, err := newInteractor()
+	if err != nil {
+		return nil, err
+	}
 
 	h := rest.NewPresentationHandlers(i)
 
@@ -63,28 +62,26 @@ func PrepareServer(
 		log.WithFields(log.Fields{"error": err}).Error("Server startup error")
 	}
 
-	// start the server
-	addr := fmt.Sprintf(":%d", port)
-	h := handlers.CompressHandlerLevel(r, gzip.BestCompression)
+	return server.New(r, server.Options{
+		Port:    port,
+		Methods: []string{http.MethodOptions, http.MethodGet, http.MethodPost},
+	})
+}
 
-	h = handlers.CORS(
-		handlers.AllowedHeaders(allowedHeaders),
-		handlers.AllowCredentials(),
-		handlers.AllowedMethods([]string{"OPTIONS", "GET", "POST"}),
-	)(h)
-	h = handlers.CombinedLoggingHandler(os.Stdout, h)
-	h = handlers.ContentTypeHandler(
-		h,
-		"application/json",
-		"application/x-www-form-urlencoded",
-	)
-	srv := &http.Server{
-		Handler:      h,
-		Addr:         addr,
-		WriteTimeout: serverTimeoutSeconds * time.Second,
-		ReadTimeout:  serverTimeoutSeconds * time.Second,
+// PrepareGRPCServer sets up the gRPC server used for service-to-service calls.
+// Callers authenticate with the token in the GRPC_AUTH_TOKEN env variable, and
+// the server refuses to start without one unless GRPC_INSECURE=true
+func PrepareGRPCServer(
+	ctx context.Context,
+) (*grpc.Server, error) {
+	i, err := newInteractor()
+	if err != nil {
+		return nil, err
 	}
-	log.Infof("Server running at port %v", addr)
-	return srv
 
+	auth, err := interceptors.AuthFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewGRPCServer(i, auth), nil
 }
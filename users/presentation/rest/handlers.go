@@ -1,10 +1,10 @@
 package rest
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/MelvinKim/platform/web"
 	"github.com/MelvinKim/users/application/common/dto"
 	"github.com/MelvinKim/users/domain"
 	"github.com/MelvinKim/users/presentation/interactor"
@@ -28,21 +28,12 @@ func NewPresentationHandlers(
 	return &PresentationHandlersImpl{i}
 }
 
-func jsonResponse(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
-}
-
 func (p PresentationHandlersImpl) CreateStudent() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		payload := &dto.StudentCreationPayload{}
-		err := json.NewDecoder(r.Body).Decode(payload)
-		if err != nil {
-			msg := fmt.Sprintf("error unmarshalling request boy to struct: %v", err)
-			w.Header().Set("Content-Type", "application/json")
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
 			return
 		}
 
@@ -54,11 +45,11 @@ func (p PresentationHandlersImpl) CreateStudent() http.HandlerFunc {
 		createdStudent, err := p.interactor.Users.CreateStudent(ctx, &student)
 		if err != nil {
 			msg := fmt.Sprintf("error creating student: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
 			return
 		}
 
-		jsonResponse(w, createdStudent, http.StatusCreated)
+		web.JSON(w, createdStudent, http.StatusCreated)
 	}
 }
 
@@ -66,10 +57,8 @@ func (p PresentationHandlersImpl) GetStudent() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		payload := &dto.GetStudentPayload{}
-		err := json.NewDecoder(r.Body).Decode(payload)
-		if err != nil {
-			msg := fmt.Sprintf("error unmarshalling request boy to struct: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		if err := web.DecodePayload(r, payload); err != nil {
+			web.PayloadError(w, err)
 			return
 		}
 
@@ -77,10 +66,10 @@ func (p PresentationHandlersImpl) GetStudent() http.HandlerFunc {
 		student, err := p.interactor.Users.GetStudent(ctx, &email)
 		if err != nil {
 			msg := fmt.Sprintf("error getting student: %v", err)
-			jsonResponse(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			web.JSON(w, map[string]string{"error": msg}, http.StatusBadRequest)
 			return
 		}
 
-		jsonResponse(w, student, http.StatusOK)
+		web.JSON(w, student, http.StatusOK)
 	}
 }
@@ -0,0 +1,139 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/MelvinKim/platform/interceptors"
+	"github.com/MelvinKim/platform/validation"
+	"github.com/MelvinKim/users/application/common/dto"
+	"github.com/MelvinKim/users/domain"
+	"github.com/MelvinKim/users/presentation/interactor"
+	"github.com/MelvinKim/users/presentation/rpc/pb"
+)
+
+// UsersServer serves the users usecase over gRPC
+type UsersServer struct {
+	pb.UnimplementedUsersServiceServer
+	interactor *interactor.Interactor
+}
+
+// NewUsersServer initializes a new gRPC users server
+func NewUsersServer(
+	i *interactor.Interactor,
+) *UsersServer {
+	return &UsersServer{interactor: i}
+}
+
+// NewGRPCServer sets up a gRPC server with the users service, the standard
+// health service and the request ID, logging, metrics and auth interceptors.
+// auth is usually interceptors.AuthFromEnv's
+func NewGRPCServer(
+	i *interactor.Interactor,
+	auth grpc.UnaryServerInterceptor,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		interceptors.RequestIDInterceptor(),
+		interceptors.LoggingInterceptor(),
+		interceptors.MetricsInterceptor("users"),
+		auth,
+	))
+	srv := grpc.NewServer(opts...)
+	pb.RegisterUsersServiceServer(srv, NewUsersServer(i))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.UsersService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(srv, healthServer)
+	return srv
+}
+
+// invalidArgument converts payload validation errors to an InvalidArgument
+// status carrying a BadRequest detail per violated field
+func invalidArgument(err error) error {
+	var violations validation.ValidationErrors
+	if !errors.As(err, &violations) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Message,
+		})
+	}
+	st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(badRequest)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func studentToProto(student *domain.Student) *pb.Student {
+	return &pb.Student{
+		Uuid:      student.UUID,
+		FirstName: student.FirstName,
+		LastName:  student.LastName,
+		Email:     student.Email,
+		CreatedAt: timestamp(student.CreatedAt),
+		UpdatedAt: timestamp(student.UpdatedAt),
+	}
+}
+
+// CreateStudent creates a new sudocode student
+func (s *UsersServer) CreateStudent(
+	ctx context.Context,
+	req *pb.CreateStudentRequest,
+) (*pb.Student, error) {
+	payload := &dto.StudentCreationPayload{
+		FirstName: req.GetFirstName(),
+		LastName:  req.GetLastName(),
+		Email:     req.GetEmail(),
+	}
+	if err := validation.Validate(payload); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	student := domain.Student{
+		FirstName: payload.FirstName,
+		LastName:  payload.LastName,
+		Email:     payload.Email,
+	}
+	createdStudent, err := s.interactor.Users.CreateStudent(ctx, &student)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error creating student: %v", err)
+	}
+	return studentToProto(createdStudent), nil
+}
+
+// GetStudent gets a student by their email address
+func (s *UsersServer) GetStudent(
+	ctx context.Context,
+	req *pb.GetStudentRequest,
+) (*pb.Student, error) {
+	email := req.GetEmail()
+	student, err := s.interactor.Users.GetStudent(ctx, &email)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error getting student: %v", err)
+	}
+	if student == nil {
+		return nil, status.Errorf(codes.NotFound, "student with email %s not found", email)
+	}
+	return studentToProto(student), nil
+}
@@ -0,0 +1,171 @@
+package rpc_test
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/MelvinKim/platform/interceptors"
+	"github.com/MelvinKim/users/domain"
+	"github.com/MelvinKim/users/presentation/interactor"
+	"github.com/MelvinKim/users/presentation/rpc"
+	"github.com/MelvinKim/users/presentation/rpc/pb"
+	"github.com/MelvinKim/users/repository/mock"
+	"github.com/MelvinKim/users/usecase"
+	"github.com/brianvoe/gofakeit/v6"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testAuthToken = "test-token"
+
+var listener *bufconn.Listener
+
+func TestMain(m *testing.M) {
+	// setup
+	mockCreate := mock.NewMockCreateRepository()
+	mockGet := mock.NewMockGetRepository()
+	mockCreate.MockCreateStudent = func(ctx context.Context, student *domain.Student) (*domain.Student, error) {
+		student.UUID = gofakeit.UUID()
+		return student, nil
+	}
+	mockGet.MockGetStudent = func(ctx context.Context, email *string) (*domain.Student, error) {
+		return nil, nil
+	}
+	i, err := interactor.NewUsersInteractor(usecase.NewUsecase(mockCreate, mockGet))
+	if err != nil {
+		log.Fatalf("unable to create test interactor: %s", err)
+	}
+
+	listener = bufconn.Listen(1024 * 1024)
+	srv := rpc.NewGRPCServer(i, interceptors.AuthInterceptor(testAuthToken))
+	go func() {
+		if err := srv.Serve(listener); err != nil {
+			log.Printf("serve error: %s", err)
+		}
+	}()
+
+	// run the tests
+	code := m.Run()
+
+	// cleanup here
+	srv.Stop()
+	os.Exit(code)
+}
+
+func dial(ctx context.Context, t *testing.T) *grpc.ClientConn {
+	conn, err := grpc.DialContext(
+		ctx,
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func authenticated(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testAuthToken)
+}
+
+func TestUsersServer_CreateStudent(t *testing.T) {
+	ctx := context.Background()
+	client := pb.NewUsersServiceClient(dial(ctx, t))
+
+	type args struct {
+		ctx context.Context
+		req *pb.CreateStudentRequest
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantCode codes.Code
+	}{
+		{
+			name: "Happy case",
+			args: args{
+				ctx: authenticated(ctx),
+				req: &pb.CreateStudentRequest{
+					FirstName: gofakeit.FirstName(),
+					LastName:  gofakeit.LastName(),
+					Email:     gofakeit.Email(),
+				},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Sad case - invalid payload",
+			args: args{
+				ctx: authenticated(ctx),
+				req: &pb.CreateStudentRequest{
+					Email: "not-an-email",
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Sad case - missing token",
+			args: args{
+				ctx: ctx,
+				req: &pb.CreateStudentRequest{
+					FirstName: gofakeit.FirstName(),
+					LastName:  gofakeit.LastName(),
+					Email:     gofakeit.Email(),
+				},
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+			student, err := client.CreateStudent(tt.args.ctx, tt.args.req, grpc.Header(&header))
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("UsersServer.CreateStudent() code = %v, want %v (err: %v)", status.Code(err), tt.wantCode, err)
+			}
+			if len(header.Get(interceptors.RequestIDHeader)) == 0 {
+				t.Errorf("expected a %s response header", interceptors.RequestIDHeader)
+			}
+			if tt.wantCode == codes.OK && student.GetUuid() == "" {
+				t.Errorf("expected student to have a valid UUID")
+			}
+		})
+	}
+}
+
+func TestUsersServer_GetStudent(t *testing.T) {
+	ctx := authenticated(context.Background())
+	client := pb.NewUsersServiceClient(dial(ctx, t))
+
+	_, err := client.GetStudent(ctx, &pb.GetStudentRequest{Email: gofakeit.Email()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("UsersServer.GetStudent() code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	client := grpc_health_v1.NewHealthClient(dial(ctx, t))
+
+	resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: pb.UsersService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		t.Fatalf("Health.Check() error = %v", err)
+	}
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v", resp.GetStatus())
+	}
+}
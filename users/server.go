@@ -2,17 +2,39 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/MelvinKim/users/presentation"
 )
 
-const PORT = 9000
+const (
+	PORT      = 9000
+	GRPC_PORT = 9090
+)
 
 func main() {
 	ctx := context.Background()
 
+	grpcSrv, err := presentation.PrepareGRPCServer(ctx)
+	if err != nil {
+		log.Errorf("gRPC server start up error: %v", err)
+		return
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", GRPC_PORT))
+	if err != nil {
+		log.Errorf("unable to listen on port %d: %v", GRPC_PORT, err)
+		return
+	}
+	go func() {
+		log.Infof("gRPC server running at port %d", GRPC_PORT)
+		if err := grpcSrv.Serve(l); err != nil {
+			log.Errorf("gRPC server error: %v", err)
+		}
+	}()
+
 	srv := presentation.PrepareServer(ctx, PORT)
 
 	if err := srv.ListenAndServe(); err != nil {
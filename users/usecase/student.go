@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/MelvinKim/platform/validation"
+	"github.com/MelvinKim/users/application/common/dto"
 	"github.com/MelvinKim/users/domain"
 	"github.com/MelvinKim/users/repository"
 )
@@ -49,19 +51,18 @@ func NewUsecase(
 	return uc
 }
 
-// CreateStudent creates a new sudocode student
+// CreateStudent creates a new sudocode student. The student is validated
+// here as well as by the handlers so that no caller can skip the rules
 func (u *Usecase) CreateStudent(
 	ctx context.Context,
 	student *domain.Student,
 ) (*domain.Student, error) {
-	if student.Email == "" {
-		return nil, fmt.Errorf("email can not be empty")
-	}
-	if student.FirstName == "" {
-		return nil, fmt.Errorf("first name can not be empty")
-	}
-	if student.LastName == "" {
-		return nil, fmt.Errorf("last name can not be empty")
+	if err := validation.Validate(&dto.StudentCreationPayload{
+		FirstName: student.FirstName,
+		LastName:  student.LastName,
+		Email:     student.Email,
+	}); err != nil {
+		return nil, err
 	}
 	return u.Create.CreateStudent(ctx, student)
 }